@@ -10,9 +10,22 @@ import (
 	"go.opentelemetry.io/collector/component"
 )
 
+const (
+	// probeModeLive routes all data to the higher priority level being retried, its exporters' errors tell
+	// whether the level recovered
+	probeModeLive = "live"
+	// probeModeShadow keeps routing data to the stable level, and shadows a fraction of it to the higher
+	// priority level being retried
+	probeModeShadow = "shadow"
+)
+
 var (
-	errNoPipelinePriority    = errors.New("No pipelines are defined in the priority list")
-	errInvalidRetryIntervals = errors.New("Retry interval must be positive, and retry_interval must be greater than retry_gap times the length of the priority list")
+	errNoPipelinePriority        = errors.New("No pipelines are defined in the priority list")
+	errInvalidRetryIntervals     = errors.New("Retry interval must be positive, and retry_interval must be greater than retry_gap times the length of the priority list")
+	errInvalidRetryBackoff       = errors.New("retry_backoff::multiplier must be at least 1, retry_backoff::randomization_factor must be between 0 and 1, and retry_backoff::max_interval must not be negative")
+	errInvalidMaxRetriesCooldown = errors.New("max_retries_cooldown must not be negative")
+	errInvalidProbeMode          = errors.New("probe::mode must be either \"live\" or \"shadow\"")
+	errInvalidShadowRatio        = errors.New("probe::shadow_ratio must be greater than 0 and at most 1")
 )
 
 type Config struct {
@@ -33,6 +46,38 @@ type Config struct {
 	// MaxRetry is the maximum retries per level, once this limit is hit for a level, even if the next pipeline level fails,
 	// it will not try to recover the level that exceeded the maximum retries
 	MaxRetries int `mapstructure:"max_retries"`
+
+	// MaxRetriesCooldown is how long a level that hit MaxRetries stays disabled, after which its retry count is reset
+	// and it is retried again. Zero keeps the level disabled
+	MaxRetriesCooldown time.Duration `mapstructure:"max_retries_cooldown"`
+
+	// RetryBackoff grows the RetryInterval after each unsuccessful retry round
+	RetryBackoff RetryBackoffConfig `mapstructure:"retry_backoff"`
+
+	// Probe defines how the health of the higher priority levels is checked during a retry
+	Probe ProbeConfig `mapstructure:"probe"`
+}
+
+type RetryBackoffConfig struct {
+	// Multiplier is the factor the RetryInterval is multiplied by after each unsuccessful retry round,
+	// 1 keeps a fixed RetryInterval
+	Multiplier float64 `mapstructure:"multiplier"`
+
+	// MaxInterval is the upper bound of the interval between two retry rounds, zero leaves it unbounded.
+	// It never brings the interval below RetryInterval
+	MaxInterval time.Duration `mapstructure:"max_interval"`
+
+	// RandomizationFactor is the jitter applied to each interval, an interval i is picked in [i - f*i, i + f*i]
+	RandomizationFactor float64 `mapstructure:"randomization_factor"`
+}
+
+type ProbeConfig struct {
+	// Mode is either "live", routing the data to the level being retried, or "shadow", sending it a copy
+	// of a fraction of the data while the stable level keeps receiving all of it
+	Mode string `mapstructure:"mode"`
+
+	// ShadowRatio is the fraction of the data copied to the level being retried in shadow mode
+	ShadowRatio float64 `mapstructure:"shadow_ratio"`
 }
 
 // Validate needs to ensure RetryInterval > # elements in PriorityList * RetryGap
@@ -44,5 +89,23 @@ func (c *Config) Validate() error {
 	if c.RetryGap <= 0 || c.RetryInterval <= 0 || c.RetryInterval <= retryTime {
 		return errInvalidRetryIntervals
 	}
+	backoff := c.RetryBackoff
+	if (backoff.Multiplier != 0 && backoff.Multiplier < 1) ||
+		backoff.RandomizationFactor < 0 || backoff.RandomizationFactor > 1 ||
+		backoff.MaxInterval < 0 {
+		return errInvalidRetryBackoff
+	}
+	if c.MaxRetriesCooldown < 0 {
+		return errInvalidMaxRetriesCooldown
+	}
+	switch c.Probe.Mode {
+	case "", probeModeLive:
+	case probeModeShadow:
+		if c.Probe.ShadowRatio <= 0 || c.Probe.ShadowRatio > 1 {
+			return errInvalidShadowRatio
+		}
+	default:
+		return errInvalidProbeMode
+	}
 	return nil
 }
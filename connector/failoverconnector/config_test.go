@@ -29,9 +29,19 @@ func TestLoadConfig(t *testing.T) {
 						component.NewIDWithName(component.DataTypeTraces, ""),
 					},
 				},
-				RetryInterval: 10 * time.Minute,
-				RetryGap:      30 * time.Second,
-				MaxRetries:    10,
+				RetryInterval:      10 * time.Minute,
+				RetryGap:           30 * time.Second,
+				MaxRetries:         10,
+				MaxRetriesCooldown: time.Hour,
+				RetryBackoff: RetryBackoffConfig{
+					Multiplier:          2,
+					MaxInterval:         time.Hour,
+					RandomizationFactor: 0.2,
+				},
+				Probe: ProbeConfig{
+					Mode:        probeModeLive,
+					ShadowRatio: 0.01,
+				},
 			},
 		},
 		{
@@ -52,9 +62,19 @@ func TestLoadConfig(t *testing.T) {
 						component.NewIDWithName(component.DataTypeTraces, "fourth"),
 					},
 				},
-				RetryInterval: 5 * time.Minute,
-				RetryGap:      time.Minute,
-				MaxRetries:    10,
+				RetryInterval:      5 * time.Minute,
+				RetryGap:           time.Minute,
+				MaxRetries:         10,
+				MaxRetriesCooldown: 30 * time.Minute,
+				RetryBackoff: RetryBackoffConfig{
+					Multiplier:          1.5,
+					MaxInterval:         20 * time.Minute,
+					RandomizationFactor: 0.1,
+				},
+				Probe: ProbeConfig{
+					Mode:        probeModeShadow,
+					ShadowRatio: 0.05,
+				},
 			},
 		},
 	}
@@ -93,6 +113,26 @@ func TestValidateConfig(t *testing.T) {
 			id:   component.NewIDWithName(metadata.Type, "invalid"),
 			err:  errInvalidRetryIntervals,
 		},
+		{
+			name: "multiplier lower than 1",
+			id:   component.NewIDWithName(metadata.Type, "invalid_backoff"),
+			err:  errInvalidRetryBackoff,
+		},
+		{
+			name: "negative max_retries_cooldown",
+			id:   component.NewIDWithName(metadata.Type, "invalid_cooldown"),
+			err:  errInvalidMaxRetriesCooldown,
+		},
+		{
+			name: "unknown probe mode",
+			id:   component.NewIDWithName(metadata.Type, "invalid_probe_mode"),
+			err:  errInvalidProbeMode,
+		},
+		{
+			name: "shadow probing without shadow_ratio",
+			id:   component.NewIDWithName(metadata.Type, "invalid_shadow_ratio"),
+			err:  errInvalidShadowRatio,
+		},
 	}
 
 	for _, tc := range testcases {
@@ -26,9 +26,19 @@ func NewFactory() connector.Factory {
 
 func createDefaultConfig() component.Config {
 	return &Config{
-		RetryGap:      30 * time.Second,
-		RetryInterval: 10 * time.Minute,
-		MaxRetries:    10,
+		RetryGap:           30 * time.Second,
+		RetryInterval:      10 * time.Minute,
+		MaxRetries:         10,
+		MaxRetriesCooldown: time.Hour,
+		RetryBackoff: RetryBackoffConfig{
+			Multiplier:          2,
+			MaxInterval:         time.Hour,
+			RandomizationFactor: 0.2,
+		},
+		Probe: ProbeConfig{
+			Mode:        probeModeLive,
+			ShadowRatio: 0.01,
+		},
 	}
 }
 
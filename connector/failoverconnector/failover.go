@@ -4,11 +4,16 @@
 package failoverconnector // import "github.com/open-telemetry/opentelemetry-collector-contrib/connector/failoverconnector"
 
 import (
+	"context"
 	"errors"
 	"sync"
 
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/zap"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/failoverconnector/internal/metadata"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/failoverconnector/internal/state"
 )
 
@@ -20,6 +25,8 @@ type failoverRouter[C any] struct {
 	pS               *state.PipelineSelector
 	wg               *sync.WaitGroup
 	consumers        []C
+	logger           *zap.Logger
+	telemetry        *metadata.TelemetryBuilder
 
 	done chan struct{}
 }
@@ -29,24 +36,39 @@ var (
 	errConsumer        = errors.New("Error registering consumer")
 )
 
-func newFailoverRouter[C any](provider consumerProvider[C], cfg *Config) *failoverRouter[C] {
+func newFailoverRouter[C any](provider consumerProvider[C], cfg *Config, set component.TelemetrySettings) (*failoverRouter[C], error) {
+	telemetry, err := metadata.NewTelemetryBuilder(set)
+	if err != nil {
+		return nil, err
+	}
+
 	var wg sync.WaitGroup
 	done := make(chan struct{})
 	pSConstants := state.PSConstants{
-		RetryInterval: cfg.RetryInterval,
-		RetryGap:      cfg.RetryGap,
-		MaxRetries:    cfg.MaxRetries,
+		RetryInterval:       cfg.RetryInterval,
+		RetryGap:            cfg.RetryGap,
+		MaxRetries:          cfg.MaxRetries,
+		MaxRetryInterval:    cfg.RetryBackoff.MaxInterval,
+		RetryMultiplier:     cfg.RetryBackoff.Multiplier,
+		RandomizationFactor: cfg.RetryBackoff.RandomizationFactor,
+		MaxRetriesCooldown:  cfg.MaxRetriesCooldown,
+		ShadowProbe:         cfg.Probe.Mode == probeModeShadow,
+		ShadowRatio:         cfg.Probe.ShadowRatio,
 	}
 
 	selector := state.NewPipelineSelector(len(cfg.PipelinePriority), pSConstants)
-	selector.Start(done, &wg)
-	return &failoverRouter[C]{
+	router := &failoverRouter[C]{
 		consumerProvider: provider,
 		cfg:              cfg,
 		pS:               selector,
 		done:             done,
 		wg:               &wg,
+		logger:           set.Logger,
+		telemetry:        telemetry,
 	}
+	selector.OnTransition(router.reportTransition)
+	selector.Start(done, &wg)
+	return router, nil
 }
 
 func (f *failoverRouter[C]) getCurrentConsumer() (C, chan bool, bool) {
@@ -58,6 +80,41 @@ func (f *failoverRouter[C]) getCurrentConsumer() (C, chan bool, bool) {
 	return f.consumers[pl], ch, true
 }
 
+// getProbeConsumer returns the consumer of the higher priority level being probed, when the data should be shadowed to it
+func (f *failoverRouter[C]) getProbeConsumer() (C, chan bool, bool) {
+	var nilConsumer C
+	pl, ch, ok := f.pS.ProbedPipeline()
+	if !ok || pl >= len(f.consumers) {
+		return nilConsumer, nil, false
+	}
+	return f.consumers[pl], ch, true
+}
+
+// reportTransition logs and counts every change of the stable priority level. Levels are reported starting at 1,
+// a level past the priority list means that no level is left to route data to
+func (f *failoverRouter[C]) reportTransition(from, to int, reason state.TransitionReason) {
+	fromLevel, toLevel := from+1, to+1
+	fields := []zap.Field{
+		zap.Int("from_level", fromLevel),
+		zap.Int("to_level", toLevel),
+		zap.String("reason", string(reason)),
+	}
+	switch {
+	case reason == state.TransitionRecovery:
+		f.logger.Info("Priority level recovered, failing back", fields...)
+	case to >= len(f.cfg.PipelinePriority):
+		f.logger.Error("No priority level left to fail over to", fields...)
+	default:
+		f.logger.Warn("Priority level failed, failing over", fields...)
+	}
+
+	f.telemetry.ConnectorFailoverTransitions.Add(context.Background(), 1, metric.WithAttributes(
+		attribute.Int("from_level", fromLevel),
+		attribute.Int("to_level", toLevel),
+		attribute.String("reason", string(reason)),
+	))
+}
+
 func (f *failoverRouter[C]) registerConsumers() error {
 	consumers := make([]C, 0)
 	for _, pipelines := range f.cfg.PipelinePriority {
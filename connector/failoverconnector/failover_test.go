@@ -14,6 +14,8 @@ import (
 	"go.opentelemetry.io/collector/connector/connectortest"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 func TestFailoverRecovery(t *testing.T) {
@@ -217,6 +219,121 @@ func TestFailoverRecovery_MaxRetries(t *testing.T) {
 
 }
 
+func TestFailoverRecovery_ShadowProbe(t *testing.T) {
+	var sinkFirst, sinkSecond consumertest.TracesSink
+	tracesFirst := component.NewIDWithName(component.DataTypeTraces, "traces/first")
+	tracesSecond := component.NewIDWithName(component.DataTypeTraces, "traces/second")
+
+	cfg := &Config{
+		PipelinePriority: [][]component.ID{{tracesFirst}, {tracesSecond}},
+		RetryInterval:    50 * time.Millisecond,
+		RetryGap:         10 * time.Millisecond,
+		MaxRetries:       10000,
+		Probe: ProbeConfig{
+			Mode:        probeModeShadow,
+			ShadowRatio: 1,
+		},
+	}
+
+	router := connector.NewTracesRouter(map[component.ID]consumer.Traces{
+		tracesFirst:  &sinkFirst,
+		tracesSecond: &sinkSecond,
+	})
+
+	conn, err := NewFactory().CreateTracesToTraces(context.Background(),
+		connectortest.NewNopSettings(), cfg, router.(consumer.Traces))
+
+	require.NoError(t, err)
+
+	failoverConnector := conn.(*tracesFailover)
+
+	tr := sampleTrace()
+
+	defer func() {
+		assert.NoError(t, failoverConnector.Shutdown(context.Background()))
+	}()
+
+	failoverConnector.failover.ModifyConsumerAtIndex(0, consumertest.NewErr(errTracesConsumer))
+
+	require.NoError(t, conn.ConsumeTraces(context.Background(), tr))
+	require.Equal(t, 1, failoverConnector.failover.pS.TestStableIndex())
+
+	// the data is never routed to the failing level, only shadowed to it
+	for i := 0; i < 20; i++ {
+		require.NoError(t, conn.ConsumeTraces(context.Background(), tr))
+		time.Sleep(5 * time.Millisecond)
+	}
+	require.Equal(t, 21, sinkSecond.SpanCount())
+	require.Equal(t, 1, failoverConnector.failover.pS.TestStableIndex())
+
+	failoverConnector.failover.ModifyConsumerAtIndex(0, &sinkFirst)
+
+	require.Eventually(t, func() bool {
+		return consumeTracesAndCheckStable(failoverConnector, 0, tr)
+	}, 3*time.Second, 5*time.Millisecond)
+
+	sinkSecond.Reset()
+	require.NoError(t, conn.ConsumeTraces(context.Background(), tr))
+	require.Equal(t, 0, sinkSecond.SpanCount())
+}
+
+func TestFailoverTransitionsAreLogged(t *testing.T) {
+	var sinkFirst, sinkSecond consumertest.TracesSink
+	tracesFirst := component.NewIDWithName(component.DataTypeTraces, "traces/first")
+	tracesSecond := component.NewIDWithName(component.DataTypeTraces, "traces/second")
+
+	cfg := &Config{
+		PipelinePriority: [][]component.ID{{tracesFirst}, {tracesSecond}},
+		RetryInterval:    50 * time.Millisecond,
+		RetryGap:         10 * time.Millisecond,
+		MaxRetries:       10000,
+	}
+
+	router := connector.NewTracesRouter(map[component.ID]consumer.Traces{
+		tracesFirst:  &sinkFirst,
+		tracesSecond: &sinkSecond,
+	})
+
+	core, logs := observer.New(zap.InfoLevel)
+	set := connectortest.NewNopSettings()
+	set.Logger = zap.New(core)
+
+	conn, err := NewFactory().CreateTracesToTraces(context.Background(), set, cfg, router.(consumer.Traces))
+
+	require.NoError(t, err)
+
+	failoverConnector := conn.(*tracesFailover)
+
+	tr := sampleTrace()
+
+	defer func() {
+		assert.NoError(t, failoverConnector.Shutdown(context.Background()))
+	}()
+
+	failoverConnector.failover.ModifyConsumerAtIndex(0, consumertest.NewErr(errTracesConsumer))
+
+	require.NoError(t, conn.ConsumeTraces(context.Background(), tr))
+	require.Equal(t, 1, failoverConnector.failover.pS.TestStableIndex())
+
+	failoverConnector.failover.ModifyConsumerAtIndex(0, &sinkFirst)
+
+	require.Eventually(t, func() bool {
+		return consumeTracesAndCheckStable(failoverConnector, 0, tr)
+	}, 3*time.Second, 5*time.Millisecond)
+
+	require.Eventually(t, func() bool {
+		return logs.FilterMessage("Priority level recovered, failing back").Len() == 1
+	}, 3*time.Second, 5*time.Millisecond)
+
+	failover := logs.FilterMessage("Priority level failed, failing over").All()
+	require.Len(t, failover, 1)
+	require.Equal(t, map[string]any{
+		"from_level": int64(1),
+		"to_level":   int64(2),
+		"reason":     "failover",
+	}, failover[0].ContextMap())
+}
+
 func resetConsumers(conn *tracesFailover, consumers ...consumer.Traces) {
 	for i, sink := range consumers {
 
@@ -0,0 +1,66 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package state // import "github.com/open-telemetry/opentelemetry-collector-contrib/connector/failoverconnector/internal/state"
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// retryBackoff computes the jittered exponential delay between two retry rounds
+type retryBackoff struct {
+	initial             time.Duration
+	max                 time.Duration
+	multiplier          float64
+	randomizationFactor float64
+
+	current time.Duration
+}
+
+func newRetryBackoff(initial time.Duration, consts PSConstants) *retryBackoff {
+	multiplier := consts.RetryMultiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	maxInterval := consts.MaxRetryInterval
+	if maxInterval > 0 && maxInterval < initial {
+		maxInterval = initial
+	}
+	return &retryBackoff{
+		initial:             initial,
+		max:                 maxInterval,
+		multiplier:          multiplier,
+		randomizationFactor: consts.RandomizationFactor,
+	}
+}
+
+// next returns the delay until the next retry round, and grows the interval for the following one
+func (b *retryBackoff) next() time.Duration {
+	if b.current == 0 {
+		b.current = b.initial
+	}
+	interval := b.current
+
+	grown := time.Duration(float64(b.current) * b.multiplier)
+	if grown < b.current {
+		// overflow
+		grown = b.current
+	}
+	if b.max > 0 && grown > b.max {
+		grown = b.max
+	}
+	b.current = grown
+
+	if b.randomizationFactor <= 0 {
+		return interval
+	}
+	// pick a random interval in [interval - delta, interval + delta]
+	delta := b.randomizationFactor * float64(interval)
+	return time.Duration(float64(interval) - delta + rand.Float64()*(2*delta+1))
+}
+
+// reset starts a new sequence, beginning at the initial interval
+func (b *retryBackoff) reset() {
+	b.current = 0
+}
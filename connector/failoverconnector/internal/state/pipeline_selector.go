@@ -5,6 +5,7 @@ package state // import "github.com/open-telemetry/opentelemetry-collector-contr
 
 import (
 	"context"
+	"math/rand/v2"
 	"reflect"
 	"sync"
 	"sync/atomic"
@@ -15,9 +16,12 @@ import (
 type PipelineSelector struct {
 	currentIndex    atomic.Int32
 	stableIndex     atomic.Int32
+	probeIndex      atomic.Int32
 	pipelineRetries []atomic.Int32
+	exhaustedAt     []atomic.Int64
 	constants       PSConstants
 	RS              *RetryState
+	onTransition    func(from, to int, reason TransitionReason)
 
 	errTryLock    *TryLock
 	stableTryLock *TryLock
@@ -25,6 +29,10 @@ type PipelineSelector struct {
 }
 
 func (p *PipelineSelector) handlePipelineError(idx int) {
+	if p.probeIndex.CompareAndSwap(int32(idx), -1) {
+		p.incrementRetryCount(idx)
+		return
+	}
 	if idx != p.loadCurrent() {
 		return
 	}
@@ -41,17 +49,25 @@ func (p *PipelineSelector) handlePipelineError(idx int) {
 
 func (p *PipelineSelector) enableRetry(ctx context.Context, retryInterval time.Duration, retryGap time.Duration) {
 	go func() {
-		ticker := time.NewTicker(retryInterval)
-		defer ticker.Stop()
+		backoff := newRetryBackoff(retryInterval, p.constants)
+		timer := time.NewTimer(backoff.next())
+		defer timer.Stop()
 
 		var cancelFunc context.CancelFunc
+		stable := p.loadStable()
 		for p.checkContinueRetry(p.loadStable()) {
 			select {
-			case <-ticker.C:
+			case <-timer.C:
 				if cancelFunc != nil {
 					cancelFunc()
 				}
+				// a recovered level starts a new backoff sequence for the remaining higher priority levels
+				if current := p.loadStable(); current != stable {
+					stable = current
+					backoff.reset()
+				}
 				cancelFunc = p.handleRetry(ctx, retryGap)
+				timer.Reset(backoff.next())
 			case <-ctx.Done():
 				return
 			}
@@ -63,7 +79,11 @@ func (p *PipelineSelector) enableRetry(ctx context.Context, retryInterval time.D
 // handleRetry is responsible for launching goroutine and returning cancelFunc
 func (p *PipelineSelector) handleRetry(parentCtx context.Context, retryGap time.Duration) context.CancelFunc {
 	retryCtx, cancelFunc := context.WithCancel(parentCtx)
-	go p.retryHighPriorityPipelines(retryCtx, retryGap)
+	if p.constants.ShadowProbe {
+		go p.probeHighPriorityPipelines(retryCtx, retryGap)
+	} else {
+		go p.retryHighPriorityPipelines(retryCtx, retryGap)
+	}
 	return cancelFunc
 }
 
@@ -78,11 +98,13 @@ func (p *PipelineSelector) updatePipelineIndex(idx int) {
 
 // NextPipeline skips through any lower priority pipelines that have exceeded their maxRetries
 func (p *PipelineSelector) setToNextPriorityPipeline(idx int) {
+	from := idx
 	for ok := true; ok; ok = p.exceededMaxRetries(idx) {
 		idx++
 	}
 	p.stableIndex.Store(int32(idx))
 	p.currentIndex.Store(int32(idx))
+	p.reportTransition(from, idx)
 }
 
 // RetryHighPriorityPipelines responsible for single iteration through all higher priority pipelines
@@ -108,10 +130,41 @@ func (p *PipelineSelector) retryHighPriorityPipelines(ctx context.Context, retry
 	}
 }
 
-// checkContinueRetry checks if retry should be suspended if all higher priority levels have exceeded their max retries
+// probeHighPriorityPipelines is the shadow probing counterpart of retryHighPriorityPipelines, every higher priority
+// pipeline is probed for a retryGap while data keeps being routed to the stable level
+func (p *PipelineSelector) probeHighPriorityPipelines(ctx context.Context, retryGap time.Duration) {
+	ticker := time.NewTicker(retryGap)
+
+	defer ticker.Stop()
+	defer p.probeIndex.Store(-1)
+
+	for i := 0; i < len(p.pipelineRetries); i++ {
+		if p.maxRetriesUsed(i) {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if i >= p.loadStable() {
+				return
+			}
+			p.probeIndex.Store(int32(i))
+		}
+	}
+
+	// the last probed pipeline gets a full retryGap as well
+	select {
+	case <-ctx.Done():
+	case <-ticker.C:
+	}
+}
+
+// checkContinueRetry checks if retry should be suspended if all higher priority levels have exceeded their max retries.
+// Levels that exceeded their max retries are only suspended for good when no cooldown is configured
 func (p *PipelineSelector) checkContinueRetry(index int) bool {
 	for i := 0; i < index; i++ {
-		if p.loadRetryCount(i) < p.constants.MaxRetries {
+		if p.constants.MaxRetriesCooldown > 0 || !p.maxRetriesUsed(i) {
 			return true
 		}
 	}
@@ -119,7 +172,7 @@ func (p *PipelineSelector) checkContinueRetry(index int) bool {
 }
 
 func (p *PipelineSelector) exceededMaxRetries(idx int) bool {
-	return p.constants.MaxRetries > 0 && idx < len(p.pipelineRetries) && (p.loadRetryCount(idx) >= p.constants.MaxRetries)
+	return idx < len(p.pipelineRetries) && p.maxRetriesUsed(idx)
 }
 
 // SetToStableIndex returns the CurrentIndex to the known Stable Index
@@ -128,15 +181,40 @@ func (p *PipelineSelector) setToStableIndex(idx int) {
 	p.currentIndex.Store(p.stableIndex.Load())
 }
 
-// MaxRetriesUsed exported access to maxRetriesUsed
+// MaxRetriesUsed checks if the level exceeded its max retries, the retry count is reset once the cooldown has passed
 func (p *PipelineSelector) maxRetriesUsed(idx int) bool {
-	return p.loadRetryCount(idx) >= p.constants.MaxRetries
+	if p.constants.MaxRetries <= 0 || p.loadRetryCount(idx) < p.constants.MaxRetries {
+		return false
+	}
+	cooldown := p.constants.MaxRetriesCooldown
+	if cooldown > 0 && time.Since(time.Unix(0, p.exhaustedAt[idx].Load())) >= cooldown {
+		p.resetRetryCount(idx)
+		return false
+	}
+	return true
 }
 
 // SetNewStableIndex Update stableIndex to the passed stable index
 func (p *PipelineSelector) setNewStableIndex(idx int) {
+	from := p.loadStable()
 	p.resetRetryCount(idx)
+	// a successful probe switches the data over to the probed level
+	if p.probeIndex.CompareAndSwap(int32(idx), -1) {
+		p.currentIndex.Store(int32(idx))
+	}
 	p.stableIndex.Store(int32(idx))
+	p.reportTransition(from, idx)
+}
+
+func (p *PipelineSelector) reportTransition(from, to int) {
+	if p.onTransition == nil || from == to {
+		return
+	}
+	reason := TransitionFailover
+	if to < from {
+		reason = TransitionRecovery
+	}
+	p.onTransition(from, to, reason)
 }
 
 // IndexIsStable returns if index passed is the stable index
@@ -157,7 +235,9 @@ func (p *PipelineSelector) loadRetryCount(idx int) int {
 }
 
 func (p *PipelineSelector) incrementRetryCount(idx int) {
-	p.pipelineRetries[idx].Add(1)
+	if int(p.pipelineRetries[idx].Add(1)) == p.constants.MaxRetries {
+		p.exhaustedAt[idx].Store(time.Now().UnixNano())
+	}
 }
 
 func (p *PipelineSelector) resetRetryCount(idx int) {
@@ -181,15 +261,22 @@ func NewPipelineSelector(lenPriority int, consts PSConstants) *PipelineSelector
 
 	ps := &PipelineSelector{
 		pipelineRetries: make([]atomic.Int32, lenPriority),
+		exhaustedAt:     make([]atomic.Int64, lenPriority),
 		constants:       consts,
 		RS:              &RetryState{},
 		errTryLock:      NewTryLock(),
 		stableTryLock:   NewTryLock(),
 		chans:           chans,
 	}
+	ps.probeIndex.Store(-1)
 	return ps
 }
 
+// OnTransition registers a function called every time the stable priority level changes, it must be set before Start
+func (p *PipelineSelector) OnTransition(fn func(from, to int, reason TransitionReason)) {
+	p.onTransition = fn
+}
+
 func (p *PipelineSelector) Start(done chan struct{}, wg *sync.WaitGroup) {
 	wg.Add(1)
 	go p.ListenToChannels(done, wg)
@@ -224,6 +311,15 @@ func (p *PipelineSelector) SelectedPipeline() (int, chan bool) {
 	return idx, nil
 }
 
+// ProbedPipeline returns the higher priority level being probed, when the data should be shadowed to it
+func (p *PipelineSelector) ProbedPipeline() (int, chan bool, bool) {
+	idx := int(p.probeIndex.Load())
+	if idx < 0 || idx >= len(p.chans) || rand.Float64() >= p.constants.ShadowRatio {
+		return idx, nil, false
+	}
+	return idx, p.chans[idx], true
+}
+
 // For Testing
 func (p *PipelineSelector) ChannelIndex(ch chan bool) int {
 	for i, ch1 := range p.chans {
@@ -252,6 +348,15 @@ func (p *PipelineSelector) TestRetryPipelines(ctx context.Context, retryInterval
 
 func (p *PipelineSelector) SetRetryCountToMax(idx int) {
 	p.pipelineRetries[idx].Store(int32(p.constants.MaxRetries))
+	p.exhaustedAt[idx].Store(time.Now().UnixNano())
+}
+
+func (p *PipelineSelector) TestProbeIndex() int {
+	return int(p.probeIndex.Load())
+}
+
+func (p *PipelineSelector) TestRetryCount(idx int) int {
+	return p.loadRetryCount(idx)
 }
 
 func (p *PipelineSelector) ResetRetryCount(idx int) {
@@ -84,3 +84,135 @@ func TestCurrentPipelineWithRetry(t *testing.T) {
 		return idx == 0
 	}, 3*time.Second, 5*time.Millisecond)
 }
+
+func TestRetryBackoff(t *testing.T) {
+	constants := PSConstants{
+		MaxRetryInterval: 350 * time.Millisecond,
+		RetryMultiplier:  2,
+	}
+	backoff := newRetryBackoff(50*time.Millisecond, constants)
+
+	require.Equal(t, 50*time.Millisecond, backoff.next())
+	require.Equal(t, 100*time.Millisecond, backoff.next())
+	require.Equal(t, 200*time.Millisecond, backoff.next())
+	require.Equal(t, 350*time.Millisecond, backoff.next())
+	require.Equal(t, 350*time.Millisecond, backoff.next())
+
+	backoff.reset()
+	require.Equal(t, 50*time.Millisecond, backoff.next())
+
+	constants.RandomizationFactor = 0.5
+	backoff = newRetryBackoff(100*time.Millisecond, constants)
+	for i := 0; i < 100; i++ {
+		backoff.reset()
+		interval := backoff.next()
+		require.GreaterOrEqual(t, interval, 50*time.Millisecond)
+		require.LessOrEqual(t, interval, 150*time.Millisecond)
+	}
+
+	// the multiplier defaults to a fixed interval
+	backoff = newRetryBackoff(100*time.Millisecond, PSConstants{})
+	require.Equal(t, 100*time.Millisecond, backoff.next())
+	require.Equal(t, 100*time.Millisecond, backoff.next())
+}
+
+func TestMaxRetriesCooldown(t *testing.T) {
+	constants := PSConstants{
+		RetryInterval:      50 * time.Millisecond,
+		RetryGap:           10 * time.Millisecond,
+		MaxRetries:         2,
+		MaxRetriesCooldown: 100 * time.Millisecond,
+	}
+	pS := NewPipelineSelector(3, constants)
+
+	pS.incrementRetryCount(0)
+	require.False(t, pS.maxRetriesUsed(0))
+	pS.incrementRetryCount(0)
+	require.True(t, pS.maxRetriesUsed(0))
+	require.True(t, pS.checkContinueRetry(1))
+
+	require.Eventually(t, func() bool {
+		return !pS.maxRetriesUsed(0)
+	}, 3*time.Second, 5*time.Millisecond)
+	require.Equal(t, 0, pS.TestRetryCount(0))
+}
+
+func TestMaxRetriesWithoutCooldown(t *testing.T) {
+	constants := PSConstants{
+		RetryInterval: 50 * time.Millisecond,
+		RetryGap:      10 * time.Millisecond,
+		MaxRetries:    1,
+	}
+	pS := NewPipelineSelector(3, constants)
+
+	pS.incrementRetryCount(0)
+	require.True(t, pS.maxRetriesUsed(0))
+	require.False(t, pS.checkContinueRetry(1))
+
+	// max_retries of 0 allows unlimited retries
+	pS = NewPipelineSelector(3, PSConstants{})
+	pS.incrementRetryCount(0)
+	require.False(t, pS.maxRetriesUsed(0))
+	require.True(t, pS.checkContinueRetry(1))
+}
+
+func TestShadowProbe(t *testing.T) {
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	constants := PSConstants{
+		RetryInterval: 50 * time.Millisecond,
+		RetryGap:      10 * time.Millisecond,
+		MaxRetries:    1000,
+		ShadowProbe:   true,
+		ShadowRatio:   1,
+	}
+	pS := NewPipelineSelector(3, constants)
+
+	transitions := make(chan TransitionReason, 2)
+	pS.OnTransition(func(_, _ int, reason TransitionReason) {
+		transitions <- reason
+	})
+	pS.Start(done, &wg)
+	defer func() {
+		pS.RS.InvokeCancel()
+		close(done)
+		wg.Wait()
+	}()
+
+	_, ch := pS.SelectedPipeline()
+	ch <- false
+	require.Eventually(t, func() bool {
+		return pS.TestStableIndex() == 1
+	}, 3*time.Second, 5*time.Millisecond)
+
+	// data keeps flowing to the stable level while the higher priority level is probed
+	var probeCh chan bool
+	require.Eventually(t, func() bool {
+		var idx int
+		var ok bool
+		idx, probeCh, ok = pS.ProbedPipeline()
+		return ok && idx == 0
+	}, 3*time.Second, 5*time.Millisecond)
+	require.Equal(t, 1, pS.TestCurrentIndex())
+
+	probeCh <- false
+	require.Eventually(t, func() bool {
+		return pS.TestRetryCount(0) == 1
+	}, 3*time.Second, 5*time.Millisecond)
+	require.Equal(t, 1, pS.TestStableIndex())
+
+	require.Eventually(t, func() bool {
+		var idx int
+		var ok bool
+		idx, probeCh, ok = pS.ProbedPipeline()
+		return ok && idx == 0
+	}, 3*time.Second, 5*time.Millisecond)
+
+	probeCh <- true
+	require.Eventually(t, func() bool {
+		return pS.TestStableIndex() == 0 && pS.TestCurrentIndex() == 0
+	}, 3*time.Second, 5*time.Millisecond)
+	require.Equal(t, -1, pS.TestProbeIndex())
+	require.Equal(t, TransitionFailover, <-transitions)
+	require.Equal(t, TransitionRecovery, <-transitions)
+}
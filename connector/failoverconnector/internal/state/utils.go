@@ -10,11 +10,27 @@ import (
 )
 
 type PSConstants struct {
-	RetryInterval time.Duration
-	RetryGap      time.Duration
-	MaxRetries    int
+	RetryInterval       time.Duration
+	RetryGap            time.Duration
+	MaxRetries          int
+	MaxRetryInterval    time.Duration
+	RetryMultiplier     float64
+	RandomizationFactor float64
+	MaxRetriesCooldown  time.Duration
+	ShadowProbe         bool
+	ShadowRatio         float64
 }
 
+// TransitionReason describes why the stable priority level changed
+type TransitionReason string
+
+const (
+	// TransitionFailover is reported when the stable level failed and data moved to a lower priority level
+	TransitionFailover TransitionReason = "failover"
+	// TransitionRecovery is reported when a higher priority level recovered and became the stable level
+	TransitionRecovery TransitionReason = "recovery"
+)
+
 type TryLock struct {
 	lock sync.Mutex
 }
@@ -33,9 +33,18 @@ func (f *logsFailover) ConsumeLogs(ctx context.Context, ld plog.Logs) error {
 	if !ok {
 		return errNoValidPipeline
 	}
+	pc, pch, probe := f.failover.getProbeConsumer()
+	var shadow plog.Logs
+	if probe {
+		shadow = plog.NewLogs()
+		ld.CopyTo(shadow)
+	}
 	err := tc.ConsumeLogs(ctx, ld)
 	if err == nil {
 		ch <- true
+		if probe {
+			pch <- pc.ConsumeLogs(ctx, shadow) == nil
+		}
 		return nil
 	}
 	return f.FailoverLogs(ctx, ld)
@@ -70,11 +79,13 @@ func newLogsToLogs(set connector.Settings, cfg component.Config, logs consumer.L
 		return nil, errors.New("consumer is not of type LogsRouter")
 	}
 
-	failover := newFailoverRouter[consumer.Logs](lr.Consumer, config)
-	err := failover.registerConsumers()
+	failover, err := newFailoverRouter[consumer.Logs](lr.Consumer, config, set.TelemetrySettings)
 	if err != nil {
 		return nil, err
 	}
+	if err = failover.registerConsumers(); err != nil {
+		return nil, err
+	}
 	return &logsFailover{
 		config:   config,
 		failover: failover,
@@ -33,9 +33,18 @@ func (f *metricsFailover) ConsumeMetrics(ctx context.Context, md pmetric.Metrics
 	if !ok {
 		return errNoValidPipeline
 	}
+	pc, pch, probe := f.failover.getProbeConsumer()
+	var shadow pmetric.Metrics
+	if probe {
+		shadow = pmetric.NewMetrics()
+		md.CopyTo(shadow)
+	}
 	err := tc.ConsumeMetrics(ctx, md)
 	if err == nil {
 		ch <- true
+		if probe {
+			pch <- pc.ConsumeMetrics(ctx, shadow) == nil
+		}
 		return nil
 	}
 	return f.FailoverMetrics(ctx, md)
@@ -70,11 +79,13 @@ func newMetricsToMetrics(set connector.Settings, cfg component.Config, metrics c
 		return nil, errors.New("consumer is not of type MetricsRouter")
 	}
 
-	failover := newFailoverRouter[consumer.Metrics](mr.Consumer, config)
-	err := failover.registerConsumers()
+	failover, err := newFailoverRouter[consumer.Metrics](mr.Consumer, config, set.TelemetrySettings)
 	if err != nil {
 		return nil, err
 	}
+	if err = failover.registerConsumers(); err != nil {
+		return nil, err
+	}
 	return &metricsFailover{
 		config:   config,
 		failover: failover,
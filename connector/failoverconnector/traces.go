@@ -33,9 +33,18 @@ func (f *tracesFailover) ConsumeTraces(ctx context.Context, td ptrace.Traces) er
 	if !ok {
 		return errNoValidPipeline
 	}
+	pc, pch, probe := f.failover.getProbeConsumer()
+	var shadow ptrace.Traces
+	if probe {
+		shadow = ptrace.NewTraces()
+		td.CopyTo(shadow)
+	}
 	err := tc.ConsumeTraces(ctx, td)
 	if err == nil {
 		ch <- true
+		if probe {
+			pch <- pc.ConsumeTraces(ctx, shadow) == nil
+		}
 		return nil
 	}
 	return f.FailoverTraces(ctx, td)
@@ -70,11 +79,13 @@ func newTracesToTraces(set connector.Settings, cfg component.Config, traces cons
 		return nil, errors.New("consumer is not of type TracesRouter")
 	}
 
-	failover := newFailoverRouter[consumer.Traces](tr.Consumer, config)
-	err := failover.registerConsumers()
+	failover, err := newFailoverRouter[consumer.Traces](tr.Consumer, config, set.TelemetrySettings)
 	if err != nil {
 		return nil, err
 	}
+	if err = failover.registerConsumers(); err != nil {
+		return nil, err
+	}
 
 	return &tracesFailover{
 		config:   config,
@@ -43,6 +43,11 @@ type Config struct {
 	// MatchOnce determines whether the connector matches multiple statements.
 	// Optional.
 	MatchOnce bool `mapstructure:"match_once"`
+
+	// LookupTables maps the name of lookup tables, used by the `Lookup` converter, to the storage extension they are
+	// read from.
+	// Optional.
+	LookupTables map[string]component.ID `mapstructure:"lookup_tables"`
 }
 
 // Validate checks if the processor configuration is valid.
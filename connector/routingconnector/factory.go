@@ -13,9 +13,14 @@ import (
 	"go.opentelemetry.io/collector/consumer"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/routingconnector/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/lookuptable"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/sharedcomponent"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
 )
 
+// lookupTables holds the storage lookup tables of the connectors, shared by their traces, metrics and logs instances
+var lookupTables = sharedcomponent.NewSharedComponents()
+
 // NewFactory returns a ConnectorFactory.
 func NewFactory() connector.Factory {
 	return connector.NewFactory(
@@ -63,3 +68,19 @@ func createLogsToLogs(
 ) (connector.Logs, error) {
 	return newLogsConnector(set, cfg, logs)
 }
+
+// storageLookupTables returns the storage lookup tables of the connector, shared by its traces, metrics and logs
+// instances, and the functions starting and stopping them.
+func storageLookupTables(cfg *Config, id component.ID) (map[string]ottl.LookupTable, component.StartFunc, component.ShutdownFunc) {
+	if len(cfg.LookupTables) == 0 {
+		return nil, nil, nil
+	}
+	tables := lookupTables.GetOrAdd(cfg, func() component.Component {
+		return lookuptable.NewTables(cfg.LookupTables, component.KindConnector, id)
+	})
+	ottlTables := make(map[string]ottl.LookupTable, len(cfg.LookupTables))
+	for name, table := range tables.Unwrap().(*lookuptable.Tables).Tables() {
+		ottlTables[name] = table
+	}
+	return ottlTables, tables.Start, tables.Shutdown
+}
@@ -8,11 +8,15 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/connector"
 	"go.opentelemetry.io/collector/connector/connectortest"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/plog"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storagetest"
 )
 
 func TestConnectorCreatedWithValidConfiguration(t *testing.T) {
@@ -59,3 +63,54 @@ func TestCreationFailsWithIncorrectConsumer(t *testing.T) {
 	assert.ErrorIs(t, err, errUnexpectedConsumer)
 	assert.Nil(t, conn)
 }
+
+func TestConnectorCreatedWithLookupTables(t *testing.T) {
+	ext := storagetest.NewFileBackedStorageExtension("test", t.TempDir())
+	host := storagetest.NewStorageHost().WithExtension(ext.ID, ext)
+	set := connectortest.NewNopSettings()
+
+	// Populate the table before the connector is started
+	client, err := ext.GetClient(context.Background(), component.KindConnector, set.ID, "tenants")
+	require.NoError(t, err)
+	require.NoError(t, client.Set(context.Background(), "acme", []byte("true")))
+	require.NoError(t, client.Close(context.Background()))
+
+	logsDefault := component.NewIDWithName(component.DataTypeLogs, "default")
+	logs0 := component.NewIDWithName(component.DataTypeLogs, "0")
+	cfg := &Config{
+		DefaultPipelines: []component.ID{logsDefault},
+		LookupTables:     map[string]component.ID{"tenants": ext.ID},
+		Table: []RoutingTableItem{{
+			Statement: `route() where Lookup("tenants", attributes["X-Tenant"]) == true`,
+			Pipelines: []component.ID{logs0},
+		}},
+	}
+
+	var defaultSink, sink0 consumertest.LogsSink
+	router := connector.NewLogsRouter(map[component.ID]consumer.Logs{
+		logsDefault: &defaultSink,
+		logs0:       &sink0,
+	})
+
+	factory := NewFactory()
+	conn, err := factory.CreateLogsToLogs(context.Background(), set, cfg, router.(consumer.Logs))
+	require.NoError(t, err)
+	require.NoError(t, conn.Start(context.Background(), host))
+
+	ld := plog.NewLogs()
+	ld.ResourceLogs().AppendEmpty().Resource().Attributes().PutStr("X-Tenant", "acme")
+	ld.ResourceLogs().AppendEmpty().Resource().Attributes().PutStr("X-Tenant", "globex")
+	require.NoError(t, conn.ConsumeLogs(context.Background(), ld))
+	require.Len(t, sink0.AllLogs(), 1)
+	tenant, _ := sink0.AllLogs()[0].ResourceLogs().At(0).Resource().Attributes().Get("X-Tenant")
+	assert.Equal(t, "acme", tenant.Str())
+	require.Len(t, defaultSink.AllLogs(), 1)
+	tenant, _ = defaultSink.AllLogs()[0].ResourceLogs().At(0).Resource().Attributes().Get("X-Tenant")
+	assert.Equal(t, "globex", tenant.Str())
+	require.NoError(t, conn.Shutdown(context.Background()))
+
+	// The tables are only provided to the statements of the connector configuring them
+	cfg.LookupTables = nil
+	_, err = factory.CreateLogsToLogs(context.Background(), set, cfg, router.(consumer.Logs))
+	assert.ErrorContains(t, err, `lookup table "tenants" is not configured`)
+}
@@ -38,20 +38,24 @@ func newLogsConnector(
 		return nil, errUnexpectedConsumer
 	}
 
+	lookupTables, start, shutdown := storageLookupTables(cfg, set.ID)
 	r, err := newRouter(
 		cfg.Table,
 		cfg.DefaultPipelines,
 		lr.Consumer,
-		set.TelemetrySettings)
+		set.TelemetrySettings,
+		lookupTables)
 
 	if err != nil {
 		return nil, err
 	}
 
 	return &logsConnector{
-		logger: set.TelemetrySettings.Logger,
-		config: cfg,
-		router: r,
+		StartFunc:    start,
+		ShutdownFunc: shutdown,
+		logger:       set.TelemetrySettings.Logger,
+		config:       cfg,
+		router:       r,
 	}, nil
 }
 
@@ -38,20 +38,24 @@ func newMetricsConnector(
 		return nil, errUnexpectedConsumer
 	}
 
+	lookupTables, start, shutdown := storageLookupTables(cfg, set.ID)
 	r, err := newRouter(
 		cfg.Table,
 		cfg.DefaultPipelines,
 		mr.Consumer,
-		set.TelemetrySettings)
+		set.TelemetrySettings,
+		lookupTables)
 
 	if err != nil {
 		return nil, err
 	}
 
 	return &metricsConnector{
-		logger: set.TelemetrySettings.Logger,
-		config: cfg,
-		router: r,
+		StartFunc:    start,
+		ShutdownFunc: shutdown,
+		logger:       set.TelemetrySettings.Logger,
+		config:       cfg,
+		router:       r,
 	}, nil
 }
 
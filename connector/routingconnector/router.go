@@ -45,10 +45,12 @@ func newRouter[C any](
 	defaultPipelineIDs []component.ID,
 	provider consumerProvider[C],
 	settings component.TelemetrySettings,
+	lookupTables map[string]ottl.LookupTable,
 ) (*router[C], error) {
 	parser, err := ottlresource.NewParser(
 		common.Functions[ottlresource.TransformContext](),
 		settings,
+		ottlresource.WithLookupTables(lookupTables),
 	)
 
 	if err != nil {
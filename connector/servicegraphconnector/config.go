@@ -5,6 +5,8 @@ package servicegraphconnector // import "github.com/open-telemetry/opentelemetry
 
 import (
 	"time"
+
+	"go.opentelemetry.io/collector/component"
 )
 
 // Config defines the configuration options for servicegraphprocessor.
@@ -50,6 +52,10 @@ type Config struct {
 	// DatabaseNameAttribute is the attribute name used to identify the database name from span attributes.
 	// The default value is db.name.
 	DatabaseNameAttribute string `mapstructure:"database_name_attribute"`
+
+	// EnableMessagingSystemLatencyHistogram enables the histogram of the time spent in the messaging system,
+	// between the end of a producer span and the start of the consumer span it is paired with.
+	EnableMessagingSystemLatencyHistogram bool `mapstructure:"enable_messaging_system_latency_histogram"`
 }
 
 type StoreConfig struct {
@@ -57,4 +63,12 @@ type StoreConfig struct {
 	MaxItems int `mapstructure:"max_items"`
 	// TTL is the time to live for items in the store.
 	TTL time.Duration `mapstructure:"ttl"`
+	// StorageID is the storage extension used to keep the edges that have not been paired yet
+	// across restarts. They are saved every CheckpointInterval and on shutdown, and restored on
+	// start with their original TTL.
+	StorageID *component.ID `mapstructure:"storage"`
+	// CheckpointInterval is the interval at which the edges that have not been paired yet are
+	// saved to the storage extension. Zero disables the periodic checkpoints, so the edges are
+	// only saved on shutdown. Only used if StorageID is set.
+	CheckpointInterval time.Duration `mapstructure:"checkpoint_interval"`
 }
@@ -34,8 +34,9 @@ func TestLoadConfig(t *testing.T) {
 			LatencyHistogramBuckets: []time.Duration{1, 2, 3, 4, 5},
 			Dimensions:              []string{"dimension-1", "dimension-2"},
 			Store: StoreConfig{
-				TTL:      time.Second,
-				MaxItems: 10,
+				TTL:                time.Second,
+				MaxItems:           10,
+				CheckpointInterval: 10 * time.Second,
 			},
 			CacheLoop:             time.Minute,
 			StoreExpirationLoop:   2 * time.Second,
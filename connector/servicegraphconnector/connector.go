@@ -15,6 +15,7 @@ import (
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/ptrace"
@@ -24,6 +25,7 @@ import (
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/servicegraphconnector/internal/metadata"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/servicegraphconnector/internal/store"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storageclient"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/pdatautil"
 )
 
@@ -34,6 +36,9 @@ const (
 	virtualNodeLabel   = "virtual_node"
 	millisecondsUnit   = "ms"
 	secondsUnit        = "s"
+
+	// messagingDestinationNameAttribute is not part of the semantic conventions version used by this package
+	messagingDestinationNameAttribute = "messaging.destination.name"
 )
 
 var (
@@ -59,24 +64,32 @@ type metricSeries struct {
 var _ processor.Traces = (*serviceGraphConnector)(nil)
 
 type serviceGraphConnector struct {
+	id              component.ID
 	config          *Config
 	logger          *zap.Logger
 	metricsConsumer consumer.Metrics
 
 	store *store.Store
+	// storageClient is set if unpaired edges are persisted across restarts
+	storageClient storage.Client
+	// checkpointing tracks the loop saving the unpaired edges periodically
+	checkpointing sync.WaitGroup
 
 	startTime time.Time
 
-	seriesMutex                          sync.Mutex
-	reqTotal                             map[string]int64
-	reqFailedTotal                       map[string]int64
-	reqClientDurationSecondsCount        map[string]uint64
-	reqClientDurationSecondsSum          map[string]float64
-	reqClientDurationSecondsBucketCounts map[string][]uint64
-	reqServerDurationSecondsCount        map[string]uint64
-	reqServerDurationSecondsSum          map[string]float64
-	reqServerDurationSecondsBucketCounts map[string][]uint64
-	reqDurationBounds                    []float64
+	seriesMutex                           sync.Mutex
+	reqTotal                              map[string]int64
+	reqFailedTotal                        map[string]int64
+	reqClientDurationSecondsCount         map[string]uint64
+	reqClientDurationSecondsSum           map[string]float64
+	reqClientDurationSecondsBucketCounts  map[string][]uint64
+	reqServerDurationSecondsCount         map[string]uint64
+	reqServerDurationSecondsSum           map[string]float64
+	reqServerDurationSecondsBucketCounts  map[string][]uint64
+	reqMessagingSystemSecondsCount        map[string]uint64
+	reqMessagingSystemSecondsSum          map[string]float64
+	reqMessagingSystemSecondsBucketCounts map[string][]uint64
+	reqDurationBounds                     []float64
 
 	metricMutex sync.RWMutex
 	keyToMetric map[string]metricSeries
@@ -127,31 +140,53 @@ func newConnector(set component.TelemetrySettings, config component.Config, next
 		logger:          set.Logger,
 		metricsConsumer: next,
 
-		startTime:                            time.Now(),
-		reqTotal:                             make(map[string]int64),
-		reqFailedTotal:                       make(map[string]int64),
-		reqClientDurationSecondsCount:        make(map[string]uint64),
-		reqClientDurationSecondsSum:          make(map[string]float64),
-		reqClientDurationSecondsBucketCounts: make(map[string][]uint64),
-		reqServerDurationSecondsCount:        make(map[string]uint64),
-		reqServerDurationSecondsSum:          make(map[string]float64),
-		reqServerDurationSecondsBucketCounts: make(map[string][]uint64),
-		reqDurationBounds:                    bounds,
-		keyToMetric:                          make(map[string]metricSeries),
-		shutdownCh:                           make(chan any),
-		telemetryBuilder:                     telemetryBuilder,
+		startTime:                             time.Now(),
+		reqTotal:                              make(map[string]int64),
+		reqFailedTotal:                        make(map[string]int64),
+		reqClientDurationSecondsCount:         make(map[string]uint64),
+		reqClientDurationSecondsSum:           make(map[string]float64),
+		reqClientDurationSecondsBucketCounts:  make(map[string][]uint64),
+		reqServerDurationSecondsCount:         make(map[string]uint64),
+		reqServerDurationSecondsSum:           make(map[string]float64),
+		reqServerDurationSecondsBucketCounts:  make(map[string][]uint64),
+		reqMessagingSystemSecondsCount:        make(map[string]uint64),
+		reqMessagingSystemSecondsSum:          make(map[string]float64),
+		reqMessagingSystemSecondsBucketCounts: make(map[string][]uint64),
+		reqDurationBounds:                     bounds,
+		keyToMetric:                           make(map[string]metricSeries),
+		shutdownCh:                            make(chan any),
+		telemetryBuilder:                      telemetryBuilder,
 	}, nil
 }
 
-func (p *serviceGraphConnector) Start(_ context.Context, _ component.Host) error {
+func (p *serviceGraphConnector) Start(ctx context.Context, host component.Host) error {
 	p.store = store.NewStore(p.config.Store.TTL, p.config.Store.MaxItems, p.onComplete, p.onExpire)
 
+	if p.config.Store.StorageID != nil {
+		client, err := storageclient.Get(ctx, host, *p.config.Store.StorageID, component.KindConnector, p.id, "")
+		if err != nil {
+			return err
+		}
+		p.storageClient = client
+
+		restored, err := p.store.Restore(ctx, client)
+		if err != nil {
+			p.logger.Warn("failed to restore unpaired edges", zap.Error(err))
+		}
+		p.logger.Debug("restored unpaired edges", zap.Int("edges", restored))
+	}
+
 	go p.metricFlushLoop(p.config.MetricsFlushInterval)
 
 	go p.cacheLoop(p.config.CacheLoop)
 
 	go p.storeExpirationLoop(p.config.StoreExpirationLoop)
 
+	if p.storageClient != nil && p.config.Store.CheckpointInterval > 0 {
+		p.checkpointing.Add(1)
+		go p.checkpointLoop(p.config.Store.CheckpointInterval)
+	}
+
 	p.logger.Info("Started servicegraphconnector")
 	return nil
 }
@@ -191,10 +226,16 @@ func (p *serviceGraphConnector) flushMetrics(ctx context.Context) error {
 	return p.metricsConsumer.ConsumeMetrics(ctx, md)
 }
 
-func (p *serviceGraphConnector) Shutdown(_ context.Context) error {
+func (p *serviceGraphConnector) Shutdown(ctx context.Context) error {
 	p.logger.Info("Shutting down servicegraphconnector")
 	close(p.shutdownCh)
-	return nil
+
+	if p.storageClient == nil {
+		return nil
+	}
+	// the last checkpoint must not overwrite the final save
+	p.checkpointing.Wait()
+	return errors.Join(p.store.Save(ctx, p.storageClient), p.storageClient.Close(ctx))
 }
 
 func (p *serviceGraphConnector) Capabilities() consumer.Capabilities {
@@ -217,12 +258,7 @@ func (p *serviceGraphConnector) ConsumeTraces(ctx context.Context, td ptrace.Tra
 	return nil
 }
 
-func (p *serviceGraphConnector) aggregateMetrics(ctx context.Context, td ptrace.Traces) (err error) {
-	var (
-		isNew             bool
-		totalDroppedSpans int
-	)
-
+func (p *serviceGraphConnector) aggregateMetrics(ctx context.Context, td ptrace.Traces) error {
 	rss := td.ResourceSpans()
 	for i := 0; i < rss.Len(); i++ {
 		rSpans := rss.At(i)
@@ -251,13 +287,15 @@ func (p *serviceGraphConnector) aggregateMetrics(ctx context.Context, td ptrace.
 				case ptrace.SpanKindClient:
 					traceID := span.TraceID()
 					key := store.NewKey(traceID, span.SpanID())
-					isNew, err = p.store.UpsertEdge(key, func(e *store.Edge) {
+					isNew, err := p.store.UpsertEdge(key, func(e *store.Edge) {
 						e.TraceID = traceID
 						e.ConnectionType = connectionType
 						e.ClientService = serviceName
 						e.ClientLatencySec = spanDuration(span)
+						e.ClientEndTimestamp = span.EndTimestamp()
 						e.Failed = e.Failed || span.Status().Code() == ptrace.StatusCodeError
 						p.upsertDimensions(clientKind, e.Dimensions, rAttributes, span.Attributes())
+						p.upsertMessagingDestination(e, span)
 
 						if virtualNodeFeatureGate.IsEnabled() {
 							p.upsertPeerAttributes(p.config.VirtualNodePeerAttributes, e.Peer, span.Attributes())
@@ -271,46 +309,94 @@ func (p *serviceGraphConnector) aggregateMetrics(ctx context.Context, td ptrace.
 							e.ServerLatencySec = spanDuration(span)
 						}
 					})
+					if err = p.recordUpsert(ctx, isNew, err); err != nil {
+						return err
+					}
 				case ptrace.SpanKindConsumer:
 					// override connection type and continue processing as span kind server
 					connectionType = store.MessagingSystem
 					fallthrough
 				case ptrace.SpanKindServer:
-					traceID := span.TraceID()
-					key := store.NewKey(traceID, span.ParentSpanID())
-					isNew, err = p.store.UpsertEdge(key, func(e *store.Edge) {
-						e.TraceID = traceID
-						e.ConnectionType = connectionType
-						e.ServerService = serviceName
-						e.ServerLatencySec = spanDuration(span)
-						e.Failed = e.Failed || span.Status().Code() == ptrace.StatusCodeError
-						p.upsertDimensions(serverKind, e.Dimensions, rAttributes, span.Attributes())
-					})
+					update := func(traceID pcommon.TraceID) store.Callback {
+						return func(e *store.Edge) {
+							e.TraceID = traceID
+							e.ConnectionType = connectionType
+							e.ServerService = serviceName
+							e.ServerLatencySec = spanDuration(span)
+							e.ServerStartTimestamp = span.StartTimestamp()
+							e.Failed = e.Failed || span.Status().Code() == ptrace.StatusCodeError
+							p.upsertDimensions(serverKind, e.Dimensions, rAttributes, span.Attributes())
+							p.upsertMessagingDestination(e, span)
+						}
+					}
+
+					// Consumer spans are often not children of the producer span, e.g. when processing
+					// messages in batches. If so, they are paired with the producer spans they link to,
+					// as well as with their parent, if any, which may still be a producer span.
+					linked := false
+					if links := span.Links(); connectionType == store.MessagingSystem {
+						for l := 0; l < links.Len(); l++ {
+							link := links.At(l)
+							if link.TraceID() == span.TraceID() && link.SpanID() == span.ParentSpanID() {
+								// paired below
+								continue
+							}
+							linked = true
+							key := store.NewKey(link.TraceID(), link.SpanID())
+							isNew, err := p.store.UpsertEdge(key, update(link.TraceID()))
+							if err = p.recordUpsert(ctx, isNew, err); err != nil {
+								return err
+							}
+						}
+					}
+					if linked && span.ParentSpanID().IsEmpty() {
+						continue
+					}
+
+					key := store.NewKey(span.TraceID(), span.ParentSpanID())
+					isNew, err := p.store.UpsertEdge(key, update(span.TraceID()))
+					if err = p.recordUpsert(ctx, isNew, err); err != nil {
+						return err
+					}
 				default:
 					// this span is not part of an edge
 					continue
 				}
+			}
+		}
+	}
+	return nil
+}
 
-				if errors.Is(err, store.ErrTooManyItems) {
-					totalDroppedSpans++
-					p.telemetryBuilder.ConnectorServicegraphDroppedSpans.Add(ctx, 1)
-					continue
-				}
+// recordUpsert records the outcome of upserting an edge. Edges that don't fit into the
+// store are counted as dropped spans, any other error is returned.
+func (p *serviceGraphConnector) recordUpsert(ctx context.Context, isNew bool, err error) error {
+	if errors.Is(err, store.ErrTooManyItems) {
+		p.telemetryBuilder.ConnectorServicegraphDroppedSpans.Add(ctx, 1)
+		return nil
+	}
 
-				// UpsertEdge will only return ErrTooManyItems
-				if err != nil {
-					return err
-				}
+	// UpsertEdge will only return ErrTooManyItems
+	if err != nil {
+		return err
+	}
 
-				if isNew {
-					p.telemetryBuilder.ConnectorServicegraphTotalEdges.Add(ctx, 1)
-				}
-			}
-		}
+	if isNew {
+		p.telemetryBuilder.ConnectorServicegraphTotalEdges.Add(ctx, 1)
 	}
 	return nil
 }
 
+// upsertMessagingDestination sets the destination of messaging system edges, if the span has one.
+func (p *serviceGraphConnector) upsertMessagingDestination(e *store.Edge, span ptrace.Span) {
+	if e.ConnectionType != store.MessagingSystem {
+		return
+	}
+	if dest, ok := pdatautil.GetAttributeValue(messagingDestinationNameAttribute, span.Attributes()); ok {
+		e.MessagingDestination = dest
+	}
+}
+
 func (p *serviceGraphConnector) upsertDimensions(kind string, m map[string]string, resourceAttr pcommon.Map, spanAttr pcommon.Map) {
 	for _, dim := range p.config.Dimensions {
 		if v, ok := pdatautil.GetAttributeValue(dim, resourceAttr, spanAttr); ok {
@@ -351,9 +437,18 @@ func (p *serviceGraphConnector) onExpire(e *store.Edge) {
 	p.telemetryBuilder.ConnectorServicegraphExpiredEdges.Add(context.Background(), 1)
 
 	if virtualNodeFeatureGate.IsEnabled() && len(p.config.VirtualNodePeerAttributes) > 0 {
+		// unpaired producer and consumer spans are connected to their messaging destination instead
+		var destination string
+		if e.ConnectionType == store.MessagingSystem {
+			destination = e.MessagingDestination
+		}
+
 		e.ConnectionType = store.VirtualNode
-		if len(e.ClientService) == 0 && e.Key.SpanIDIsEmpty() {
+		if len(e.ClientService) == 0 && (e.Key.SpanIDIsEmpty() || destination != "") {
 			e.ClientService = "user"
+			if destination != "" {
+				e.ClientService = destination
+			}
 			if p.config.VirtualNodeExtraLabel {
 				e.VirtualNodeLabel = store.ClientVirtualNode
 			}
@@ -362,6 +457,9 @@ func (p *serviceGraphConnector) onExpire(e *store.Edge) {
 
 		if len(e.ServerService) == 0 {
 			e.ServerService = p.getPeerHost(p.config.VirtualNodePeerAttributes, e.Peer)
+			if destination != "" {
+				e.ServerService = destination
+			}
 			if p.config.VirtualNodeExtraLabel {
 				e.VirtualNodeLabel = store.ServerVirtualNode
 			}
@@ -386,6 +484,9 @@ func (p *serviceGraphConnector) aggregateMetricsForEdge(e *store.Edge) {
 		p.updateErrorMetrics(metricKey)
 	}
 	p.updateDurationMetrics(metricKey, e.ServerLatencySec, e.ClientLatencySec)
+	if p.config.EnableMessagingSystemLatencyHistogram && e.ConnectionType == store.MessagingSystem {
+		p.updateMessagingSystemLatencyMetrics(metricKey, messagingSystemLatency(e))
+	}
 }
 
 func (p *serviceGraphConnector) updateSeries(key string, dimensions pcommon.Map) {
@@ -437,6 +538,26 @@ func (p *serviceGraphConnector) updateClientDurationMetrics(key string, duration
 	p.reqClientDurationSecondsBucketCounts[key][index]++
 }
 
+func (p *serviceGraphConnector) updateMessagingSystemLatencyMetrics(key string, latency float64) {
+	index := sort.SearchFloat64s(p.reqDurationBounds, latency) // Search bucket index
+	if _, ok := p.reqMessagingSystemSecondsBucketCounts[key]; !ok {
+		p.reqMessagingSystemSecondsBucketCounts[key] = make([]uint64, len(p.reqDurationBounds)+1)
+	}
+	p.reqMessagingSystemSecondsSum[key] += latency
+	p.reqMessagingSystemSecondsCount[key]++
+	p.reqMessagingSystemSecondsBucketCounts[key][index]++
+}
+
+// messagingSystemLatency returns the time between the end of the producer span and the start of the
+// consumer span in seconds (legacy ms). Clock skew between both services may cause the consumer span to
+// start first, which is recorded as no latency.
+func messagingSystemLatency(e *store.Edge) float64 {
+	if e.ClientEndTimestamp == 0 || e.ServerStartTimestamp <= e.ClientEndTimestamp {
+		return 0
+	}
+	return durationToFloat(time.Duration(e.ServerStartTimestamp - e.ClientEndTimestamp))
+}
+
 func buildDimensions(e *store.Edge) pcommon.Map {
 	dims := pcommon.NewMap()
 	dims.PutStr("client", e.ClientService)
@@ -532,7 +653,43 @@ func (p *serviceGraphConnector) collectLatencyMetrics(ilm pmetric.ScopeMetrics)
 		return err
 	}
 
-	return p.collectClientLatencyMetrics(ilm)
+	if err := p.collectClientLatencyMetrics(ilm); err != nil {
+		return err
+	}
+
+	return p.collectMessagingSystemLatencyMetrics(ilm)
+}
+
+func (p *serviceGraphConnector) collectMessagingSystemLatencyMetrics(ilm pmetric.ScopeMetrics) error {
+	if len(p.reqMessagingSystemSecondsCount) > 0 {
+		mDuration := ilm.Metrics().AppendEmpty()
+		mDuration.SetName("traces_service_graph_request_messaging_system")
+		mDuration.SetUnit(secondsUnit)
+		if legacyLatencyUnitMsFeatureGate.IsEnabled() {
+			mDuration.SetUnit(millisecondsUnit)
+		}
+		// TODO: Support other aggregation temporalities
+		mDuration.SetEmptyHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+		timestamp := pcommon.NewTimestampFromTime(time.Now())
+
+		for key := range p.reqMessagingSystemSecondsCount {
+			dpDuration := mDuration.Histogram().DataPoints().AppendEmpty()
+			dpDuration.SetStartTimestamp(pcommon.NewTimestampFromTime(p.startTime))
+			dpDuration.SetTimestamp(timestamp)
+			dpDuration.ExplicitBounds().FromRaw(p.reqDurationBounds)
+			dpDuration.BucketCounts().FromRaw(p.reqMessagingSystemSecondsBucketCounts[key])
+			dpDuration.SetCount(p.reqMessagingSystemSecondsCount[key])
+			dpDuration.SetSum(p.reqMessagingSystemSecondsSum[key])
+
+			dimensions, ok := p.dimensionsForSeries(key)
+			if !ok {
+				return fmt.Errorf("failed to find dimensions for key %s", key)
+			}
+
+			dimensions.CopyTo(dpDuration.Attributes())
+		}
+	}
+	return nil
 }
 
 func (p *serviceGraphConnector) collectClientLatencyMetrics(ilm pmetric.ScopeMetrics) error {
@@ -617,6 +774,25 @@ func (p *serviceGraphConnector) buildMetricKey(clientName, serverName, connectio
 	return metricKey.String()
 }
 
+// checkpointLoop periodically saves the edges that have not been paired yet, so
+// that they survive a crash and not only a graceful shutdown.
+func (p *serviceGraphConnector) checkpointLoop(d time.Duration) {
+	defer p.checkpointing.Done()
+
+	t := time.NewTicker(d)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			if err := p.store.Save(context.Background(), p.storageClient); err != nil {
+				p.logger.Warn("failed to checkpoint unpaired edges", zap.Error(err))
+			}
+		case <-p.shutdownCh:
+			return
+		}
+	}
+}
+
 // storeExpirationLoop periodically expires old entries from the store.
 func (p *serviceGraphConnector) storeExpirationLoop(d time.Duration) {
 	t := time.NewTicker(d)
@@ -676,6 +852,9 @@ func (p *serviceGraphConnector) cleanCache() {
 		delete(p.reqServerDurationSecondsCount, key)
 		delete(p.reqServerDurationSecondsSum, key)
 		delete(p.reqServerDurationSecondsBucketCounts, key)
+		delete(p.reqMessagingSystemSecondsCount, key)
+		delete(p.reqMessagingSystemSecondsSum, key)
+		delete(p.reqMessagingSystemSecondsBucketCounts, key)
 	}
 	p.seriesMutex.Unlock()
 
@@ -27,6 +27,8 @@ import (
 	"go.opentelemetry.io/otel/sdk/metric/metricdata"
 	"go.uber.org/zap/zaptest"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/servicegraphconnector/internal/store"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storagetest"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/golden"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/pdatatest/pmetrictest"
 )
@@ -739,3 +741,243 @@ func TestVirtualNodeClientLabels(t *testing.T) {
 	)
 	require.NoError(t, err)
 }
+
+func TestUnpairedEdgesPersistedAcrossRestart(t *testing.T) {
+	ext := storagetest.NewFileBackedStorageExtension("test", t.TempDir())
+	host := storagetest.NewStorageHost().WithExtension(ext.ID, ext)
+	cfg := &Config{
+		Store: StoreConfig{
+			MaxItems:  10,
+			TTL:       time.Hour,
+			StorageID: &ext.ID,
+		},
+	}
+	set := componenttest.NewNopTelemetrySettings()
+	set.Logger = zaptest.NewLogger(t)
+
+	td := buildSampleTrace(t, "val")
+	clientOnly, serverOnly := ptrace.NewTraces(), ptrace.NewTraces()
+	td.CopyTo(clientOnly)
+	td.CopyTo(serverOnly)
+	clientOnly.ResourceSpans().At(0).ScopeSpans().At(0).Spans().RemoveIf(func(span ptrace.Span) bool {
+		return span.Kind() != ptrace.SpanKindClient
+	})
+	serverOnly.ResourceSpans().At(0).ScopeSpans().At(0).Spans().RemoveIf(func(span ptrace.Span) bool {
+		return span.Kind() != ptrace.SpanKindServer
+	})
+
+	conn, err := newConnector(set, cfg, newMockMetricsExporter())
+	require.NoError(t, err)
+	require.NoError(t, conn.Start(context.Background(), host))
+	require.NoError(t, conn.ConsumeTraces(context.Background(), clientOnly))
+	assert.Equal(t, 1, conn.store.Len())
+	require.NoError(t, conn.Shutdown(context.Background()))
+
+	// the server span arrives after a restart and is paired with the restored client span
+	conn, err = newConnector(set, cfg, newMockMetricsExporter())
+	require.NoError(t, err)
+	require.NoError(t, conn.Start(context.Background(), host))
+	defer func() {
+		require.NoError(t, conn.Shutdown(context.Background()))
+	}()
+	assert.Equal(t, 1, conn.store.Len())
+
+	require.NoError(t, conn.ConsumeTraces(context.Background(), serverOnly))
+	assert.Equal(t, 0, conn.store.Len())
+	assert.Len(t, conn.reqTotal, 1)
+}
+
+func TestUnpairedEdgesCheckpointedPeriodically(t *testing.T) {
+	ext := storagetest.NewInMemoryStorageExtension("test")
+	host := storagetest.NewStorageHost().WithExtension(ext.ID, ext)
+	cfg := &Config{
+		Store: StoreConfig{
+			MaxItems:           10,
+			TTL:                time.Hour,
+			StorageID:          &ext.ID,
+			CheckpointInterval: 10 * time.Millisecond,
+		},
+	}
+
+	td := buildSampleTrace(t, "val")
+	td.ResourceSpans().At(0).ScopeSpans().At(0).Spans().RemoveIf(func(span ptrace.Span) bool {
+		return span.Kind() != ptrace.SpanKindClient
+	})
+
+	conn, err := newConnector(componenttest.NewNopTelemetrySettings(), cfg, newMockMetricsExporter())
+	require.NoError(t, err)
+	require.NoError(t, conn.Start(context.Background(), host))
+	defer func() {
+		require.NoError(t, conn.Shutdown(context.Background()))
+	}()
+	require.NoError(t, conn.ConsumeTraces(context.Background(), td))
+
+	// the edge is saved without waiting for the shutdown
+	assert.Eventually(t, func() bool {
+		restarted := store.NewStore(time.Hour, 10, func(*store.Edge) {}, func(*store.Edge) {})
+		n, err := restarted.Restore(context.Background(), conn.storageClient)
+		return err == nil && n == 1
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestStorageExtensionNotFound(t *testing.T) {
+	storageID := storagetest.NewStorageID("test")
+	cfg := &Config{Store: StoreConfig{MaxItems: 10, TTL: time.Second, StorageID: &storageID}}
+
+	conn, err := newConnector(componenttest.NewNopTelemetrySettings(), cfg, newMockMetricsExporter())
+	require.NoError(t, err)
+	require.ErrorContains(t, conn.Start(context.Background(), storagetest.NewStorageHost()), "storage extension 'test_storage/test' not found")
+}
+
+func buildMessagingTraces(producerSpanID, consumerLinkSpanID pcommon.SpanID) ptrace.Traces {
+	pEnd := time.Date(2022, 1, 2, 3, 4, 6, 0, time.UTC)
+	// consumer starts 500ms after the message was produced
+	cStart := time.Date(2022, 1, 2, 3, 4, 6, 500_000_000, time.UTC)
+
+	traces := ptrace.NewTraces()
+
+	producer := traces.ResourceSpans().AppendEmpty()
+	producer.Resource().Attributes().PutStr(semconv.AttributeServiceName, "producer-service")
+	producerSpan := producer.ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	producerSpan.SetName("orders publish")
+	producerSpan.SetTraceID(pcommon.TraceID([16]byte{1, 2, 3}))
+	producerSpan.SetSpanID(producerSpanID)
+	producerSpan.SetKind(ptrace.SpanKindProducer)
+	producerSpan.SetStartTimestamp(pcommon.NewTimestampFromTime(pEnd.Add(-time.Second)))
+	producerSpan.SetEndTimestamp(pcommon.NewTimestampFromTime(pEnd))
+	producerSpan.Attributes().PutStr(messagingDestinationNameAttribute, "orders")
+
+	// the consumer processes the message in its own trace, linking to the producer
+	consumer := traces.ResourceSpans().AppendEmpty()
+	consumer.Resource().Attributes().PutStr(semconv.AttributeServiceName, "consumer-service")
+	consumerSpan := consumer.ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	consumerSpan.SetName("orders process")
+	consumerSpan.SetTraceID(pcommon.TraceID([16]byte{4, 5, 6}))
+	consumerSpan.SetSpanID(pcommon.SpanID([8]byte{4, 5, 6}))
+	consumerSpan.SetKind(ptrace.SpanKindConsumer)
+	consumerSpan.SetStartTimestamp(pcommon.NewTimestampFromTime(cStart))
+	consumerSpan.SetEndTimestamp(pcommon.NewTimestampFromTime(cStart.Add(time.Second)))
+	consumerSpan.Attributes().PutStr(messagingDestinationNameAttribute, "orders")
+	link := consumerSpan.Links().AppendEmpty()
+	link.SetTraceID(pcommon.TraceID([16]byte{1, 2, 3}))
+	link.SetSpanID(consumerLinkSpanID)
+
+	return traces
+}
+
+func TestMessagingSystemLinks(t *testing.T) {
+	cfg := &Config{
+		Store:                                 StoreConfig{MaxItems: 10, TTL: time.Hour},
+		EnableMessagingSystemLatencyHistogram: true,
+	}
+
+	set := componenttest.NewNopTelemetrySettings()
+	set.Logger = zaptest.NewLogger(t)
+	conn, err := newConnector(set, cfg, newMockMetricsExporter())
+	require.NoError(t, err)
+	require.NoError(t, conn.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		require.NoError(t, conn.Shutdown(context.Background()))
+	}()
+
+	producerSpanID := pcommon.SpanID([8]byte{1, 2, 3})
+	require.NoError(t, conn.ConsumeTraces(context.Background(), buildMessagingTraces(producerSpanID, producerSpanID)))
+	assert.Equal(t, 0, conn.store.Len())
+
+	metrics := conn.metricsConsumer.(*mockMetricsExporter).GetMetrics()
+	require.Len(t, metrics, 1)
+
+	var found bool
+	ms := metrics[0].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	for i := 0; i < ms.Len(); i++ {
+		m := ms.At(i)
+		if m.Name() != "traces_service_graph_request_messaging_system" {
+			continue
+		}
+		found = true
+		assert.Equal(t, secondsUnit, m.Unit())
+		require.Equal(t, 1, m.Histogram().DataPoints().Len())
+		dp := m.Histogram().DataPoints().At(0)
+		assert.Equal(t, uint64(1), dp.Count())
+		assert.InDelta(t, 0.5, dp.Sum(), 0.0001)
+		verifyAttr(t, dp.Attributes(), "client", "producer-service")
+		verifyAttr(t, dp.Attributes(), "server", "consumer-service")
+		verifyAttr(t, dp.Attributes(), "connection_type", string(store.MessagingSystem))
+	}
+	assert.True(t, found, "messaging system latency histogram not found")
+}
+
+func TestMessagingSystemLinksAndParent(t *testing.T) {
+	cfg := &Config{
+		Store: StoreConfig{MaxItems: 10, TTL: time.Hour},
+	}
+
+	set := componenttest.NewNopTelemetrySettings()
+	set.Logger = zaptest.NewLogger(t)
+	conn, err := newConnector(set, cfg, newMockMetricsExporter())
+	require.NoError(t, err)
+	require.NoError(t, conn.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		require.NoError(t, conn.Shutdown(context.Background()))
+	}()
+
+	producerSpanID := pcommon.SpanID([8]byte{1, 2, 3})
+	td := buildMessagingTraces(producerSpanID, producerSpanID)
+
+	// the consumer is also the child of a producer span in its own trace
+	parent := td.ResourceSpans().At(0).ScopeSpans().At(0).Spans().AppendEmpty()
+	td.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).CopyTo(parent)
+	parent.SetTraceID(pcommon.TraceID([16]byte{4, 5, 6}))
+	parent.SetSpanID(pcommon.SpanID([8]byte{7, 8, 9}))
+	td.ResourceSpans().At(1).ScopeSpans().At(0).Spans().At(0).SetParentSpanID(parent.SpanID())
+
+	require.NoError(t, conn.ConsumeTraces(context.Background(), td))
+	assert.Equal(t, 0, conn.store.Len())
+
+	require.Len(t, conn.reqTotal, 1)
+	for key, total := range conn.reqTotal {
+		assert.Equal(t, int64(2), total)
+		dims, ok := conn.dimensionsForSeries(key)
+		require.True(t, ok)
+		verifyAttr(t, dims, "client", "producer-service")
+		verifyAttr(t, dims, "server", "consumer-service")
+	}
+}
+
+func TestMessagingSystemUnpairedDestination(t *testing.T) {
+	cfg := &Config{
+		Store:                     StoreConfig{MaxItems: 10, TTL: time.Nanosecond},
+		VirtualNodePeerAttributes: []string{semconv.AttributePeerService},
+	}
+
+	set := componenttest.NewNopTelemetrySettings()
+	set.Logger = zaptest.NewLogger(t)
+	conn, err := newConnector(set, cfg, newMockMetricsExporter())
+	require.NoError(t, err)
+	require.NoError(t, conn.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		require.NoError(t, conn.Shutdown(context.Background()))
+	}()
+
+	// the consumer links to a span other than the producer span, so neither is paired
+	td := buildMessagingTraces(pcommon.SpanID([8]byte{1, 2, 3}), pcommon.SpanID([8]byte{7, 8, 9}))
+	require.NoError(t, conn.ConsumeTraces(context.Background(), td))
+	assert.Equal(t, 2, conn.store.Len())
+
+	time.Sleep(time.Millisecond)
+	conn.store.Expire()
+	assert.Equal(t, 0, conn.store.Len())
+
+	edges := map[string]string{}
+	for key := range conn.reqTotal {
+		dims, ok := conn.dimensionsForSeries(key)
+		require.True(t, ok)
+		client, _ := dims.Get("client")
+		server, _ := dims.Get("server")
+		edges[client.Str()] = server.Str()
+	}
+	assert.Equal(t, map[string]string{
+		"producer-service": "orders",
+		"orders":           "consumer-service",
+	}, edges)
+}
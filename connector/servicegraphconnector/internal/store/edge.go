@@ -50,6 +50,14 @@ type Edge struct {
 
 	// VirtualNodeLabel is an optional label to be added to the spans
 	VirtualNodeLabel VirtualNodeLabel
+
+	// MessagingDestination is the destination name of the producer or consumer span
+	// of a messaging system edge
+	MessagingDestination string
+
+	// ClientEndTimestamp and ServerStartTimestamp are the end of the client span and the start
+	// of the server span. For messaging system edges, the time in between is spent in the queue.
+	ClientEndTimestamp, ServerStartTimestamp pcommon.Timestamp
 }
 
 func newEdge(key Key, ttl time.Duration) *Edge {
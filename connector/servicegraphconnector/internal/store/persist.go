@@ -0,0 +1,154 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package store // import "github.com/open-telemetry/opentelemetry-collector-contrib/connector/servicegraphconnector/internal/store"
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+const edgesKey = "edges"
+
+// Storage is the key-value storage the edges that have not been paired yet are
+// persisted to. It is satisfied by the clients of the storage extensions.
+type Storage interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, value []byte) error
+	Delete(ctx context.Context, key string) error
+}
+
+// persistedEdge is the serialized form of an Edge that has not been paired yet.
+type persistedEdge struct {
+	KeyTraceID pcommon.TraceID `json:"key_trace_id"`
+	KeySpanID  pcommon.SpanID  `json:"key_span_id"`
+
+	TraceID          pcommon.TraceID   `json:"trace_id"`
+	ConnectionType   ConnectionType    `json:"connection_type"`
+	ServerService    string            `json:"server_service"`
+	ClientService    string            `json:"client_service"`
+	ServerLatencySec float64           `json:"server_latency_sec"`
+	ClientLatencySec float64           `json:"client_latency_sec"`
+	Failed           bool              `json:"failed"`
+	Dimensions       map[string]string `json:"dimensions"`
+	Peer             map[string]string `json:"peer"`
+	VirtualNodeLabel VirtualNodeLabel  `json:"virtual_node_label"`
+	Expiration       time.Time         `json:"expiration"`
+
+	MessagingDestination string            `json:"messaging_destination"`
+	ClientEndTimestamp   pcommon.Timestamp `json:"client_end_timestamp"`
+	ServerStartTimestamp pcommon.Timestamp `json:"server_start_timestamp"`
+}
+
+func toPersisted(e *Edge) persistedEdge {
+	return persistedEdge{
+		KeyTraceID:       e.Key.tid,
+		KeySpanID:        e.Key.sid,
+		TraceID:          e.TraceID,
+		ConnectionType:   e.ConnectionType,
+		ServerService:    e.ServerService,
+		ClientService:    e.ClientService,
+		ServerLatencySec: e.ServerLatencySec,
+		ClientLatencySec: e.ClientLatencySec,
+		Failed:           e.Failed,
+		Dimensions:       e.Dimensions,
+		Peer:             e.Peer,
+		VirtualNodeLabel: e.VirtualNodeLabel,
+		Expiration:       e.expiration,
+
+		MessagingDestination: e.MessagingDestination,
+		ClientEndTimestamp:   e.ClientEndTimestamp,
+		ServerStartTimestamp: e.ServerStartTimestamp,
+	}
+}
+
+func (pe persistedEdge) edge() *Edge {
+	e := &Edge{
+		Key:              NewKey(pe.KeyTraceID, pe.KeySpanID),
+		TraceID:          pe.TraceID,
+		ConnectionType:   pe.ConnectionType,
+		ServerService:    pe.ServerService,
+		ClientService:    pe.ClientService,
+		ServerLatencySec: pe.ServerLatencySec,
+		ClientLatencySec: pe.ClientLatencySec,
+		Failed:           pe.Failed,
+		Dimensions:       pe.Dimensions,
+		Peer:             pe.Peer,
+		VirtualNodeLabel: pe.VirtualNodeLabel,
+		expiration:       pe.Expiration,
+
+		MessagingDestination: pe.MessagingDestination,
+		ClientEndTimestamp:   pe.ClientEndTimestamp,
+		ServerStartTimestamp: pe.ServerStartTimestamp,
+	}
+	if e.Dimensions == nil {
+		e.Dimensions = make(map[string]string)
+	}
+	if e.Peer == nil {
+		e.Peer = make(map[string]string)
+	}
+	return e
+}
+
+// Save persists the edges that have not been paired yet to the storage, so that
+// they can still be paired after a restart using Restore. Each call replaces the
+// edges saved by the previous one.
+func (s *Store) Save(ctx context.Context, client Storage) error {
+	s.mtx.Lock()
+	edges := make([]persistedEdge, 0, s.l.Len())
+	for ele := s.l.Front(); ele != nil; ele = ele.Next() {
+		edges = append(edges, toPersisted(ele.Value.(*Edge)))
+	}
+	s.mtx.Unlock()
+
+	buf, err := json.Marshal(edges)
+	if err != nil {
+		return err
+	}
+	return client.Set(ctx, edgesKey, buf)
+}
+
+// Restore loads the edges persisted by Save and returns how many were added to
+// the store. Edges keep their original expiration, so the ones that expired in
+// the meantime are evicted on the next call to Expire.
+//
+// Restored edges are removed from the storage, so they are not restored twice.
+func (s *Store) Restore(ctx context.Context, client Storage) (int, error) {
+	buf, err := client.Get(ctx, edgesKey)
+	if err != nil || buf == nil {
+		return 0, err
+	}
+
+	var edges []persistedEdge
+	if err = json.Unmarshal(buf, &edges); err != nil {
+		return 0, err
+	}
+	// tryEvictHead relies on the list being ordered by expiration
+	sort.SliceStable(edges, func(i, j int) bool {
+		return edges[i].Expiration.Before(edges[j].Expiration)
+	})
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	var n int
+	for _, pe := range edges {
+		e := pe.edge()
+		if _, ok := s.m[e.Key]; ok {
+			continue
+		}
+		if s.l.Len() >= s.maxItems {
+			err = ErrTooManyItems
+			break
+		}
+		s.m[e.Key] = s.l.PushBack(e)
+		n++
+	}
+
+	return n, errors.Join(err, client.Delete(ctx, edgesKey))
+}
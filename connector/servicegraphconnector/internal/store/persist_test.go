@@ -0,0 +1,104 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storagetest"
+)
+
+func TestStoreSaveRestore(t *testing.T) {
+	ctx := context.Background()
+	client := storagetest.NewInMemoryClient(component.KindConnector, component.MustNewID("servicegraph"), "")
+	key := NewKey(pcommon.TraceID([16]byte{1, 2, 3}), pcommon.SpanID([8]byte{1, 2, 3}))
+
+	s := NewStore(time.Hour, 10, noopCallback, noopCallback)
+	_, err := s.UpsertEdge(key, func(e *Edge) {
+		e.TraceID = pcommon.TraceID([16]byte{1, 2, 3})
+		e.ClientService = clientService
+		e.ClientLatencySec = 1.5
+		e.Dimensions["region"] = "eu"
+	})
+	require.NoError(t, err)
+	require.NoError(t, s.Save(ctx, client))
+
+	// the server half arrives after a restart
+	var completed *Edge
+	restarted := NewStore(time.Hour, 10, func(e *Edge) { completed = e }, noopCallback)
+	n, err := restarted.Restore(ctx, client)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, 1, restarted.Len())
+
+	_, err = restarted.UpsertEdge(key, func(e *Edge) {
+		e.ServerService = "server"
+	})
+	require.NoError(t, err)
+	require.NotNil(t, completed)
+	assert.Equal(t, clientService, completed.ClientService)
+	assert.Equal(t, "server", completed.ServerService)
+	assert.Equal(t, 1.5, completed.ClientLatencySec)
+	assert.Equal(t, map[string]string{"region": "eu"}, completed.Dimensions)
+	assert.Equal(t, 0, restarted.Len())
+
+	// restored edges are removed from the storage
+	n, err = NewStore(time.Hour, 10, noopCallback, noopCallback).Restore(ctx, client)
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+}
+
+func TestStoreRestoreExpired(t *testing.T) {
+	ctx := context.Background()
+	client := storagetest.NewInMemoryClient(component.KindConnector, component.MustNewID("servicegraph"), "")
+
+	s := NewStore(time.Hour, 10, noopCallback, noopCallback)
+	for i := 0; i < 3; i++ {
+		_, err := s.UpsertEdge(NewKey(pcommon.TraceID([16]byte{byte(i)}), pcommon.SpanID([8]byte{1})), func(e *Edge) {
+			e.ClientService = clientService
+			if i != 1 {
+				e.expiration = time.UnixMicro(0)
+			}
+		})
+		require.NoError(t, err)
+	}
+	require.NoError(t, s.Save(ctx, client))
+
+	var onExpireCount int
+	restarted := NewStore(time.Hour, 10, noopCallback, countingCallback(&onExpireCount))
+	n, err := restarted.Restore(ctx, client)
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+
+	restarted.Expire()
+	assert.Equal(t, 2, onExpireCount)
+	assert.Equal(t, 1, restarted.Len())
+}
+
+func TestStoreRestoreTooManyItems(t *testing.T) {
+	ctx := context.Background()
+	client := storagetest.NewInMemoryClient(component.KindConnector, component.MustNewID("servicegraph"), "")
+
+	s := NewStore(time.Hour, 10, noopCallback, noopCallback)
+	for i := 0; i < 3; i++ {
+		_, err := s.UpsertEdge(NewKey(pcommon.TraceID([16]byte{byte(i)}), pcommon.SpanID([8]byte{1})), func(e *Edge) {
+			e.ClientService = clientService
+		})
+		require.NoError(t, err)
+	}
+	require.NoError(t, s.Save(ctx, client))
+
+	restarted := NewStore(time.Hour, 2, noopCallback, noopCallback)
+	n, err := restarted.Restore(ctx, client)
+	require.ErrorIs(t, err, ErrTooManyItems)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, 2, restarted.Len())
+}
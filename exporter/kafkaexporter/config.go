@@ -46,6 +46,9 @@ type Config struct {
 	// Encoding of messages (default "otlp_proto")
 	Encoding string `mapstructure:"encoding"`
 
+	// LogsEncodingExtension marshals the logs with an encoding extension instead of Encoding.
+	LogsEncodingExtension *EncodingExtension `mapstructure:"logs_encoding_extension"`
+
 	// PartitionTracesByID sets the message key of outgoing trace messages to the trace ID.
 	// Please note: does not have any effect on Jaeger encoding exporters since Jaeger exporters include
 	// trace ID as the message key by default.
@@ -66,6 +69,16 @@ type Config struct {
 	Authentication kafka.Authentication `mapstructure:"auth"`
 }
 
+// EncodingExtension defines configuration for marshaling messages with an encoding extension.
+type EncodingExtension struct {
+	// ID of the encoding extension, which must implement a marshaler of the exported signal.
+	ID component.ID `mapstructure:"id"`
+
+	// MessagePerRecord marshals each record to its own message, for encodings which only support
+	// a single record per payload. Otherwise, all the records of a request are marshaled to a single message.
+	MessagePerRecord bool `mapstructure:"message_per_record"`
+}
+
 // Metadata defines configuration for retrieving metadata from the broker.
 type Metadata struct {
 	// Whether to maintain a full set of metadata for all topics, or just
@@ -149,7 +149,18 @@ func (e *kafkaLogsProducer) Close(context.Context) error {
 	return e.producer.Close()
 }
 
-func (e *kafkaLogsProducer) start(_ context.Context, _ component.Host) error {
+func (e *kafkaLogsProducer) start(_ context.Context, host component.Host) error {
+	if ext := e.cfg.LogsEncodingExtension; ext != nil {
+		marshaler, err := loadEncodingExtension[plog.Marshaler](host, ext.ID)
+		if err != nil {
+			return err
+		}
+		e.marshaler = &logsEncodingMarshaler{
+			marshaler:        *marshaler,
+			encoding:         ext.ID.String(),
+			messagePerRecord: ext.MessagePerRecord,
+		}
+	}
 	producer, err := newSaramaProducer(e.cfg)
 	if err != nil {
 		return err
@@ -250,6 +261,19 @@ func newLogsExporter(config Config, set exporter.Settings) (*kafkaLogsProducer,
 
 }
 
+// loadEncodingExtension loads the encoding extension with the given ID.
+func loadEncodingExtension[T any](host component.Host, id component.ID) (*T, error) {
+	encodingExtension, ok := host.GetExtensions()[id]
+	if !ok {
+		return nil, fmt.Errorf("unknown encoding extension %q", id)
+	}
+	marshaler, ok := encodingExtension.(T)
+	if !ok {
+		return nil, fmt.Errorf("extension %q is not a marshaler", id)
+	}
+	return &marshaler, nil
+}
+
 type resourceSlice[T any] interface {
 	Len() int
 	At(int) T
@@ -6,12 +6,14 @@ package kafkaexporter
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/IBM/sarama"
 	"github.com/IBM/sarama/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config/configtls"
 	"go.opentelemetry.io/collector/exporter/exportertest"
@@ -83,6 +85,30 @@ func TestNewLogsExporter_err_traces_encoding(t *testing.T) {
 	assert.Nil(t, mexp)
 }
 
+func TestNewLogsExporter_encoding_extension(t *testing.T) {
+	c := Config{
+		ProtocolVersion:       "0.0.0",
+		Encoding:              defaultEncoding,
+		LogsEncodingExtension: &EncodingExtension{ID: component.MustNewID("logs_encoding"), MessagePerRecord: true},
+	}
+	lexp, err := newLogsExporter(c, exportertest.NewNopSettings())
+	require.NoError(t, err)
+	err = lexp.start(context.Background(), &testComponentHost{})
+	// the marshaler is resolved before the producer fails to be created
+	assert.Error(t, err)
+	assert.Equal(t, &logsEncodingMarshaler{marshaler: &nopComponent{}, encoding: "logs_encoding", messagePerRecord: true}, lexp.marshaler)
+}
+
+func TestNewLogsExporter_encoding_extension_errors(t *testing.T) {
+	for _, id := range []component.ID{component.MustNewID("logs_nomarshaler"), component.MustNewID("logs_missing")} {
+		c := Config{Encoding: defaultEncoding, LogsEncodingExtension: &EncodingExtension{ID: id}}
+		lexp, err := newLogsExporter(c, exportertest.NewNopSettings())
+		require.NoError(t, err)
+		err = lexp.start(context.Background(), &testComponentHost{})
+		assert.ErrorContains(t, err, id.String())
+	}
+}
+
 func TestNewExporter_err_auth_type(t *testing.T) {
 	c := Config{
 		ProtocolVersion: "2.0.0",
@@ -281,6 +307,45 @@ func TestLogsDataPusher(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestLogsDataPusher_encoding_extension(t *testing.T) {
+	tests := []struct {
+		name             string
+		messagePerRecord bool
+		messages         []string
+	}{
+		{name: "message per request", messages: []string{"0,1"}},
+		{name: "message per record", messagePerRecord: true, messages: []string{"0", "1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := sarama.NewConfig()
+			producer := mocks.NewSyncProducer(t, c)
+			for _, body := range tt.messages {
+				producer.ExpectSendMessageWithCheckerFunctionAndSucceed(func(val []byte) error {
+					if string(val) != body {
+						return fmt.Errorf("unexpected message %q, expected %q", val, body)
+					}
+					return nil
+				})
+			}
+
+			p := kafkaLogsProducer{
+				producer:  producer,
+				marshaler: &logsEncodingMarshaler{marshaler: &nopComponent{}, encoding: "logs_encoding", messagePerRecord: tt.messagePerRecord},
+			}
+			t.Cleanup(func() {
+				require.NoError(t, p.Close(context.Background()))
+			})
+			logs := testdata.GenerateLogs(2)
+			records := logs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords()
+			records.At(0).Body().SetStr("0")
+			records.At(1).Body().SetStr("1")
+			err := p.logsDataPusher(context.Background(), logs)
+			require.NoError(t, err)
+		})
+	}
+}
+
 func TestLogsDataPusher_attr(t *testing.T) {
 	c := sarama.NewConfig()
 	producer := mocks.NewSyncProducer(t, c)
@@ -437,3 +502,42 @@ func Test_GetTopic(t *testing.T) {
 		})
 	}
 }
+
+type testComponentHost struct{}
+
+func (h *testComponentHost) GetExtensions() map[component.ID]component.Component {
+	return map[component.ID]component.Component{
+		component.MustNewID("logs_encoding"):    &nopComponent{},
+		component.MustNewID("logs_nomarshaler"): &nopNoMarshalerComponent{},
+	}
+}
+
+// nopComponent marshals the bodies of the log records as a comma separated string.
+type nopComponent struct{}
+
+func (c *nopComponent) Start(_ context.Context, _ component.Host) error {
+	return nil
+}
+
+func (c *nopComponent) Shutdown(_ context.Context) error {
+	return nil
+}
+
+func (c *nopComponent) MarshalLogs(ld plog.Logs) ([]byte, error) {
+	var bodies []string
+	records := ld.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords()
+	for i := 0; i < records.Len(); i++ {
+		bodies = append(bodies, records.At(i).Body().AsString())
+	}
+	return []byte(strings.Join(bodies, ",")), nil
+}
+
+type nopNoMarshalerComponent struct{}
+
+func (c *nopNoMarshalerComponent) Start(_ context.Context, _ component.Host) error {
+	return nil
+}
+
+func (c *nopNoMarshalerComponent) Shutdown(_ context.Context) error {
+	return nil
+}
@@ -97,3 +97,53 @@ func createLogMarshaler(config Config) (LogsMarshaler, error) {
 		return nil, errUnrecognizedEncoding
 	}
 }
+
+// logsEncodingMarshaler produces messages with the marshaler of an encoding extension, either a message
+// holding all the logs or, if messagePerRecord is set, a message per log record.
+type logsEncodingMarshaler struct {
+	marshaler        plog.Marshaler
+	encoding         string
+	messagePerRecord bool
+}
+
+func (m *logsEncodingMarshaler) Marshal(logs plog.Logs, topic string) ([]*sarama.ProducerMessage, error) {
+	if !m.messagePerRecord {
+		b, err := m.marshaler.MarshalLogs(logs)
+		if err != nil {
+			return nil, err
+		}
+		return []*sarama.ProducerMessage{{Topic: topic, Value: sarama.ByteEncoder(b)}}, nil
+	}
+
+	var messages []*sarama.ProducerMessage
+	for i := 0; i < logs.ResourceLogs().Len(); i++ {
+		rl := logs.ResourceLogs().At(i)
+		for j := 0; j < rl.ScopeLogs().Len(); j++ {
+			sl := rl.ScopeLogs().At(j)
+			for k := 0; k < sl.LogRecords().Len(); k++ {
+				single := plog.NewLogs()
+				srl := single.ResourceLogs().AppendEmpty()
+				rl.Resource().CopyTo(srl.Resource())
+				srl.SetSchemaUrl(rl.SchemaUrl())
+				ssl := srl.ScopeLogs().AppendEmpty()
+				sl.Scope().CopyTo(ssl.Scope())
+				ssl.SetSchemaUrl(sl.SchemaUrl())
+				sl.LogRecords().At(k).CopyTo(ssl.LogRecords().AppendEmpty())
+
+				b, err := m.marshaler.MarshalLogs(single)
+				if err != nil {
+					return nil, err
+				}
+				messages = append(messages, &sarama.ProducerMessage{
+					Topic: topic,
+					Value: sarama.ByteEncoder(b),
+				})
+			}
+		}
+	}
+	return messages, nil
+}
+
+func (m *logsEncodingMarshaler) Encoding() string {
+	return m.encoding
+}
@@ -18,6 +18,8 @@ const (
 	metricNameRouting
 	resourceRouting
 	streamIDRouting
+	attrRouting
+	exprRouting
 )
 
 const (
@@ -26,6 +28,8 @@ const (
 	metricNameRoutingStr = "metric"
 	resourceRoutingStr   = "resource"
 	streamIDRoutingStr   = "streamID"
+	attrRoutingStr       = "attributes"
+	exprRoutingStr       = "expression"
 )
 
 // Config defines configuration for the exporter.
@@ -33,11 +37,52 @@ type Config struct {
 	Protocol   Protocol         `mapstructure:"protocol"`
 	Resolver   ResolverSettings `mapstructure:"resolver"`
 	RoutingKey string           `mapstructure:"routing_key"`
+
+	// RoutingAttributes is the list of attributes used to build the routing key, when routing_key is "attributes".
+	RoutingAttributes []string `mapstructure:"routing_attributes"`
+
+	// RoutingExpression is the OTTL value expression used to build the routing key, when routing_key is "expression".
+	RoutingExpression string `mapstructure:"routing_expression"`
+
+	// LoadFactor enables consistent hashing with bounded loads: no endpoint receives more than
+	// load_factor times its share of the routing decisions. Zero disables it, otherwise it must be at least 1.
+	LoadFactor float64 `mapstructure:"load_factor"`
+
+	// Weights holds the relative weight of endpoints in the ring. Endpoints not listed have a weight of 1.
+	Weights map[string]int `mapstructure:"weights"`
+
+	// Ejection configures the temporary removal of failing endpoints from the ring.
+	Ejection EjectionSettings `mapstructure:"ejection"`
 }
 
-// Protocol holds the individual protocol-specific settings. Only OTLP is supported at the moment.
+// Protocol holds the individual protocol-specific settings. OTLP is used unless another exporter is configured.
 type Protocol struct {
 	OTLP otlpexporter.Config `mapstructure:"otlp"`
+
+	// Exporter configures an exporter of any type to be used for each backend instead of the OTLP exporter.
+	Exporter *ExporterSettings `mapstructure:"exporter"`
+}
+
+// ExporterSettings defines the exporter used for each backend.
+type ExporterSettings struct {
+	// Type is the type of the exporter, such as "otelarrow" or "kafka". The exporter has to be part of the collector distribution.
+	Type string `mapstructure:"type"`
+
+	// EndpointKey is the configuration key of the exporter set to the backend endpoint. Defaults to "endpoint".
+	EndpointKey string `mapstructure:"endpoint_key"`
+
+	// Config is the configuration of the exporter, excluding the endpoint.
+	Config map[string]any `mapstructure:"config"`
+}
+
+// EjectionSettings defines when endpoints are ejected from the ring.
+type EjectionSettings struct {
+	// ConsecutiveFailures is the number of consecutive failed exports after which an endpoint is ejected.
+	// Zero disables ejection.
+	ConsecutiveFailures int `mapstructure:"consecutive_failures"`
+
+	// Duration is how long an ejected endpoint stays out of the ring.
+	Duration time.Duration `mapstructure:"duration"`
 }
 
 // ResolverSettings defines the configurations for the backend resolver
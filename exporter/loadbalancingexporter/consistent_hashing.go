@@ -4,7 +4,9 @@
 package loadbalancingexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter"
 
 import (
+	"encoding/binary"
 	"hash/crc32"
+	"math"
 	"sort"
 )
 
@@ -29,24 +31,65 @@ type hashRing struct {
 
 // newHashRing builds a new immutable consistent hash ring based on the given endpoints.
 func newHashRing(endpoints []string) *hashRing {
-	items := positionsForEndpoints(endpoints, defaultWeight)
+	return newWeightedHashRing(endpoints, nil)
+}
+
+// newWeightedHashRing builds a new immutable consistent hash ring based on the given endpoints, where each endpoint
+// gets a number of positions proportional to its weight.
+func newWeightedHashRing(endpoints []string, weights map[string]int) *hashRing {
+	items := positionsForWeightedEndpoints(endpoints, defaultWeight, weights)
 	return &hashRing{
 		items: items,
 	}
 }
 
+// endpointWeight returns the weight of the given endpoint, defaulting to 1 for endpoints without a weight
+func endpointWeight(weights map[string]int, endpoint string) int {
+	if w, ok := weights[endpointWithPort(endpoint)]; ok && w > 0 {
+		return w
+	}
+	return 1
+}
+
 // endpointFor calculates which backend is responsible for the given traceID
 func (h *hashRing) endpointFor(identifier []byte) string {
 	if h == nil {
 		// perhaps the ring itself couldn't get initialized yet?
 		return ""
 	}
+	return h.findEndpoint(positionFor(identifier))
+}
+
+// walk calls fn for each distinct endpoint, in the order they appear in the ring starting from the position of the
+// given identifier, until fn returns false. The first endpoint is the same as the one returned by endpointFor.
+func (h *hashRing) walk(identifier []byte, fn func(endpoint string) bool) {
+	if h == nil || len(h.items) == 0 {
+		return
+	}
+	pos := positionFor(identifier)
+	start := sort.Search(len(h.items), func(i int) bool {
+		return h.items[i].pos >= pos
+	})
+
+	seen := map[string]bool{}
+	for i := 0; i < len(h.items); i++ {
+		endpoint := h.items[(start+i)%len(h.items)].endpoint
+		if seen[endpoint] {
+			continue
+		}
+		seen[endpoint] = true
+		if !fn(endpoint) {
+			return
+		}
+	}
+}
+
+// positionFor calculates the position of the given identifier in the ring
+func positionFor(identifier []byte) position {
 	hasher := crc32.NewIEEE()
 	hasher.Write(identifier)
 	hash := hasher.Sum32()
-	pos := hash % maxPositions
-
-	return h.findEndpoint(position(pos))
+	return position(hash % maxPositions)
 }
 
 // findEndpoint returns the "next" endpoint starting from the given position, or an empty string in case no endpoints are available
@@ -105,6 +148,10 @@ func positionsFor(endpoint string, numPoints int) []position {
 		h := crc32.NewIEEE()
 		h.Write([]byte(endpoint))
 		h.Write([]byte{byte(i)})
+		if i > math.MaxUint8 {
+			// weighted endpoints have more positions than a single byte can tell apart
+			h.Write(binary.BigEndian.AppendUint32(nil, uint32(i)))
+		}
 		hash := h.Sum32()
 		pos := hash % maxPositions
 		res = append(res, position(pos))
@@ -115,11 +162,16 @@ func positionsFor(endpoint string, numPoints int) []position {
 
 // positionsForEndpoints calculates all the positions for all the given endpoints
 func positionsForEndpoints(endpoints []string, weight int) []ringItem {
+	return positionsForWeightedEndpoints(endpoints, weight, nil)
+}
+
+// positionsForWeightedEndpoints calculates all the positions for all the given endpoints, multiplying the number of
+// positions for each endpoint by its weight
+func positionsForWeightedEndpoints(endpoints []string, weight int, weights map[string]int) []ringItem {
 	var items []ringItem
 	positions := map[position]bool{} // tracking the used positions
 	for _, endpoint := range endpoints {
-		// for this initial implementation, we don't allow endpoints to have custom weights
-		for _, pos := range positionsFor(endpoint, weight) {
+		for _, pos := range positionsFor(endpoint, weight*endpointWeight(weights, endpoint)) {
 			// if this position is occupied already, skip this item
 			if _, found := positions[pos]; found {
 				continue
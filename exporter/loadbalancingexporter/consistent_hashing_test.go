@@ -47,6 +47,51 @@ func TestEndpointFor(t *testing.T) {
 	}
 }
 
+func TestNewWeightedHashRing(t *testing.T) {
+	// prepare
+	endpoints := []string{"endpoint-1", "endpoint-2"}
+	weights := map[string]int{"endpoint-1:4317": 3}
+
+	// test
+	ring := newWeightedHashRing(endpoints, weights)
+
+	// verify
+	counts := map[string]int{}
+	for _, item := range ring.items {
+		counts[item.endpoint]++
+	}
+	// a few positions might be lost to collisions
+	assert.InDelta(t, 3*defaultWeight, counts["endpoint-1"], 10)
+	assert.InDelta(t, defaultWeight, counts["endpoint-2"], 10)
+}
+
+func TestWalk(t *testing.T) {
+	// prepare
+	endpoints := []string{"endpoint-1", "endpoint-2", "endpoint-3"}
+	ring := newHashRing(endpoints)
+
+	for _, id := range [][]byte{{1, 2, 0, 0}, {128, 128, 0, 0}, []byte("ad-service-7")} {
+		// test
+		var walked []string
+		ring.walk(id, func(endpoint string) bool {
+			walked = append(walked, endpoint)
+			return true
+		})
+
+		// verify
+		assert.ElementsMatch(t, endpoints, walked)
+		assert.Equal(t, ring.endpointFor(id), walked[0])
+	}
+
+	// the walk stops when asked to
+	calls := 0
+	ring.walk([]byte{1, 2, 0, 0}, func(string) bool {
+		calls++
+		return false
+	})
+	assert.Equal(t, 1, calls)
+}
+
 func TestPositionsFor(t *testing.T) {
 	// prepare
 	endpoint := "host1"
@@ -56,6 +101,14 @@ func TestPositionsFor(t *testing.T) {
 
 	// verify
 	assert.Len(t, positions, 10)
+
+	// test
+	positions = positionsFor(endpoint, 1000)
+
+	// verify
+	// positions beyond the 256th don't repeat the first ones
+	assert.Len(t, positions, 1000)
+	assert.NotEqual(t, positions[0], positions[256])
 }
 
 func TestBinarySearch(t *testing.T) {
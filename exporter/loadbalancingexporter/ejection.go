@@ -0,0 +1,88 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package loadbalancingexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter"
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/zap"
+)
+
+// ejectionEnabled returns whether failing endpoints are ejected. The sending queue of the backend exporters is then
+// disabled, as queued exports always succeed and their failures would never be seen by the load balancer.
+func ejectionEnabled(cfg *Config) bool {
+	return cfg.Ejection.ConsecutiveFailures > 0
+}
+
+// onExportResult tracks the consecutive failures of the exporter's endpoint, ejecting the endpoint from the ring
+// once the failures reach the configured threshold.
+func (lb *loadBalancer) onExportResult(ctx context.Context, exp *wrappedExporter, err error) {
+	if lb.ejection.ConsecutiveFailures <= 0 {
+		return
+	}
+
+	lb.healthLock.Lock()
+	if err == nil {
+		delete(lb.failures, exp.endpoint)
+		lb.healthLock.Unlock()
+		return
+	}
+	lb.failures[exp.endpoint]++
+	if _, ejected := lb.ejected[exp.endpoint]; ejected || lb.failures[exp.endpoint] < lb.ejection.ConsecutiveFailures {
+		lb.healthLock.Unlock()
+		return
+	}
+	delete(lb.failures, exp.endpoint)
+	endpoint := exp.endpoint
+	lb.ejected[endpoint] = time.AfterFunc(lb.ejection.Duration, func() {
+		lb.readmit(endpoint)
+	})
+	lb.healthLock.Unlock()
+
+	lb.logger.Warn("ejecting endpoint from the ring after consecutive failures",
+		zap.String("endpoint", endpoint),
+		zap.Int("failures", lb.ejection.ConsecutiveFailures),
+		zap.Duration("duration", lb.ejection.Duration))
+	lb.telemetry.LoadbalancerNumEjections.Add(ctx, 1, metric.WithAttributeSet(exp.endpointAttr))
+
+	lb.updateLock.Lock()
+	defer lb.updateLock.Unlock()
+	lb.updateRing()
+}
+
+// readmit adds an ejected endpoint back to the ring, provided it is still part of the resolved endpoints.
+func (lb *loadBalancer) readmit(endpoint string) {
+	lb.healthLock.Lock()
+	delete(lb.ejected, endpoint)
+	lb.healthLock.Unlock()
+
+	lb.updateLock.Lock()
+	defer lb.updateLock.Unlock()
+	if lb.updateRing() {
+		lb.logger.Info("endpoint readmitted to the ring", zap.String("endpoint", endpoint))
+	}
+}
+
+// availableEndpoints returns the resolved endpoints that are not ejected. The resolved endpoints are returned when all
+// of them are ejected, as sending data to failing backends is better than not sending it at all.
+func (lb *loadBalancer) availableEndpoints() []string {
+	lb.healthLock.Lock()
+	defer lb.healthLock.Unlock()
+
+	if len(lb.ejected) == 0 {
+		return lb.resolved
+	}
+	var available []string
+	for _, endpoint := range lb.resolved {
+		if _, ejected := lb.ejected[endpointWithPort(endpoint)]; !ejected {
+			available = append(available, endpoint)
+		}
+	}
+	if len(available) == 0 {
+		return lb.resolved
+	}
+	return available
+}
@@ -0,0 +1,124 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package loadbalancingexporter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+)
+
+func newEjectionTestLoadBalancer(t *testing.T, ejection EjectionSettings) *loadBalancer {
+	ts, tb := getTelemetryAssets(t)
+	cfg := &Config{
+		Resolver: ResolverSettings{
+			Static: &StaticResolver{Hostnames: []string{"endpoint-1", "endpoint-2"}},
+		},
+		Ejection: ejection,
+	}
+	componentFactory := func(_ context.Context, _ string) (component.Component, error) {
+		return newNopMockExporter(), nil
+	}
+	lb, err := newLoadBalancer(ts.Logger, cfg, componentFactory, tb)
+	require.NotNil(t, lb)
+	require.NoError(t, err)
+
+	require.NoError(t, lb.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		require.NoError(t, lb.Shutdown(context.Background()))
+	})
+	return lb
+}
+
+func TestEjection(t *testing.T) {
+	// prepare
+	lb := newEjectionTestLoadBalancer(t, EjectionSettings{ConsecutiveFailures: 2, Duration: 100 * time.Millisecond})
+	// this service name will reach the endpoint-2 -- see the consistent hashing tests for more info
+	id := []byte("get-recommendations-1")
+	exp, endpoint, err := lb.exporterAndEndpoint(id)
+	require.NoError(t, err)
+	require.Equal(t, "endpoint-2", endpoint)
+
+	// test
+	// a success in between failures resets the count
+	lb.onExportResult(context.Background(), exp, errors.New("some expected err"))
+	lb.onExportResult(context.Background(), exp, nil)
+	lb.onExportResult(context.Background(), exp, errors.New("some expected err"))
+
+	// verify
+	_, endpoint, err = lb.exporterAndEndpoint(id)
+	require.NoError(t, err)
+	assert.Equal(t, "endpoint-2", endpoint)
+
+	// test
+	lb.onExportResult(context.Background(), exp, errors.New("some expected err"))
+
+	// verify
+	_, endpoint, err = lb.exporterAndEndpoint(id)
+	require.NoError(t, err)
+	assert.Equal(t, "endpoint-1", endpoint)
+	// the exporter is kept, as the endpoint is still resolved
+	assert.Contains(t, lb.exporters, "endpoint-2:4317")
+
+	// the endpoint is readmitted once the ejection duration is over
+	assert.Eventually(t, func() bool {
+		_, endpoint, err = lb.exporterAndEndpoint(id)
+		return err == nil && endpoint == "endpoint-2"
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestEjectionOfAllEndpoints(t *testing.T) {
+	// prepare
+	lb := newEjectionTestLoadBalancer(t, EjectionSettings{ConsecutiveFailures: 1, Duration: time.Minute})
+
+	// test
+	for _, exp := range lb.exporters {
+		lb.onExportResult(context.Background(), exp, errors.New("some expected err"))
+	}
+
+	// verify
+	// all endpoints are used when all of them are ejected
+	assert.Len(t, lb.ejected, 2)
+	assert.Len(t, lb.ring.items, 2*defaultWeight)
+}
+
+func TestEjectionDisabled(t *testing.T) {
+	// prepare
+	lb := newEjectionTestLoadBalancer(t, EjectionSettings{})
+
+	// test
+	for _, exp := range lb.exporters {
+		for i := 0; i < 10; i++ {
+			lb.onExportResult(context.Background(), exp, errors.New("some expected err"))
+		}
+	}
+
+	// verify
+	assert.Empty(t, lb.ejected)
+	assert.Len(t, lb.ring.items, 2*defaultWeight)
+}
+
+func TestNewLoadBalancerInvalidEjection(t *testing.T) {
+	ts, tb := getTelemetryAssets(t)
+	for _, ejection := range []EjectionSettings{
+		{ConsecutiveFailures: -1},
+		{ConsecutiveFailures: 3},
+	} {
+		cfg := simpleConfig()
+		cfg.Ejection = ejection
+
+		// test
+		p, err := newLoadBalancer(ts.Logger, cfg, nil, tb)
+
+		// verify
+		assert.Nil(t, p)
+		assert.Equal(t, errInvalidEjection, err)
+	}
+}
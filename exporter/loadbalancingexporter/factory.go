@@ -35,6 +35,9 @@ func createDefaultConfig() component.Config {
 		Protocol: Protocol{
 			OTLP: *otlpDefaultCfg,
 		},
+		Ejection: EjectionSettings{
+			Duration: defaultEjectionDuration,
+		},
 	}
 }
 
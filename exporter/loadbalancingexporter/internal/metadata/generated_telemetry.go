@@ -33,6 +33,7 @@ type TelemetryBuilder struct {
 	LoadbalancerBackendOutcome    metric.Int64Counter
 	LoadbalancerNumBackendUpdates metric.Int64Counter
 	LoadbalancerNumBackends       metric.Int64Gauge
+	LoadbalancerNumEjections      metric.Int64Counter
 	LoadbalancerNumResolutions    metric.Int64Counter
 	meters                        map[configtelemetry.Level]metric.Meter
 }
@@ -73,6 +74,12 @@ func NewTelemetryBuilder(settings component.TelemetrySettings, options ...teleme
 		metric.WithUnit("{backends}"),
 	)
 	errs = errors.Join(errs, err)
+	builder.LoadbalancerNumEjections, err = builder.meters[configtelemetry.LevelBasic].Int64Counter(
+		"otelcol_loadbalancer_num_ejections",
+		metric.WithDescription("Number of times an endpoint was ejected from the ring after consecutive failures."),
+		metric.WithUnit("{ejections}"),
+	)
+	errs = errors.Join(errs, err)
 	builder.LoadbalancerNumResolutions, err = builder.meters[configtelemetry.LevelBasic].Int64Counter(
 		"otelcol_loadbalancer_num_resolutions",
 		metric.WithDescription("Number of times the resolver has triggered new resolutions."),
@@ -7,9 +7,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
+	"slices"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/hashicorp/golang-lru/v2/simplelru"
 	"go.opentelemetry.io/collector/component"
 	"go.uber.org/zap"
 
@@ -18,21 +22,53 @@ import (
 
 const (
 	defaultPort = "4317"
+
+	defaultEjectionDuration = 30 * time.Second
+
+	// loadDecayThreshold is the total load after which the tracked loads are halved, so that bounded loads
+	// reflect the recent traffic rather than the whole lifetime of the ring
+	loadDecayThreshold = 1 << 16
+
+	// maxPinnedKeys is the number of routing keys whose endpoint is remembered when bounded loads are enabled, so
+	// that the telemetry sharing a routing key keeps going to the same endpoint when loads change
+	maxPinnedKeys = 1 << 16
 )
 
 var (
 	errNoResolver                = errors.New("no resolvers specified for the exporter")
 	errMultipleResolversProvided = errors.New("only one resolver should be specified")
+	errInvalidLoadFactor         = errors.New("load_factor must be either 0 or at least 1")
+	errInvalidEjection           = errors.New("ejection::consecutive_failures must not be negative, and ejection::duration must be positive when ejection is enabled")
 )
 
 type componentFactory func(ctx context.Context, endpoint string) (component.Component, error)
 
 type loadBalancer struct {
-	logger *zap.Logger
-	host   component.Host
-
-	res  resolver
-	ring *hashRing
+	logger    *zap.Logger
+	host      component.Host
+	telemetry *metadata.TelemetryBuilder
+
+	res      resolver
+	resolved []string
+	ring     *hashRing
+	weights  map[string]int
+
+	// requiresFactories is set when the backends use an exporter other than OTLP, built from the host's factories
+	requiresFactories bool
+
+	// endpoints failing ejection.ConsecutiveFailures times in a row are left out of the ring for ejection.Duration
+	ejection   EjectionSettings
+	failures   map[string]int
+	ejected    map[string]*time.Timer
+	healthLock sync.Mutex
+
+	// loadFactor, when set, bounds the load of each endpoint to loadFactor times its weighted share of totalLoad
+	loadFactor  float64
+	loads       map[string]uint64
+	pinned      *simplelru.LRU[string, string]
+	totalLoad   uint64
+	totalWeight int
+	loadLock    sync.Mutex
 
 	componentFactory componentFactory
 	exporters        map[string]*wrappedExporter
@@ -45,6 +81,29 @@ type loadBalancer struct {
 func newLoadBalancer(logger *zap.Logger, cfg component.Config, factory componentFactory, telemetry *metadata.TelemetryBuilder) (*loadBalancer, error) {
 	oCfg := cfg.(*Config)
 
+	if oCfg.LoadFactor != 0 && oCfg.LoadFactor < 1 {
+		return nil, errInvalidLoadFactor
+	}
+	pinned, err := simplelru.NewLRU[string, string](maxPinnedKeys, nil)
+	if err != nil {
+		return nil, err
+	}
+	weights := make(map[string]int, len(oCfg.Weights))
+	for endpoint, weight := range oCfg.Weights {
+		if weight < 1 {
+			return nil, fmt.Errorf("invalid weight %d for endpoint %q: weights must be positive", weight, endpoint)
+		}
+		weights[endpointWithPort(endpoint)] = weight
+	}
+	if oCfg.Ejection.ConsecutiveFailures < 0 || (oCfg.Ejection.ConsecutiveFailures > 0 && oCfg.Ejection.Duration <= 0) {
+		return nil, errInvalidEjection
+	}
+	if oCfg.Protocol.Exporter != nil {
+		if _, err := component.NewType(oCfg.Protocol.Exporter.Type); err != nil {
+			return nil, fmt.Errorf("invalid exporter type: %w", err)
+		}
+	}
+
 	var count = 0
 	if oCfg.Resolver.DNS != nil {
 		count++
@@ -132,28 +191,40 @@ func newLoadBalancer(logger *zap.Logger, cfg component.Config, factory component
 	}
 
 	return &loadBalancer{
-		logger:           logger,
-		res:              res,
-		componentFactory: factory,
-		exporters:        map[string]*wrappedExporter{},
+		logger:            logger,
+		telemetry:         telemetry,
+		res:               res,
+		weights:           weights,
+		requiresFactories: oCfg.Protocol.Exporter != nil,
+		ejection:          oCfg.Ejection,
+		failures:          map[string]int{},
+		ejected:           map[string]*time.Timer{},
+		loadFactor:        oCfg.LoadFactor,
+		loads:             map[string]uint64{},
+		pinned:            pinned,
+		componentFactory:  factory,
+		exporters:         map[string]*wrappedExporter{},
 	}, nil
 }
 
 func (lb *loadBalancer) Start(ctx context.Context, host component.Host) error {
+	if _, ok := host.(hostWithFactories); lb.requiresFactories && !ok {
+		return errHostWithoutFactories
+	}
 	lb.res.onChange(lb.onBackendChanges)
 	lb.host = host
 	return lb.res.start(ctx)
 }
 
 func (lb *loadBalancer) onBackendChanges(resolved []string) {
-	newRing := newHashRing(resolved)
-
-	if !newRing.equal(lb.ring) {
-		lb.updateLock.Lock()
-		defer lb.updateLock.Unlock()
+	lb.updateLock.Lock()
+	defer lb.updateLock.Unlock()
 
-		lb.ring = newRing
+	// the resolved endpoints might change without changing the ring, when ejected endpoints are involved
+	membersChanged := !slices.Equal(lb.resolved, resolved)
+	lb.resolved = resolved
 
+	if lb.updateRing() || membersChanged {
 		// TODO: set a timeout?
 		ctx := context.Background()
 
@@ -163,6 +234,19 @@ func (lb *loadBalancer) onBackendChanges(resolved []string) {
 	}
 }
 
+// updateRing rebuilds the ring out of the resolved endpoints that are not ejected, returning whether it changed.
+// The caller must hold the update lock.
+func (lb *loadBalancer) updateRing() bool {
+	endpoints := lb.availableEndpoints()
+	newRing := newWeightedHashRing(endpoints, lb.weights)
+	if newRing.equal(lb.ring) {
+		return false
+	}
+	lb.ring = newRing
+	lb.resetLoads(endpoints)
+	return true
+}
+
 func (lb *loadBalancer) addMissingExporters(ctx context.Context, endpoints []string) {
 	for _, endpoint := range endpoints {
 		endpoint = endpointWithPort(endpoint)
@@ -218,6 +302,12 @@ func endpointFound(endpoint string, endpoints []string) bool {
 }
 
 func (lb *loadBalancer) Shutdown(ctx context.Context) error {
+	lb.healthLock.Lock()
+	for _, timer := range lb.ejected {
+		timer.Stop()
+	}
+	lb.healthLock.Unlock()
+
 	err := lb.res.shutdown(ctx)
 	lb.stopped = true
 	return err
@@ -230,7 +320,12 @@ func (lb *loadBalancer) exporterAndEndpoint(identifier []byte) (*wrappedExporter
 	// for details: https://github.com/open-telemetry/opentelemetry-collector-contrib/issues/1690
 	lb.updateLock.RLock()
 	defer lb.updateLock.RUnlock()
-	endpoint := lb.ring.endpointFor(identifier)
+	var endpoint string
+	if lb.loadFactor > 0 {
+		endpoint = lb.boundedEndpointFor(identifier)
+	} else {
+		endpoint = lb.ring.endpointFor(identifier)
+	}
 	exp, found := lb.exporters[endpointWithPort(endpoint)]
 	if !found {
 		// something is really wrong... how come we couldn't find the exporter??
@@ -239,3 +334,64 @@ func (lb *loadBalancer) exporterAndEndpoint(identifier []byte) (*wrappedExporter
 
 	return exp, endpoint, nil
 }
+
+// boundedEndpointFor implements consistent hashing with bounded loads: the identifier is assigned to the first endpoint,
+// starting from its position in the ring, whose load doesn't exceed the load factor times its weighted share of the
+// total load. This keeps a hot routing key from overloading a single backend. The endpoint is then pinned for the
+// identifier, so that all the telemetry with the same routing key, like the spans of a trace, reaches the same backend
+// until the ring changes or the identifier is evicted from the maxPinnedKeys most recently used ones.
+func (lb *loadBalancer) boundedEndpointFor(identifier []byte) string {
+	lb.loadLock.Lock()
+	defer lb.loadLock.Unlock()
+
+	if endpoint, ok := lb.pinned.Get(string(identifier)); ok {
+		lb.loads[endpoint]++
+		lb.totalLoad++
+		return endpoint
+	}
+
+	if lb.totalLoad >= loadDecayThreshold {
+		lb.totalLoad = 0
+		for endpoint, load := range lb.loads {
+			lb.loads[endpoint] = load / 2
+			lb.totalLoad += load / 2
+		}
+	}
+
+	total := float64(lb.totalLoad + 1)
+	var first, found string
+	lb.ring.walk(identifier, func(endpoint string) bool {
+		if first == "" {
+			first = endpoint
+		}
+		capacity := math.Ceil(lb.loadFactor * total * float64(endpointWeight(lb.weights, endpoint)) / float64(lb.totalWeight))
+		if float64(lb.loads[endpoint]+1) <= capacity {
+			found = endpoint
+			return false
+		}
+		return true
+	})
+	if found == "" {
+		// can't happen with a load factor of at least 1, but let's not drop data if it does
+		found = first
+	}
+	if found != "" {
+		lb.loads[found]++
+		lb.totalLoad++
+		lb.pinned.Add(string(identifier), found)
+	}
+	return found
+}
+
+// resetLoads clears the tracked loads and the pinned endpoints, as the share of each endpoint changes with the ring
+func (lb *loadBalancer) resetLoads(endpoints []string) {
+	lb.loadLock.Lock()
+	defer lb.loadLock.Unlock()
+	lb.loads = map[string]uint64{}
+	lb.pinned.Purge()
+	lb.totalLoad = 0
+	lb.totalWeight = 0
+	for _, endpoint := range endpoints {
+		lb.totalWeight += endpointWeight(lb.weights, endpoint)
+	}
+}
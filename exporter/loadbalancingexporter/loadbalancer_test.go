@@ -6,6 +6,7 @@ package loadbalancingexporter
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -410,6 +411,141 @@ func TestFailedExporterInRing(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestNewLoadBalancerInvalidLoadFactor(t *testing.T) {
+	// prepare
+	ts, tb := getTelemetryAssets(t)
+	cfg := simpleConfig()
+	cfg.LoadFactor = 0.5
+
+	// test
+	p, err := newLoadBalancer(ts.Logger, cfg, nil, tb)
+
+	// verify
+	assert.Nil(t, p)
+	assert.Equal(t, errInvalidLoadFactor, err)
+}
+
+func TestNewLoadBalancerInvalidWeight(t *testing.T) {
+	// prepare
+	ts, tb := getTelemetryAssets(t)
+	cfg := simpleConfig()
+	cfg.Weights = map[string]int{"endpoint-1": 0}
+
+	// test
+	p, err := newLoadBalancer(ts.Logger, cfg, nil, tb)
+
+	// verify
+	assert.Nil(t, p)
+	assert.Error(t, err)
+}
+
+func TestBoundedLoads(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		loadFactor float64
+		weights    map[string]int
+		maxLoads   map[string]int
+	}{
+		{
+			name:       "without bounded loads",
+			loadFactor: 0,
+			// the identifiers go to whichever endpoint they hash to
+			maxLoads: map[string]int{"endpoint-1": 1000, "endpoint-2": 1000, "endpoint-3": 1000},
+		},
+		{
+			name:       "bounded loads",
+			loadFactor: 1.25,
+			maxLoads:   map[string]int{"endpoint-1": 417, "endpoint-2": 417, "endpoint-3": 417},
+		},
+		{
+			name:       "weighted bounded loads",
+			loadFactor: 1,
+			weights:    map[string]int{"endpoint-1": 2},
+			maxLoads:   map[string]int{"endpoint-1": 500, "endpoint-2": 250, "endpoint-3": 250},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// prepare
+			ts, tb := getTelemetryAssets(t)
+			cfg := &Config{
+				Resolver: ResolverSettings{
+					Static: &StaticResolver{Hostnames: []string{"endpoint-1", "endpoint-2", "endpoint-3"}},
+				},
+				LoadFactor: tt.loadFactor,
+				Weights:    tt.weights,
+			}
+			componentFactory := func(_ context.Context, _ string) (component.Component, error) {
+				return newNopMockExporter(), nil
+			}
+			p, err := newLoadBalancer(ts.Logger, cfg, componentFactory, tb)
+			require.NotNil(t, p)
+			require.NoError(t, err)
+
+			require.NoError(t, p.Start(context.Background(), componenttest.NewNopHost()))
+			defer func() {
+				require.NoError(t, p.Shutdown(context.Background()))
+			}()
+
+			// test
+			loads := map[string]int{}
+			for i := 0; i < 1000; i++ {
+				_, endpoint, err := p.exporterAndEndpoint([]byte(fmt.Sprintf("service-%d", i)))
+				require.NoError(t, err)
+				loads[endpoint]++
+			}
+
+			// verify
+			total := 0
+			for endpoint, load := range loads {
+				assert.LessOrEqual(t, load, tt.maxLoads[endpoint], endpoint)
+				total += load
+			}
+			assert.Equal(t, 1000, total)
+		})
+	}
+}
+
+func TestBoundedLoadsKeepRoutingKeyAffinity(t *testing.T) {
+	// prepare
+	ts, tb := getTelemetryAssets(t)
+	cfg := &Config{
+		Resolver: ResolverSettings{
+			Static: &StaticResolver{Hostnames: []string{"endpoint-1", "endpoint-2", "endpoint-3"}},
+		},
+		LoadFactor: 1,
+	}
+	componentFactory := func(_ context.Context, _ string) (component.Component, error) {
+		return newNopMockExporter(), nil
+	}
+	p, err := newLoadBalancer(ts.Logger, cfg, componentFactory, tb)
+	require.NotNil(t, p)
+	require.NoError(t, err)
+
+	require.NoError(t, p.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		require.NoError(t, p.Shutdown(context.Background()))
+	}()
+
+	// test
+	_, first, err := p.exporterAndEndpoint([]byte("hot-trace"))
+	require.NoError(t, err)
+	others := map[string]int{}
+	for i := 0; i < 100; i++ {
+		// the hot trace keeps going to the same endpoint, even when it is above its share of the load
+		_, endpoint, err := p.exporterAndEndpoint([]byte("hot-trace"))
+		require.NoError(t, err)
+		assert.Equal(t, first, endpoint)
+
+		_, endpoint, err = p.exporterAndEndpoint([]byte(fmt.Sprintf("trace-%d", i)))
+		require.NoError(t, err)
+		others[endpoint]++
+	}
+
+	// verify
+	// the other traces are sent to the other endpoints, as the endpoint of the hot trace is at capacity
+	assert.Less(t, others[first], 10)
+}
+
 func TestNewLoadBalancerInvalidNamespaceAwsResolver(t *testing.T) {
 	// prepare
 	ts, tb := getTelemetryAssets(t)
@@ -20,12 +20,18 @@ import (
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter/internal/metadata"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/batchpersignal"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottllog"
 )
 
 var _ exporter.Logs = (*logExporterImp)(nil)
 
 type logExporterImp struct {
 	loadBalancer *loadBalancer
+	routingKey   routingKey
+
+	routingAttributes []string
+	routingExpression *ottl.ValueExpression[ottllog.TransformContext]
 
 	started    bool
 	shutdownWg sync.WaitGroup
@@ -39,20 +45,48 @@ func newLogsExporter(params exporter.Settings, cfg component.Config) (*logExport
 		return nil, err
 	}
 	exporterFactory := otlpexporter.NewFactory()
+	var lb *loadBalancer
 	cfFunc := func(ctx context.Context, endpoint string) (component.Component, error) {
+		if settings := cfg.(*Config).Protocol.Exporter; settings != nil {
+			factory, eCfg, err := buildBackendExporterConfig(lb.host, settings, endpoint, ejectionEnabled(cfg.(*Config)))
+			if err != nil {
+				return nil, err
+			}
+			return factory.CreateLogsExporter(ctx, params, eCfg)
+		}
 		oCfg := buildExporterConfig(cfg.(*Config), endpoint)
 		return exporterFactory.CreateLogsExporter(ctx, params, &oCfg)
 	}
 
-	lb, err := newLoadBalancer(params.Logger, cfg, cfFunc, telemetry)
+	lb, err = newLoadBalancer(params.Logger, cfg, cfFunc, telemetry)
 	if err != nil {
 		return nil, err
 	}
 
-	return &logExporterImp{
+	logExporter := logExporterImp{
 		loadBalancer: lb,
+		routingKey:   traceIDRouting,
 		telemetry:    telemetry,
-	}, nil
+	}
+
+	switch cfg.(*Config).RoutingKey {
+	case attrRoutingStr:
+		if len(cfg.(*Config).RoutingAttributes) == 0 {
+			return nil, errNoRoutingAttributes
+		}
+		logExporter.routingKey = attrRouting
+		logExporter.routingAttributes = cfg.(*Config).RoutingAttributes
+	case exprRoutingStr:
+		logExporter.routingKey = exprRouting
+		logExporter.routingExpression, err = newRoutingExpression(cfg.(*Config).RoutingExpression, params.TelemetrySettings,
+			func(functions map[string]ottl.Factory[ottllog.TransformContext], settings component.TelemetrySettings) (ottl.Parser[ottllog.TransformContext], error) {
+				return ottllog.NewParser(functions, settings)
+			})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &logExporter, nil
 }
 
 func (e *logExporterImp) Capabilities() consumer.Capabilities {
@@ -76,25 +110,35 @@ func (e *logExporterImp) Shutdown(ctx context.Context) error {
 
 func (e *logExporterImp) ConsumeLogs(ctx context.Context, ld plog.Logs) error {
 	var errs error
-	batches := batchpersignal.SplitLogs(ld)
-	for _, batch := range batches {
-		errs = multierr.Append(errs, e.consumeLog(ctx, batch))
+	switch e.routingKey {
+	case attrRouting, exprRouting:
+		batches, err := e.splitLogsByRoutingKey(ctx, ld)
+		if err != nil {
+			return err
+		}
+		for key, batch := range batches {
+			errs = multierr.Append(errs, e.consumeLog(ctx, []byte(key), batch))
+		}
+	default:
+		batches := batchpersignal.SplitLogs(ld)
+		for _, batch := range batches {
+			traceID := traceIDFromLogs(batch)
+			balancingKey := traceID
+			if traceID == pcommon.NewTraceIDEmpty() {
+				// every log may not contain a traceID
+				// generate a random traceID as balancingKey
+				// so the log can be routed to a random backend
+				balancingKey = random()
+			}
+			errs = multierr.Append(errs, e.consumeLog(ctx, balancingKey[:], batch))
+		}
 	}
 
 	return errs
 }
 
-func (e *logExporterImp) consumeLog(ctx context.Context, ld plog.Logs) error {
-	traceID := traceIDFromLogs(ld)
-	balancingKey := traceID
-	if traceID == pcommon.NewTraceIDEmpty() {
-		// every log may not contain a traceID
-		// generate a random traceID as balancingKey
-		// so the log can be routed to a random backend
-		balancingKey = random()
-	}
-
-	le, _, err := e.loadBalancer.exporterAndEndpoint(balancingKey[:])
+func (e *logExporterImp) consumeLog(ctx context.Context, balancingKey []byte, ld plog.Logs) error {
+	le, _, err := e.loadBalancer.exporterAndEndpoint(balancingKey)
 	if err != nil {
 		return err
 	}
@@ -105,6 +149,7 @@ func (e *logExporterImp) consumeLog(ctx context.Context, ld plog.Logs) error {
 	start := time.Now()
 	err = le.ConsumeLogs(ctx, ld)
 	duration := time.Since(start)
+	e.loadBalancer.onExportResult(ctx, le, err)
 	e.telemetry.LoadbalancerBackendLatency.Record(ctx, duration.Milliseconds(), metric.WithAttributeSet(le.endpointAttr))
 	if err == nil {
 		e.telemetry.LoadbalancerBackendOutcome.Add(ctx, 1, metric.WithAttributeSet(le.successAttr))
@@ -115,6 +160,56 @@ func (e *logExporterImp) consumeLog(ctx context.Context, ld plog.Logs) error {
 	return err
 }
 
+// splitLogsByRoutingKey splits the log records based on the routing key built from the routing_attributes or the
+// routing_expression. Attributes are looked up in the log record, then in the scope and then in the resource.
+func (e *logExporterImp) splitLogsByRoutingKey(ctx context.Context, ld plog.Logs) (map[string]plog.Logs, error) {
+	results := map[string]plog.Logs{}
+
+	for i := 0; i < ld.ResourceLogs().Len(); i++ {
+		rl := ld.ResourceLogs().At(i)
+
+		for j := 0; j < rl.ScopeLogs().Len(); j++ {
+			sl := rl.ScopeLogs().At(j)
+			logsByKey := map[string]plog.LogRecordSlice{}
+
+			for k := 0; k < sl.LogRecords().Len(); k++ {
+				lr := sl.LogRecords().At(k)
+
+				var key string
+				if e.routingKey == attrRouting {
+					key = routingKeyFromAttributes(e.routingAttributes, lr.Attributes(), sl.Scope().Attributes(), rl.Resource().Attributes())
+				} else {
+					var err error
+					tCtx := ottllog.NewTransformContext(lr, sl.Scope(), rl.Resource(), sl, rl)
+					if key, err = routingKeyFromExpression(ctx, e.routingExpression, tCtx); err != nil {
+						return nil, err
+					}
+				}
+
+				records, ok := logsByKey[key]
+				if !ok {
+					out, ok := results[key]
+					if !ok {
+						out = plog.NewLogs()
+						results[key] = out
+					}
+					rlClone := out.ResourceLogs().AppendEmpty()
+					rl.Resource().CopyTo(rlClone.Resource())
+					rlClone.SetSchemaUrl(rl.SchemaUrl())
+					slClone := rlClone.ScopeLogs().AppendEmpty()
+					sl.Scope().CopyTo(slClone.Scope())
+					slClone.SetSchemaUrl(sl.SchemaUrl())
+					records = slClone.LogRecords()
+					logsByKey[key] = records
+				}
+				lr.CopyTo(records.AppendEmpty())
+			}
+		}
+	}
+
+	return results, nil
+}
+
 func traceIDFromLogs(ld plog.Logs) pcommon.TraceID {
 	rl := ld.ResourceLogs()
 	if rl.Len() == 0 {
@@ -42,6 +42,14 @@ func TestNewLogsExporter(t *testing.T) {
 			&Config{},
 			errNoResolver,
 		},
+		{
+			"expression without routing expression",
+			&Config{
+				Resolver:   simpleConfig().Resolver,
+				RoutingKey: exprRoutingStr,
+			},
+			errNoRoutingExpression,
+		},
 	} {
 		t.Run(tt.desc, func(t *testing.T) {
 			// test
@@ -296,6 +304,57 @@ func TestLogsWithoutTraceID(t *testing.T) {
 	assert.Len(t, sink.AllLogs(), 1)
 }
 
+func TestLogsWithRoutingExpression(t *testing.T) {
+	ts, tb := getTelemetryAssets(t)
+	cfg := simpleConfig()
+	cfg.RoutingKey = exprRoutingStr
+	cfg.RoutingExpression = `attributes["tenant"]`
+
+	sink := new(consumertest.LogsSink)
+	componentFactory := func(_ context.Context, _ string) (component.Component, error) {
+		return newMockLogsExporter(sink.ConsumeLogs), nil
+	}
+	lb, err := newLoadBalancer(ts.Logger, cfg, componentFactory, tb)
+	require.NotNil(t, lb)
+	require.NoError(t, err)
+
+	p, err := newLogsExporter(ts, cfg)
+	require.NotNil(t, p)
+	require.NoError(t, err)
+
+	// pre-load an exporter here, so that we don't use the actual OTLP exporter
+	lb.addMissingExporters(context.Background(), []string{"endpoint-1"})
+	p.loadBalancer = lb
+
+	err = p.Start(context.Background(), componenttest.NewNopHost())
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, p.Shutdown(context.Background()))
+	}()
+
+	logs := plog.NewLogs()
+	records := logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+	for _, tenant := range []string{"tenant-1", "tenant-2", "tenant-2"} {
+		records.AppendEmpty().Attributes().PutStr("tenant", tenant)
+	}
+
+	// test
+	batches, err := p.splitLogsByRoutingKey(context.Background(), logs)
+
+	// verify
+	require.NoError(t, err)
+	require.Len(t, batches, 2)
+	assert.Equal(t, 1, batches["tenant-1"].LogRecordCount())
+	assert.Equal(t, 2, batches["tenant-2"].LogRecordCount())
+
+	// test
+	err = p.ConsumeLogs(context.Background(), logs)
+
+	// verify
+	assert.NoError(t, err)
+	assert.Equal(t, 3, sink.LogRecordCount())
+}
+
 // this test validates that exporter is can concurrently change the endpoints while consuming logs.
 func TestConsumeLogs_ConcurrentResolverChange(t *testing.T) {
 	ts, tb := getTelemetryAssets(t)
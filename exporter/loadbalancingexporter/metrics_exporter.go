@@ -14,6 +14,7 @@ import (
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/exporter"
 	"go.opentelemetry.io/collector/exporter/otlpexporter"
+	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	conventions "go.opentelemetry.io/collector/semconv/v1.6.1"
 	"go.opentelemetry.io/otel/metric"
@@ -22,6 +23,8 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter/internal/metadata"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/exp/metrics"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/exp/metrics/identity"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottldatapoint"
 )
 
 var _ exporter.Metrics = (*metricExporterImp)(nil)
@@ -30,6 +33,9 @@ type metricExporterImp struct {
 	loadBalancer *loadBalancer
 	routingKey   routingKey
 
+	routingAttributes []string
+	routingExpression *ottl.ValueExpression[ottldatapoint.TransformContext]
+
 	stopped    bool
 	shutdownWg sync.WaitGroup
 	telemetry  *metadata.TelemetryBuilder
@@ -41,12 +47,20 @@ func newMetricsExporter(params exporter.Settings, cfg component.Config) (*metric
 		return nil, err
 	}
 	exporterFactory := otlpexporter.NewFactory()
+	var lb *loadBalancer
 	cfFunc := func(ctx context.Context, endpoint string) (component.Component, error) {
+		if settings := cfg.(*Config).Protocol.Exporter; settings != nil {
+			factory, eCfg, err := buildBackendExporterConfig(lb.host, settings, endpoint, ejectionEnabled(cfg.(*Config)))
+			if err != nil {
+				return nil, err
+			}
+			return factory.CreateMetricsExporter(ctx, params, eCfg)
+		}
 		oCfg := buildExporterConfig(cfg.(*Config), endpoint)
 		return exporterFactory.CreateMetricsExporter(ctx, params, &oCfg)
 	}
 
-	lb, err := newLoadBalancer(params.Logger, cfg, cfFunc, telemetry)
+	lb, err = newLoadBalancer(params.Logger, cfg, cfFunc, telemetry)
 	if err != nil {
 		return nil, err
 	}
@@ -67,6 +81,21 @@ func newMetricsExporter(params exporter.Settings, cfg component.Config) (*metric
 		metricExporter.routingKey = metricNameRouting
 	case streamIDRoutingStr:
 		metricExporter.routingKey = streamIDRouting
+	case attrRoutingStr:
+		if len(cfg.(*Config).RoutingAttributes) == 0 {
+			return nil, errNoRoutingAttributes
+		}
+		metricExporter.routingKey = attrRouting
+		metricExporter.routingAttributes = cfg.(*Config).RoutingAttributes
+	case exprRoutingStr:
+		metricExporter.routingKey = exprRouting
+		metricExporter.routingExpression, err = newRoutingExpression(cfg.(*Config).RoutingExpression, params.TelemetrySettings,
+			func(functions map[string]ottl.Factory[ottldatapoint.TransformContext], settings component.TelemetrySettings) (ottl.Parser[ottldatapoint.TransformContext], error) {
+				return ottldatapoint.NewParser(functions, settings)
+			})
+		if err != nil {
+			return nil, err
+		}
 	default:
 		return nil, fmt.Errorf("unsupported routing_key: %q", cfg.(*Config).RoutingKey)
 	}
@@ -105,6 +134,14 @@ func (e *metricExporterImp) ConsumeMetrics(ctx context.Context, md pmetric.Metri
 		batches = splitMetricsByMetricName(md)
 	case streamIDRouting:
 		batches = splitMetricsByStreamID(md)
+	case attrRouting:
+		batches = splitMetricsByAttributes(md, e.routingAttributes)
+	case exprRouting:
+		var err error
+		batches, err = splitMetricsByExpression(ctx, md, e.routingExpression)
+		if err != nil {
+			return err
+		}
 	}
 
 	// Now assign each batch to an exporter, and merge as we go
@@ -133,6 +170,7 @@ func (e *metricExporterImp) ConsumeMetrics(ctx context.Context, md pmetric.Metri
 		start := time.Now()
 		err := exp.ConsumeMetrics(ctx, mds)
 		duration := time.Since(start)
+		e.loadBalancer.onExportResult(ctx, exp, err)
 
 		exp.consumeWG.Done()
 		errs = multierr.Append(errs, err)
@@ -226,19 +264,46 @@ func splitMetricsByMetricName(md pmetric.Metrics) map[string]pmetric.Metrics {
 }
 
 func splitMetricsByStreamID(md pmetric.Metrics) map[string]pmetric.Metrics {
+	results, _ := splitMetricsByDataPoint(md, func(rm pmetric.ResourceMetrics, sm pmetric.ScopeMetrics, m pmetric.Metric, dp dataPoint) (string, error) {
+		return identity.OfStream(identity.OfResourceMetric(rm.Resource(), sm.Scope(), m), dp).String(), nil
+	})
+	return results
+}
+
+// splitMetricsByAttributes splits the datapoints based on the values of the given attributes, looked up in the
+// datapoint, then in the scope and then in the resource.
+func splitMetricsByAttributes(md pmetric.Metrics, attributes []string) map[string]pmetric.Metrics {
+	results, _ := splitMetricsByDataPoint(md, func(rm pmetric.ResourceMetrics, sm pmetric.ScopeMetrics, _ pmetric.Metric, dp dataPoint) (string, error) {
+		return routingKeyFromAttributes(attributes, dp.Attributes(), sm.Scope().Attributes(), rm.Resource().Attributes()), nil
+	})
+	return results
+}
+
+// splitMetricsByExpression splits the datapoints based on the result of the routing expression.
+func splitMetricsByExpression(ctx context.Context, md pmetric.Metrics, expression *ottl.ValueExpression[ottldatapoint.TransformContext]) (map[string]pmetric.Metrics, error) {
+	return splitMetricsByDataPoint(md, func(rm pmetric.ResourceMetrics, sm pmetric.ScopeMetrics, m pmetric.Metric, dp dataPoint) (string, error) {
+		tCtx := ottldatapoint.NewTransformContext(dp, m, sm.Metrics(), sm.Scope(), rm.Resource(), sm, rm)
+		return routingKeyFromExpression(ctx, expression, tCtx)
+	})
+}
+
+// dataPoint is implemented by the datapoints of all metric types.
+type dataPoint interface {
+	Attributes() pcommon.Map
+}
+
+// splitMetricsByDataPoint splits the metrics into one batch per routing key, as returned by keyFor for each datapoint.
+func splitMetricsByDataPoint(md pmetric.Metrics, keyFor func(pmetric.ResourceMetrics, pmetric.ScopeMetrics, pmetric.Metric, dataPoint) (string, error)) (map[string]pmetric.Metrics, error) {
 	results := map[string]pmetric.Metrics{}
 
 	for i := 0; i < md.ResourceMetrics().Len(); i++ {
 		rm := md.ResourceMetrics().At(i)
-		res := rm.Resource()
 
 		for j := 0; j < rm.ScopeMetrics().Len(); j++ {
 			sm := rm.ScopeMetrics().At(j)
-			scope := sm.Scope()
 
 			for k := 0; k < sm.Metrics().Len(); k++ {
 				m := sm.Metrics().At(k)
-				metricID := identity.OfResourceMetric(res, scope, m)
 
 				switch m.Type() {
 				case pmetric.MetricTypeGauge:
@@ -253,7 +318,10 @@ func splitMetricsByStreamID(md pmetric.Metrics) map[string]pmetric.Metrics {
 						dpClone := gaugeClone.DataPoints().AppendEmpty()
 						dp.CopyTo(dpClone)
 
-						key := identity.OfStream(metricID, dp).String()
+						key, err := keyFor(rm, sm, m, dp)
+						if err != nil {
+							return nil, err
+						}
 						existing, ok := results[key]
 						if ok {
 							metrics.Merge(existing, newMD)
@@ -275,7 +343,10 @@ func splitMetricsByStreamID(md pmetric.Metrics) map[string]pmetric.Metrics {
 						dpClone := sumClone.DataPoints().AppendEmpty()
 						dp.CopyTo(dpClone)
 
-						key := identity.OfStream(metricID, dp).String()
+						key, err := keyFor(rm, sm, m, dp)
+						if err != nil {
+							return nil, err
+						}
 						existing, ok := results[key]
 						if ok {
 							metrics.Merge(existing, newMD)
@@ -296,7 +367,10 @@ func splitMetricsByStreamID(md pmetric.Metrics) map[string]pmetric.Metrics {
 						dpClone := histogramClone.DataPoints().AppendEmpty()
 						dp.CopyTo(dpClone)
 
-						key := identity.OfStream(metricID, dp).String()
+						key, err := keyFor(rm, sm, m, dp)
+						if err != nil {
+							return nil, err
+						}
 						existing, ok := results[key]
 						if ok {
 							metrics.Merge(existing, newMD)
@@ -317,7 +391,10 @@ func splitMetricsByStreamID(md pmetric.Metrics) map[string]pmetric.Metrics {
 						dpClone := expHistogramClone.DataPoints().AppendEmpty()
 						dp.CopyTo(dpClone)
 
-						key := identity.OfStream(metricID, dp).String()
+						key, err := keyFor(rm, sm, m, dp)
+						if err != nil {
+							return nil, err
+						}
 						existing, ok := results[key]
 						if ok {
 							metrics.Merge(existing, newMD)
@@ -337,7 +414,10 @@ func splitMetricsByStreamID(md pmetric.Metrics) map[string]pmetric.Metrics {
 						dpClone := sumClone.DataPoints().AppendEmpty()
 						dp.CopyTo(dpClone)
 
-						key := identity.OfStream(metricID, dp).String()
+						key, err := keyFor(rm, sm, m, dp)
+						if err != nil {
+							return nil, err
+						}
 						existing, ok := results[key]
 						if ok {
 							metrics.Merge(existing, newMD)
@@ -350,7 +430,7 @@ func splitMetricsByStreamID(md pmetric.Metrics) map[string]pmetric.Metrics {
 		}
 	}
 
-	return results
+	return results, nil
 }
 
 func cloneMetricWithoutType(rm pmetric.ResourceMetrics, sm pmetric.ScopeMetrics, m pmetric.Metric) (md pmetric.Metrics, mClone pmetric.Metric) {
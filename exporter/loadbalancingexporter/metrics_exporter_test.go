@@ -33,6 +33,8 @@ import (
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter/internal/metadata"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/golden"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottldatapoint"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/pdatatest/pmetrictest"
 )
 
@@ -91,6 +93,14 @@ func TestNewMetricsExporter(t *testing.T) {
 			},
 			errNoResolver,
 		},
+		{
+			"attributes without routing attributes",
+			&Config{
+				Resolver:   endpoint2Config().Resolver,
+				RoutingKey: attrRoutingStr,
+			},
+			errNoRoutingAttributes,
+		},
 	} {
 		t.Run(tt.desc, func(t *testing.T) {
 			// test
@@ -310,6 +320,58 @@ func TestSplitMetrics(t *testing.T) {
 	}
 }
 
+func TestSplitMetricsByRoutingKey(t *testing.T) {
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr(conventions.AttributeServiceName, serviceName1)
+	ms := rm.ScopeMetrics().AppendEmpty().Metrics()
+	sum := ms.AppendEmpty()
+	sum.SetName(signal1Name)
+	dps := sum.SetEmptySum().DataPoints()
+	for _, tenant := range []string{"tenant-1", "tenant-2", "tenant-2"} {
+		dps.AppendEmpty().Attributes().PutStr("tenant", tenant)
+	}
+	gauge := ms.AppendEmpty()
+	gauge.SetName(signal2Name)
+	gauge.SetEmptyGauge().DataPoints().AppendEmpty()
+
+	countDataPoints := func(batches map[string]pmetric.Metrics) map[string]int {
+		counts := map[string]int{}
+		for key, md := range batches {
+			counts[key] = md.DataPointCount()
+		}
+		return counts
+	}
+
+	// test
+	batches := splitMetricsByAttributes(md, []string{"tenant", conventions.AttributeServiceName})
+
+	// verify
+	assert.Equal(t, map[string]int{
+		"tenant-1\x00" + serviceName1: 1,
+		"tenant-2\x00" + serviceName1: 2,
+		"\x00" + serviceName1:         1,
+	}, countDataPoints(batches))
+
+	// prepare
+	expression, err := newRoutingExpression(`Concat([metric.name, attributes["tenant"]], "/")`, componenttest.NewNopTelemetrySettings(),
+		func(functions map[string]ottl.Factory[ottldatapoint.TransformContext], settings component.TelemetrySettings) (ottl.Parser[ottldatapoint.TransformContext], error) {
+			return ottldatapoint.NewParser(functions, settings)
+		})
+	require.NoError(t, err)
+
+	// test
+	batches, err = splitMetricsByExpression(context.Background(), md, expression)
+
+	// verify
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{
+		signal1Name + "/tenant-1": 1,
+		signal1Name + "/tenant-2": 2,
+		signal2Name + "/<nil>":    1,
+	}, countDataPoints(batches))
+}
+
 func TestConsumeMetrics_SingleEndpoint(t *testing.T) {
 	ts, tb := getTelemetryAssets(t)
 	t.Parallel()
@@ -0,0 +1,71 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package loadbalancingexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter"
+
+import (
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/exporter"
+)
+
+const (
+	defaultEndpointKey = "endpoint"
+	sendingQueueKey    = "sending_queue"
+)
+
+var errHostWithoutFactories = errors.New("the host does not provide the exporter factories, required by protocol::exporter")
+
+// hostWithFactories is an interface that the component.Host passed to the exporter's Start function must implement,
+// when an exporter other than OTLP is configured
+type hostWithFactories interface {
+	component.Host
+	GetFactory(component.Kind, component.Type) component.Factory
+}
+
+// buildBackendExporterConfig returns the factory and the configuration of the configured exporter, with the
+// endpoint key set to the given endpoint. When disableQueue is set, the sending queue of the exporter is disabled,
+// if it has one.
+func buildBackendExporterConfig(h component.Host, settings *ExporterSettings, endpoint string, disableQueue bool) (exporter.Factory, component.Config, error) {
+	fh, ok := h.(hostWithFactories)
+	if !ok {
+		return nil, nil, errHostWithoutFactories
+	}
+
+	typ, err := component.NewType(settings.Type)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid exporter type: %w", err)
+	}
+	factory, ok := fh.GetFactory(component.KindExporter, typ).(exporter.Factory)
+	if !ok {
+		return nil, nil, fmt.Errorf("exporter factory for type %q not found", settings.Type)
+	}
+
+	raw := make(map[string]any, len(settings.Config)+1)
+	for k, v := range settings.Config {
+		raw[k] = v
+	}
+	endpointKey := settings.EndpointKey
+	if endpointKey == "" {
+		endpointKey = defaultEndpointKey
+	}
+	raw[endpointKey] = endpoint
+
+	cfg := factory.CreateDefaultConfig()
+	if disableQueue {
+		defaults := confmap.New()
+		if err := defaults.Marshal(cfg); err == nil && defaults.IsSet(sendingQueueKey) {
+			raw[sendingQueueKey] = map[string]any{"enabled": false}
+		}
+	}
+	if err := confmap.NewFromStringMap(raw).Unmarshal(cfg); err != nil {
+		return nil, nil, fmt.Errorf("invalid %q exporter configuration: %w", settings.Type, err)
+	}
+	if err := component.ValidateConfig(cfg); err != nil {
+		return nil, nil, fmt.Errorf("invalid %q exporter configuration: %w", settings.Type, err)
+	}
+	return factory, cfg, nil
+}
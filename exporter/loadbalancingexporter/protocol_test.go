@@ -0,0 +1,146 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package loadbalancingexporter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/exporter/otlpexporter"
+)
+
+type factoryHost struct {
+	component.Host
+	factories map[component.Type]component.Factory
+}
+
+func (h *factoryHost) GetFactory(kind component.Kind, componentType component.Type) component.Factory {
+	if kind != component.KindExporter {
+		return nil
+	}
+	return h.factories[componentType]
+}
+
+func newFactoryHost() *factoryHost {
+	otlpFactory := otlpexporter.NewFactory()
+	return &factoryHost{
+		Host: componenttest.NewNopHost(),
+		factories: map[component.Type]component.Factory{
+			otlpFactory.Type(): otlpFactory,
+		},
+	}
+}
+
+// hostWithoutFactories hides the GetFactory method of the host it wraps
+type hostWithoutFactories struct {
+	component.Host
+}
+
+func TestBuildBackendExporterConfig(t *testing.T) {
+	// prepare
+	settings := &ExporterSettings{
+		Type: "otlp",
+		Config: map[string]any{
+			"timeout": "2s",
+		},
+	}
+
+	// test
+	factory, cfg, err := buildBackendExporterConfig(newFactoryHost(), settings, "backend-1:4317", false)
+
+	// verify
+	require.NoError(t, err)
+	assert.Equal(t, "otlp", factory.Type().String())
+	oCfg := cfg.(*otlpexporter.Config)
+	assert.Equal(t, "backend-1:4317", oCfg.Endpoint)
+	assert.Equal(t, 2*time.Second, oCfg.Timeout)
+	assert.True(t, oCfg.QueueConfig.Enabled)
+	// the settings are not modified
+	assert.NotContains(t, settings.Config, "endpoint")
+}
+
+func TestBuildBackendExporterConfigWithoutQueue(t *testing.T) {
+	// prepare
+	settings := &ExporterSettings{
+		Type: "otlp",
+		Config: map[string]any{
+			"sending_queue": map[string]any{"num_consumers": 2},
+		},
+	}
+
+	// test
+	_, cfg, err := buildBackendExporterConfig(newFactoryHost(), settings, "backend-1:4317", true)
+
+	// verify
+	require.NoError(t, err)
+	assert.False(t, cfg.(*otlpexporter.Config).QueueConfig.Enabled)
+}
+
+func TestBuildBackendExporterConfigErrors(t *testing.T) {
+	for _, tt := range []struct {
+		desc     string
+		host     component.Host
+		settings *ExporterSettings
+	}{
+		{
+			"host without factories",
+			hostWithoutFactories{componenttest.NewNopHost()},
+			&ExporterSettings{Type: "otlp"},
+		},
+		{
+			"unknown exporter type",
+			newFactoryHost(),
+			&ExporterSettings{Type: "kafka"},
+		},
+		{
+			"invalid exporter type",
+			newFactoryHost(),
+			&ExporterSettings{Type: "not/a/type"},
+		},
+		{
+			"invalid configuration",
+			newFactoryHost(),
+			&ExporterSettings{Type: "otlp", Config: map[string]any{"unknown": true}},
+		},
+		{
+			"invalid endpoint key",
+			newFactoryHost(),
+			&ExporterSettings{Type: "otlp", EndpointKey: "unknown"},
+		},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			// test
+			_, _, err := buildBackendExporterConfig(tt.host, tt.settings, "backend-1:4317", false)
+
+			// verify
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestTracesExporterWithBackendExporter(t *testing.T) {
+	// prepare
+	cfg := simpleConfig()
+	cfg.Protocol.Exporter = &ExporterSettings{Type: "otlp"}
+
+	p, err := newTracesExporter(exportertest.NewNopSettings(), cfg)
+	require.NotNil(t, p)
+	require.NoError(t, err)
+
+	// test
+	assert.Equal(t, errHostWithoutFactories, p.Start(context.Background(), hostWithoutFactories{componenttest.NewNopHost()}))
+	require.NoError(t, p.Start(context.Background(), newFactoryHost()))
+	defer func() {
+		require.NoError(t, p.Shutdown(context.Background()))
+	}()
+
+	// verify
+	assert.Contains(t, p.loadBalancer.exporters, "endpoint-1:4317")
+}
@@ -0,0 +1,71 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package loadbalancingexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+)
+
+var (
+	errNoRoutingAttributes = errors.New("routing_attributes must be set when routing_key is \"attributes\"")
+	errNoRoutingExpression = errors.New("routing_expression must be set when routing_key is \"expression\"")
+)
+
+// routingKeyFromAttributes builds a routing key out of the values of the given attributes. Each attribute is looked up
+// in the given maps, in order, and the first value found is used. Missing attributes contribute an empty value.
+func routingKeyFromAttributes(attributes []string, maps ...pcommon.Map) string {
+	var sb strings.Builder
+	for i, name := range attributes {
+		if i > 0 {
+			sb.WriteByte(0)
+		}
+		for _, m := range maps {
+			if v, ok := m.Get(name); ok {
+				sb.WriteString(v.AsString())
+				break
+			}
+		}
+	}
+	return sb.String()
+}
+
+// newRoutingExpression parses the routing_expression as an OTTL value expression, with the parser built by newParser
+// for the signal's context and the standard converters.
+func newRoutingExpression[K any](
+	expression string,
+	settings component.TelemetrySettings,
+	newParser func(map[string]ottl.Factory[K], component.TelemetrySettings) (ottl.Parser[K], error),
+) (*ottl.ValueExpression[K], error) {
+	if expression == "" {
+		return nil, errNoRoutingExpression
+	}
+	parser, err := newParser(ottlfuncs.StandardConverters[K](), settings)
+	if err != nil {
+		return nil, err
+	}
+	valueExpression, err := parser.ParseValueExpression(expression)
+	if err != nil {
+		return nil, fmt.Errorf("invalid routing_expression: %w", err)
+	}
+	return valueExpression, nil
+}
+
+// routingKeyFromExpression evaluates the routing expression against the given context. Values that are not strings
+// are converted to their string representation, and nil values result in an empty key.
+func routingKeyFromExpression[K any](ctx context.Context, expression *ottl.ValueExpression[K], tCtx K) (string, error) {
+	key, err := ottl.StandardStringLikeGetter[K]{Getter: expression.Eval}.Get(ctx, tCtx)
+	if err != nil || key == nil {
+		return "", err
+	}
+	return *key, nil
+}
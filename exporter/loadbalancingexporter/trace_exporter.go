@@ -20,6 +20,8 @@ import (
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter/internal/metadata"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/batchpersignal"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlspan"
 )
 
 var _ exporter.Traces = (*traceExporterImp)(nil)
@@ -30,6 +32,9 @@ type traceExporterImp struct {
 	loadBalancer *loadBalancer
 	routingKey   routingKey
 
+	routingAttributes []string
+	routingExpression *ottl.ValueExpression[ottlspan.TransformContext]
+
 	stopped    bool
 	shutdownWg sync.WaitGroup
 	telemetry  *metadata.TelemetryBuilder
@@ -43,12 +48,20 @@ func newTracesExporter(params exporter.Settings, cfg component.Config) (*traceEx
 	}
 
 	exporterFactory := otlpexporter.NewFactory()
+	var lb *loadBalancer
 	cfFunc := func(ctx context.Context, endpoint string) (component.Component, error) {
+		if settings := cfg.(*Config).Protocol.Exporter; settings != nil {
+			factory, eCfg, err := buildBackendExporterConfig(lb.host, settings, endpoint, ejectionEnabled(cfg.(*Config)))
+			if err != nil {
+				return nil, err
+			}
+			return factory.CreateTracesExporter(ctx, params, eCfg)
+		}
 		oCfg := buildExporterConfig(cfg.(*Config), endpoint)
 		return exporterFactory.CreateTracesExporter(ctx, params, &oCfg)
 	}
 
-	lb, err := newLoadBalancer(params.Logger, cfg, cfFunc, telemetry)
+	lb, err = newLoadBalancer(params.Logger, cfg, cfFunc, telemetry)
 	if err != nil {
 		return nil, err
 	}
@@ -63,6 +76,21 @@ func newTracesExporter(params exporter.Settings, cfg component.Config) (*traceEx
 	case svcRoutingStr:
 		traceExporter.routingKey = svcRouting
 	case traceIDRoutingStr, "":
+	case attrRoutingStr:
+		if len(cfg.(*Config).RoutingAttributes) == 0 {
+			return nil, errNoRoutingAttributes
+		}
+		traceExporter.routingKey = attrRouting
+		traceExporter.routingAttributes = cfg.(*Config).RoutingAttributes
+	case exprRoutingStr:
+		traceExporter.routingKey = exprRouting
+		traceExporter.routingExpression, err = newRoutingExpression(cfg.(*Config).RoutingExpression, params.TelemetrySettings,
+			func(functions map[string]ottl.Factory[ottlspan.TransformContext], settings component.TelemetrySettings) (ottl.Parser[ottlspan.TransformContext], error) {
+				return ottlspan.NewParser(functions, settings)
+			})
+		if err != nil {
+			return nil, err
+		}
 	default:
 		return nil, fmt.Errorf("unsupported routing_key: %s", cfg.(*Config).RoutingKey)
 	}
@@ -72,6 +100,9 @@ func newTracesExporter(params exporter.Settings, cfg component.Config) (*traceEx
 func buildExporterConfig(cfg *Config, endpoint string) otlpexporter.Config {
 	oCfg := cfg.Protocol.OTLP
 	oCfg.Endpoint = endpoint
+	if ejectionEnabled(cfg) {
+		oCfg.QueueConfig.Enabled = false
+	}
 	return oCfg
 }
 
@@ -91,30 +122,48 @@ func (e *traceExporterImp) Shutdown(ctx context.Context) error {
 }
 
 func (e *traceExporterImp) ConsumeTraces(ctx context.Context, td ptrace.Traces) error {
-	batches := batchpersignal.SplitTraces(td)
-
 	exporterSegregatedTraces := make(exporterTraces)
 	endpoints := make(map[*wrappedExporter]string)
-	for _, batch := range batches {
-		routingID, err := routingIdentifiersFromTraces(batch, e.routingKey)
+	assign := func(rid string, batch ptrace.Traces) error {
+		exp, endpoint, err := e.loadBalancer.exporterAndEndpoint([]byte(rid))
 		if err != nil {
 			return err
 		}
 
-		for rid := range routingID {
-			exp, endpoint, err := e.loadBalancer.exporterAndEndpoint([]byte(rid))
+		_, ok := exporterSegregatedTraces[exp]
+		if !ok {
+			exp.consumeWG.Add(1)
+			exporterSegregatedTraces[exp] = ptrace.NewTraces()
+		}
+		exporterSegregatedTraces[exp] = mergeTraces(exporterSegregatedTraces[exp], batch)
+
+		endpoints[exp] = endpoint
+		return nil
+	}
+
+	switch e.routingKey {
+	case attrRouting, exprRouting:
+		batches, err := e.splitTracesByRoutingKey(ctx, td)
+		if err != nil {
+			return err
+		}
+		for rid, batch := range batches {
+			if err := assign(rid, batch); err != nil {
+				return err
+			}
+		}
+	default:
+		for _, batch := range batchpersignal.SplitTraces(td) {
+			routingID, err := routingIdentifiersFromTraces(batch, e.routingKey)
 			if err != nil {
 				return err
 			}
 
-			_, ok := exporterSegregatedTraces[exp]
-			if !ok {
-				exp.consumeWG.Add(1)
-				exporterSegregatedTraces[exp] = ptrace.NewTraces()
+			for rid := range routingID {
+				if err := assign(rid, batch); err != nil {
+					return err
+				}
 			}
-			exporterSegregatedTraces[exp] = mergeTraces(exporterSegregatedTraces[exp], batch)
-
-			endpoints[exp] = endpoint
 		}
 	}
 
@@ -126,6 +175,7 @@ func (e *traceExporterImp) ConsumeTraces(ctx context.Context, td ptrace.Traces)
 		exp.consumeWG.Done()
 		errs = multierr.Append(errs, err)
 		duration := time.Since(start)
+		e.loadBalancer.onExportResult(ctx, exp, err)
 		e.telemetry.LoadbalancerBackendLatency.Record(ctx, duration.Milliseconds(), metric.WithAttributeSet(exp.endpointAttr))
 		if err == nil {
 			e.telemetry.LoadbalancerBackendOutcome.Add(ctx, 1, metric.WithAttributeSet(exp.successAttr))
@@ -168,3 +218,53 @@ func routingIdentifiersFromTraces(td ptrace.Traces, key routingKey) (map[string]
 	ids[string(tid[:])] = true
 	return ids, nil
 }
+
+// splitTracesByRoutingKey splits the spans based on the routing key built from the routing_attributes or the
+// routing_expression. Attributes are looked up in the span, then in the scope and then in the resource.
+func (e *traceExporterImp) splitTracesByRoutingKey(ctx context.Context, td ptrace.Traces) (map[string]ptrace.Traces, error) {
+	results := map[string]ptrace.Traces{}
+
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		rs := td.ResourceSpans().At(i)
+
+		for j := 0; j < rs.ScopeSpans().Len(); j++ {
+			ss := rs.ScopeSpans().At(j)
+			spansByKey := map[string]ptrace.SpanSlice{}
+
+			for k := 0; k < ss.Spans().Len(); k++ {
+				span := ss.Spans().At(k)
+
+				var key string
+				if e.routingKey == attrRouting {
+					key = routingKeyFromAttributes(e.routingAttributes, span.Attributes(), ss.Scope().Attributes(), rs.Resource().Attributes())
+				} else {
+					var err error
+					tCtx := ottlspan.NewTransformContext(span, ss.Scope(), rs.Resource(), ss, rs)
+					if key, err = routingKeyFromExpression(ctx, e.routingExpression, tCtx); err != nil {
+						return nil, err
+					}
+				}
+
+				spans, ok := spansByKey[key]
+				if !ok {
+					out, ok := results[key]
+					if !ok {
+						out = ptrace.NewTraces()
+						results[key] = out
+					}
+					rsClone := out.ResourceSpans().AppendEmpty()
+					rs.Resource().CopyTo(rsClone.Resource())
+					rsClone.SetSchemaUrl(rs.SchemaUrl())
+					ssClone := rsClone.ScopeSpans().AppendEmpty()
+					ss.Scope().CopyTo(ssClone.Scope())
+					ssClone.SetSchemaUrl(ss.SchemaUrl())
+					spans = ssClone.Spans()
+					spansByKey[key] = spans
+				}
+				span.CopyTo(spans.AppendEmpty())
+			}
+		}
+	}
+
+	return results, nil
+}
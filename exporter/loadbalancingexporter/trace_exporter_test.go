@@ -48,6 +48,22 @@ func TestNewTracesExporter(t *testing.T) {
 			&Config{},
 			errNoResolver,
 		},
+		{
+			"attributes without routing attributes",
+			&Config{
+				Resolver:   simpleConfig().Resolver,
+				RoutingKey: attrRoutingStr,
+			},
+			errNoRoutingAttributes,
+		},
+		{
+			"expression without routing expression",
+			&Config{
+				Resolver:   simpleConfig().Resolver,
+				RoutingKey: exprRoutingStr,
+			},
+			errNoRoutingExpression,
+		},
 	} {
 		t.Run(tt.desc, func(t *testing.T) {
 			// test
@@ -277,6 +293,64 @@ func TestServiceBasedRoutingForSameTraceId(t *testing.T) {
 	}
 }
 
+func TestSplitTracesByRoutingKey(t *testing.T) {
+	for _, tt := range []struct {
+		desc     string
+		config   *Config
+		expected map[string][]string
+	}{
+		{
+			"attributes",
+			&Config{
+				Resolver:          simpleConfig().Resolver,
+				RoutingKey:        attrRoutingStr,
+				RoutingAttributes: []string{"tenant", conventions.AttributeServiceName},
+			},
+			map[string][]string{
+				"tenant-1\x00service-1": {"span-1"},
+				"tenant-2\x00service-1": {"span-2", "span-3"},
+				"\x00service-2":         {"span-4"},
+			},
+		},
+		{
+			"expression",
+			&Config{
+				Resolver:          simpleConfig().Resolver,
+				RoutingKey:        exprRoutingStr,
+				RoutingExpression: `Concat([resource.attributes["service.name"], attributes["tenant"]], "/")`,
+			},
+			map[string][]string{
+				"service-1/tenant-1": {"span-1"},
+				"service-1/tenant-2": {"span-2", "span-3"},
+				"service-2/<nil>":    {"span-4"},
+			},
+		},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			p, err := newTracesExporter(exportertest.NewNopSettings(), tt.config)
+			require.NoError(t, err)
+
+			// test
+			batches, err := p.splitTracesByRoutingKey(context.Background(), tracesWithTenants())
+
+			// verify
+			require.NoError(t, err)
+			actual := map[string][]string{}
+			for key, td := range batches {
+				for i := 0; i < td.ResourceSpans().Len(); i++ {
+					ss := td.ResourceSpans().At(i).ScopeSpans()
+					for j := 0; j < ss.Len(); j++ {
+						for k := 0; k < ss.At(j).Spans().Len(); k++ {
+							actual[key] = append(actual[key], ss.At(j).Spans().At(k).Name())
+						}
+					}
+				}
+			}
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
 func TestConsumeTracesExporterNoEndpoint(t *testing.T) {
 	ts, tb := getTelemetryAssets(t)
 	componentFactory := func(_ context.Context, _ string) (component.Component, error) {
@@ -378,6 +452,20 @@ func TestBuildExporterConfig(t *testing.T) {
 	assert.Equal(t, defaultCfg.RetryConfig, exporterCfg.RetryConfig)
 }
 
+func TestBuildExporterConfigWithEjection(t *testing.T) {
+	// prepare
+	cfg := createDefaultConfig().(*Config)
+	cfg.Ejection.ConsecutiveFailures = 3
+
+	// test
+	exporterCfg := buildExporterConfig(cfg, "the-endpoint")
+
+	// verify
+	// the failed exports must be returned to the load balancer instead of being queued
+	assert.False(t, exporterCfg.QueueConfig.Enabled)
+	assert.True(t, cfg.Protocol.OTLP.QueueConfig.Enabled)
+}
+
 func TestBatchWithTwoTraces(t *testing.T) {
 	ts, tb := getTelemetryAssets(t)
 	sink := new(consumertest.TracesSink)
@@ -714,6 +802,25 @@ func simpleTracesWithServiceName() ptrace.Traces {
 	return traces
 }
 
+func tracesWithTenants() ptrace.Traces {
+	traces := ptrace.NewTraces()
+
+	rs1 := traces.ResourceSpans().AppendEmpty()
+	rs1.Resource().Attributes().PutStr(conventions.AttributeServiceName, "service-1")
+	spans := rs1.ScopeSpans().AppendEmpty().Spans()
+	for i, tenant := range []string{"tenant-1", "tenant-2", "tenant-2"} {
+		span := spans.AppendEmpty()
+		span.SetName(fmt.Sprintf("span-%d", i+1))
+		span.Attributes().PutStr("tenant", tenant)
+	}
+
+	rs2 := traces.ResourceSpans().AppendEmpty()
+	rs2.Resource().Attributes().PutStr(conventions.AttributeServiceName, "service-2")
+	rs2.ScopeSpans().AppendEmpty().Spans().AppendEmpty().SetName("span-4")
+
+	return traces
+}
+
 func twoServicesWithSameTraceID() ptrace.Traces {
 	traces := ptrace.NewTraces()
 	traces.ResourceSpans().EnsureCapacity(2)
@@ -13,18 +13,28 @@ type avroDeserializer interface {
 	Deserialize([]byte) (map[string]any, error)
 }
 
+type avroSerializer interface {
+	Serialize(map[string]any) ([]byte, error)
+}
+
 type avroStaticSchemaDeserializer struct {
 	codec *goavro.Codec
+	typ   *avroType
 }
 
-func newAVROStaticSchemaDeserializer(schema string) (avroDeserializer, error) {
+func newAVROStaticSchemaDeserializer(schema string) (*avroStaticSchemaDeserializer, error) {
 	codec, err := goavro.NewCodec(schema)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create avro codec: %w", err)
 	}
+	typ, err := parseAvroType(schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create avro codec: %w", err)
+	}
 
 	return &avroStaticSchemaDeserializer{
 		codec: codec,
+		typ:   typ,
 	}, nil
 }
 
@@ -36,3 +46,12 @@ func (d *avroStaticSchemaDeserializer) Deserialize(data []byte) (map[string]any,
 
 	return native.(map[string]any), nil
 }
+
+func (d *avroStaticSchemaDeserializer) Serialize(record map[string]any) ([]byte, error) {
+	data, err := d.codec.BinaryFromNative(nil, d.typ.nativeFromRaw(record))
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize avro record: %w", err)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,253 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package avrologencodingextension // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/encoding/avrologencodingextension"
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/linkedin/goavro/v2"
+)
+
+// avroType is an Avro schema, used to convert the values of log bodies as produced by UnmarshalLogs
+// back to the native values expected by goavro.
+type avroType struct {
+	// name is the name goavro gives to the type in unions: the name of a primitive type, followed by
+	// its logical type if it has one, e.g. long.timestamp-millis, the full name of a named type,
+	// or array, map and union.
+	name string
+	// kind is the primitive or complex type, e.g. long or record.
+	kind     string
+	fields   []avroField
+	items    *avroType
+	branches []*avroType
+}
+
+type avroField struct {
+	name string
+	typ  *avroType
+}
+
+var avroPrimitiveTypes = map[string]bool{
+	"null": true, "boolean": true, "int": true, "long": true,
+	"float": true, "double": true, "bytes": true, "string": true,
+}
+
+// avroLogicalTypes are the logical types decoded by goavro to values replaced by replaceLogicalTypes.
+var avroLogicalTypes = map[string]bool{
+	"long.timestamp-millis": true, "long.timestamp-micros": true, "int.date": true,
+	"int.time-millis": true, "long.time-micros": true,
+}
+
+// parseAvroType parses an Avro schema, which must be valid for goavro.
+func parseAvroType(schema string) (*avroType, error) {
+	var raw any
+	if err := json.Unmarshal([]byte(schema), &raw); err != nil {
+		return nil, fmt.Errorf("invalid avro schema: %w", err)
+	}
+	p := avroTypeParser{named: make(map[string]*avroType)}
+	return p.parse(raw, "")
+}
+
+type avroTypeParser struct {
+	// named holds the named types by full name, so that they can be referenced once defined.
+	named map[string]*avroType
+}
+
+func (p *avroTypeParser) parse(raw any, namespace string) (*avroType, error) {
+	switch schema := raw.(type) {
+	case string:
+		if avroPrimitiveTypes[schema] {
+			return &avroType{name: schema, kind: schema}, nil
+		}
+		if t, ok := p.named[avroFullName(schema, namespace)]; ok {
+			return t, nil
+		}
+		if t, ok := p.named[schema]; ok {
+			return t, nil
+		}
+		return nil, fmt.Errorf("unknown avro type %q", schema)
+	case []any:
+		t := &avroType{name: "union", kind: "union"}
+		for _, branch := range schema {
+			bt, err := p.parse(branch, namespace)
+			if err != nil {
+				return nil, err
+			}
+			t.branches = append(t.branches, bt)
+		}
+		return t, nil
+	case map[string]any:
+		return p.parseMap(schema, namespace)
+	default:
+		return nil, fmt.Errorf("invalid avro type %v", raw)
+	}
+}
+
+func (p *avroTypeParser) parseMap(schema map[string]any, namespace string) (*avroType, error) {
+	kind, _ := schema["type"].(string)
+	switch kind {
+	case "record", "error", "enum", "fixed":
+		name, _ := schema["name"].(string)
+		if ns, ok := schema["namespace"].(string); ok && !strings.Contains(name, ".") {
+			namespace = ns
+		}
+		fullName := avroFullName(name, namespace)
+		if i := strings.LastIndex(fullName, "."); i >= 0 {
+			namespace = fullName[:i]
+		}
+		t := &avroType{name: fullName, kind: kind}
+		p.named[fullName] = t
+		fields, _ := schema["fields"].([]any)
+		for _, f := range fields {
+			field, ok := f.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("invalid field of avro record %q", fullName)
+			}
+			fieldName, _ := field["name"].(string)
+			// the logical type of a primitive type can be set on the field itself
+			var ft *avroType
+			var err error
+			if _, ok = field["type"].(string); ok {
+				ft, err = p.parseMap(field, namespace)
+			} else {
+				ft, err = p.parse(field["type"], namespace)
+			}
+			if err != nil {
+				return nil, err
+			}
+			t.fields = append(t.fields, avroField{name: fieldName, typ: ft})
+		}
+		return t, nil
+	case "array", "map":
+		key := "items"
+		if kind == "map" {
+			key = "values"
+		}
+		items, err := p.parse(schema[key], namespace)
+		if err != nil {
+			return nil, err
+		}
+		return &avroType{name: kind, kind: kind, items: items}, nil
+	default:
+		t, err := p.parse(schema["type"], namespace)
+		if err != nil {
+			return nil, err
+		}
+		if logicalType, ok := schema["logicalType"].(string); ok && avroLogicalTypes[t.kind+"."+logicalType] {
+			return &avroType{name: t.kind + "." + logicalType, kind: t.kind}, nil
+		}
+		return t, nil
+	}
+}
+
+func avroFullName(name, namespace string) string {
+	if namespace == "" || strings.Contains(name, ".") {
+		return name
+	}
+	return namespace + "." + name
+}
+
+// nativeFromRaw converts a value of a log body to the native value of the type: the logical types
+// replaced by replaceLogicalTypes are restored, and the values of unions which are not wrapped in a
+// map keyed by the name of their type, e.g. when set by a processor, are wrapped. The values which
+// don't match the type are returned as is, for goavro to report them.
+func (t *avroType) nativeFromRaw(value any) any {
+	switch t.kind {
+	case "union":
+		return t.unionFromRaw(value)
+	case "record", "error":
+		record, ok := value.(map[string]any)
+		if !ok {
+			return value
+		}
+		native := make(map[string]any, len(record))
+		for k, v := range record {
+			native[k] = v
+		}
+		for _, f := range t.fields {
+			if v, ok := record[f.name]; ok {
+				native[f.name] = f.typ.nativeFromRaw(v)
+			}
+		}
+		return native
+	case "array":
+		array, ok := value.([]any)
+		if !ok {
+			return value
+		}
+		native := make([]any, len(array))
+		for i, v := range array {
+			native[i] = t.items.nativeFromRaw(v)
+		}
+		return native
+	case "map":
+		m, ok := value.(map[string]any)
+		if !ok {
+			return value
+		}
+		native := make(map[string]any, len(m))
+		for k, v := range m {
+			native[k] = t.items.nativeFromRaw(v)
+		}
+		return native
+	}
+
+	nanos, ok := value.(int64)
+	if !ok {
+		return value
+	}
+	switch t.name {
+	case "long.timestamp-millis", "long.timestamp-micros", "int.date":
+		return time.Unix(0, nanos).UTC()
+	case "int.time-millis", "long.time-micros":
+		return time.Duration(nanos)
+	}
+	return value
+}
+
+func (t *avroType) unionFromRaw(value any) any {
+	if value == nil {
+		return nil
+	}
+	if wrapped, ok := value.(map[string]any); ok && len(wrapped) == 1 {
+		for name, v := range wrapped {
+			for _, branch := range t.branches {
+				if branch.name == name {
+					return goavro.Union(name, branch.nativeFromRaw(v))
+				}
+			}
+		}
+	}
+	for _, branch := range t.branches {
+		if branch.accepts(value) {
+			return goavro.Union(branch.name, branch.nativeFromRaw(value))
+		}
+	}
+	return value
+}
+
+// accepts returns whether the value of a log body can be of the type.
+func (t *avroType) accepts(value any) bool {
+	switch value.(type) {
+	case bool:
+		return t.kind == "boolean"
+	case int64:
+		return t.kind == "int" || t.kind == "long"
+	case float64:
+		return t.kind == "float" || t.kind == "double"
+	case string:
+		return t.kind == "string" || t.kind == "enum"
+	case []byte:
+		return t.kind == "bytes" || t.kind == "fixed"
+	case []any:
+		return t.kind == "array"
+	case map[string]any:
+		return t.kind == "map" || t.kind == "record" || t.kind == "error"
+	default:
+		return false
+	}
+}
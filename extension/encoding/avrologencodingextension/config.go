@@ -3,15 +3,63 @@
 
 package avrologencodingextension // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/encoding/avrologencodingextension"
 
-import "errors"
+import (
+	"errors"
+	"time"
 
-var errNoSchema = errors.New("no schema provided")
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/config/configopaque"
+)
+
+var (
+	errNoSchema                = errors.New("no schema provided")
+	errSchemaAndRegistry       = errors.New("schema and schema_registry cannot be used together")
+	errNoRegistryEndpoint      = errors.New("no schema_registry endpoint provided")
+	errNegativeRegistryTimeout = errors.New("schema_registry timeout must not be negative")
+	errNegativeLatestTTL       = errors.New("schema_registry latest_ttl must not be negative")
+)
 
 type Config struct {
 	Schema string `mapstructure:"schema"`
+
+	// SchemaRegistry resolves the schemas of payloads in the schema registry wire format,
+	// instead of using a static schema.
+	SchemaRegistry *SchemaRegistryConfig `mapstructure:"schema_registry"`
+}
+
+// SchemaRegistryConfig configures the schema registry holding the schemas of the payloads.
+type SchemaRegistryConfig struct {
+	// ClientConfig configures the HTTP client of the schema registry: its endpoint, e.g.
+	// http://localhost:8081, TLS settings and timeout. The default timeout is 10s.
+	confighttp.ClientConfig `mapstructure:",squash"`
+	// Subject is the subject whose latest schema is used to marshal logs.
+	// It is not required to unmarshal logs.
+	Subject string `mapstructure:"subject"`
+	// Username and Password are the credentials used for basic authentication, if set.
+	Username string              `mapstructure:"username"`
+	Password configopaque.String `mapstructure:"password"`
+	// LatestTTL is how long the latest schema of the subject is used before being resolved again,
+	// so that new schema versions are picked up. Default is 5m.
+	LatestTTL time.Duration `mapstructure:"latest_ttl"`
 }
 
 func (c *Config) Validate() error {
+	if c.SchemaRegistry != nil {
+		if c.Schema != "" {
+			return errSchemaAndRegistry
+		}
+		if c.SchemaRegistry.Endpoint == "" {
+			return errNoRegistryEndpoint
+		}
+		if c.SchemaRegistry.Timeout < 0 {
+			return errNegativeRegistryTimeout
+		}
+		if c.SchemaRegistry.LatestTTL < 0 {
+			return errNegativeLatestTTL
+		}
+		return nil
+	}
+
 	if c.Schema == "" {
 		return errNoSchema
 	}
@@ -5,8 +5,10 @@ package avrologencodingextension
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/config/confighttp"
 )
 
 func TestConfigValidate(t *testing.T) {
@@ -17,4 +19,25 @@ func TestConfigValidate(t *testing.T) {
 	cfg.Schema = "schema1"
 	err = cfg.Validate()
 	assert.NoError(t, err)
+
+	cfg.SchemaRegistry = &SchemaRegistryConfig{ClientConfig: confighttp.ClientConfig{Endpoint: "http://localhost:8081"}}
+	err = cfg.Validate()
+	assert.ErrorIs(t, err, errSchemaAndRegistry)
+
+	cfg.Schema = ""
+	err = cfg.Validate()
+	assert.NoError(t, err)
+
+	cfg.SchemaRegistry.Timeout = -time.Second
+	err = cfg.Validate()
+	assert.ErrorIs(t, err, errNegativeRegistryTimeout)
+
+	cfg.SchemaRegistry.Timeout = 0
+	cfg.SchemaRegistry.LatestTTL = -time.Second
+	err = cfg.Validate()
+	assert.ErrorIs(t, err, errNegativeLatestTTL)
+
+	cfg.SchemaRegistry = &SchemaRegistryConfig{}
+	err = cfg.Validate()
+	assert.ErrorIs(t, err, errNoRegistryEndpoint)
 }
@@ -5,6 +5,7 @@ package avrologencodingextension // import "github.com/open-telemetry/openteleme
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -17,19 +18,31 @@ import (
 
 var (
 	_ encoding.LogsUnmarshalerExtension = (*avroLogExtension)(nil)
+	_ encoding.LogsMarshalerExtension   = (*avroLogExtension)(nil)
 )
 
+var errNotSingleLogRecord = errors.New("exactly one log record with a map body can be marshaled")
+
 type avroLogExtension struct {
 	deserializer avroDeserializer
+	serializer   avroSerializer
+	// registry is set when the schemas are resolved from a schema registry.
+	registry *schemaRegistry
 }
 
-func newExtension(config *Config) (*avroLogExtension, error) {
+func newExtension(config *Config, settings component.TelemetrySettings) (*avroLogExtension, error) {
+	if config.SchemaRegistry != nil {
+		registry := newSchemaRegistry(config.SchemaRegistry, settings)
+		codec := &schemaRegistryCodec{registry: registry}
+		return &avroLogExtension{deserializer: codec, serializer: codec, registry: registry}, nil
+	}
+
 	deserializer, err := newAVROStaticSchemaDeserializer(config.Schema)
 	if err != nil {
 		return nil, err
 	}
 
-	return &avroLogExtension{deserializer: deserializer}, nil
+	return &avroLogExtension{deserializer: deserializer, serializer: deserializer}, nil
 }
 
 func (e *avroLogExtension) UnmarshalLogs(buf []byte) (plog.Logs, error) {
@@ -43,7 +56,8 @@ func (e *avroLogExtension) UnmarshalLogs(buf []byte) (plog.Logs, error) {
 	logRecords := p.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
 	logRecords.SetObservedTimestamp(pcommon.NewTimestampFromTime(time.Now()))
 
-	// removes time.Time values as FromRaw does not support it
+	// replaces time.Time and time.Duration values, which FromRaw does not support, by their nanoseconds.
+	// They are restored by the serializers from the schema.
 	replaceLogicalTypes(avroLog)
 
 	// Set the unmarshaled avro as the body of the log record
@@ -54,6 +68,31 @@ func (e *avroLogExtension) UnmarshalLogs(buf []byte) (plog.Logs, error) {
 	return p, nil
 }
 
+// MarshalLogs serializes the map body of a single log record, as produced by UnmarshalLogs.
+func (e *avroLogExtension) MarshalLogs(ld plog.Logs) ([]byte, error) {
+	if ld.LogRecordCount() != 1 {
+		return nil, errNotSingleLogRecord
+	}
+	var body pcommon.Value
+	for i := 0; i < ld.ResourceLogs().Len(); i++ {
+		sls := ld.ResourceLogs().At(i).ScopeLogs()
+		for j := 0; j < sls.Len(); j++ {
+			if records := sls.At(j).LogRecords(); records.Len() > 0 {
+				body = records.At(0).Body()
+			}
+		}
+	}
+	if body.Type() != pcommon.ValueTypeMap {
+		return nil, errNotSingleLogRecord
+	}
+
+	data, err := e.serializer.Serialize(body.Map().AsRaw())
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize avro log: %w", err)
+	}
+	return data, nil
+}
+
 func replaceLogicalTypes(m map[string]any) {
 	for k, v := range m {
 		m[k] = transformValue(v)
@@ -65,6 +104,10 @@ func transformValue(value any) any {
 		return timeValue.UnixNano()
 	}
 
+	if durationValue, ok := value.(time.Duration); ok {
+		return int64(durationValue)
+	}
+
 	if mapValue, ok := value.(map[string]any); ok {
 		replaceLogicalTypes(mapValue)
 		return mapValue
@@ -80,7 +123,10 @@ func transformValue(value any) any {
 	return value
 }
 
-func (e *avroLogExtension) Start(_ context.Context, _ component.Host) error {
+func (e *avroLogExtension) Start(ctx context.Context, host component.Host) error {
+	if e.registry != nil {
+		return e.registry.start(ctx, host)
+	}
 	return nil
 }
 
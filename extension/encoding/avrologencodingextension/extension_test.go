@@ -7,9 +7,11 @@ import (
 	"context"
 	"testing"
 
+	"github.com/linkedin/goavro/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/pdata/plog"
 )
 
 func TestExtension_Start_Shutdown(t *testing.T) {
@@ -27,7 +29,7 @@ func TestUnmarshal(t *testing.T) {
 
 	schema, data := createAVROTestData(t)
 
-	e, err := newExtension(&Config{Schema: schema})
+	e, err := newExtension(&Config{Schema: schema}, componenttest.NewNopTelemetrySettings())
 	assert.NoError(t, err)
 
 	logs, err := e.UnmarshalLogs(data)
@@ -45,9 +47,103 @@ func TestInvalidUnmarshal(t *testing.T) {
 		t.Fatalf("Failed to read avro schema file: %q", err.Error())
 	}
 
-	e, err := newExtension(&Config{Schema: string(schema)})
+	e, err := newExtension(&Config{Schema: string(schema)}, componenttest.NewNopTelemetrySettings())
 	assert.NoError(t, err)
 
 	_, err = e.UnmarshalLogs([]byte("NOT A AVRO"))
 	assert.Error(t, err)
 }
+
+func TestMarshal(t *testing.T) {
+	t.Parallel()
+
+	schema := `{
+		"type": "record",
+		"name": "Log",
+		"fields": [
+			{ "name": "message", "type": "string" },
+			{ "name": "count", "type": "long" }
+		]
+	}`
+	e, err := newExtension(&Config{Schema: schema}, componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+
+	logs := plog.NewLogs()
+	require.NoError(t, logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetEmptyMap().FromRaw(
+		map[string]any{"message": "log message", "count": int64(5)}))
+
+	data, err := e.MarshalLogs(logs)
+	require.NoError(t, err)
+
+	logs, err = e.UnmarshalLogs(data)
+	require.NoError(t, err)
+	assert.Equal(t, "{\"count\":5,\"message\":\"log message\"}", logs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Body().AsString())
+
+	logs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().AppendEmpty()
+	_, err = e.MarshalLogs(logs)
+	assert.ErrorIs(t, err, errNotSingleLogRecord)
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	schema := `{
+		"type": "record",
+		"name": "Event",
+		"namespace": "example",
+		"fields": [
+			{ "name": "timestamp", "type": { "type": "long", "logicalType": "timestamp-millis" } },
+			{ "name": "micros", "type": "long", "logicalType": "timestamp-micros" },
+			{ "name": "date", "type": { "type": "int", "logicalType": "date" } },
+			{ "name": "time", "type": { "type": "int", "logicalType": "time-millis" } },
+			{ "name": "user", "type": ["null", "string"] },
+			{ "name": "count", "type": ["null", "int", "double"] },
+			{ "name": "source", "type": ["null", {
+				"type": "record",
+				"name": "Source",
+				"fields": [
+					{ "name": "host", "type": "string" },
+					{ "name": "seen", "type": ["null", { "type": "long", "logicalType": "timestamp-millis" }] }
+				]
+			}] },
+			{ "name": "tags", "type": { "type": "array", "items": ["null", "string"] } },
+			{ "name": "sources", "type": { "type": "map", "values": "Source" } }
+		]
+	}`
+	codec, err := goavro.NewCodec(schema)
+	require.NoError(t, err)
+	data := encodeAVROLogTestData(codec, `{
+		"timestamp": 1697187201488,
+		"micros": 1697187201488123,
+		"date": 19643,
+		"time": 3723004,
+		"user": { "string": "alice" },
+		"count": { "int": 5 },
+		"source": { "example.Source": { "host": "host1", "seen": { "long.timestamp-millis": 1697187201488 } } },
+		"tags": [null, { "string": "a" }],
+		"sources": { "backup": { "host": "host2", "seen": null } }
+	}`)
+	require.NotEmpty(t, data)
+
+	e, err := newExtension(&Config{Schema: schema}, componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+	logs, err := e.UnmarshalLogs(data)
+	require.NoError(t, err)
+	marshaled, err := e.MarshalLogs(logs)
+	require.NoError(t, err)
+	assert.Equal(t, data, marshaled)
+
+	// union values set without their type name are wrapped
+	body := logs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Body().Map()
+	body.PutStr("user", "bob")
+	body.PutDouble("count", 2.5)
+	marshaled, err = e.MarshalLogs(logs)
+	require.NoError(t, err)
+	logs, err = e.UnmarshalLogs(marshaled)
+	require.NoError(t, err)
+	body = logs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Body().Map()
+	user, _ := body.Get("user")
+	assert.Equal(t, map[string]any{"string": "bob"}, user.Map().AsRaw())
+	count, _ := body.Get("count")
+	assert.Equal(t, map[string]any{"double": 2.5}, count.Map().AsRaw())
+}
@@ -21,8 +21,8 @@ func NewFactory() extension.Factory {
 	)
 }
 
-func createExtension(_ context.Context, _ extension.Settings, config component.Config) (extension.Extension, error) {
-	return newExtension(config.(*Config))
+func createExtension(_ context.Context, settings extension.Settings, config component.Config) (extension.Extension, error) {
+	return newExtension(config.(*Config), settings.TelemetrySettings)
 }
 
 func createDefaultConfig() component.Config {
@@ -0,0 +1,393 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package avrologencodingextension // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/encoding/avrologencodingextension"
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/linkedin/goavro/v2"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"golang.org/x/sync/singleflight"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	// Register the well-known types which may be imported by the Protobuf schemas.
+	_ "google.golang.org/protobuf/types/known/anypb"
+	_ "google.golang.org/protobuf/types/known/durationpb"
+	_ "google.golang.org/protobuf/types/known/emptypb"
+	_ "google.golang.org/protobuf/types/known/structpb"
+	_ "google.golang.org/protobuf/types/known/timestamppb"
+	_ "google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+const (
+	schemaTypeAvro     = "AVRO"
+	schemaTypeProtobuf = "PROTOBUF"
+
+	defaultSchemaRegistryTimeout = 10 * time.Second
+	defaultLatestSchemaTTL       = 5 * time.Minute
+
+	// failedLookupTTL is how long a failed lookup is returned again without querying the registry.
+	failedLookupTTL = 30 * time.Second
+	// maxFailedLookups bounds the number of failed lookups which are remembered.
+	maxFailedLookups = 1024
+
+	// maxSchemaReferenceDepth bounds the resolution of the references between schemas.
+	maxSchemaReferenceDepth = 32
+)
+
+// registrySchema is a schema as returned by the schema registry REST API.
+type registrySchema struct {
+	ID         int                 `json:"id"`
+	Version    int                 `json:"version"`
+	SchemaType string              `json:"schemaType"`
+	Schema     string              `json:"schema"`
+	References []registryReference `json:"references"`
+}
+
+type registryReference struct {
+	Name    string `json:"name"`
+	Subject string `json:"subject"`
+	Version int    `json:"version"`
+}
+
+// registeredSchema is a compiled schema of the registry. Exactly one of avro and proto is set, avroType
+// being set with avro.
+type registeredSchema struct {
+	id       int
+	avro     *goavro.Codec
+	avroType *avroType
+	proto    protoreflect.FileDescriptor
+}
+
+// schemaRegistry resolves schemas from a Confluent compatible schema registry. Schemas resolved by
+// ID are immutable in the registry, so they are cached for the lifetime of the extension, while the
+// latest schema of the subject is refreshed once its TTL expired. Concurrent lookups of the same
+// schema share a single request, and failed lookups are not retried before failedLookupTTL.
+type schemaRegistry struct {
+	clientConfig confighttp.ClientConfig
+	settings     component.TelemetrySettings
+	endpoint     string
+	subject      string
+	username     string
+	password     string
+	latestTTL    time.Duration
+	// client is created by start.
+	client *http.Client
+	now    func() time.Time
+
+	lookups singleflight.Group
+
+	mu            sync.Mutex
+	byID          map[int]*registeredSchema
+	latest        *registeredSchema
+	latestExpires time.Time
+	failed        map[string]failedLookup
+}
+
+// failedLookup is the error of a lookup, returned again until expires.
+type failedLookup struct {
+	err     error
+	expires time.Time
+}
+
+func newSchemaRegistry(cfg *SchemaRegistryConfig, settings component.TelemetrySettings) *schemaRegistry {
+	latestTTL := cfg.LatestTTL
+	if latestTTL == 0 {
+		latestTTL = defaultLatestSchemaTTL
+	}
+	return &schemaRegistry{
+		clientConfig: cfg.ClientConfig,
+		settings:     settings,
+		endpoint:     strings.TrimSuffix(cfg.Endpoint, "/"),
+		subject:      cfg.Subject,
+		username:     cfg.Username,
+		password:     string(cfg.Password),
+		latestTTL:    latestTTL,
+		now:          time.Now,
+		byID:         make(map[int]*registeredSchema),
+		failed:       make(map[string]failedLookup),
+	}
+}
+
+// start creates the HTTP client of the schema registry.
+func (r *schemaRegistry) start(ctx context.Context, host component.Host) error {
+	client, err := r.clientConfig.ToClient(ctx, host, r.settings)
+	if err != nil {
+		return fmt.Errorf("failed to create the schema registry client: %w", err)
+	}
+	if client.Timeout == 0 {
+		client.Timeout = defaultSchemaRegistryTimeout
+	}
+	r.client = client
+	return nil
+}
+
+// schemaByID returns the schema with the given ID.
+func (r *schemaRegistry) schemaByID(id int) (*registeredSchema, error) {
+	r.mu.Lock()
+	s, ok := r.byID[id]
+	r.mu.Unlock()
+	if ok {
+		return s, nil
+	}
+
+	return r.lookup("id/"+strconv.Itoa(id), func() (*registeredSchema, error) {
+		path := fmt.Sprintf("/schemas/ids/%d", id)
+		s, err := r.resolve(path)
+		if err != nil {
+			return nil, err
+		}
+		s.id = id
+		r.mu.Lock()
+		r.byID[id] = s
+		r.mu.Unlock()
+		return s, nil
+	})
+}
+
+// latestSchema returns the latest schema of the configured subject, which is used to marshal logs.
+func (r *schemaRegistry) latestSchema() (*registeredSchema, error) {
+	if r.subject == "" {
+		return nil, errors.New("no schema_registry subject provided to marshal logs")
+	}
+
+	r.mu.Lock()
+	latest := r.latest
+	fresh := latest != nil && r.now().Before(r.latestExpires)
+	r.mu.Unlock()
+	if fresh {
+		return latest, nil
+	}
+
+	s, err := r.lookup("latest", func() (*registeredSchema, error) {
+		rs, err := r.fetch("/subjects/" + url.PathEscape(r.subject) + "/versions/latest")
+		if err != nil {
+			return nil, err
+		}
+		r.mu.Lock()
+		s, ok := r.byID[rs.ID]
+		r.mu.Unlock()
+		if !ok {
+			// The latest schema changed, its ID identifies it from now on.
+			if s, err = r.schemaByID(rs.ID); err != nil {
+				return nil, err
+			}
+		}
+		r.mu.Lock()
+		r.latest = s
+		r.latestExpires = r.now().Add(r.latestTTL)
+		r.mu.Unlock()
+		return s, nil
+	})
+	if err != nil && latest != nil {
+		// Keep marshaling with the previous latest schema while the registry is unavailable.
+		return latest, nil
+	}
+	return s, err
+}
+
+// lookup resolves a schema once for all the concurrent callers using the same key, without holding
+// the lock during the requests. The errors are remembered for failedLookupTTL.
+func (r *schemaRegistry) lookup(key string, resolve func() (*registeredSchema, error)) (*registeredSchema, error) {
+	r.mu.Lock()
+	failed, ok := r.failed[key]
+	r.mu.Unlock()
+	if ok && r.now().Before(failed.expires) {
+		return nil, failed.err
+	}
+
+	v, err, _ := r.lookups.Do(key, func() (any, error) {
+		s, err := resolve()
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if err != nil {
+			r.rememberFailure(key, err)
+			return nil, err
+		}
+		delete(r.failed, key)
+		return s, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*registeredSchema), nil
+}
+
+// rememberFailure records a failed lookup, dropping the expired ones when too many are remembered.
+// It must be called with mu held.
+func (r *schemaRegistry) rememberFailure(key string, err error) {
+	now := r.now()
+	if len(r.failed) >= maxFailedLookups {
+		for k, f := range r.failed {
+			if !now.Before(f.expires) {
+				delete(r.failed, k)
+			}
+		}
+	}
+	if len(r.failed) >= maxFailedLookups {
+		// Forget an arbitrary failure rather than growing without bounds.
+		for k := range r.failed {
+			delete(r.failed, k)
+			break
+		}
+	}
+	r.failed[key] = failedLookup{err: err, expires: now.Add(failedLookupTTL)}
+}
+
+// resolve fetches and compiles the schema available at the given path of the registry.
+func (r *schemaRegistry) resolve(path string) (*registeredSchema, error) {
+	rs, err := r.fetch(path)
+	if err != nil {
+		return nil, err
+	}
+	if rs.SchemaType == schemaTypeProtobuf {
+		if rs, err = r.fetch(path + "?format=serialized"); err != nil {
+			return nil, err
+		}
+	}
+	s, err := r.compile(rs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile schema %d: %w", rs.ID, err)
+	}
+	return s, nil
+}
+
+func (r *schemaRegistry) compile(rs *registrySchema) (*registeredSchema, error) {
+	switch rs.SchemaType {
+	case "", schemaTypeAvro:
+		if len(rs.References) > 0 {
+			return nil, errors.New("avro schema references are not supported")
+		}
+		codec, err := goavro.NewCodec(rs.Schema)
+		if err != nil {
+			return nil, err
+		}
+		typ, err := parseAvroType(rs.Schema)
+		if err != nil {
+			return nil, err
+		}
+		return &registeredSchema{avro: codec, avroType: typ}, nil
+	case schemaTypeProtobuf:
+		files := &protoregistry.Files{}
+		for _, ref := range rs.References {
+			if err := r.registerProtoReference(files, ref, 0); err != nil {
+				return nil, err
+			}
+		}
+		fd, err := newProtoFile(rs.Schema, files)
+		if err != nil {
+			return nil, err
+		}
+		return &registeredSchema{proto: fd}, nil
+	default:
+		return nil, fmt.Errorf("unsupported schema type %q", rs.SchemaType)
+	}
+}
+
+// registerProtoReference registers the file referenced by a Protobuf schema, and the files it references.
+func (r *schemaRegistry) registerProtoReference(files *protoregistry.Files, ref registryReference, depth int) error {
+	if depth >= maxSchemaReferenceDepth {
+		return errors.New("too many nested schema references")
+	}
+	if _, err := files.FindFileByPath(ref.Name); err == nil {
+		return nil
+	}
+	if _, err := protoregistry.GlobalFiles.FindFileByPath(ref.Name); err == nil {
+		return nil
+	}
+
+	rs, err := r.fetch(fmt.Sprintf("/subjects/%s/versions/%d?format=serialized", url.PathEscape(ref.Subject), ref.Version))
+	if err != nil {
+		return fmt.Errorf("failed to resolve reference %q: %w", ref.Name, err)
+	}
+	for _, nested := range rs.References {
+		if err = r.registerProtoReference(files, nested, depth+1); err != nil {
+			return err
+		}
+	}
+	fd, err := newProtoFile(rs.Schema, files)
+	if err != nil {
+		return fmt.Errorf("failed to compile reference %q: %w", ref.Name, err)
+	}
+	return files.RegisterFile(fd)
+}
+
+func (r *schemaRegistry) fetch(path string) (*registrySchema, error) {
+	req, err := http.NewRequest(http.MethodGet, r.endpoint+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.schemaregistry.v1+json")
+	if r.username != "" {
+		req.SetBasicAuth(r.username, r.password)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query schema registry: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema registry response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("schema registry returned status %d for %s: %s", resp.StatusCode, path, strconv.Quote(string(body)))
+	}
+
+	var rs registrySchema
+	if err = json.Unmarshal(body, &rs); err != nil {
+		return nil, fmt.Errorf("failed to decode schema registry response: %w", err)
+	}
+	return &rs, nil
+}
+
+// newProtoFile builds the descriptor of a Protobuf schema in the serialized format, that is
+// a base64 encoded FileDescriptorProto.
+func newProtoFile(schema string, files *protoregistry.Files) (protoreflect.FileDescriptor, error) {
+	data, err := base64.StdEncoding.DecodeString(schema)
+	if err != nil {
+		return nil, fmt.Errorf("invalid serialized protobuf schema: %w", err)
+	}
+	var fdp descriptorpb.FileDescriptorProto
+	if err = proto.Unmarshal(data, &fdp); err != nil {
+		return nil, fmt.Errorf("invalid serialized protobuf schema: %w", err)
+	}
+	return protodesc.NewFile(&fdp, protoResolver{files: files})
+}
+
+// protoResolver resolves the files registered for a schema, and the well-known types.
+type protoResolver struct {
+	files *protoregistry.Files
+}
+
+func (r protoResolver) FindFileByPath(path string) (protoreflect.FileDescriptor, error) {
+	if fd, err := r.files.FindFileByPath(path); err == nil {
+		return fd, nil
+	}
+	return protoregistry.GlobalFiles.FindFileByPath(path)
+}
+
+func (r protoResolver) FindDescriptorByName(name protoreflect.FullName) (protoreflect.Descriptor, error) {
+	if d, err := r.files.FindDescriptorByName(name); err == nil {
+		return d, nil
+	}
+	return protoregistry.GlobalFiles.FindDescriptorByName(name)
+}
@@ -0,0 +1,331 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package avrologencodingextension
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+const (
+	testAvroSchemaID  = 1
+	testProtoSchemaID = 2
+
+	testAvroSchema = `{
+		"type": "record",
+		"name": "Log",
+		"fields": [
+			{ "name": "message", "type": "string" },
+			{ "name": "count", "type": "long" }
+		]
+	}`
+)
+
+func testProtoFile() *descriptorpb.FileDescriptorProto {
+	return &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("log.proto"),
+		Package: proto.String("example"),
+		Syntax:  proto.String("proto3"),
+		EnumType: []*descriptorpb.EnumDescriptorProto{{
+			Name: proto.String("Level"),
+			Value: []*descriptorpb.EnumValueDescriptorProto{
+				{Name: proto.String("DEBUG"), Number: proto.Int32(0)},
+				{Name: proto.String("INFO"), Number: proto.Int32(1)},
+			},
+		}},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Log"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				{Name: proto.String("message"), JsonName: proto.String("message"), Number: proto.Int32(1), Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()},
+				{Name: proto.String("count"), JsonName: proto.String("count"), Number: proto.Int32(2), Type: descriptorpb.FieldDescriptorProto_TYPE_INT64.Enum(), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()},
+				{Name: proto.String("tags"), JsonName: proto.String("tags"), Number: proto.Int32(3), Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(), Label: descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()},
+				{Name: proto.String("level"), JsonName: proto.String("level"), Number: proto.Int32(4), Type: descriptorpb.FieldDescriptorProto_TYPE_ENUM.Enum(), TypeName: proto.String(".example.Level"), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()},
+			},
+		}},
+	}
+}
+
+// newTestSchemaRegistry starts a schema registry stand-in serving an Avro schema under the
+// "avro-logs" subject and a Protobuf schema under the "proto-logs" subject.
+func newTestSchemaRegistry(t *testing.T) (*httptest.Server, *atomic.Int64) {
+	serialized, err := proto.Marshal(testProtoFile())
+	require.NoError(t, err)
+	avroSchema := map[string]any{"id": testAvroSchemaID, "version": 1, "schema": testAvroSchema}
+	protoSchema := map[string]any{"id": testProtoSchemaID, "version": 1, "schemaType": "PROTOBUF", "schema": `syntax = "proto3"; package example; enum Level { DEBUG = 0; INFO = 1; } message Log { string message = 1; int64 count = 2; repeated string tags = 3; Level level = 4; }`}
+	serializedProtoSchema := map[string]any{"id": testProtoSchemaID, "version": 1, "schemaType": "PROTOBUF", "schema": base64.StdEncoding.EncodeToString(serialized)}
+
+	requests := &atomic.Int64{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		var resp map[string]any
+		switch r.URL.Path {
+		case "/schemas/ids/1", "/subjects/avro-logs/versions/latest":
+			resp = avroSchema
+		case "/schemas/ids/2", "/subjects/proto-logs/versions/latest":
+			resp = protoSchema
+			if r.URL.Query().Get("format") == "serialized" {
+				resp = serializedProtoSchema
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error_code":40403,"message":"Schema not found"}`))
+			return
+		}
+		assert.NoError(t, json.NewEncoder(w).Encode(resp))
+	}))
+	t.Cleanup(server.Close)
+	return server, requests
+}
+
+func newStartedExtension(t *testing.T, cfg *SchemaRegistryConfig) *avroLogExtension {
+	e, err := newExtension(&Config{SchemaRegistry: cfg}, componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+	require.NoError(t, e.Start(context.Background(), componenttest.NewNopHost()))
+	return e
+}
+
+func newStartedSchemaRegistry(t *testing.T, cfg *SchemaRegistryConfig) *schemaRegistry {
+	registry := newSchemaRegistry(cfg, componenttest.NewNopTelemetrySettings())
+	require.NoError(t, registry.start(context.Background(), componenttest.NewNopHost()))
+	return registry
+}
+
+func wireFormat(id int, payload []byte) []byte {
+	data := make([]byte, wireFormatHeaderSize)
+	binary.BigEndian.PutUint32(data[1:], uint32(id))
+	return append(data, payload...)
+}
+
+func TestSchemaRegistryAvro(t *testing.T) {
+	server, requests := newTestSchemaRegistry(t)
+	e := newStartedExtension(t, &SchemaRegistryConfig{ClientConfig: confighttp.ClientConfig{Endpoint: server.URL}, Subject: "avro-logs"})
+
+	codec, err := newAVROStaticSchemaDeserializer(testAvroSchema)
+	require.NoError(t, err)
+	payload, err := codec.Serialize(map[string]any{"message": "log message", "count": int64(5)})
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		logs, err := e.UnmarshalLogs(wireFormat(testAvroSchemaID, payload))
+		require.NoError(t, err)
+		body := logs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Body()
+		assert.Equal(t, map[string]any{"message": "log message", "count": int64(5)}, body.Map().AsRaw())
+	}
+	// the schema is cached
+	assert.Equal(t, int64(1), requests.Load())
+
+	logs := plog.NewLogs()
+	require.NoError(t, logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetEmptyMap().FromRaw(
+		map[string]any{"message": "other message", "count": int64(7)}))
+	data, err := e.MarshalLogs(logs)
+	require.NoError(t, err)
+	assert.Equal(t, wireFormat(testAvroSchemaID, mustSerialize(t, codec, map[string]any{"message": "other message", "count": int64(7)})), data)
+}
+
+func mustSerialize(t *testing.T, s avroSerializer, record map[string]any) []byte {
+	data, err := s.Serialize(record)
+	require.NoError(t, err)
+	return data
+}
+
+func TestSchemaRegistryProtobuf(t *testing.T) {
+	server, requests := newTestSchemaRegistry(t)
+	e := newStartedExtension(t, &SchemaRegistryConfig{ClientConfig: confighttp.ClientConfig{Endpoint: server.URL}, Subject: "proto-logs"})
+
+	fd, err := protodesc.NewFile(testProtoFile(), nil)
+	require.NoError(t, err)
+	msg := dynamicpb.NewMessage(fd.Messages().Get(0))
+	msg.Set(fd.Messages().Get(0).Fields().ByName("message"), protoreflect.ValueOfString("log message"))
+	msg.Set(fd.Messages().Get(0).Fields().ByName("count"), protoreflect.ValueOfInt64(5))
+	msg.Set(fd.Messages().Get(0).Fields().ByName("level"), protoreflect.ValueOfEnum(1))
+	tags := msg.Mutable(fd.Messages().Get(0).Fields().ByName("tags")).List()
+	tags.Append(protoreflect.ValueOfString("a"))
+	tags.Append(protoreflect.ValueOfString("b"))
+	payload, err := proto.Marshal(msg)
+	require.NoError(t, err)
+
+	expected := map[string]any{
+		"message": "log message",
+		"count":   int64(5),
+		"tags":    []any{"a", "b"},
+		"level":   "INFO",
+	}
+	// message indexes [0] are written as a single 0
+	logs, err := e.UnmarshalLogs(wireFormat(testProtoSchemaID, append([]byte{0}, payload...)))
+	require.NoError(t, err)
+	body := logs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Body()
+	assert.Equal(t, expected, body.Map().AsRaw())
+
+	// message indexes [0] written explicitly, as a count of 1 followed by the index 0
+	logs, err = e.UnmarshalLogs(wireFormat(testProtoSchemaID, append([]byte{2, 0}, payload...)))
+	require.NoError(t, err)
+	assert.Equal(t, expected, logs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Body().Map().AsRaw())
+	assert.Equal(t, int64(2), requests.Load())
+
+	data, err := e.MarshalLogs(logs)
+	require.NoError(t, err)
+	logs, err = e.UnmarshalLogs(data)
+	require.NoError(t, err)
+	assert.Equal(t, expected, logs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Body().Map().AsRaw())
+}
+
+func TestSchemaRegistryErrors(t *testing.T) {
+	server, _ := newTestSchemaRegistry(t)
+	e := newStartedExtension(t, &SchemaRegistryConfig{ClientConfig: confighttp.ClientConfig{Endpoint: server.URL}})
+
+	_, err := e.UnmarshalLogs([]byte{1, 2})
+	assert.ErrorIs(t, err, errInvalidWireFormat)
+
+	_, err = e.UnmarshalLogs(wireFormat(42, []byte{0}))
+	assert.ErrorContains(t, err, "status 404")
+
+	_, err = e.UnmarshalLogs(wireFormat(testProtoSchemaID, []byte{4, 0}))
+	assert.ErrorIs(t, err, errInvalidWireFormat)
+
+	_, err = e.UnmarshalLogs(wireFormat(testProtoSchemaID, []byte{2, 4}))
+	assert.ErrorContains(t, err, "out of range")
+
+	// a subject is required to marshal logs
+	logs := plog.NewLogs()
+	logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetEmptyMap()
+	_, err = e.MarshalLogs(logs)
+	assert.Error(t, err)
+
+	// a single log record is marshaled
+	_, err = e.MarshalLogs(plog.NewLogs())
+	assert.ErrorIs(t, err, errNotSingleLogRecord)
+}
+
+func TestSchemaRegistryLatestTTL(t *testing.T) {
+	server, requests := newTestSchemaRegistry(t)
+	registry := newStartedSchemaRegistry(t, &SchemaRegistryConfig{ClientConfig: confighttp.ClientConfig{Endpoint: server.URL}, Subject: "avro-logs", LatestTTL: time.Minute})
+	now := time.Now()
+	registry.now = func() time.Time { return now }
+
+	s, err := registry.latestSchema()
+	require.NoError(t, err)
+	assert.Equal(t, testAvroSchemaID, s.id)
+	assert.Equal(t, int64(2), requests.Load())
+
+	// the latest schema is cached until its TTL expired, and its ID is not resolved again
+	_, err = registry.latestSchema()
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), requests.Load())
+	now = now.Add(time.Minute)
+	_, err = registry.latestSchema()
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), requests.Load())
+
+	// the previous latest schema is kept while the registry is unavailable
+	server.Close()
+	now = now.Add(time.Minute)
+	s, err = registry.latestSchema()
+	require.NoError(t, err)
+	assert.Equal(t, testAvroSchemaID, s.id)
+}
+
+func TestSchemaRegistryFailedLookups(t *testing.T) {
+	server, requests := newTestSchemaRegistry(t)
+	registry := newStartedSchemaRegistry(t, &SchemaRegistryConfig{ClientConfig: confighttp.ClientConfig{Endpoint: server.URL}})
+	now := time.Now()
+	registry.now = func() time.Time { return now }
+
+	_, err := registry.schemaByID(42)
+	assert.ErrorContains(t, err, "status 404")
+	_, err = registry.schemaByID(42)
+	assert.ErrorContains(t, err, "status 404")
+	assert.Equal(t, int64(1), requests.Load())
+
+	now = now.Add(failedLookupTTL)
+	_, err = registry.schemaByID(42)
+	assert.ErrorContains(t, err, "status 404")
+	assert.Equal(t, int64(2), requests.Load())
+
+	// the remembered failures are bounded
+	for id := 100; id < 100+2*maxFailedLookups; id++ {
+		_, err = registry.schemaByID(id)
+		assert.Error(t, err)
+	}
+	assert.Len(t, registry.failed, maxFailedLookups)
+}
+
+func TestSchemaRegistryConcurrentLookups(t *testing.T) {
+	release := make(chan struct{})
+	requests := &atomic.Int64{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		<-release
+		assert.NoError(t, json.NewEncoder(w).Encode(map[string]any{"id": testAvroSchemaID, "schema": testAvroSchema}))
+	}))
+	t.Cleanup(server.Close)
+	registry := newStartedSchemaRegistry(t, &SchemaRegistryConfig{ClientConfig: confighttp.ClientConfig{Endpoint: server.URL}})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := registry.schemaByID(testAvroSchemaID)
+			assert.NoError(t, err)
+		}()
+	}
+	// the cached schemas remain available while a lookup is in progress
+	registry.mu.Lock()
+	registry.byID[3] = &registeredSchema{id: 3}
+	registry.mu.Unlock()
+	s, err := registry.schemaByID(3)
+	require.NoError(t, err)
+	assert.Equal(t, 3, s.id)
+
+	assert.Eventually(t, func() bool { return requests.Load() > 0 }, 5*time.Second, 10*time.Millisecond)
+	time.Sleep(100 * time.Millisecond) // let the other lookups wait for the request in progress
+	close(release)
+	wg.Wait()
+	assert.Equal(t, int64(1), requests.Load())
+}
+
+func TestSchemaRegistryTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		assert.NoError(t, json.NewEncoder(w).Encode(map[string]any{"id": testAvroSchemaID, "schema": testAvroSchema}))
+	}))
+	t.Cleanup(server.Close)
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0o600))
+
+	// the certificate of the registry is not trusted without its CA
+	registry := newStartedSchemaRegistry(t, &SchemaRegistryConfig{ClientConfig: confighttp.ClientConfig{Endpoint: server.URL}})
+	_, err := registry.schemaByID(testAvroSchemaID)
+	assert.ErrorContains(t, err, "certificate")
+
+	registry = newStartedSchemaRegistry(t, &SchemaRegistryConfig{ClientConfig: confighttp.ClientConfig{
+		Endpoint:   server.URL,
+		TLSSetting: configtls.ClientConfig{Config: configtls.Config{CAFile: caFile}},
+	}})
+	s, err := registry.schemaByID(testAvroSchemaID)
+	require.NoError(t, err)
+	assert.Equal(t, testAvroSchemaID, s.id)
+}
@@ -0,0 +1,210 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package avrologencodingextension // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/encoding/avrologencodingextension"
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+const (
+	wireFormatMagicByte  byte = 0
+	wireFormatHeaderSize      = 5
+)
+
+var errInvalidWireFormat = errors.New("invalid schema registry wire format")
+
+// schemaRegistryCodec reads and writes payloads in the schema registry wire format: a magic byte and
+// the big-endian schema ID, followed by the message indexes for Protobuf, and the encoded record.
+// Both Avro and Protobuf schemas are supported, the schema type being given by the registry.
+type schemaRegistryCodec struct {
+	registry *schemaRegistry
+}
+
+var (
+	_ avroDeserializer = (*schemaRegistryCodec)(nil)
+	_ avroSerializer   = (*schemaRegistryCodec)(nil)
+)
+
+func (c *schemaRegistryCodec) Deserialize(data []byte) (map[string]any, error) {
+	id, payload, err := parseWireFormat(data)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := c.registry.schemaByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if schema.avro != nil {
+		native, _, err := schema.avro.NativeFromBinary(payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to deserialize avro record: %w", err)
+		}
+		record, ok := native.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("schema %d is not an avro record", id)
+		}
+		return record, nil
+	}
+
+	indexes, payload, err := readMessageIndexes(payload)
+	if err != nil {
+		return nil, err
+	}
+	md, err := protoMessageByIndexes(schema.proto, indexes)
+	if err != nil {
+		return nil, err
+	}
+	msg := dynamicpb.NewMessage(md)
+	if err = proto.Unmarshal(payload, msg); err != nil {
+		return nil, fmt.Errorf("failed to deserialize protobuf message: %w", err)
+	}
+	return protoMessageToMap(msg), nil
+}
+
+// Serialize encodes the record with the latest schema of the subject. Protobuf records are
+// encoded as the first message of the schema.
+func (c *schemaRegistryCodec) Serialize(record map[string]any) ([]byte, error) {
+	schema, err := c.registry.latestSchema()
+	if err != nil {
+		return nil, err
+	}
+	data := make([]byte, wireFormatHeaderSize, wireFormatHeaderSize+1)
+	data[0] = wireFormatMagicByte
+	binary.BigEndian.PutUint32(data[1:], uint32(schema.id))
+
+	if schema.avro != nil {
+		data, err = schema.avro.BinaryFromNative(data, schema.avroType.nativeFromRaw(record))
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize avro record: %w", err)
+		}
+		return data, nil
+	}
+
+	md, err := protoMessageByIndexes(schema.proto, []int{0})
+	if err != nil {
+		return nil, err
+	}
+	js, err := json.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+	msg := dynamicpb.NewMessage(md)
+	if err = protojson.Unmarshal(js, msg); err != nil {
+		return nil, fmt.Errorf("failed to convert record to protobuf message %s: %w", md.FullName(), err)
+	}
+	payload, err := proto.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize protobuf message: %w", err)
+	}
+	// The message indexes of the first message are written as a single 0.
+	data = append(data, 0)
+	return append(data, payload...), nil
+}
+
+func parseWireFormat(data []byte) (int, []byte, error) {
+	if len(data) < wireFormatHeaderSize || data[0] != wireFormatMagicByte {
+		return 0, nil, errInvalidWireFormat
+	}
+	return int(binary.BigEndian.Uint32(data[1:wireFormatHeaderSize])), data[wireFormatHeaderSize:], nil
+}
+
+// readMessageIndexes reads the zigzag varint encoded indexes of the Protobuf message in the schema,
+// preceded by their count. A count of 0 stands for the first message of the schema.
+func readMessageIndexes(data []byte) ([]int, []byte, error) {
+	count, n := binary.Varint(data)
+	if n <= 0 || count < 0 || count > int64(len(data)) {
+		return nil, nil, fmt.Errorf("%w: invalid message indexes", errInvalidWireFormat)
+	}
+	data = data[n:]
+	if count == 0 {
+		return []int{0}, data, nil
+	}
+	indexes := make([]int, count)
+	for i := range indexes {
+		index, n := binary.Varint(data)
+		if n <= 0 || index < 0 {
+			return nil, nil, fmt.Errorf("%w: invalid message indexes", errInvalidWireFormat)
+		}
+		indexes[i] = int(index)
+		data = data[n:]
+	}
+	return indexes, data, nil
+}
+
+func protoMessageByIndexes(fd protoreflect.FileDescriptor, indexes []int) (protoreflect.MessageDescriptor, error) {
+	messages := fd.Messages()
+	var md protoreflect.MessageDescriptor
+	for _, index := range indexes {
+		if index >= messages.Len() {
+			return nil, fmt.Errorf("message index %d out of range in %s", index, fd.Path())
+		}
+		md = messages.Get(index)
+		messages = md.Messages()
+	}
+	if md == nil {
+		return nil, fmt.Errorf("no message in %s", fd.Path())
+	}
+	return md, nil
+}
+
+// protoMessageToMap converts the populated fields of a message to values supported by pcommon.Map.
+func protoMessageToMap(m protoreflect.Message) map[string]any {
+	out := make(map[string]any)
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.IsList():
+			list := v.List()
+			values := make([]any, list.Len())
+			for i := range values {
+				values[i] = protoValueToRaw(fd, list.Get(i))
+			}
+			out[string(fd.Name())] = values
+		case fd.IsMap():
+			values := make(map[string]any, v.Map().Len())
+			v.Map().Range(func(k protoreflect.MapKey, mv protoreflect.Value) bool {
+				values[k.String()] = protoValueToRaw(fd.MapValue(), mv)
+				return true
+			})
+			out[string(fd.Name())] = values
+		default:
+			out[string(fd.Name())] = protoValueToRaw(fd, v)
+		}
+		return true
+	})
+	return out
+}
+
+func protoValueToRaw(fd protoreflect.FieldDescriptor, v protoreflect.Value) any {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return protoMessageToMap(v.Message())
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			return string(ev.Name())
+		}
+		return int64(v.Enum())
+	case protoreflect.BoolKind:
+		return v.Bool()
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return v.Int()
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return int64(v.Uint())
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return v.Float()
+	case protoreflect.BytesKind:
+		return v.Bytes()
+	default:
+		return v.String()
+	}
+}
@@ -4,6 +4,7 @@
 package solarwindsapmsettingsextension // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/solarwindsapmsettingsextension"
 
 import (
+	"fmt"
 	"os"
 	"regexp"
 	"strings"
@@ -11,12 +12,26 @@ import (
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configgrpc"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/config/configretry"
 )
 
 type Config struct {
 	ClientConfig configgrpc.ClientConfig `mapstructure:",squash"`
 	Key          string                  `mapstructure:"key"`
-	Interval     time.Duration           `mapstructure:"interval"`
+	// Keys are additional service keys. One settings document is kept per service.
+	Keys     []string      `mapstructure:"keys"`
+	Interval time.Duration `mapstructure:"interval"`
+	// BackOffConfig configures how a failed refresh is retried before falling back to Interval.
+	BackOffConfig configretry.BackOffConfig `mapstructure:"retry_on_failure"`
+	// HTTPServerConfig, when set, serves the latest settings as JSON to local APM agents.
+	HTTPServerConfig *confighttp.ServerConfig `mapstructure:"http"`
+	// GRPCServerConfig, when set, serves the latest settings through the same GetSettings RPC
+	// exposed by the Solarwinds APM collector, so local APM agents can point at the collector.
+	GRPCServerConfig *configgrpc.ServerConfig `mapstructure:"grpc"`
+	// StorageID is the ID of a storage extension used to keep the last-known-good settings,
+	// so they are available after a restart even if the APM collector is unreachable.
+	StorageID *component.ID `mapstructure:"storage"`
 }
 
 const (
@@ -31,7 +46,8 @@ func createDefaultConfig() component.Config {
 		ClientConfig: configgrpc.ClientConfig{
 			Endpoint: DefaultEndpoint,
 		},
-		Interval: DefaultInterval,
+		Interval:      DefaultInterval,
+		BackOffConfig: configretry.NewDefaultBackOffConfig(),
 	}
 }
 
@@ -43,15 +59,18 @@ func (cfg *Config) Validate() error {
 		cfg.ClientConfig.Endpoint = DefaultEndpoint
 	}
 	// Key
-	keyArr := strings.Split(cfg.Key, ":")
-	if len(keyArr) == 2 && len(keyArr[1]) == 0 {
-		/**
-		 * Service name is empty. We are trying our best effort to resolve the service name
-		 */
-		serviceName := resolveServiceNameBestEffort()
-		if len(serviceName) > 0 {
-			cfg.Key = keyArr[0] + ":" + serviceName
+	cfg.Key = resolveKey(cfg.Key)
+	// Keys
+	for i := range cfg.Keys {
+		cfg.Keys[i] = resolveKey(cfg.Keys[i])
+	}
+	services := make(map[string]struct{})
+	for _, key := range cfg.ServiceKeys() {
+		name := serviceName(key)
+		if _, ok := services[name]; ok {
+			return fmt.Errorf("duplicate service %q in keys", name)
 		}
+		services[name] = struct{}{}
 	}
 	// Interval
 	if cfg.Interval.Seconds() < MinimumInterval.Seconds() {
@@ -63,6 +82,41 @@ func (cfg *Config) Validate() error {
 	return nil
 }
 
+// ServiceKeys returns the service keys to get settings for. Key comes first, followed by Keys.
+// When Keys is empty, Key is returned even if empty.
+func (cfg *Config) ServiceKeys() []string {
+	if len(cfg.Keys) == 0 {
+		return []string{cfg.Key}
+	}
+	if len(cfg.Key) == 0 {
+		return cfg.Keys
+	}
+	return append([]string{cfg.Key}, cfg.Keys...)
+}
+
+// resolveKey fills in the service name of a `<token>:` key.
+func resolveKey(key string) string {
+	keyArr := strings.Split(key, ":")
+	if len(keyArr) == 2 && len(keyArr[1]) == 0 {
+		/**
+		 * Service name is empty. We are trying our best effort to resolve the service name
+		 */
+		serviceName := resolveServiceNameBestEffort()
+		if len(serviceName) > 0 {
+			return keyArr[0] + ":" + serviceName
+		}
+	}
+	return key
+}
+
+// serviceName returns the `<name>` part of a `<token>:<name>` key.
+func serviceName(key string) string {
+	if i := strings.Index(key, ":"); i >= 0 {
+		return key[i+1:]
+	}
+	return ""
+}
+
 func resolveServiceNameBestEffort() string {
 	if otelServiceName, otelServiceNameDefined := os.LookupEnv("OTEL_SERVICE_NAME"); otelServiceNameDefined && len(otelServiceName) > 0 {
 		return otelServiceName
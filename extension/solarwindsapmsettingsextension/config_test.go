@@ -13,6 +13,9 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configgrpc"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/config/confignet"
+	"go.opentelemetry.io/collector/config/configretry"
 	"go.opentelemetry.io/collector/confmap/confmaptest"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/solarwindsapmsettingsextension/internal/metadata"
@@ -21,6 +24,8 @@ import (
 func TestLoadConfig(t *testing.T) {
 	t.Parallel()
 
+	storageID := component.MustNewID("file_storage")
+
 	tests := []struct {
 		id       component.ID
 		expected component.Config
@@ -35,8 +40,9 @@ func TestLoadConfig(t *testing.T) {
 				ClientConfig: configgrpc.ClientConfig{
 					Endpoint: "apm.collector.na-01.cloud.solarwinds.com:443",
 				},
-				Key:      "something:name",
-				Interval: time.Duration(10) * time.Second,
+				Key:           "something:name",
+				Interval:      time.Duration(10) * time.Second,
+				BackOffConfig: configretry.NewDefaultBackOffConfig(),
 			},
 		},
 		{
@@ -45,8 +51,9 @@ func TestLoadConfig(t *testing.T) {
 				ClientConfig: configgrpc.ClientConfig{
 					Endpoint: "apm.collector.na-02.cloud.solarwinds.com:443",
 				},
-				Key:      "something:name",
-				Interval: time.Duration(10) * time.Second,
+				Key:           "something:name",
+				Interval:      time.Duration(10) * time.Second,
+				BackOffConfig: configretry.NewDefaultBackOffConfig(),
 			},
 		},
 		{
@@ -55,8 +62,9 @@ func TestLoadConfig(t *testing.T) {
 				ClientConfig: configgrpc.ClientConfig{
 					Endpoint: "apm.collector.eu-01.cloud.solarwinds.com:443",
 				},
-				Key:      "something:name",
-				Interval: time.Duration(10) * time.Second,
+				Key:           "something:name",
+				Interval:      time.Duration(10) * time.Second,
+				BackOffConfig: configretry.NewDefaultBackOffConfig(),
 			},
 		},
 		{
@@ -65,8 +73,9 @@ func TestLoadConfig(t *testing.T) {
 				ClientConfig: configgrpc.ClientConfig{
 					Endpoint: "apm.collector.apj-01.cloud.solarwinds.com:443",
 				},
-				Key:      "something:name",
-				Interval: time.Duration(10) * time.Second,
+				Key:           "something:name",
+				Interval:      time.Duration(10) * time.Second,
+				BackOffConfig: configretry.NewDefaultBackOffConfig(),
 			},
 		},
 		{
@@ -75,8 +84,9 @@ func TestLoadConfig(t *testing.T) {
 				ClientConfig: configgrpc.ClientConfig{
 					Endpoint: "apm.collector.na-01.st-ssp.solarwinds.com:443",
 				},
-				Key:      "something:name",
-				Interval: time.Duration(10) * time.Second,
+				Key:           "something:name",
+				Interval:      time.Duration(10) * time.Second,
+				BackOffConfig: configretry.NewDefaultBackOffConfig(),
 			},
 		},
 		{
@@ -85,8 +95,9 @@ func TestLoadConfig(t *testing.T) {
 				ClientConfig: configgrpc.ClientConfig{
 					Endpoint: "apm.collector.na-01.dev-ssp.solarwinds.com:443",
 				},
-				Key:      "something:name",
-				Interval: time.Duration(10) * time.Second,
+				Key:           "something:name",
+				Interval:      time.Duration(10) * time.Second,
+				BackOffConfig: configretry.NewDefaultBackOffConfig(),
 			},
 		},
 		{
@@ -95,8 +106,9 @@ func TestLoadConfig(t *testing.T) {
 				ClientConfig: configgrpc.ClientConfig{
 					Endpoint: DefaultEndpoint,
 				},
-				Key:      "something:name",
-				Interval: time.Duration(10) * time.Second,
+				Key:           "something:name",
+				Interval:      time.Duration(10) * time.Second,
+				BackOffConfig: configretry.NewDefaultBackOffConfig(),
 			},
 		},
 		{
@@ -105,8 +117,9 @@ func TestLoadConfig(t *testing.T) {
 				ClientConfig: configgrpc.ClientConfig{
 					Endpoint: DefaultEndpoint,
 				},
-				Key:      "something:name",
-				Interval: time.Duration(10) * time.Second,
+				Key:           "something:name",
+				Interval:      time.Duration(10) * time.Second,
+				BackOffConfig: configretry.NewDefaultBackOffConfig(),
 			},
 		},
 		{
@@ -115,8 +128,9 @@ func TestLoadConfig(t *testing.T) {
 				ClientConfig: configgrpc.ClientConfig{
 					Endpoint: DefaultEndpoint,
 				},
-				Key:      "something:name",
-				Interval: time.Duration(10) * time.Second,
+				Key:           "something:name",
+				Interval:      time.Duration(10) * time.Second,
+				BackOffConfig: configretry.NewDefaultBackOffConfig(),
 			},
 		},
 		{
@@ -125,8 +139,9 @@ func TestLoadConfig(t *testing.T) {
 				ClientConfig: configgrpc.ClientConfig{
 					Endpoint: DefaultEndpoint,
 				},
-				Key:      "something:name",
-				Interval: time.Duration(10) * time.Second,
+				Key:           "something:name",
+				Interval:      time.Duration(10) * time.Second,
+				BackOffConfig: configretry.NewDefaultBackOffConfig(),
 			},
 		},
 		{
@@ -135,8 +150,9 @@ func TestLoadConfig(t *testing.T) {
 				ClientConfig: configgrpc.ClientConfig{
 					Endpoint: DefaultEndpoint,
 				},
-				Key:      "something:name",
-				Interval: time.Duration(10) * time.Second,
+				Key:           "something:name",
+				Interval:      time.Duration(10) * time.Second,
+				BackOffConfig: configretry.NewDefaultBackOffConfig(),
 			},
 		},
 		{
@@ -145,8 +161,9 @@ func TestLoadConfig(t *testing.T) {
 				ClientConfig: configgrpc.ClientConfig{
 					Endpoint: DefaultEndpoint,
 				},
-				Key:      "something:name",
-				Interval: time.Duration(10) * time.Second,
+				Key:           "something:name",
+				Interval:      time.Duration(10) * time.Second,
+				BackOffConfig: configretry.NewDefaultBackOffConfig(),
 			},
 		},
 		{
@@ -155,8 +172,9 @@ func TestLoadConfig(t *testing.T) {
 				ClientConfig: configgrpc.ClientConfig{
 					Endpoint: DefaultEndpoint,
 				},
-				Key:      "something:name",
-				Interval: time.Duration(10) * time.Second,
+				Key:           "something:name",
+				Interval:      time.Duration(10) * time.Second,
+				BackOffConfig: configretry.NewDefaultBackOffConfig(),
 			},
 		},
 		{
@@ -165,8 +183,9 @@ func TestLoadConfig(t *testing.T) {
 				ClientConfig: configgrpc.ClientConfig{
 					Endpoint: DefaultEndpoint,
 				},
-				Key:      "something:name",
-				Interval: time.Duration(10) * time.Second,
+				Key:           "something:name",
+				Interval:      time.Duration(10) * time.Second,
+				BackOffConfig: configretry.NewDefaultBackOffConfig(),
 			},
 		},
 		{
@@ -175,8 +194,9 @@ func TestLoadConfig(t *testing.T) {
 				ClientConfig: configgrpc.ClientConfig{
 					Endpoint: DefaultEndpoint,
 				},
-				Key:      "something:name",
-				Interval: time.Duration(10) * time.Second,
+				Key:           "something:name",
+				Interval:      time.Duration(10) * time.Second,
+				BackOffConfig: configretry.NewDefaultBackOffConfig(),
 			},
 		},
 		{
@@ -185,8 +205,9 @@ func TestLoadConfig(t *testing.T) {
 				ClientConfig: configgrpc.ClientConfig{
 					Endpoint: DefaultEndpoint,
 				},
-				Key:      "",
-				Interval: time.Duration(10) * time.Second,
+				Key:           "",
+				Interval:      time.Duration(10) * time.Second,
+				BackOffConfig: configretry.NewDefaultBackOffConfig(),
 			},
 		},
 		{
@@ -195,8 +216,9 @@ func TestLoadConfig(t *testing.T) {
 				ClientConfig: configgrpc.ClientConfig{
 					Endpoint: DefaultEndpoint,
 				},
-				Key:      ":",
-				Interval: time.Duration(10) * time.Second,
+				Key:           ":",
+				Interval:      time.Duration(10) * time.Second,
+				BackOffConfig: configretry.NewDefaultBackOffConfig(),
 			},
 		},
 		{
@@ -205,8 +227,9 @@ func TestLoadConfig(t *testing.T) {
 				ClientConfig: configgrpc.ClientConfig{
 					Endpoint: DefaultEndpoint,
 				},
-				Key:      "::",
-				Interval: time.Duration(10) * time.Second,
+				Key:           "::",
+				Interval:      time.Duration(10) * time.Second,
+				BackOffConfig: configretry.NewDefaultBackOffConfig(),
 			},
 		},
 		{
@@ -215,8 +238,9 @@ func TestLoadConfig(t *testing.T) {
 				ClientConfig: configgrpc.ClientConfig{
 					Endpoint: DefaultEndpoint,
 				},
-				Key:      ":name",
-				Interval: time.Duration(10) * time.Second,
+				Key:           ":name",
+				Interval:      time.Duration(10) * time.Second,
+				BackOffConfig: configretry.NewDefaultBackOffConfig(),
 			},
 		},
 		{
@@ -225,8 +249,9 @@ func TestLoadConfig(t *testing.T) {
 				ClientConfig: configgrpc.ClientConfig{
 					Endpoint: DefaultEndpoint,
 				},
-				Key:      "token:",
-				Interval: time.Duration(10) * time.Second,
+				Key:           "token:",
+				Interval:      time.Duration(10) * time.Second,
+				BackOffConfig: configretry.NewDefaultBackOffConfig(),
 			},
 		},
 		{
@@ -235,8 +260,9 @@ func TestLoadConfig(t *testing.T) {
 				ClientConfig: configgrpc.ClientConfig{
 					Endpoint: DefaultEndpoint,
 				},
-				Key:      "token:name",
-				Interval: MinimumInterval,
+				Key:           "token:name",
+				Interval:      MinimumInterval,
+				BackOffConfig: configretry.NewDefaultBackOffConfig(),
 			},
 		},
 		{
@@ -245,8 +271,9 @@ func TestLoadConfig(t *testing.T) {
 				ClientConfig: configgrpc.ClientConfig{
 					Endpoint: DefaultEndpoint,
 				},
-				Key:      "token:name",
-				Interval: MaximumInterval,
+				Key:           "token:name",
+				Interval:      MaximumInterval,
+				BackOffConfig: configretry.NewDefaultBackOffConfig(),
 			},
 		},
 		{
@@ -255,8 +282,49 @@ func TestLoadConfig(t *testing.T) {
 				ClientConfig: configgrpc.ClientConfig{
 					Endpoint: DefaultEndpoint,
 				},
+				Key:           "token:name",
+				Interval:      MinimumInterval,
+				BackOffConfig: configretry.NewDefaultBackOffConfig(),
+			},
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "24"),
+			expected: &Config{
+				ClientConfig: configgrpc.ClientConfig{
+					Endpoint: "apm.collector.na-01.cloud.solarwinds.com:443",
+				},
+				Key:           "token:name",
+				Interval:      time.Duration(10) * time.Second,
+				BackOffConfig: configretry.NewDefaultBackOffConfig(),
+				HTTPServerConfig: &confighttp.ServerConfig{
+					Endpoint: "localhost:4319",
+				},
+				GRPCServerConfig: &configgrpc.ServerConfig{
+					NetAddr: confignet.AddrConfig{
+						Endpoint:  "localhost:4320",
+						Transport: confignet.TransportTypeTCP,
+					},
+				},
+				StorageID: &storageID,
+			},
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "25"),
+			expected: &Config{
+				ClientConfig: configgrpc.ClientConfig{
+					Endpoint: "apm.collector.na-01.cloud.solarwinds.com:443",
+				},
 				Key:      "token:name",
-				Interval: MinimumInterval,
+				Keys:     []string{"token:name2", "token:name3"},
+				Interval: time.Duration(10) * time.Second,
+				BackOffConfig: configretry.BackOffConfig{
+					Enabled:             true,
+					InitialInterval:     time.Second,
+					RandomizationFactor: 0.5,
+					Multiplier:          1.5,
+					MaxInterval:         30 * time.Second,
+					MaxElapsedTime:      5 * time.Minute,
+				},
 			},
 		},
 	}
@@ -275,6 +343,20 @@ func TestLoadConfig(t *testing.T) {
 	}
 }
 
+func TestValidateDuplicateService(t *testing.T) {
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.Key = "token:name"
+	cfg.Keys = []string{"token2:other", "token3:name"}
+	require.EqualError(t, cfg.Validate(), `duplicate service "name" in keys`)
+}
+
+func TestServiceKeys(t *testing.T) {
+	require.Equal(t, []string{""}, (&Config{}).ServiceKeys())
+	require.Equal(t, []string{"token:name"}, (&Config{Key: "token:name"}).ServiceKeys())
+	require.Equal(t, []string{"token:name2"}, (&Config{Keys: []string{"token:name2"}}).ServiceKeys())
+	require.Equal(t, []string{"token:name", "token:name2"}, (&Config{Key: "token:name", Keys: []string{"token:name2"}}).ServiceKeys())
+}
+
 func TestResolveServiceNameBestEffort(t *testing.T) {
 	// Without any environment variables
 	require.Empty(t, resolveServiceNameBestEffort())
@@ -9,36 +9,91 @@ import (
 	"crypto/x509"
 	"encoding/binary"
 	"encoding/json"
+	"fmt"
 	"math"
+	"net/url"
 	"os"
-	"path"
+	"path/filepath"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/cenkalti/backoff/v4"
 	"github.com/solarwindscloud/apm-proto/go/collectorpb"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/extension"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/solarwindsapmsettingsextension/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storageclient"
 )
 
 const (
 	jsonOutputFile      = "solarwinds-apm-settings.json"
 	grpcContextDeadline = 1 * time.Second
+	// lastKnownGoodKey is the storage key prefix of the last settings successfully received from the APM collector.
+	lastKnownGoodKey = "last_known_good_settings"
 )
 
+var _ SettingsProvider = (*solarwindsapmSettingsExtension)(nil)
+
 type solarwindsapmSettingsExtension struct {
 	config            *Config
+	id                component.ID
 	cancel            context.CancelFunc
 	conn              *grpc.ClientConn
 	client            collectorpb.TraceCollectorClient
 	telemetrySettings component.TelemetrySettings
+	telemetryBuilder  *metadata.TelemetryBuilder
+	services          []*serviceSettings
+	cache             *settingsCache
+	storageClient     storage.Client
+	servers           []component.Component
+	wg                sync.WaitGroup
+}
+
+// serviceSettings tracks the settings of one service key.
+type serviceSettings struct {
+	key      string
+	name     string
+	filename string
+	// expiresAt is when the settings expire if they are not refreshed. It is zero if there are no settings or no TTL.
+	expiresAt time.Time
 }
 
 func newSolarwindsApmSettingsExtension(extensionCfg *Config, settings extension.Settings) (extension.Extension, error) {
+	telemetryBuilder, err := metadata.NewTelemetryBuilder(settings.TelemetrySettings)
+	if err != nil {
+		return nil, err
+	}
+	var services []*serviceSettings
+	for i, key := range extensionCfg.ServiceKeys() {
+		service := &serviceSettings{
+			key:  key,
+			name: serviceName(key),
+		}
+		// The first service keeps the well-known file name, so existing APM agents keep working.
+		// Other service names are escaped, so that they cannot point outside of the temporary directory.
+		if i == 0 {
+			service.filename = filepath.Join(os.TempDir(), jsonOutputFile)
+		} else {
+			service.filename = filepath.Join(os.TempDir(), strings.TrimSuffix(jsonOutputFile, ".json")+"-"+url.PathEscape(service.name)+".json")
+		}
+		services = append(services, service)
+	}
 	settingsExtension := &solarwindsapmSettingsExtension{
 		config:            extensionCfg,
+		id:                settings.ID,
 		telemetrySettings: settings.TelemetrySettings,
+		telemetryBuilder:  telemetryBuilder,
+		services:          services,
+		cache:             newSettingsCache(services[0].name),
 	}
 	return settingsExtension, nil
 }
@@ -58,50 +113,222 @@ func (extension *solarwindsapmSettingsExtension) Start(_ context.Context, host c
 	extension.telemetrySettings.Logger.Info("created a gRPC client", zap.String("endpoint", extension.config.ClientConfig.Endpoint))
 	extension.client = collectorpb.NewTraceCollectorClient(extension.conn)
 
-	outputFile := path.Join(os.TempDir(), jsonOutputFile)
-	// initial refresh
-	refresh(extension, outputFile)
-
-	go func() {
-		ticker := time.NewTicker(extension.config.Interval)
-		defer ticker.Stop()
-		for {
-			select {
-			case <-ticker.C:
-				refresh(extension, outputFile)
-			case <-ctx.Done():
-				extension.telemetrySettings.Logger.Info("received ctx.Done() from ticker")
-				return
-			}
+	if extension.config.StorageID != nil {
+		if extension.storageClient, err = storageclient.Get(ctx, host, *extension.config.StorageID, component.KindExtension, extension.id, ""); err != nil {
+			return err
+		}
+		for _, service := range extension.services {
+			restore(ctx, extension, service)
 		}
-	}()
+	}
+	if extension.config.HTTPServerConfig != nil {
+		extension.servers = append(extension.servers, newSettingsHTTPServer(extension.telemetrySettings, *extension.config.HTTPServerConfig, extension.cache))
+	}
+	if extension.config.GRPCServerConfig != nil {
+		extension.servers = append(extension.servers, newSettingsGRPCServer(extension.telemetrySettings, *extension.config.GRPCServerConfig, extension.cache, extension.config.ServiceKeys()))
+	}
+	for _, server := range extension.servers {
+		if err = server.Start(ctx, host); err != nil {
+			return err
+		}
+	}
+
+	for _, service := range extension.services {
+		extension.wg.Add(1)
+		go func(service *serviceSettings) {
+			defer extension.wg.Done()
+			schedule(ctx, extension, service)
+		}(service)
+	}
 
 	return nil
 }
 
-func (extension *solarwindsapmSettingsExtension) Shutdown(_ context.Context) error {
+func (extension *solarwindsapmSettingsExtension) Shutdown(ctx context.Context) error {
 	extension.telemetrySettings.Logger.Info("shutting down solarwinds apm settings extension")
 	if extension.cancel != nil {
 		extension.cancel()
 	}
+	extension.wg.Wait()
+	for _, server := range extension.servers {
+		if err := server.Shutdown(ctx); err != nil {
+			extension.telemetrySettings.Logger.Error("error while shutting down the settings server", zap.Error(err))
+		}
+	}
+	if extension.storageClient != nil {
+		if err := extension.storageClient.Close(ctx); err != nil {
+			extension.telemetrySettings.Logger.Error("error while closing the storage client", zap.Error(err))
+		}
+	}
 	if extension.conn != nil {
 		return extension.conn.Close()
 	}
 	return nil
 }
 
-func refresh(extension *solarwindsapmSettingsExtension, filename string) {
+// Settings returns the latest JSON encoded settings of the service.
+func (extension *solarwindsapmSettingsExtension) Settings(service string) []byte {
+	return extension.cache.settings(service)
+}
+
+// Subscribe registers a listener which is called whenever the settings of a service change.
+func (extension *solarwindsapmSettingsExtension) Subscribe(listener SettingsListener) func() {
+	return extension.cache.subscribe(listener)
+}
+
+// schedule refreshes the settings of a service every interval until ctx is done. A failed refresh is
+// retried with exponential backoff, never waiting longer than the interval, and the settings are evicted
+// once their TTL passes without a successful refresh.
+func schedule(ctx context.Context, extension *solarwindsapmSettingsExtension, service *serviceSettings) {
+	var expBackOff *backoff.ExponentialBackOff
+	if extension.config.BackOffConfig.Enabled {
+		expBackOff = &backoff.ExponentialBackOff{
+			InitialInterval:     extension.config.BackOffConfig.InitialInterval,
+			RandomizationFactor: extension.config.BackOffConfig.RandomizationFactor,
+			Multiplier:          extension.config.BackOffConfig.Multiplier,
+			MaxInterval:         extension.config.BackOffConfig.MaxInterval,
+			MaxElapsedTime:      extension.config.BackOffConfig.MaxElapsedTime,
+			Stop:                backoff.Stop,
+			Clock:               backoff.SystemClock,
+		}
+		expBackOff.Reset()
+	}
+	attrs := metric.WithAttributes(attribute.String("service", service.name))
+	for {
+		wait := extension.config.Interval
+		if err := refresh(ctx, extension, service); err != nil {
+			extension.telemetryBuilder.SolarwindsapmsettingsRefreshFailures.Add(ctx, 1, attrs)
+			expire(extension, service)
+			if expBackOff != nil {
+				if next := expBackOff.NextBackOff(); next != backoff.Stop {
+					wait = min(next, extension.config.Interval)
+				} else {
+					// Give up retrying faster than the interval until the next successful refresh.
+					expBackOff.Reset()
+				}
+			}
+		} else {
+			extension.telemetryBuilder.SolarwindsapmsettingsRefreshes.Add(ctx, 1, attrs)
+			if expBackOff != nil {
+				expBackOff.Reset()
+			}
+		}
+		if !service.expiresAt.IsZero() {
+			if untilExpiry := time.Until(service.expiresAt); untilExpiry > 0 && untilExpiry < wait {
+				wait = untilExpiry
+			}
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			extension.telemetrySettings.Logger.Info("received ctx.Done() from ticker")
+			return
+		}
+	}
+}
+
+// expire evicts the settings of the service if their TTL passed.
+func expire(extension *solarwindsapmSettingsExtension, service *serviceSettings) {
+	if service.expiresAt.IsZero() || time.Now().Before(service.expiresAt) {
+		return
+	}
+	service.expiresAt = time.Time{}
+	if !extension.cache.evict(service.name) {
+		return
+	}
+	extension.telemetryBuilder.SolarwindsapmsettingsExpiredSettings.Add(context.Background(), 1, metric.WithAttributes(attribute.String("service", service.name)))
+	if err := os.Remove(service.filename); err != nil && !os.IsNotExist(err) {
+		extension.telemetrySettings.Logger.Error("unable to remove "+service.filename, zap.Error(err))
+	}
+	extension.telemetrySettings.Logger.Warn("settings expired", zap.String("service", service.name))
+}
+
+// ttl returns the shortest positive TTL of the settings, or zero if none of them has a TTL.
+func ttl(response *collectorpb.SettingsResult) time.Duration {
+	var shortest time.Duration
+	for _, item := range response.GetSettings() {
+		if item.GetTtl() == 0 {
+			continue
+		}
+		if current := time.Duration(item.GetTtl()) * time.Second; shortest == 0 || current < shortest {
+			shortest = current
+		}
+	}
+	return shortest
+}
+
+// setExpiry sets when the settings of the service expire, counting from now.
+func setExpiry(service *serviceSettings, response *collectorpb.SettingsResult) {
+	if d := ttl(response); d > 0 {
+		service.expiresAt = time.Now().Add(d)
+	} else {
+		service.expiresAt = time.Time{}
+	}
+}
+
+// restore loads the last-known-good settings from the storage extension, so they are
+// served until the APM collector answers. The TTL of restored settings counts from now.
+func restore(ctx context.Context, extension *solarwindsapmSettingsExtension, service *serviceSettings) {
+	ctx, cancel := context.WithTimeout(ctx, grpcContextDeadline)
+	defer cancel()
+	data, err := extension.storageClient.Get(ctx, lastKnownGoodKey+"/"+service.name)
+	if err != nil {
+		extension.telemetrySettings.Logger.Error("unable to read last-known-good settings", zap.String("service", service.name), zap.Error(err))
+		return
+	}
+	if data == nil {
+		return
+	}
+	response := &collectorpb.SettingsResult{}
+	if err = proto.Unmarshal(data, response); err != nil {
+		extension.telemetrySettings.Logger.Error("unable to decode last-known-good settings", zap.String("service", service.name), zap.Error(err))
+		return
+	}
+	content, err := settingsToJSON(response)
+	if err != nil {
+		extension.telemetrySettings.Logger.Error("unable to marshal setting JSON[] byte from settings", zap.Error(err))
+		return
+	}
+	if err = os.WriteFile(service.filename, content, 0600); err != nil {
+		extension.telemetrySettings.Logger.Error("unable to write "+service.filename, zap.Error(err))
+	}
+	extension.cache.update(service.name, response, content)
+	setExpiry(service, response)
+	extension.telemetrySettings.Logger.Info("restored last-known-good settings", zap.String("service", service.name))
+}
+
+// persist saves the settings as last-known-good in the storage extension, if any.
+func persist(ctx context.Context, extension *solarwindsapmSettingsExtension, service *serviceSettings, response *collectorpb.SettingsResult) {
+	if extension.storageClient == nil {
+		return
+	}
+	data, err := proto.Marshal(response)
+	if err != nil {
+		extension.telemetrySettings.Logger.Error("unable to encode last-known-good settings", zap.String("service", service.name), zap.Error(err))
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, grpcContextDeadline)
+	defer cancel()
+	if err = extension.storageClient.Set(ctx, lastKnownGoodKey+"/"+service.name, data); err != nil {
+		extension.telemetrySettings.Logger.Error("unable to store last-known-good settings", zap.String("service", service.name), zap.Error(err))
+	}
+}
+
+// refresh gets the settings of the service from the APM collector. The call is cancelled when ctx is done.
+func refresh(ctx context.Context, extension *solarwindsapmSettingsExtension, service *serviceSettings) error {
 	extension.telemetrySettings.Logger.Info("time to refresh", zap.String("endpoint", extension.config.ClientConfig.Endpoint))
 	hostname, err := os.Hostname()
 	if err != nil {
 		extension.telemetrySettings.Logger.Error("unable to call os.Hostname()", zap.Error(err))
-		return
+		return err
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), grpcContextDeadline)
+	ctx, cancel := context.WithTimeout(ctx, grpcContextDeadline)
 	defer cancel()
 
 	request := &collectorpb.SettingsRequest{
-		ApiKey: extension.config.Key,
+		ApiKey: service.key,
 		Identity: &collectorpb.HostID{
 			Hostname: hostname,
 		},
@@ -110,72 +337,85 @@ func refresh(extension *solarwindsapmSettingsExtension, filename string) {
 	response, err := extension.client.GetSettings(ctx, request)
 	if err != nil {
 		extension.telemetrySettings.Logger.Error("unable to get settings", zap.String("endpoint", extension.config.ClientConfig.Endpoint), zap.Error(err))
-		return
+		return err
 	}
 	switch result := response.GetResult(); result {
 	case collectorpb.ResultCode_OK:
 		if len(response.GetWarning()) > 0 {
 			extension.telemetrySettings.Logger.Warn("GetSettings succeed", zap.String("result", result.String()), zap.String("warning", response.GetWarning()))
 		}
-		var settings []map[string]any
-		for _, item := range response.GetSettings() {
-			setting := make(map[string]any)
-			setting["flags"] = string(item.GetFlags())
-			setting["timestamp"] = item.GetTimestamp()
-			setting["value"] = item.GetValue()
-			arguments := make(map[string]any)
-			if value, ok := item.Arguments["BucketCapacity"]; ok {
-				arguments["BucketCapacity"] = math.Float64frombits(binary.LittleEndian.Uint64(value))
-			}
-			if value, ok := item.Arguments["BucketRate"]; ok {
-				arguments["BucketRate"] = math.Float64frombits(binary.LittleEndian.Uint64(value))
-			}
-			if value, ok := item.Arguments["TriggerRelaxedBucketCapacity"]; ok {
-				arguments["TriggerRelaxedBucketCapacity"] = math.Float64frombits(binary.LittleEndian.Uint64(value))
-			}
-			if value, ok := item.Arguments["TriggerRelaxedBucketRate"]; ok {
-				arguments["TriggerRelaxedBucketRate"] = math.Float64frombits(binary.LittleEndian.Uint64(value))
-			}
-			if value, ok := item.Arguments["TriggerStrictBucketCapacity"]; ok {
-				arguments["TriggerStrictBucketCapacity"] = math.Float64frombits(binary.LittleEndian.Uint64(value))
-			}
-			if value, ok := item.Arguments["TriggerStrictBucketRate"]; ok {
-				arguments["TriggerStrictBucketRate"] = math.Float64frombits(binary.LittleEndian.Uint64(value))
-			}
-			if value, ok := item.Arguments["MetricsFlushInterval"]; ok {
-				arguments["MetricsFlushInterval"] = int32(binary.LittleEndian.Uint32(value))
-			}
-			if value, ok := item.Arguments["MaxTransactions"]; ok {
-				arguments["MaxTransactions"] = int32(binary.LittleEndian.Uint32(value))
-			}
-			if value, ok := item.Arguments["MaxCustomMetrics"]; ok {
-				arguments["MaxCustomMetrics"] = int32(binary.LittleEndian.Uint32(value))
-			}
-			if value, ok := item.Arguments["EventsFlushInterval"]; ok {
-				arguments["EventsFlushInterval"] = int32(binary.LittleEndian.Uint32(value))
-			}
-			if value, ok := item.Arguments["ProfilingInterval"]; ok {
-				arguments["ProfilingInterval"] = int32(binary.LittleEndian.Uint32(value))
-			}
-			setting["arguments"] = arguments
-			setting["ttl"] = item.GetTtl()
-			settings = append(settings, setting)
-		}
-		if content, err := json.Marshal(settings); err != nil {
+		content, err := settingsToJSON(response)
+		if err != nil {
 			extension.telemetrySettings.Logger.Error("unable to marshal setting JSON[] byte from settings", zap.Error(err))
+			return err
+		}
+		filename := service.filename
+		if err := os.WriteFile(filename, content, 0600); err != nil {
+			extension.telemetrySettings.Logger.Error("unable to write "+filename, zap.Error(err))
 		} else {
-			if err := os.WriteFile(filename, content, 0600); err != nil {
-				extension.telemetrySettings.Logger.Error("unable to write "+filename, zap.Error(err))
+			if len(response.GetWarning()) > 0 {
+				extension.telemetrySettings.Logger.Warn(filename + " is refreshed (soft disabled)")
 			} else {
-				if len(response.GetWarning()) > 0 {
-					extension.telemetrySettings.Logger.Warn(filename + " is refreshed (soft disabled)")
-				} else {
-					extension.telemetrySettings.Logger.Info(filename + " is refreshed")
-				}
-				extension.telemetrySettings.Logger.Info(string(content))
+				extension.telemetrySettings.Logger.Info(filename + " is refreshed")
 			}
+			extension.telemetrySettings.Logger.Info(string(content))
+		}
+		setExpiry(service, response)
+		if extension.cache.update(service.name, response, content) {
+			persist(ctx, extension, service, response)
 		}
+		return nil
 	default:
 		extension.telemetrySettings.Logger.Warn("GetSettings failed", zap.String("result", result.String()), zap.String("warning", response.GetWarning()))
+		return fmt.Errorf("GetSettings failed: %s", result.String())
+	}
+}
+
+// settingsToJSON decodes the settings arguments and encodes the settings as a JSON array.
+func settingsToJSON(response *collectorpb.SettingsResult) ([]byte, error) {
+	var settings []map[string]any
+	for _, item := range response.GetSettings() {
+		setting := make(map[string]any)
+		setting["flags"] = string(item.GetFlags())
+		setting["timestamp"] = item.GetTimestamp()
+		setting["value"] = item.GetValue()
+		arguments := make(map[string]any)
+		if value, ok := item.Arguments["BucketCapacity"]; ok {
+			arguments["BucketCapacity"] = math.Float64frombits(binary.LittleEndian.Uint64(value))
+		}
+		if value, ok := item.Arguments["BucketRate"]; ok {
+			arguments["BucketRate"] = math.Float64frombits(binary.LittleEndian.Uint64(value))
+		}
+		if value, ok := item.Arguments["TriggerRelaxedBucketCapacity"]; ok {
+			arguments["TriggerRelaxedBucketCapacity"] = math.Float64frombits(binary.LittleEndian.Uint64(value))
+		}
+		if value, ok := item.Arguments["TriggerRelaxedBucketRate"]; ok {
+			arguments["TriggerRelaxedBucketRate"] = math.Float64frombits(binary.LittleEndian.Uint64(value))
+		}
+		if value, ok := item.Arguments["TriggerStrictBucketCapacity"]; ok {
+			arguments["TriggerStrictBucketCapacity"] = math.Float64frombits(binary.LittleEndian.Uint64(value))
+		}
+		if value, ok := item.Arguments["TriggerStrictBucketRate"]; ok {
+			arguments["TriggerStrictBucketRate"] = math.Float64frombits(binary.LittleEndian.Uint64(value))
+		}
+		if value, ok := item.Arguments["MetricsFlushInterval"]; ok {
+			arguments["MetricsFlushInterval"] = int32(binary.LittleEndian.Uint32(value))
+		}
+		if value, ok := item.Arguments["MaxTransactions"]; ok {
+			arguments["MaxTransactions"] = int32(binary.LittleEndian.Uint32(value))
+		}
+		if value, ok := item.Arguments["MaxCustomMetrics"]; ok {
+			arguments["MaxCustomMetrics"] = int32(binary.LittleEndian.Uint32(value))
+		}
+		if value, ok := item.Arguments["EventsFlushInterval"]; ok {
+			arguments["EventsFlushInterval"] = int32(binary.LittleEndian.Uint32(value))
+		}
+		if value, ok := item.Arguments["ProfilingInterval"]; ok {
+			arguments["ProfilingInterval"] = int32(binary.LittleEndian.Uint32(value))
+		}
+		setting["arguments"] = arguments
+		setting["ttl"] = item.GetTtl()
+		settings = append(settings, setting)
 	}
+	return json.Marshal(settings)
 }
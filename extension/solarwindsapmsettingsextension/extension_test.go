@@ -6,6 +6,7 @@ package solarwindsapmsettingsextension
 import (
 	"context"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -18,8 +19,11 @@ import (
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config/configgrpc"
 	"go.opentelemetry.io/collector/extension"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/solarwindsapmsettingsextension/internal/metadata"
 )
 
 func TestCreateExtension(t *testing.T) {
@@ -74,6 +78,16 @@ func createAnExtension(c *Config, t *testing.T) extension.Extension {
 	return ex
 }
 
+func TestServiceFilenames(t *testing.T) {
+	ex, err := newSolarwindsApmSettingsExtension(&Config{Key: "token:first", Keys: []string{"token:../../etc/second"}}, newNopSettings())
+	require.NoError(t, err)
+	services := ex.(*solarwindsapmSettingsExtension).services
+	require.Len(t, services, 2)
+	require.Equal(t, filepath.Join(os.TempDir(), jsonOutputFile), services[0].filename)
+	require.Equal(t, os.TempDir(), filepath.Dir(services[1].filename))
+	require.Equal(t, "solarwinds-apm-settings-..%2F..%2Fetc%2Fsecond.json", filepath.Base(services[1].filename))
+}
+
 func TestRefresh(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -254,13 +268,19 @@ func TestRefresh(t *testing.T) {
 				config:            tt.cfg,
 				telemetrySettings: settings.TelemetrySettings,
 				client:            mockTraceCollectorClient,
+				cache:             newSettingsCache("name"),
+			}
+			err := refresh(context.Background(), settingsExtension, &serviceSettings{key: tt.cfg.Key, name: "name", filename: tt.filename})
+			if tt.fileExist {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
 			}
-			refresh(settingsExtension, tt.filename)
 			require.Equal(t, len(tt.expectedLogMessages), observedLogs.Len())
 			for index, observedLog := range observedLogs.All() {
 				require.Equal(t, tt.expectedLogMessages[index], observedLog.Message)
 			}
-			_, err := os.Stat(tt.filename)
+			_, err = os.Stat(tt.filename)
 			if tt.fileExist {
 				require.NoError(t, err)
 				require.NoError(t, os.Remove(tt.filename))
@@ -270,3 +290,110 @@ func TestRefresh(t *testing.T) {
 		})
 	}
 }
+
+type mapStorageClient struct {
+	data map[string][]byte
+}
+
+func (c *mapStorageClient) Get(_ context.Context, key string) ([]byte, error) {
+	return c.data[key], nil
+}
+
+func (c *mapStorageClient) Set(_ context.Context, key string, value []byte) error {
+	c.data[key] = value
+	return nil
+}
+
+func (c *mapStorageClient) Delete(_ context.Context, key string) error {
+	delete(c.data, key)
+	return nil
+}
+
+func (c *mapStorageClient) Batch(_ context.Context, _ ...storage.Operation) error {
+	return nil
+}
+
+func (c *mapStorageClient) Close(_ context.Context) error {
+	return nil
+}
+
+func TestLastKnownGoodSettings(t *testing.T) {
+	storageClient := &mapStorageClient{data: map[string][]byte{}}
+	mockTraceCollectorClient := &mocks.TraceCollectorClient{}
+	mockTraceCollectorClient.On("GetSettings", mock.Anything, mock.Anything).Return(&collectorpb.SettingsResult{
+		Result: collectorpb.ResultCode_OK,
+		Settings: []*collectorpb.OboeSetting{
+			{
+				Flags: []byte("flag1"),
+				Value: 1000000,
+				Ttl:   120,
+			},
+		},
+	}, nil)
+	settings := newNopSettings()
+	first := &solarwindsapmSettingsExtension{
+		config:            &Config{},
+		telemetrySettings: settings.TelemetrySettings,
+		client:            mockTraceCollectorClient,
+		cache:             newSettingsCache("name"),
+		storageClient:     storageClient,
+	}
+	service := &serviceSettings{key: "token:name", name: "name", filename: "testdata/refresh_last_known_good.json"}
+	require.NoError(t, refresh(context.Background(), first, service))
+	require.NoError(t, os.Remove(service.filename))
+	require.Contains(t, storageClient.data, lastKnownGoodKey+"/name")
+
+	second := &solarwindsapmSettingsExtension{
+		config:            &Config{},
+		telemetrySettings: settings.TelemetrySettings,
+		cache:             newSettingsCache("name"),
+		storageClient:     storageClient,
+	}
+	restored := &serviceSettings{key: "token:name", name: "name", filename: service.filename}
+	restore(context.Background(), second, restored)
+	require.NoError(t, os.Remove(service.filename))
+	require.Equal(t, first.Settings("name"), second.Settings("name"))
+	require.Equal(t, `[{"arguments":{},"flags":"flag1","timestamp":0,"ttl":120,"value":1000000}]`, string(second.Settings("")))
+	require.WithinDuration(t, time.Now().Add(120*time.Second), restored.expiresAt, 5*time.Second)
+}
+
+func TestExpire(t *testing.T) {
+	settings := newNopSettings()
+	telemetryBuilder, err := metadata.NewTelemetryBuilder(settings.TelemetrySettings)
+	require.NoError(t, err)
+	settingsExtension := &solarwindsapmSettingsExtension{
+		config:            &Config{},
+		telemetrySettings: settings.TelemetrySettings,
+		telemetryBuilder:  telemetryBuilder,
+		cache:             newSettingsCache("name"),
+	}
+	service := &serviceSettings{key: "token:name", name: "name", filename: "testdata/refresh_expire.json"}
+	require.NoError(t, os.WriteFile(service.filename, []byte("[]"), 0600))
+	settingsExtension.cache.update(service.name, &collectorpb.SettingsResult{}, []byte("[]"))
+
+	var evicted bool
+	settingsExtension.Subscribe(func(_ string, content []byte) {
+		evicted = content == nil
+	})
+
+	// not expired yet
+	service.expiresAt = time.Now().Add(time.Minute)
+	expire(settingsExtension, service)
+	require.NotNil(t, settingsExtension.Settings("name"))
+	require.False(t, evicted)
+
+	// expired
+	service.expiresAt = time.Now().Add(-time.Second)
+	expire(settingsExtension, service)
+	require.Nil(t, settingsExtension.Settings("name"))
+	require.True(t, evicted)
+	require.True(t, service.expiresAt.IsZero())
+	_, err = os.Stat(service.filename)
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestTTL(t *testing.T) {
+	require.Zero(t, ttl(&collectorpb.SettingsResult{}))
+	require.Zero(t, ttl(&collectorpb.SettingsResult{Settings: []*collectorpb.OboeSetting{{Ttl: 0}}}))
+	require.Equal(t, 60*time.Second, ttl(&collectorpb.SettingsResult{Settings: []*collectorpb.OboeSetting{{Ttl: 0}, {Ttl: 120}, {Ttl: 60}}}))
+}
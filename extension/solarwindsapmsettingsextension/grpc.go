@@ -0,0 +1,102 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package solarwindsapmsettingsextension // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/solarwindsapmsettingsextension"
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/solarwindscloud/apm-proto/go/collectorpb"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configgrpc"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var _ component.Component = (*settingsGRPCServer)(nil)
+
+// settingsGRPCServer answers GetSettings from the latest settings received from the APM collector.
+// Only requests carrying one of the configured service keys are answered. The other TraceCollector
+// methods are not implemented.
+type settingsGRPCServer struct {
+	collectorpb.UnimplementedTraceCollectorServer
+
+	telemetry component.TelemetrySettings
+	config    configgrpc.ServerConfig
+	cache     *settingsCache
+	keys      map[string]struct{}
+
+	server *grpc.Server
+}
+
+func newSettingsGRPCServer(telemetry component.TelemetrySettings, config configgrpc.ServerConfig, cache *settingsCache, keys []string) *settingsGRPCServer {
+	server := &settingsGRPCServer{
+		telemetry: telemetry,
+		config:    config,
+		cache:     cache,
+		keys:      make(map[string]struct{}, len(keys)),
+	}
+	for _, key := range keys {
+		server.keys[key] = struct{}{}
+	}
+	return server
+}
+
+func (s *settingsGRPCServer) Start(ctx context.Context, host component.Host) error {
+	var err error
+	s.server, err = s.config.ToServer(ctx, host, s.telemetry)
+	if err != nil {
+		return err
+	}
+	collectorpb.RegisterTraceCollectorServer(s.server, s)
+
+	listener, err := s.config.NetAddr.Listen(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to listen on gRPC port: %w", err)
+	}
+	s.telemetry.Logger.Info("serving settings over gRPC", zap.String("endpoint", s.config.NetAddr.Endpoint))
+
+	go func() {
+		if err := s.server.Serve(listener); err != nil {
+			s.telemetry.Logger.Error("could not launch gRPC service", zap.Error(err))
+		}
+	}()
+	return nil
+}
+
+func (s *settingsGRPCServer) Shutdown(ctx context.Context) error {
+	if s.server == nil {
+		return nil
+	}
+	stopped := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-ctx.Done():
+		s.server.Stop()
+	case <-stopped:
+	}
+	return nil
+}
+
+// GetSettings returns the latest settings of the service named in the request's api key,
+// or TRY_LATER if no settings are available. Requests with an api key which is not configured
+// are rejected.
+func (s *settingsGRPCServer) GetSettings(_ context.Context, request *collectorpb.SettingsRequest) (*collectorpb.SettingsResult, error) {
+	if _, ok := s.keys[request.GetApiKey()]; !ok {
+		return nil, status.Error(codes.Unauthenticated, "invalid api key")
+	}
+	result := s.cache.settingsResult(serviceName(request.GetApiKey()))
+	if result == nil {
+		return &collectorpb.SettingsResult{
+			Result:  collectorpb.ResultCode_TRY_LATER,
+			Warning: "settings are not available yet",
+		}, nil
+	}
+	return result, nil
+}
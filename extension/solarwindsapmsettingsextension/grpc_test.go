@@ -0,0 +1,44 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package solarwindsapmsettingsextension
+
+import (
+	"context"
+	"testing"
+
+	"github.com/solarwindscloud/apm-proto/go/collectorpb"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configgrpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestSettingsGRPCGetSettings(t *testing.T) {
+	cache := newSettingsCache("svc1")
+	server := newSettingsGRPCServer(componenttest.NewNopTelemetrySettings(), configgrpc.ServerConfig{}, cache, []string{"token:svc1", "token:svc2"})
+
+	_, err := server.GetSettings(context.Background(), &collectorpb.SettingsRequest{})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = server.GetSettings(context.Background(), &collectorpb.SettingsRequest{ApiKey: "other:svc1"})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	result, err := server.GetSettings(context.Background(), &collectorpb.SettingsRequest{ApiKey: "token:svc1"})
+	require.NoError(t, err)
+	require.Equal(t, collectorpb.ResultCode_TRY_LATER, result.GetResult())
+
+	expected := &collectorpb.SettingsResult{
+		Result:   collectorpb.ResultCode_OK,
+		Settings: []*collectorpb.OboeSetting{{Value: 1000000}},
+	}
+	cache.update("svc1", expected, []byte(`[{"value":1000000}]`))
+	result, err = server.GetSettings(context.Background(), &collectorpb.SettingsRequest{ApiKey: "token:svc1"})
+	require.NoError(t, err)
+	require.Same(t, expected, result)
+
+	result, err = server.GetSettings(context.Background(), &collectorpb.SettingsRequest{ApiKey: "token:svc2"})
+	require.NoError(t, err)
+	require.Equal(t, collectorpb.ResultCode_TRY_LATER, result.GetResult())
+}
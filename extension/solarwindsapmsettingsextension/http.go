@@ -0,0 +1,100 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package solarwindsapmsettingsextension // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/solarwindsapmsettingsextension"
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"sync"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componentstatus"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.uber.org/zap"
+)
+
+const settingsHTTPPath = "/solarwinds-apm-settings"
+
+var _ component.Component = (*settingsHTTPServer)(nil)
+
+// settingsHTTPServer serves the latest settings as JSON on settingsHTTPPath.
+// The service is selected with the `service` query parameter.
+type settingsHTTPServer struct {
+	telemetry component.TelemetrySettings
+	config    confighttp.ServerConfig
+	cache     *settingsCache
+
+	server     *http.Server
+	shutdownWG sync.WaitGroup
+}
+
+func newSettingsHTTPServer(telemetry component.TelemetrySettings, config confighttp.ServerConfig, cache *settingsCache) *settingsHTTPServer {
+	return &settingsHTTPServer{
+		telemetry: telemetry,
+		config:    config,
+		cache:     cache,
+	}
+}
+
+func (s *settingsHTTPServer) Start(ctx context.Context, host component.Host) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc(settingsHTTPPath, s.settingsHandler)
+
+	var err error
+	s.server, err = s.config.ToServer(ctx, host, s.telemetry, mux)
+	if err != nil {
+		return err
+	}
+	listener, err := s.config.ToListener(ctx)
+	if err != nil {
+		return err
+	}
+	s.telemetry.Logger.Info("serving settings over HTTP", zap.String("endpoint", s.config.Endpoint))
+
+	s.shutdownWG.Add(1)
+	go func() {
+		defer s.shutdownWG.Done()
+		if err := s.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			componentstatus.ReportStatus(host, componentstatus.NewFatalErrorEvent(err))
+		}
+	}()
+	return nil
+}
+
+func (s *settingsHTTPServer) Shutdown(ctx context.Context) error {
+	if s.server == nil {
+		return nil
+	}
+	err := s.server.Shutdown(ctx)
+	s.shutdownWG.Wait()
+	return err
+}
+
+// settingsHandler writes the latest settings. An ETag is returned so that agents
+// polling the endpoint with If-None-Match only receive the body when it changed.
+func (s *settingsHTTPServer) settingsHandler(rw http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	content := s.cache.settings(r.URL.Query().Get("service"))
+	if content == nil {
+		http.Error(rw, "settings are not available yet", http.StatusServiceUnavailable)
+		return
+	}
+	sum := sha256.Sum256(content)
+	etag := `"` + hex.EncodeToString(sum[:]) + `"`
+	rw.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		rw.WriteHeader(http.StatusNotModified)
+		return
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	if _, err := rw.Write(content); err != nil {
+		s.telemetry.Logger.Debug("unable to write settings response", zap.Error(err))
+	}
+}
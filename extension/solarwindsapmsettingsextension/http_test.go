@@ -0,0 +1,54 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package solarwindsapmsettingsextension
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/solarwindscloud/apm-proto/go/collectorpb"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/confighttp"
+)
+
+func TestSettingsHTTPHandler(t *testing.T) {
+	cache := newSettingsCache("svc1")
+	server := newSettingsHTTPServer(componenttest.NewNopTelemetrySettings(), confighttp.ServerConfig{}, cache)
+
+	rw := httptest.NewRecorder()
+	server.settingsHandler(rw, httptest.NewRequest(http.MethodGet, settingsHTTPPath, nil))
+	require.Equal(t, http.StatusServiceUnavailable, rw.Code)
+
+	cache.update("svc1", &collectorpb.SettingsResult{}, []byte(`[{"value":1}]`))
+	cache.update("svc2", &collectorpb.SettingsResult{}, []byte(`[{"value":2}]`))
+	rw = httptest.NewRecorder()
+	server.settingsHandler(rw, httptest.NewRequest(http.MethodGet, settingsHTTPPath, nil))
+	require.Equal(t, http.StatusOK, rw.Code)
+	require.Equal(t, "application/json", rw.Header().Get("Content-Type"))
+	require.Equal(t, `[{"value":1}]`, rw.Body.String())
+	etag := rw.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	req := httptest.NewRequest(http.MethodGet, settingsHTTPPath, nil)
+	req.Header.Set("If-None-Match", etag)
+	rw = httptest.NewRecorder()
+	server.settingsHandler(rw, req)
+	require.Equal(t, http.StatusNotModified, rw.Code)
+	require.Empty(t, rw.Body.String())
+
+	rw = httptest.NewRecorder()
+	server.settingsHandler(rw, httptest.NewRequest(http.MethodGet, settingsHTTPPath+"?service=svc2", nil))
+	require.Equal(t, http.StatusOK, rw.Code)
+	require.Equal(t, `[{"value":2}]`, rw.Body.String())
+
+	rw = httptest.NewRecorder()
+	server.settingsHandler(rw, httptest.NewRequest(http.MethodGet, settingsHTTPPath+"?service=unknown", nil))
+	require.Equal(t, http.StatusServiceUnavailable, rw.Code)
+
+	rw = httptest.NewRecorder()
+	server.settingsHandler(rw, httptest.NewRequest(http.MethodPost, settingsHTTPPath, nil))
+	require.Equal(t, http.StatusMethodNotAllowed, rw.Code)
+}
@@ -0,0 +1,133 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package solarwindsapmsettingsextension // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/solarwindsapmsettingsextension"
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/solarwindscloud/apm-proto/go/collectorpb"
+	"go.opentelemetry.io/collector/extension"
+)
+
+// SettingsListener is called with the JSON encoded settings of a service every time they change.
+// settings is nil when the settings of the service expired. It is an alias, so that components can
+// declare their own SettingsProvider interface without depending on this module.
+type SettingsListener = func(service string, settings []byte)
+
+// SettingsProvider is implemented by the extension so that other components can
+// read the latest Solarwinds APM settings and be notified when they change.
+type SettingsProvider interface {
+	extension.Extension
+	// Settings returns the latest JSON encoded settings of the service, or nil if there are none.
+	// An empty service refers to the service of the first configured key.
+	Settings(service string) []byte
+	// Subscribe registers a listener which is called whenever the settings of a service change.
+	// The listener is called immediately for every service whose settings are already known.
+	// The returned function removes the listener.
+	Subscribe(listener SettingsListener) func()
+}
+
+type settingsEntry struct {
+	result  *collectorpb.SettingsResult
+	content []byte
+}
+
+// settingsCache holds the latest settings per service and the registered listeners.
+type settingsCache struct {
+	mu             sync.RWMutex
+	defaultService string
+	entries        map[string]settingsEntry
+	listeners      map[int]SettingsListener
+	nextID         int
+}
+
+func newSettingsCache(defaultService string) *settingsCache {
+	return &settingsCache{
+		defaultService: defaultService,
+		entries:        make(map[string]settingsEntry),
+		listeners:      make(map[int]SettingsListener),
+	}
+}
+
+// update stores the settings of the service and notifies the listeners. It reports whether the settings changed.
+func (c *settingsCache) update(service string, result *collectorpb.SettingsResult, content []byte) bool {
+	c.mu.Lock()
+	previous := c.entries[service]
+	c.entries[service] = settingsEntry{result: result, content: content}
+	if bytes.Equal(previous.content, content) {
+		c.mu.Unlock()
+		return false
+	}
+	listeners := c.listenersLocked()
+	c.mu.Unlock()
+
+	for _, listener := range listeners {
+		listener(service, content)
+	}
+	return true
+}
+
+// evict removes the settings of the service and notifies the listeners. It reports whether there were settings.
+func (c *settingsCache) evict(service string) bool {
+	c.mu.Lock()
+	if _, ok := c.entries[service]; !ok {
+		c.mu.Unlock()
+		return false
+	}
+	delete(c.entries, service)
+	listeners := c.listenersLocked()
+	c.mu.Unlock()
+
+	for _, listener := range listeners {
+		listener(service, nil)
+	}
+	return true
+}
+
+func (c *settingsCache) listenersLocked() []SettingsListener {
+	listeners := make([]SettingsListener, 0, len(c.listeners))
+	for _, listener := range c.listeners {
+		listeners = append(listeners, listener)
+	}
+	return listeners
+}
+
+func (c *settingsCache) lookup(service string) settingsEntry {
+	if service == "" {
+		service = c.defaultService
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.entries[service]
+}
+
+func (c *settingsCache) settingsResult(service string) *collectorpb.SettingsResult {
+	return c.lookup(service).result
+}
+
+func (c *settingsCache) settings(service string) []byte {
+	return c.lookup(service).content
+}
+
+func (c *settingsCache) subscribe(listener SettingsListener) func() {
+	c.mu.Lock()
+	id := c.nextID
+	c.nextID++
+	c.listeners[id] = listener
+	current := make(map[string][]byte, len(c.entries))
+	for service, entry := range c.entries {
+		current[service] = entry.content
+	}
+	c.mu.Unlock()
+
+	for service, content := range current {
+		listener(service, content)
+	}
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		delete(c.listeners, id)
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package solarwindsapmsettingsextension
+
+import (
+	"testing"
+
+	"github.com/solarwindscloud/apm-proto/go/collectorpb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSettingsCache(t *testing.T) {
+	cache := newSettingsCache("svc1")
+	require.Nil(t, cache.settings(""))
+	require.Nil(t, cache.settingsResult("svc1"))
+
+	received := map[string][][]byte{}
+	unsubscribe := cache.subscribe(func(service string, settings []byte) {
+		received[service] = append(received[service], settings)
+	})
+	require.Empty(t, received, "listener must not be called before settings are known")
+
+	result := &collectorpb.SettingsResult{Result: collectorpb.ResultCode_OK}
+	require.True(t, cache.update("svc1", result, []byte(`[{"value":1}]`)))
+	require.False(t, cache.update("svc1", result, []byte(`[{"value":1}]`)), "unchanged settings must not notify")
+	require.True(t, cache.update("svc1", result, []byte(`[{"value":2}]`)))
+	require.True(t, cache.update("svc2", result, []byte(`[{"value":3}]`)))
+	require.Equal(t, [][]byte{[]byte(`[{"value":1}]`), []byte(`[{"value":2}]`)}, received["svc1"])
+	require.Equal(t, [][]byte{[]byte(`[{"value":3}]`)}, received["svc2"])
+	require.Equal(t, []byte(`[{"value":2}]`), cache.settings(""), "empty service must refer to the default service")
+	require.Equal(t, []byte(`[{"value":3}]`), cache.settings("svc2"))
+	require.Same(t, result, cache.settingsResult("svc1"))
+
+	late := map[string][]byte{}
+	cache.subscribe(func(service string, settings []byte) {
+		late[service] = settings
+	})
+	require.Equal(t, map[string][]byte{"svc1": []byte(`[{"value":2}]`), "svc2": []byte(`[{"value":3}]`)}, late, "late listener must receive the current settings")
+
+	unsubscribe()
+	require.True(t, cache.evict("svc2"))
+	require.False(t, cache.evict("svc2"))
+	require.Nil(t, cache.settings("svc2"))
+	require.Len(t, received["svc2"], 1)
+	require.Contains(t, late, "svc2")
+	require.Nil(t, late["svc2"], "eviction must notify with nil settings")
+}
@@ -0,0 +1,154 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package lookuptable reads keyed tables of reference data, such as the lookup tables of the OTTL Lookup converter,
+// from the storage extensions configured in components.
+package lookuptable // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/lookuptable"
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/golang-lru/v2/simplelru"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storageclient"
+)
+
+const (
+	// cacheSize is the maximum number of keys cached by a lookup table.
+	cacheSize = 10000
+	// cacheTTL is how long the value of a key is cached by a lookup table.
+	cacheTTL = time.Minute
+)
+
+// Tables are the lookup tables of a component. The tables can be used once started.
+type Tables struct {
+	tables      map[string]*Table
+	kind        component.Kind
+	componentID component.ID
+}
+
+var _ component.Component = (*Tables)(nil)
+
+// NewTables returns the lookup tables of a component, mapping the name of each table to the ID of the storage
+// extension it is read from. The storage client of each table is named after the table.
+func NewTables(storageIDs map[string]component.ID, kind component.Kind, componentID component.ID) *Tables {
+	tables := make(map[string]*Table, len(storageIDs))
+	for name, storageID := range storageIDs {
+		cache, _ := simplelru.NewLRU[string, cacheEntry](cacheSize, nil)
+		tables[name] = &Table{
+			name:      name,
+			storageID: storageID,
+			cache:     cache,
+			ttl:       cacheTTL,
+		}
+	}
+	return &Tables{
+		tables:      tables,
+		kind:        kind,
+		componentID: componentID,
+	}
+}
+
+// Tables returns the tables by name.
+func (t *Tables) Tables() map[string]*Table {
+	return t.tables
+}
+
+// Start opens the storage clients of the tables.
+func (t *Tables) Start(ctx context.Context, host component.Host) error {
+	for name, table := range t.tables {
+		client, err := storageclient.Get(ctx, host, table.storageID, t.kind, t.componentID, name)
+		if err != nil {
+			return fmt.Errorf("failed to open lookup table %q: %w", name, err)
+		}
+		table.setClient(client)
+	}
+	return nil
+}
+
+// Shutdown closes the storage clients of the tables.
+func (t *Tables) Shutdown(ctx context.Context) error {
+	var errs error
+	for _, table := range t.tables {
+		if client := table.setClient(nil); client != nil {
+			errs = errors.Join(errs, client.Close(ctx))
+		}
+	}
+	return errs
+}
+
+// Table is a table read from a storage extension. Values that are valid JSON documents are decoded, other
+// values are returned as strings. The values of recently used keys, including the keys which are not in the table,
+// are cached for a minute.
+type Table struct {
+	name      string
+	storageID component.ID
+	ttl       time.Duration
+
+	mu     sync.Mutex
+	client storage.Client
+	cache  *simplelru.LRU[string, cacheEntry]
+}
+
+type cacheEntry struct {
+	value    pcommon.Value
+	found    bool
+	cachedAt time.Time
+}
+
+// Get returns the value of the key, and whether the key is in the table.
+func (t *Table) Get(ctx context.Context, key string) (pcommon.Value, bool, error) {
+	now := time.Now()
+	t.mu.Lock()
+	client := t.client
+	entry, cached := t.cache.Get(key)
+	t.mu.Unlock()
+
+	if client == nil {
+		return pcommon.Value{}, false, fmt.Errorf("lookup table %q is not started", t.name)
+	}
+	if cached && now.Sub(entry.cachedAt) < t.ttl {
+		return entry.value, entry.found, nil
+	}
+
+	buf, err := client.Get(ctx, key)
+	if err != nil {
+		return pcommon.Value{}, false, err
+	}
+	entry = cacheEntry{cachedAt: now}
+	if buf != nil {
+		entry.value, entry.found = decodeValue(buf), true
+	}
+
+	t.mu.Lock()
+	t.cache.Add(key, entry)
+	t.mu.Unlock()
+	return entry.value, entry.found, nil
+}
+
+// setClient replaces the storage client of the table, and clears its cache. It returns the previous client.
+func (t *Table) setClient(client storage.Client) storage.Client {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	previous := t.client
+	t.client = client
+	t.cache.Purge()
+	return previous
+}
+
+func decodeValue(buf []byte) pcommon.Value {
+	var raw any
+	value := pcommon.NewValueEmpty()
+	if err := json.Unmarshal(buf, &raw); err != nil || value.FromRaw(raw) != nil {
+		value.SetStr(string(buf))
+	}
+	return value
+}
@@ -0,0 +1,90 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package lookuptable
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storagetest"
+)
+
+func TestLookupTables(t *testing.T) {
+	host := storagetest.NewStorageHost().WithInMemoryStorageExtension("storage")
+	tables := NewTables(map[string]component.ID{"owners": storagetest.NewStorageID("storage")}, component.KindProcessor, component.MustNewID("transform"))
+	table := tables.Tables()["owners"]
+	require.NotNil(t, table)
+
+	_, _, err := table.Get(context.Background(), "checkout")
+	assert.ErrorContains(t, err, `lookup table "owners" is not started`)
+
+	require.NoError(t, tables.Start(context.Background(), host))
+	require.NoError(t, table.client.Set(context.Background(), "checkout", []byte("payments")))
+	require.NoError(t, table.client.Set(context.Background(), "search", []byte(`{"team": "discovery"}`)))
+
+	value, ok, err := table.Get(context.Background(), "checkout")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "payments", value.Str())
+
+	value, ok, err = table.Get(context.Background(), "search")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, map[string]any{"team": "discovery"}, value.Map().AsRaw())
+
+	_, ok, err = table.Get(context.Background(), "unknown")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, tables.Shutdown(context.Background()))
+	_, _, err = table.Get(context.Background(), "checkout")
+	assert.Error(t, err)
+}
+
+func TestLookupTableCache(t *testing.T) {
+	host := storagetest.NewStorageHost().WithInMemoryStorageExtension("storage")
+	tables := NewTables(map[string]component.ID{"owners": storagetest.NewStorageID("storage")}, component.KindProcessor, component.MustNewID("transform"))
+	table := tables.Tables()["owners"]
+	require.NoError(t, tables.Start(context.Background(), host))
+	defer func() { require.NoError(t, tables.Shutdown(context.Background())) }()
+
+	require.NoError(t, table.client.Set(context.Background(), "checkout", []byte("payments")))
+	value, _, err := table.Get(context.Background(), "checkout")
+	require.NoError(t, err)
+	assert.Equal(t, "payments", value.Str())
+	_, ok, err := table.Get(context.Background(), "search")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// The values, and the keys which are not in the table, are cached
+	require.NoError(t, table.client.Set(context.Background(), "checkout", []byte("billing")))
+	require.NoError(t, table.client.Set(context.Background(), "search", []byte("discovery")))
+	value, _, err = table.Get(context.Background(), "checkout")
+	require.NoError(t, err)
+	assert.Equal(t, "payments", value.Str())
+	_, ok, err = table.Get(context.Background(), "search")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// Expired values are read again
+	table.ttl = 0
+	value, _, err = table.Get(context.Background(), "checkout")
+	require.NoError(t, err)
+	assert.Equal(t, "billing", value.Str())
+	value, ok, err = table.Get(context.Background(), "search")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "discovery", value.Str())
+}
+
+func TestLookupTablesStartError(t *testing.T) {
+	host := storagetest.NewStorageHost()
+	tables := NewTables(map[string]component.ID{"owners": storagetest.NewStorageID("missing")}, component.KindProcessor, component.MustNewID("transform"))
+	err := tables.Start(context.Background(), host)
+	assert.ErrorContains(t, err, `failed to open lookup table "owners": storage extension 'test_storage/missing' not found`)
+}
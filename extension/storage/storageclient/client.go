@@ -0,0 +1,27 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package storageclient gets storage clients from the storage extensions configured in components.
+package storageclient // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storageclient"
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+)
+
+// Get returns a client of the storage extension storageID for the component. The name distinguishes the clients of
+// a component which needs more than one.
+func Get(ctx context.Context, host component.Host, storageID component.ID, kind component.Kind, componentID component.ID, name string) (storage.Client, error) {
+	ext, ok := host.GetExtensions()[storageID]
+	if !ok {
+		return nil, fmt.Errorf("storage extension '%s' not found", storageID)
+	}
+	storageExtension, ok := ext.(storage.Extension)
+	if !ok {
+		return nil, fmt.Errorf("non-storage extension '%s' found", storageID)
+	}
+	return storageExtension.GetClient(ctx, kind, componentID, name)
+}
@@ -0,0 +1,35 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package storageclient
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storagetest"
+)
+
+func TestGet(t *testing.T) {
+	componentID := component.MustNewID("test")
+	host := storagetest.NewStorageHost().
+		WithInMemoryStorageExtension("storage").
+		WithNonStorageExtension("other")
+
+	client, err := Get(context.Background(), host, storagetest.NewStorageID("storage"), component.KindProcessor, componentID, "name")
+	require.NoError(t, err)
+	creatorID, err := storagetest.CreatorID(context.Background(), client)
+	require.NoError(t, err)
+	assert.Equal(t, storagetest.NewStorageID("storage"), creatorID)
+	require.NoError(t, client.Close(context.Background()))
+
+	_, err = Get(context.Background(), host, storagetest.NewStorageID("missing"), component.KindProcessor, componentID, "")
+	assert.ErrorContains(t, err, "storage extension 'test_storage/missing' not found")
+
+	_, err = Get(context.Background(), host, storagetest.NewNonStorageID("other"), component.KindProcessor, componentID, "")
+	assert.ErrorContains(t, err, "non-storage extension 'non_storage/other' found")
+}
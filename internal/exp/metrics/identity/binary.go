@@ -0,0 +1,102 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package identity // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/exp/metrics/identity"
+
+import (
+	"encoding"
+	"encoding/binary"
+	"errors"
+
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+var (
+	_ encoding.BinaryMarshaler   = Stream{}
+	_ encoding.BinaryUnmarshaler = (*Stream)(nil)
+)
+
+var errInvalidBinary = errors.New("invalid binary stream identity")
+
+// MarshalBinary encodes the stream identity, so that it can be persisted and
+// later restored using UnmarshalBinary.
+func (i Stream) MarshalBinary() ([]byte, error) {
+	m := i.metric
+	buf := make([]byte, 0, 3*16+len(m.scope.name)+len(m.scope.version)+len(m.name)+len(m.unit)+16)
+
+	buf = append(buf, m.scope.resource.attrs[:]...)
+	buf = appendString(buf, m.scope.name)
+	buf = appendString(buf, m.scope.version)
+	buf = append(buf, m.scope.attrs[:]...)
+
+	buf = appendString(buf, m.name)
+	buf = appendString(buf, m.unit)
+	var mono byte
+	if m.monotonic {
+		mono = 1
+	}
+	buf = append(buf, byte(m.ty), mono, byte(m.temporality))
+
+	buf = append(buf, i.attrs[:]...)
+	return buf, nil
+}
+
+// UnmarshalBinary decodes a stream identity encoded by MarshalBinary.
+func (i *Stream) UnmarshalBinary(data []byte) error {
+	var (
+		id  Stream
+		ok  = true
+		str string
+	)
+	data, ok = readHash(data, &id.metric.scope.resource.attrs, ok)
+	data, str, ok = readString(data, ok)
+	id.metric.scope.name = str
+	data, str, ok = readString(data, ok)
+	id.metric.scope.version = str
+	data, ok = readHash(data, &id.metric.scope.attrs, ok)
+
+	data, str, ok = readString(data, ok)
+	id.metric.name = str
+	data, str, ok = readString(data, ok)
+	id.metric.unit = str
+	if !ok || len(data) < 3 {
+		return errInvalidBinary
+	}
+	id.metric.ty = pmetric.MetricType(data[0])
+	id.metric.monotonic = data[1] == 1
+	id.metric.temporality = pmetric.AggregationTemporality(data[2])
+	data = data[3:]
+
+	data, ok = readHash(data, &id.attrs, ok)
+	if !ok || len(data) != 0 {
+		return errInvalidBinary
+	}
+
+	*i = id
+	return nil
+}
+
+func appendString(buf []byte, s string) []byte {
+	buf = binary.AppendUvarint(buf, uint64(len(s)))
+	return append(buf, s...)
+}
+
+func readString(data []byte, ok bool) ([]byte, string, bool) {
+	if !ok {
+		return data, "", false
+	}
+	n, size := binary.Uvarint(data)
+	if size <= 0 || uint64(len(data)-size) < n {
+		return data, "", false
+	}
+	data = data[size:]
+	return data[n:], string(data[:n]), true
+}
+
+func readHash(data []byte, dst *[16]byte, ok bool) ([]byte, bool) {
+	if !ok || len(data) < len(dst) {
+		return data, false
+	}
+	copy(dst[:], data)
+	return data[len(dst):], true
+}
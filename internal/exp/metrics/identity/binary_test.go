@@ -0,0 +1,45 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package identity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+func TestStreamBinary(t *testing.T) {
+	res := pcommon.NewResource()
+	res.Attributes().PutStr("service.name", "svc")
+	scope := pcommon.NewInstrumentationScope()
+	scope.SetName("scope")
+	scope.SetVersion("v1")
+	scope.Attributes().PutInt("n", 1)
+
+	m := pmetric.NewMetric()
+	m.SetName("requests")
+	m.SetUnit("1")
+	sum := m.SetEmptySum()
+	sum.SetIsMonotonic(true)
+	sum.SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+	dp := sum.DataPoints().AppendEmpty()
+	dp.Attributes().PutStr("path", "/")
+
+	id := OfStream(OfResourceMetric(res, scope, m), dp)
+
+	data, err := id.MarshalBinary()
+	require.NoError(t, err)
+
+	var got Stream
+	require.NoError(t, got.UnmarshalBinary(data))
+	require.Equal(t, id, got)
+	require.Equal(t, id.Hash().Sum64(), got.Hash().Sum64())
+
+	for n := 0; n < len(data); n++ {
+		require.ErrorIs(t, got.UnmarshalBinary(data[:n]), errInvalidBinary)
+	}
+	require.ErrorIs(t, got.UnmarshalBinary(append(data, 0)), errInvalidBinary)
+}
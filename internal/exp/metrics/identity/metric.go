@@ -40,6 +40,13 @@ func (i Metric) Scope() Scope {
 	return i.scope
 }
 
+// WithTemporality returns the identity of the same metric, had it the given
+// aggregation temporality.
+func (i Metric) WithTemporality(temporality pmetric.AggregationTemporality) Metric {
+	i.temporality = temporality
+	return i
+}
+
 func OfMetric(scope Scope, m pmetric.Metric) Metric {
 	id := Metric{
 		scope: scope,
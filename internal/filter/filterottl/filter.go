@@ -20,8 +20,9 @@ import (
 // NewBoolExprForSpan creates a BoolExpr[ottlspan.TransformContext] that will return true if any of the given OTTL conditions evaluate to true.
 // The passed in functions should use the ottlspan.TransformContext.
 // If a function named `match` is not present in the function map it will be added automatically so that parsing works as expected
-func NewBoolExprForSpan(conditions []string, functions map[string]ottl.Factory[ottlspan.TransformContext], errorMode ottl.ErrorMode, set component.TelemetrySettings) (expr.BoolExpr[ottlspan.TransformContext], error) {
-	parser, err := ottlspan.NewParser(functions, set)
+// The options are applied to the parser of the conditions.
+func NewBoolExprForSpan(conditions []string, functions map[string]ottl.Factory[ottlspan.TransformContext], errorMode ottl.ErrorMode, set component.TelemetrySettings, options ...ottlspan.Option) (expr.BoolExpr[ottlspan.TransformContext], error) {
+	parser, err := ottlspan.NewParser(functions, set, options...)
 	if err != nil {
 		return nil, err
 	}
@@ -36,8 +37,9 @@ func NewBoolExprForSpan(conditions []string, functions map[string]ottl.Factory[o
 // NewBoolExprForSpanEvent creates a BoolExpr[ottlspanevent.TransformContext] that will return true if any of the given OTTL conditions evaluate to true.
 // The passed in functions should use the ottlspanevent.TransformContext.
 // If a function named `match` is not present in the function map it will be added automatically so that parsing works as expected
-func NewBoolExprForSpanEvent(conditions []string, functions map[string]ottl.Factory[ottlspanevent.TransformContext], errorMode ottl.ErrorMode, set component.TelemetrySettings) (expr.BoolExpr[ottlspanevent.TransformContext], error) {
-	parser, err := ottlspanevent.NewParser(functions, set)
+// The options are applied to the parser of the conditions.
+func NewBoolExprForSpanEvent(conditions []string, functions map[string]ottl.Factory[ottlspanevent.TransformContext], errorMode ottl.ErrorMode, set component.TelemetrySettings, options ...ottlspanevent.Option) (expr.BoolExpr[ottlspanevent.TransformContext], error) {
+	parser, err := ottlspanevent.NewParser(functions, set, options...)
 	if err != nil {
 		return nil, err
 	}
@@ -52,8 +54,9 @@ func NewBoolExprForSpanEvent(conditions []string, functions map[string]ottl.Fact
 // NewBoolExprForMetric creates a BoolExpr[ottlmetric.TransformContext] that will return true if any of the given OTTL conditions evaluate to true.
 // The passed in functions should use the ottlmetric.TransformContext.
 // If a function named `match` is not present in the function map it will be added automatically so that parsing works as expected
-func NewBoolExprForMetric(conditions []string, functions map[string]ottl.Factory[ottlmetric.TransformContext], errorMode ottl.ErrorMode, set component.TelemetrySettings) (expr.BoolExpr[ottlmetric.TransformContext], error) {
-	parser, err := ottlmetric.NewParser(functions, set)
+// The options are applied to the parser of the conditions.
+func NewBoolExprForMetric(conditions []string, functions map[string]ottl.Factory[ottlmetric.TransformContext], errorMode ottl.ErrorMode, set component.TelemetrySettings, options ...ottlmetric.Option) (expr.BoolExpr[ottlmetric.TransformContext], error) {
+	parser, err := ottlmetric.NewParser(functions, set, options...)
 	if err != nil {
 		return nil, err
 	}
@@ -68,8 +71,9 @@ func NewBoolExprForMetric(conditions []string, functions map[string]ottl.Factory
 // NewBoolExprForDataPoint creates a BoolExpr[ottldatapoint.TransformContext] that will return true if any of the given OTTL conditions evaluate to true.
 // The passed in functions should use the ottldatapoint.TransformContext.
 // If a function named `match` is not present in the function map it will be added automatically so that parsing works as expected
-func NewBoolExprForDataPoint(conditions []string, functions map[string]ottl.Factory[ottldatapoint.TransformContext], errorMode ottl.ErrorMode, set component.TelemetrySettings) (expr.BoolExpr[ottldatapoint.TransformContext], error) {
-	parser, err := ottldatapoint.NewParser(functions, set)
+// The options are applied to the parser of the conditions.
+func NewBoolExprForDataPoint(conditions []string, functions map[string]ottl.Factory[ottldatapoint.TransformContext], errorMode ottl.ErrorMode, set component.TelemetrySettings, options ...ottldatapoint.Option) (expr.BoolExpr[ottldatapoint.TransformContext], error) {
+	parser, err := ottldatapoint.NewParser(functions, set, options...)
 	if err != nil {
 		return nil, err
 	}
@@ -84,8 +88,9 @@ func NewBoolExprForDataPoint(conditions []string, functions map[string]ottl.Fact
 // NewBoolExprForLog creates a BoolExpr[ottllog.TransformContext] that will return true if any of the given OTTL conditions evaluate to true.
 // The passed in functions should use the ottllog.TransformContext.
 // If a function named `match` is not present in the function map it will be added automatically so that parsing works as expected
-func NewBoolExprForLog(conditions []string, functions map[string]ottl.Factory[ottllog.TransformContext], errorMode ottl.ErrorMode, set component.TelemetrySettings) (expr.BoolExpr[ottllog.TransformContext], error) {
-	parser, err := ottllog.NewParser(functions, set)
+// The options are applied to the parser of the conditions.
+func NewBoolExprForLog(conditions []string, functions map[string]ottl.Factory[ottllog.TransformContext], errorMode ottl.ErrorMode, set component.TelemetrySettings, options ...ottllog.Option) (expr.BoolExpr[ottllog.TransformContext], error) {
+	parser, err := ottllog.NewParser(functions, set, options...)
 	if err != nil {
 		return nil, err
 	}
@@ -100,8 +105,9 @@ func NewBoolExprForLog(conditions []string, functions map[string]ottl.Factory[ot
 // NewBoolExprForResource creates a BoolExpr[ottlresource.TransformContext] that will return true if any of the given OTTL conditions evaluate to true.
 // The passed in functions should use the ottlresource.TransformContext.
 // If a function named `match` is not present in the function map it will be added automatically so that parsing works as expected
-func NewBoolExprForResource(conditions []string, functions map[string]ottl.Factory[ottlresource.TransformContext], errorMode ottl.ErrorMode, set component.TelemetrySettings) (expr.BoolExpr[ottlresource.TransformContext], error) {
-	parser, err := ottlresource.NewParser(functions, set)
+// The options are applied to the parser of the conditions.
+func NewBoolExprForResource(conditions []string, functions map[string]ottl.Factory[ottlresource.TransformContext], errorMode ottl.ErrorMode, set component.TelemetrySettings, options ...ottlresource.Option) (expr.BoolExpr[ottlresource.TransformContext], error) {
+	parser, err := ottlresource.NewParser(functions, set, options...)
 	if err != nil {
 		return nil, err
 	}
@@ -116,8 +122,9 @@ func NewBoolExprForResource(conditions []string, functions map[string]ottl.Facto
 // NewBoolExprForScope creates a BoolExpr[ottlscope.TransformContext] that will return true if any of the given OTTL conditions evaluate to true.
 // The passed in functions should use the ottlresource.TransformContext.
 // If a function named `match` is not present in the function map it will be added automatically so that parsing works as expected
-func NewBoolExprForScope(conditions []string, functions map[string]ottl.Factory[ottlscope.TransformContext], errorMode ottl.ErrorMode, set component.TelemetrySettings) (expr.BoolExpr[ottlscope.TransformContext], error) {
-	parser, err := ottlscope.NewParser(functions, set)
+// The options are applied to the parser of the conditions.
+func NewBoolExprForScope(conditions []string, functions map[string]ottl.Factory[ottlscope.TransformContext], errorMode ottl.ErrorMode, set component.TelemetrySettings, options ...ottlscope.Option) (expr.BoolExpr[ottlscope.TransformContext], error) {
+	parser, err := ottlscope.NewParser(functions, set, options...)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,86 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottl // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+
+import (
+	"context"
+	"fmt"
+	"maps"
+)
+
+// binding is a name bound to a value while a statement is built, like a loop variable or a parameter of a
+// user-defined function. Bindings are referenced like single field paths, which they shadow.
+type binding[K any] interface {
+	getter(keys []Key[K]) (Getter[K], error)
+	getSetter(keys []Key[K]) (GetSetter[K], error)
+}
+
+// lookupBinding returns the binding referenced by a path, and the keys indexing it. Only paths made of a single field
+// without a context may reference a binding.
+func (p *Parser[K]) lookupBinding(path *path) (binding[K], []key, bool) {
+	if path.Context != "" || len(path.Fields) != 1 {
+		return nil, nil, false
+	}
+	b, ok := p.bindings[path.Fields[0].Name]
+	if !ok {
+		return nil, nil, false
+	}
+	return b, path.Fields[0].Keys, true
+}
+
+// withBindings returns a copy of the parser in which the given bindings are added to the existing ones.
+func (p *Parser[K]) withBindings(bindings map[string]binding[K]) *Parser[K] {
+	scoped := *p
+	scoped.bindings = make(map[string]binding[K], len(p.bindings)+len(bindings))
+	maps.Copy(scoped.bindings, p.bindings)
+	maps.Copy(scoped.bindings, bindings)
+	return &scoped
+}
+
+// loopVariable is a variable of a `for each` loop. Its value is carried by the context.Context passed to the body of
+// the loop, using the variable itself as the key.
+type loopVariable[K any] struct {
+	name string
+}
+
+func (v *loopVariable[K]) getter(keys []Key[K]) (Getter[K], error) {
+	return &exprGetter[K]{
+		expr: Expr[K]{exprFunc: func(ctx context.Context, _ K) (any, error) {
+			return ctx.Value(v), nil
+		}},
+		keys: keys,
+	}, nil
+}
+
+func (v *loopVariable[K]) getSetter([]Key[K]) (GetSetter[K], error) {
+	return nil, fmt.Errorf("loop variable %q cannot be set", v.name)
+}
+
+// parameter is a parameter of a user-defined function, bound to the argument of a call. The argument is built in the
+// scope of the caller, so it may reference the caller's own bindings.
+type parameter[K any] struct {
+	name   string
+	arg    value
+	caller *Parser[K]
+}
+
+func (a *parameter[K]) getter(keys []Key[K]) (Getter[K], error) {
+	if len(keys) == 0 {
+		return a.caller.newGetter(a.arg)
+	}
+	if a.arg.Literal != nil && a.arg.Literal.Path != nil {
+		return a.caller.newPathGetter(a.arg.Literal.Path, keys)
+	}
+	if a.arg.Literal != nil && a.arg.Literal.Converter != nil {
+		return a.caller.newGetterFromConverter(*a.arg.Literal.Converter, keys...)
+	}
+	return nil, fmt.Errorf("parameter %q cannot be indexed, only paths and converters may be indexed", a.name)
+}
+
+func (a *parameter[K]) getSetter(keys []Key[K]) (GetSetter[K], error) {
+	if a.arg.Literal == nil || a.arg.Literal.Path == nil {
+		return nil, fmt.Errorf("parameter %q must be bound to a path to be set", a.name)
+	}
+	return a.caller.newGetSetter(a.arg.Literal.Path, keys)
+}
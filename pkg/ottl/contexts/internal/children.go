@@ -0,0 +1,30 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/internal"
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/internal/ottlcommon"
+)
+
+// accessChildrenKey reads into the child items of a collection, like `events[0]["name"]`. The first key is the index
+// of the item, and the following keys index a copy of the item, whose fields are named after the paths of the child
+// context. Child items are read-only from their parent context.
+func accessChildrenKey[K any](name string, children func(tCtx K) any, keys []ottl.Key[K]) ottl.StandardGetSetter[K] {
+	return ottl.StandardGetSetter[K]{
+		Getter: func(ctx context.Context, tCtx K) (any, error) {
+			s, ok := ottlcommon.ChildSlice(children(tCtx))
+			if !ok {
+				return nil, nil
+			}
+			return GetSliceValue[K](ctx, tCtx, s, keys)
+		},
+		Setter: func(_ context.Context, _ K, _ any) error {
+			return fmt.Errorf("the items of %s cannot be set by index", name)
+		},
+	}
+}
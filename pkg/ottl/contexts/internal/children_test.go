@@ -0,0 +1,122 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottltest"
+)
+
+func TestSpanPathGetSetter_Children(t *testing.T) {
+	tests := []struct {
+		name string
+		path ottl.Path[*spanContext]
+		want any
+	}{
+		{
+			name: "event",
+			path: &TestPath[*spanContext]{
+				N: "events",
+				KeySlice: []ottl.Key[*spanContext]{
+					&TestKey[*spanContext]{I: ottltest.Intp(0)},
+				},
+			},
+			want: func() pcommon.Map {
+				m := pcommon.NewMap()
+				m.PutStr("name", "event")
+				m.PutInt("time_unix_nano", 0)
+				m.PutEmptyMap("attributes")
+				m.PutInt("dropped_attributes_count", 0)
+				return m
+			}(),
+		},
+		{
+			name: "event name",
+			path: &TestPath[*spanContext]{
+				N: "events",
+				KeySlice: []ottl.Key[*spanContext]{
+					&TestKey[*spanContext]{I: ottltest.Intp(0)},
+					&TestKey[*spanContext]{S: ottltest.Strp("name")},
+				},
+			},
+			want: "event",
+		},
+		{
+			name: "link trace_id",
+			path: &TestPath[*spanContext]{
+				N: "links",
+				KeySlice: []ottl.Key[*spanContext]{
+					&TestKey[*spanContext]{I: ottltest.Intp(0)},
+					&TestKey[*spanContext]{S: ottltest.Strp("trace_id")},
+				},
+			},
+			want: hex.EncodeToString(traceID[:]),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accessor, err := SpanPathGetSetter[*spanContext](tt.path)
+			require.NoError(t, err)
+
+			span := createSpan()
+
+			got, err := accessor.Get(context.Background(), newSpanContext(span))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+
+			err = accessor.Set(context.Background(), newSpanContext(span), "value")
+			assert.Error(t, err)
+			assert.Equal(t, createSpan(), span)
+		})
+	}
+}
+
+func TestSpanPathGetSetter_ChildrenOutOfBounds(t *testing.T) {
+	accessor, err := SpanPathGetSetter[*spanContext](&TestPath[*spanContext]{
+		N: "events",
+		KeySlice: []ottl.Key[*spanContext]{
+			&TestKey[*spanContext]{I: ottltest.Intp(1)},
+		},
+	})
+	require.NoError(t, err)
+
+	_, err = accessor.Get(context.Background(), newSpanContext(createSpan()))
+	assert.ErrorContains(t, err, "index 1 out of bounds")
+}
+
+func TestMetricPathGetSetter_Children(t *testing.T) {
+	accessor, err := MetricPathGetSetter[*metricContext](&TestPath[*metricContext]{
+		N: "data_points",
+		KeySlice: []ottl.Key[*metricContext]{
+			&TestKey[*metricContext]{I: ottltest.Intp(1)},
+			&TestKey[*metricContext]{S: ottltest.Strp("attributes")},
+			&TestKey[*metricContext]{S: ottltest.Strp("host")},
+		},
+	})
+	require.NoError(t, err)
+
+	metric := createMetricTelemetry()
+	metric.Sum().DataPoints().AppendEmpty().SetIntValue(1)
+	dp := metric.Sum().DataPoints().AppendEmpty()
+	dp.SetDoubleValue(2)
+	dp.Attributes().PutStr("host", "localhost")
+
+	got, err := accessor.Get(context.Background(), newMetricContext(metric))
+	assert.NoError(t, err)
+	assert.Equal(t, "localhost", got)
+
+	empty := pmetric.NewMetric()
+	got, err = accessor.Get(context.Background(), newMetricContext(empty))
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+}
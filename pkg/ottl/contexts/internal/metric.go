@@ -45,6 +45,9 @@ func MetricPathGetSetter[K MetricContext](path ottl.Path[K]) (ottl.GetSetter[K],
 	case "is_monotonic":
 		return accessIsMonotonic[K](), nil
 	case "data_points":
+		if keys := path.Keys(); keys != nil {
+			return accessChildrenKey[K]("data_points", func(tCtx K) any { return dataPoints(tCtx.GetMetric()) }, keys), nil
+		}
 		return accessDataPoints[K](), nil
 	default:
 		return nil, FormatDefaultErrorMessage(path.Name(), path.String(), "Metric", MetricRef)
@@ -172,23 +175,27 @@ func accessIsMonotonic[K MetricContext]() ottl.StandardGetSetter[K] {
 	}
 }
 
+// dataPoints returns the data points of the metric, or nil if the metric type is empty.
+func dataPoints(metric pmetric.Metric) any {
+	switch metric.Type() {
+	case pmetric.MetricTypeSum:
+		return metric.Sum().DataPoints()
+	case pmetric.MetricTypeGauge:
+		return metric.Gauge().DataPoints()
+	case pmetric.MetricTypeHistogram:
+		return metric.Histogram().DataPoints()
+	case pmetric.MetricTypeExponentialHistogram:
+		return metric.ExponentialHistogram().DataPoints()
+	case pmetric.MetricTypeSummary:
+		return metric.Summary().DataPoints()
+	}
+	return nil
+}
+
 func accessDataPoints[K MetricContext]() ottl.StandardGetSetter[K] {
 	return ottl.StandardGetSetter[K]{
 		Getter: func(_ context.Context, tCtx K) (any, error) {
-			metric := tCtx.GetMetric()
-			switch metric.Type() {
-			case pmetric.MetricTypeSum:
-				return metric.Sum().DataPoints(), nil
-			case pmetric.MetricTypeGauge:
-				return metric.Gauge().DataPoints(), nil
-			case pmetric.MetricTypeHistogram:
-				return metric.Histogram().DataPoints(), nil
-			case pmetric.MetricTypeExponentialHistogram:
-				return metric.ExponentialHistogram().DataPoints(), nil
-			case pmetric.MetricTypeSummary:
-				return metric.Summary().DataPoints(), nil
-			}
-			return nil, nil
+			return dataPoints(tCtx.GetMetric()), nil
 		},
 		Setter: func(_ context.Context, tCtx K, val any) error {
 			metric := tCtx.GetMetric()
@@ -107,10 +107,16 @@ func SpanPathGetSetter[K SpanContext](path ottl.Path[K]) (ottl.GetSetter[K], err
 	case "dropped_attributes_count":
 		return accessSpanDroppedAttributesCount[K](), nil
 	case "events":
+		if keys := path.Keys(); keys != nil {
+			return accessChildrenKey[K]("events", func(tCtx K) any { return tCtx.GetSpan().Events() }, keys), nil
+		}
 		return accessEvents[K](), nil
 	case "dropped_events_count":
 		return accessDroppedEventsCount[K](), nil
 	case "links":
+		if keys := path.Keys(); keys != nil {
+			return accessChildrenKey[K]("links", func(tCtx K) any { return tCtx.GetSpan().Links() }, keys), nil
+		}
 		return accessLinks[K](), nil
 	case "dropped_links_count":
 		return accessDroppedLinksCount[K](), nil
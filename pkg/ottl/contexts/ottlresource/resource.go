@@ -71,6 +71,20 @@ func NewParser(functions map[string]ottl.Factory[TransformContext], telemetrySet
 	return p, nil
 }
 
+// WithFunctionDefinitions declares user-defined functions, which can be called like editors by the parsed statements.
+func WithFunctionDefinitions(definitions []ottl.FunctionDefinition) Option {
+	return func(p *ottl.Parser[TransformContext]) {
+		ottl.WithFunctionDefinitions[TransformContext](definitions)(p)
+	}
+}
+
+// WithLookupTables provides lookup tables, by name, to the functions of the parsed statements.
+func WithLookupTables(tables map[string]ottl.LookupTable) Option {
+	return func(p *ottl.Parser[TransformContext]) {
+		ottl.WithLookupTables[TransformContext](tables)(p)
+	}
+}
+
 type StatementSequenceOption func(*ottl.StatementSequence[TransformContext])
 
 func WithStatementSequenceErrorMode(errorMode ottl.ErrorMode) StatementSequenceOption {
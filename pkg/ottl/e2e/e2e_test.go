@@ -14,6 +14,7 @@ import (
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottllog"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlspan"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
@@ -301,6 +302,7 @@ func Test_e2e_editors(t *testing.T) {
 }
 
 func Test_e2e_converters(t *testing.T) {
+	t.Setenv("OTTL_E2E_HMAC_KEY", "secret")
 	tests := []struct {
 		statement string
 		want      func(tCtx ottllog.TransformContext)
@@ -631,6 +633,50 @@ func Test_e2e_converters(t *testing.T) {
 				tCtx.GetLogRecord().Attributes().PutDouble("test", 60)
 			},
 		},
+		{
+			statement: `set(attributes["test"], FormatTime(time, "%Y-%m-%dT%H:%M:%S"))`,
+			want: func(tCtx ottllog.TransformContext) {
+				tCtx.GetLogRecord().Attributes().PutStr("test", "2020-02-11T20:26:12")
+			},
+		},
+		{
+			statement: `set(attributes["test"], FormatTime(time, "%Y-%m-%d %H:%M %Z", "America/New_York"))`,
+			want: func(tCtx ottllog.TransformContext) {
+				tCtx.GetLogRecord().Attributes().PutStr("test", "2020-02-11 15:26 EST")
+			},
+		},
+		{
+			statement: `set(attributes["test"], "internal") where IsInCIDR("10.1.2.3", ["10.0.0.0/8", "172.16.0.0/12"])`,
+			want: func(tCtx ottllog.TransformContext) {
+				tCtx.GetLogRecord().Attributes().PutStr("test", "internal")
+			},
+		},
+		{
+			statement: `set(attributes["test"], CanonicalIP("2001:DB8:0::1"))`,
+			want: func(tCtx ottllog.TransformContext) {
+				tCtx.GetLogRecord().Attributes().PutStr("test", "2001:db8::1")
+			},
+		},
+		{
+			statement: `set(attributes["test"], HMAC("pass", "OTTL_E2E_HMAC_KEY"))`,
+			want: func(tCtx ottllog.TransformContext) {
+				tCtx.GetLogRecord().Attributes().PutStr("test", "e6c5a66d508e2a31d30ba42c6cdb532da55257a8f72f2b73d0b687ca2a30d041")
+			},
+		},
+		{
+			statement: `set(attributes["test"], GetXML("<a><b id=\"1\">pass</b><c>fail</c></a>", "/a/b"))`,
+			want: func(tCtx ottllog.TransformContext) {
+				tCtx.GetLogRecord().Attributes().PutStr("test", `<b id="1">pass</b>`)
+			},
+		},
+		{
+			statement: `set(attributes["test"], GetXMLValues("<a><b id=\"1\">x</b><b id=\"2\">y</b></a>", "//b/@id"))`,
+			want: func(tCtx ottllog.TransformContext) {
+				s := tCtx.GetLogRecord().Attributes().PutEmptySlice("test")
+				s.AppendEmpty().SetStr("1")
+				s.AppendEmpty().SetStr("2")
+			},
+		},
 		{
 			statement: `set(attributes["test"], SHA1("pass"))`,
 			want: func(tCtx ottllog.TransformContext) {
@@ -950,6 +996,23 @@ func Test_e2e_ottl_features(t *testing.T) {
 				tCtx.GetLogRecord().Attributes().PutBool("isMap", true)
 			},
 		},
+		{
+			name:      "for each over a map",
+			statement: `for each k, v in attributes["foo"] { set(attributes[k], v) where IsString(v) }`,
+			want: func(tCtx ottllog.TransformContext) {
+				tCtx.GetLogRecord().Attributes().PutStr("bar", "pass")
+				tCtx.GetLogRecord().Attributes().PutStr("flags", "pass")
+			},
+		},
+		{
+			name:      "for each over a list",
+			statement: `for each i, v in Split(attributes["flags"], "|") { set(attributes[v], i) }`,
+			want: func(tCtx ottllog.TransformContext) {
+				tCtx.GetLogRecord().Attributes().PutInt("A", 0)
+				tCtx.GetLogRecord().Attributes().PutInt("B", 1)
+				tCtx.GetLogRecord().Attributes().PutInt("C", 2)
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -971,6 +1034,76 @@ func Test_e2e_ottl_features(t *testing.T) {
 	}
 }
 
+func Test_e2e_user_functions(t *testing.T) {
+	definitions := []ottl.FunctionDefinition{
+		{
+			Name:       "normalize",
+			Parameters: []string{"target"},
+			Statements: []string{
+				`replace_pattern(target, "^https?://", "")`,
+				`set(target, ConvertCase(target, "lower"))`,
+			},
+		},
+		{
+			Name:       "user_xml",
+			Parameters: []string{"target"},
+			Statements: []string{
+				`set(target, "<User id=\"1\"><Password>secret</Password></User>")`,
+				`delete_xml(target, "/User/Password")`,
+				`insert_xml(target, "/User", "<Name>joe</Name>")`,
+				`rename_xml(target, "/User/@id", "key")`,
+			},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		statement string
+		want      func(tCtx ottllog.TransformContext)
+	}{
+		{
+			name:      "function with a path argument",
+			statement: `normalize(attributes["http.url"])`,
+			want: func(tCtx ottllog.TransformContext) {
+				tCtx.GetLogRecord().Attributes().PutStr("http.url", "localhost/health")
+			},
+		},
+		{
+			name:      "function called in a loop",
+			statement: `for each k, v in attributes { normalize(attributes[k]) where IsString(v) }`,
+			want: func(tCtx ottllog.TransformContext) {
+				tCtx.GetLogRecord().Attributes().PutStr("http.url", "localhost/health")
+				tCtx.GetLogRecord().Attributes().PutStr("flags", "a|b|c")
+			},
+		},
+		{
+			name:      "function editing XML",
+			statement: `user_xml(attributes["test"])`,
+			want: func(tCtx ottllog.TransformContext) {
+				tCtx.GetLogRecord().Attributes().PutStr("test", `<User key="1"><Name>joe</Name></User>`)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.statement, func(t *testing.T) {
+			settings := componenttest.NewNopTelemetrySettings()
+			logParser, err := ottllog.NewParser(ottlfuncs.StandardFuncs[ottllog.TransformContext](), settings, ottllog.WithFunctionDefinitions(definitions))
+			assert.NoError(t, err)
+			logStatements, err := logParser.ParseStatement(tt.statement)
+			assert.NoError(t, err)
+
+			tCtx := constructLogTransformContext()
+			_, _, _ = logStatements.Execute(context.Background(), tCtx)
+
+			exTCtx := constructLogTransformContext()
+			tt.want(exTCtx)
+
+			assert.NoError(t, plogtest.CompareResourceLogs(newResourceLogs(exTCtx), newResourceLogs(tCtx)))
+		})
+	}
+}
+
 func Test_ProcessTraces_TraceContext(t *testing.T) {
 	tests := []struct {
 		statement string
@@ -982,6 +1115,34 @@ func Test_ProcessTraces_TraceContext(t *testing.T) {
 				tCtx.GetSpan().Attributes().PutStr("entrypoint-root", "operationB")
 			},
 		},
+		{
+			statement: `set(attributes["error.type"], Filter(events, {"name": "exception"})[0]["attributes"]["exception.type"]) where Len(Filter(events, {"name": "exception", "attributes": {"exception.type": "Timeout.*"}})) > 0`,
+			want: func(tCtx ottlspan.TransformContext) {
+				tCtx.GetSpan().Attributes().PutStr("error.type", "TimeoutError")
+			},
+		},
+		{
+			statement: `set(attributes["error.type"], "none") where Len(Filter(events, {"name": "exception", "attributes": {"exception.type": "Value.*"}})) > 0`,
+			want:      func(_ ottlspan.TransformContext) {},
+		},
+		{
+			statement: `set(attributes["first.event"], events[0]["name"])`,
+			want: func(tCtx ottlspan.TransformContext) {
+				tCtx.GetSpan().Attributes().PutStr("first.event", "started")
+			},
+		},
+		{
+			statement: `set(attributes["publishes"], Len(Filter(links, {"attributes": {"messaging.operation": "publish"}})))`,
+			want: func(tCtx ottlspan.TransformContext) {
+				tCtx.GetSpan().Attributes().PutInt("publishes", 1)
+			},
+		},
+		{
+			statement: `for each i, event in events { set(attributes["last.event"], event["name"]) where i > 0 }`,
+			want: func(tCtx ottlspan.TransformContext) {
+				tCtx.GetSpan().Attributes().PutStr("last.event", "exception")
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -1075,4 +1236,12 @@ func fillSpanOne(span ptrace.Span) {
 	span.SetName("operationB")
 	span.SetSpanID(spanID)
 	span.SetTraceID(traceID)
+	started := span.Events().AppendEmpty()
+	started.SetName("started")
+	exception := span.Events().AppendEmpty()
+	exception.SetName("exception")
+	exception.Attributes().PutStr("exception.type", "TimeoutError")
+	link := span.Links().AppendEmpty()
+	link.SetTraceID(traceID)
+	link.Attributes().PutStr("messaging.operation", "publish")
 }
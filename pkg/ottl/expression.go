@@ -71,7 +71,7 @@ func (l literal[K]) Get(context.Context, K) (any, error) {
 
 type exprGetter[K any] struct {
 	expr Expr[K]
-	keys []key
+	keys []Key[K]
 }
 
 func (g exprGetter[K]) Get(ctx context.Context, tCtx K) (any, error) {
@@ -85,17 +85,25 @@ func (g exprGetter[K]) Get(ctx context.Context, tCtx K) (any, error) {
 	}
 
 	for _, k := range g.keys {
+		s, err := k.String(ctx, tCtx)
+		if err != nil {
+			return nil, err
+		}
+		i, err := k.Int(ctx, tCtx)
+		if err != nil {
+			return nil, err
+		}
 		switch {
-		case k.String != nil:
+		case s != nil:
 			switch r := result.(type) {
 			case pcommon.Map:
-				val, ok := r.Get(*k.String)
+				val, ok := r.Get(*s)
 				if !ok {
 					return nil, fmt.Errorf("key not found in map")
 				}
 				result = ottlcommon.GetValue(val)
 			case map[string]any:
-				val, ok := r[*k.String]
+				val, ok := r[*s]
 				if !ok {
 					return nil, fmt.Errorf("key not found in map")
 				}
@@ -103,18 +111,18 @@ func (g exprGetter[K]) Get(ctx context.Context, tCtx K) (any, error) {
 			default:
 				return nil, fmt.Errorf("type, %T, does not support string indexing", result)
 			}
-		case k.Int != nil:
+		case i != nil:
 			switch r := result.(type) {
 			case pcommon.Slice:
-				if int(*k.Int) >= r.Len() || int(*k.Int) < 0 {
-					return nil, fmt.Errorf("index %v out of bounds", *k.Int)
+				if int(*i) >= r.Len() || int(*i) < 0 {
+					return nil, fmt.Errorf("index %v out of bounds", *i)
 				}
-				result = ottlcommon.GetValue(r.At(int(*k.Int)))
+				result = ottlcommon.GetValue(r.At(int(*i)))
 			case []any:
-				if int(*k.Int) >= len(r) || int(*k.Int) < 0 {
-					return nil, fmt.Errorf("index %v out of bounds", *k.Int)
+				if int(*i) >= len(r) || int(*i) < 0 {
+					return nil, fmt.Errorf("index %v out of bounds", *i)
 				}
-				result = r[*k.Int]
+				result = r[*i]
 			default:
 				return nil, fmt.Errorf("type, %T, does not support int indexing", result)
 			}
@@ -741,11 +749,7 @@ func (p *Parser[K]) newGetter(val value) (Getter[K], error) {
 			return &literal[K]{value: *i}, nil
 		}
 		if eL.Path != nil {
-			np, err := p.newPath(eL.Path)
-			if err != nil {
-				return nil, err
-			}
-			return p.parsePath(np)
+			return p.newPathGetter(eL.Path, nil)
 		}
 		if eL.Converter != nil {
 			return p.newGetterFromConverter(*eL.Converter)
@@ -783,14 +787,18 @@ func (p *Parser[K]) newGetter(val value) (Getter[K], error) {
 	return p.evaluateMathExpression(val.MathExpression)
 }
 
-func (p *Parser[K]) newGetterFromConverter(c converter) (Getter[K], error) {
+func (p *Parser[K]) newGetterFromConverter(c converter, extraKeys ...Key[K]) (Getter[K], error) {
 	call, err := p.newFunctionCall(editor(c))
 	if err != nil {
 		return nil, err
 	}
+	keys, err := p.newKeys(c.Keys)
+	if err != nil {
+		return nil, err
+	}
 	return &exprGetter[K]{
 		expr: call,
-		keys: c.Keys,
+		keys: append(keys, extraKeys...),
 	}, nil
 }
 
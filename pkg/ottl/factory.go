@@ -3,7 +3,12 @@
 
 package ottl // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
 
-import "go.opentelemetry.io/collector/component"
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
 
 // Arguments holds the arguments for an OTTL function, with arguments
 // specified as fields on a struct. Argument ordering is defined
@@ -13,6 +18,15 @@ type Arguments any
 // component to the OTTL for use in functions.
 type FunctionContext struct {
 	Set component.TelemetrySettings
+	// LookupTables are the lookup tables provided by the component, by name.
+	LookupTables map[string]LookupTable
+}
+
+// LookupTable is a keyed table of reference data, provided by a component to
+// functions enriching telemetry, such as the Lookup converter.
+type LookupTable interface {
+	// Get returns the value of the key, and whether the key is in the table.
+	Get(ctx context.Context, key string) (pcommon.Value, bool, error)
 }
 
 // Factory defines an OTTL function factory that will generate an OTTL
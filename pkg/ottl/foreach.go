@@ -0,0 +1,115 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottl // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/internal/ottlcommon"
+)
+
+type loopItem struct {
+	key   any
+	value any
+}
+
+// newForEach builds a `for each` loop. The loop variables are bound while the body of the loop is built, and set to
+// the index or key and the value of each item when the body runs.
+func (p *Parser[K]) newForEach(f *forEach) (Expr[K], error) {
+	target, err := p.newGetter(f.Target)
+	if err != nil {
+		return Expr[K]{}, err
+	}
+
+	valueVar := &loopVariable[K]{name: f.Value}
+	bindings := map[string]binding[K]{f.Value: valueVar}
+	var keyVar *loopVariable[K]
+	if f.Key != nil {
+		if *f.Key == f.Value {
+			return Expr[K]{}, fmt.Errorf("loop variables must have different names, got %q twice", f.Value)
+		}
+		keyVar = &loopVariable[K]{name: *f.Key}
+		bindings[*f.Key] = keyVar
+	}
+
+	body, err := p.withBindings(bindings).newStatement(f.Body, "")
+	if err != nil {
+		return Expr[K]{}, err
+	}
+
+	return Expr[K]{exprFunc: func(ctx context.Context, tCtx K) (any, error) {
+		val, err := target.Get(ctx, tCtx)
+		if err != nil {
+			return nil, err
+		}
+		items, err := loopItems(val)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			itemCtx := context.WithValue(ctx, valueVar, item.value)
+			if keyVar != nil {
+				itemCtx = context.WithValue(itemCtx, keyVar, item.key)
+			}
+			if _, _, err = body.Execute(itemCtx, tCtx); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	}}, nil
+}
+
+// loopItems returns the items of a list, with their index, or of a map, with their key. The items are collected
+// before the body of the loop runs, so that the body may modify the list or map. A nil value has no items. Span
+// events, span links and data points are read-only copies, named after the paths of their context.
+func loopItems(val any) ([]loopItem, error) {
+	switch v := val.(type) {
+	case nil:
+		return nil, nil
+	case pcommon.Map:
+		items := make([]loopItem, 0, v.Len())
+		v.Range(func(k string, item pcommon.Value) bool {
+			items = append(items, loopItem{key: k, value: ottlcommon.GetValue(item)})
+			return true
+		})
+		return items, nil
+	case pcommon.Slice:
+		items := make([]loopItem, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			items[i] = loopItem{key: int64(i), value: ottlcommon.GetValue(v.At(i))}
+		}
+		return items, nil
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		items := make([]loopItem, len(keys))
+		for i, k := range keys {
+			items[i] = loopItem{key: k, value: v[k]}
+		}
+		return items, nil
+	case []byte:
+		return nil, TypeError("for each requires a list or a map, got []byte")
+	}
+	if children, ok := ottlcommon.ChildSlice(val); ok {
+		return loopItems(children)
+	}
+
+	rv := reflect.ValueOf(val)
+	if rv.Kind() != reflect.Slice {
+		return nil, TypeError(fmt.Sprintf("for each requires a list or a map, got %T", val))
+	}
+	items := make([]loopItem, rv.Len())
+	for i := range items {
+		items[i] = loopItem{key: int64(i), value: rv.Index(i).Interface()}
+	}
+	return items, nil
+}
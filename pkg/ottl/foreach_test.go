@@ -0,0 +1,319 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/internal/ottlcommon"
+)
+
+// attributesParsePath parses the `attributes` path of a pcommon.Map context. The attributes may be indexed by a key,
+// followed by indexes of slices when they are read.
+func attributesParsePath(p Path[pcommon.Map]) (GetSetter[pcommon.Map], error) {
+	if p == nil || p.Name() != "attributes" {
+		return nil, fmt.Errorf("bad path %v", p)
+	}
+	keys := p.Keys()
+	return &StandardGetSetter[pcommon.Map]{
+		Getter: func(ctx context.Context, tCtx pcommon.Map) (any, error) {
+			if len(keys) == 0 {
+				return tCtx, nil
+			}
+			s, err := keys[0].String(ctx, tCtx)
+			if err != nil {
+				return nil, err
+			}
+			val, ok := tCtx.Get(*s)
+			if !ok {
+				return nil, nil
+			}
+			for _, k := range keys[1:] {
+				i, err := k.Int(ctx, tCtx)
+				if err != nil {
+					return nil, err
+				}
+				val = val.Slice().At(int(*i))
+			}
+			return ottlcommon.GetValue(val), nil
+		},
+		Setter: func(ctx context.Context, tCtx pcommon.Map, val any) error {
+			if len(keys) == 0 {
+				return errors.New("attributes cannot be replaced")
+			}
+			s, err := keys[0].String(ctx, tCtx)
+			if err != nil {
+				return err
+			}
+			return tCtx.PutEmpty(*s).FromRaw(val)
+		},
+	}, nil
+}
+
+type setArguments struct {
+	Target Setter[pcommon.Map]
+	Value  Getter[pcommon.Map]
+}
+
+func newSetFactory() Factory[pcommon.Map] {
+	return NewFactory("set", &setArguments{}, func(_ FunctionContext, oArgs Arguments) (ExprFunc[pcommon.Map], error) {
+		args := oArgs.(*setArguments)
+		return func(ctx context.Context, tCtx pcommon.Map) (any, error) {
+			val, err := args.Value.Get(ctx, tCtx)
+			if err != nil {
+				return nil, err
+			}
+			return nil, args.Target.Set(ctx, tCtx, val)
+		}, nil
+	})
+}
+
+func newAttributesParser(t *testing.T, options ...Option[pcommon.Map]) Parser[pcommon.Map] {
+	p, err := NewParser[pcommon.Map](
+		CreateFactoryMap(newSetFactory()),
+		attributesParsePath,
+		componenttest.NewNopTelemetrySettings(),
+		options...,
+	)
+	require.NoError(t, err)
+	return p
+}
+
+func newLoopAttributes() pcommon.Map {
+	attrs := pcommon.NewMap()
+	src := attrs.PutEmptyMap("src")
+	src.PutStr("a", "1")
+	src.PutStr("b", "2")
+	list := attrs.PutEmptySlice("list")
+	list.AppendEmpty().SetStr("x")
+	list.AppendEmpty().SetStr("y")
+	objs := attrs.PutEmptySlice("objs")
+	objs.AppendEmpty().SetEmptyMap().PutStr("name", "first")
+	objs.AppendEmpty().SetEmptyMap().PutStr("name", "second")
+	attrs.PutStr("str", "value")
+	return attrs
+}
+
+func Test_ForEach(t *testing.T) {
+	tests := []struct {
+		name      string
+		statement string
+		want      func(attrs pcommon.Map)
+	}{
+		{
+			name:      "map keys and values",
+			statement: `for each k, v in attributes["src"] { set(attributes[k], v) }`,
+			want: func(attrs pcommon.Map) {
+				attrs.PutStr("a", "1")
+				attrs.PutStr("b", "2")
+			},
+		},
+		{
+			name:      "map values",
+			statement: `for each v in attributes["src"] { set(attributes[v], true) }`,
+			want: func(attrs pcommon.Map) {
+				attrs.PutBool("1", true)
+				attrs.PutBool("2", true)
+			},
+		},
+		{
+			name:      "list indexes and values",
+			statement: `for each i, v in attributes["list"] { set(attributes[v], i) }`,
+			want: func(attrs pcommon.Map) {
+				attrs.PutInt("x", 0)
+				attrs.PutInt("y", 1)
+			},
+		},
+		{
+			name:      "list literal",
+			statement: `for each v in ["c", "d"] { set(attributes[v], v) }`,
+			want: func(attrs pcommon.Map) {
+				attrs.PutStr("c", "c")
+				attrs.PutStr("d", "d")
+			},
+		},
+		{
+			name:      "indexed loop variable",
+			statement: `for each v in attributes["objs"] { set(attributes["last"], v["name"]) }`,
+			want: func(attrs pcommon.Map) {
+				attrs.PutStr("last", "second")
+			},
+		},
+		{
+			name:      "where clause of the body",
+			statement: `for each k, v in attributes["src"] { set(attributes[k], v) where v == "2" }`,
+			want: func(attrs pcommon.Map) {
+				attrs.PutStr("b", "2")
+			},
+		},
+		{
+			name:      "where clause of the statement",
+			statement: `for each k, v in attributes["src"] { set(attributes[k], v) } where attributes["str"] == "other"`,
+			want:      func(pcommon.Map) {},
+		},
+		{
+			name:      "nested loops",
+			statement: `for each k, v in attributes["src"] { for each i, item in attributes["list"] { set(attributes[item], k) where i == 0 } }`,
+			want: func(attrs pcommon.Map) {
+				attrs.PutStr("x", "b")
+			},
+		},
+		{
+			name:      "modifying the map",
+			statement: `for each k, v in attributes["src"] { set(attributes["src"], k) }`,
+			want: func(attrs pcommon.Map) {
+				attrs.PutStr("src", "b")
+			},
+		},
+		{
+			name:      "missing target",
+			statement: `for each v in attributes["missing"] { set(attributes["found"], true) }`,
+			want:      func(pcommon.Map) {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newAttributesParser(t)
+			statement, err := p.ParseStatement(tt.statement)
+			require.NoError(t, err)
+
+			attrs := newLoopAttributes()
+			_, _, err = statement.Execute(context.Background(), attrs)
+			require.NoError(t, err)
+
+			expected := newLoopAttributes()
+			tt.want(expected)
+			assert.Equal(t, expected.AsRaw(), attrs.AsRaw())
+		})
+	}
+}
+
+func Test_ForEach_ParseError(t *testing.T) {
+	tests := []struct {
+		name      string
+		statement string
+		err       string
+	}{
+		{
+			name:      "setting a loop variable",
+			statement: `for each v in attributes["list"] { set(v, "x") }`,
+			err:       `loop variable "v" cannot be set`,
+		},
+		{
+			name:      "same variable names",
+			statement: `for each v, v in attributes["list"] { set(attributes["x"], v) }`,
+			err:       `loop variables must have different names`,
+		},
+		{
+			name:      "loop variable outside of the loop",
+			statement: `for each v in attributes["list"] { set(attributes["x"], v) } where v == "x"`,
+			err:       `bad path`,
+		},
+		{
+			name:      "undefined key variable",
+			statement: `set(attributes[k], "x")`,
+			err:       `undefined variable "k" used as key`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newAttributesParser(t)
+			_, err := p.ParseStatement(tt.statement)
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func Test_ForEach_ExecuteError(t *testing.T) {
+	p := newAttributesParser(t)
+
+	statement, err := p.ParseStatement(`for each v in attributes["str"] { set(attributes["x"], v) }`)
+	require.NoError(t, err)
+	_, _, err = statement.Execute(context.Background(), newLoopAttributes())
+	assert.ErrorContains(t, err, "for each requires a list or a map, got string")
+
+	statement, err = p.ParseStatement(`for each v in attributes["objs"] { set(attributes[v], "x") }`)
+	require.NoError(t, err)
+	_, _, err = statement.Execute(context.Background(), newLoopAttributes())
+	assert.ErrorContains(t, err, `key "v" must be a string or an int`)
+}
+
+func Test_loopItems(t *testing.T) {
+	m := pcommon.NewMap()
+	m.PutStr("a", "1")
+	s := pcommon.NewSlice()
+	s.AppendEmpty().SetInt(1)
+	links := ptrace.NewSpanLinkSlice()
+	links.AppendEmpty().SetFlags(1)
+	linkMap := pcommon.NewMap()
+	linkMap.PutStr("trace_id", "00000000000000000000000000000000")
+	linkMap.PutStr("span_id", "0000000000000000")
+	linkMap.PutStr("trace_state", "")
+	linkMap.PutInt("flags", 1)
+	linkMap.PutEmptyMap("attributes")
+	linkMap.PutInt("dropped_attributes_count", 0)
+
+	tests := []struct {
+		name string
+		val  any
+		want []loopItem
+	}{
+		{
+			name: "nil",
+			val:  nil,
+			want: nil,
+		},
+		{
+			name: "pcommon map",
+			val:  m,
+			want: []loopItem{{key: "a", value: "1"}},
+		},
+		{
+			name: "pcommon slice",
+			val:  s,
+			want: []loopItem{{key: int64(0), value: int64(1)}},
+		},
+		{
+			name: "map",
+			val:  map[string]any{"b": 2, "a": 1},
+			want: []loopItem{{key: "a", value: 1}, {key: "b", value: 2}},
+		},
+		{
+			name: "any slice",
+			val:  []any{"a", int64(1)},
+			want: []loopItem{{key: int64(0), value: "a"}, {key: int64(1), value: int64(1)}},
+		},
+		{
+			name: "string slice",
+			val:  []string{"a", "b"},
+			want: []loopItem{{key: int64(0), value: "a"}, {key: int64(1), value: "b"}},
+		},
+		{
+			name: "span links",
+			val:  links,
+			want: []loopItem{{key: int64(0), value: linkMap}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, err := loopItems(tt.val)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, items)
+		})
+	}
+
+	_, err := loopItems([]byte("a"))
+	assert.Error(t, err)
+	_, err = loopItems(int64(1))
+	assert.Error(t, err)
+}
@@ -41,6 +41,9 @@ func buildOriginalText(path *path) string {
 				if k.String != nil {
 					builder.WriteString(*k.String)
 				}
+				if k.Variable != nil {
+					builder.WriteString(*k.Variable)
+				}
 				builder.WriteString("]")
 			}
 		}
@@ -64,10 +67,14 @@ func (p *Parser[K]) newPath(path *path) (*basePath[K], error) {
 	originalText := buildOriginalText(path)
 	var current *basePath[K]
 	for i := len(fields) - 1; i >= 0; i-- {
+		keys, err := p.newKeys(fields[i].Keys)
+		if err != nil {
+			return nil, err
+		}
 		current = &basePath[K]{
 			context:      pathContext,
 			name:         fields[i].Name,
-			keys:         newKeys[K](fields[i].Keys),
+			keys:         keys,
 			nextPath:     current,
 			originalText: originalText,
 		}
@@ -195,18 +202,33 @@ func (p *basePath[K]) isComplete() error {
 	return p.nextPath.isComplete()
 }
 
-func newKeys[K any](keys []key) []Key[K] {
+func (p *Parser[K]) newKeys(keys []key) ([]Key[K], error) {
 	if len(keys) == 0 {
-		return nil
+		return nil, nil
 	}
 	ks := make([]Key[K], len(keys))
 	for i := range keys {
+		if keys[i].Variable != nil {
+			b, ok := p.bindings[*keys[i].Variable]
+			if !ok {
+				return nil, fmt.Errorf("undefined variable %q used as key", *keys[i].Variable)
+			}
+			g, err := b.getter(nil)
+			if err != nil {
+				return nil, err
+			}
+			ks[i] = &baseKey[K]{
+				name:   *keys[i].Variable,
+				getter: g,
+			}
+			continue
+		}
 		ks[i] = &baseKey[K]{
 			s: keys[i].String,
 			i: keys[i].Int,
 		}
 	}
-	return ks
+	return ks, nil
 }
 
 // Key represents a chain of keys in an OTTL statement, such as `attributes["foo"]["bar"]`.
@@ -229,14 +251,44 @@ var _ Key[any] = &baseKey[any]{}
 type baseKey[K any] struct {
 	s *string
 	i *int64
+	// getter returns the value of a key named after a variable.
+	name   string
+	getter Getter[K]
+}
+
+func (k *baseKey[K]) String(ctx context.Context, tCtx K) (*string, error) {
+	if k.getter == nil {
+		return k.s, nil
+	}
+	val, err := k.variable(ctx, tCtx)
+	if s, ok := val.(string); ok {
+		return &s, nil
+	}
+	return nil, err
 }
 
-func (k *baseKey[K]) String(_ context.Context, _ K) (*string, error) {
-	return k.s, nil
+func (k *baseKey[K]) Int(ctx context.Context, tCtx K) (*int64, error) {
+	if k.getter == nil {
+		return k.i, nil
+	}
+	val, err := k.variable(ctx, tCtx)
+	if i, ok := val.(int64); ok {
+		return &i, nil
+	}
+	return nil, err
 }
 
-func (k *baseKey[K]) Int(_ context.Context, _ K) (*int64, error) {
-	return k.i, nil
+func (k *baseKey[K]) variable(ctx context.Context, tCtx K) (any, error) {
+	val, err := k.getter.Get(ctx, tCtx)
+	if err != nil {
+		return nil, err
+	}
+	switch val.(type) {
+	case string, int64:
+		return val, nil
+	default:
+		return nil, TypeError(fmt.Sprintf("key %q must be a string or an int, got %T", k.name, val))
+	}
 }
 
 func (p *Parser[K]) parsePath(ip *basePath[K]) (GetSetter[K], error) {
@@ -251,7 +303,51 @@ func (p *Parser[K]) parsePath(ip *basePath[K]) (GetSetter[K], error) {
 	return g, nil
 }
 
+// newPathGetter returns the Getter of a path, which may reference a loop variable or a parameter of a user-defined
+// function. The extra keys are appended to the keys of the last field of the path.
+func (p *Parser[K]) newPathGetter(path *path, extraKeys []Key[K]) (Getter[K], error) {
+	if b, keys, ok := p.lookupBinding(path); ok {
+		ks, err := p.newKeys(keys)
+		if err != nil {
+			return nil, err
+		}
+		return b.getter(append(ks, extraKeys...))
+	}
+	return p.newGetSetterFromPath(path, extraKeys)
+}
+
+// newGetSetter returns the GetSetter of a path, which may reference a parameter of a user-defined function. The extra
+// keys are appended to the keys of the last field of the path.
+func (p *Parser[K]) newGetSetter(path *path, extraKeys []Key[K]) (GetSetter[K], error) {
+	if b, keys, ok := p.lookupBinding(path); ok {
+		ks, err := p.newKeys(keys)
+		if err != nil {
+			return nil, err
+		}
+		return b.getSetter(append(ks, extraKeys...))
+	}
+	return p.newGetSetterFromPath(path, extraKeys)
+}
+
+func (p *Parser[K]) newGetSetterFromPath(path *path, extraKeys []Key[K]) (GetSetter[K], error) {
+	np, err := p.newPath(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(extraKeys) > 0 {
+		last := np
+		for last.nextPath != nil {
+			last = last.nextPath
+		}
+		last.keys = append(last.keys, extraKeys...)
+	}
+	return p.parsePath(np)
+}
+
 func (p *Parser[K]) newFunctionCall(ed editor) (Expr[K], error) {
+	if uf, ok := p.userFunctions[ed.Function]; ok {
+		return p.newUserFunctionCall(uf, ed)
+	}
 	f, ok := p.functions[ed.Function]
 	if !ok {
 		return Expr[K]{}, fmt.Errorf("undefined function %q", ed.Function)
@@ -276,7 +372,7 @@ func (p *Parser[K]) newFunctionCall(ed editor) (Expr[K], error) {
 		}
 	}
 
-	fn, err := f.CreateFunction(FunctionContext{Set: p.telemetrySettings}, args)
+	fn, err := f.CreateFunction(FunctionContext{Set: p.telemetrySettings, LookupTables: p.lookupTables}, args)
 	if err != nil {
 		return Expr[K]{}, fmt.Errorf("couldn't create function: %w", err)
 	}
@@ -356,7 +452,7 @@ func (p *Parser[K]) buildArgs(ed editor, argsVal reflect.Value) error {
 			if !ok {
 				return fmt.Errorf("undefined function %s", name)
 			}
-			val = StandardFunctionGetter[K]{FCtx: FunctionContext{Set: p.telemetrySettings}, Fact: f}
+			val = StandardFunctionGetter[K]{FCtx: FunctionContext{Set: p.telemetrySettings, LookupTables: p.lookupTables}, Fact: f}
 		case fieldType.Kind() == reflect.Slice:
 			val, err = p.buildSliceArg(arg.Value, fieldType)
 		default:
@@ -476,11 +572,7 @@ func (p *Parser[K]) buildArg(argVal value, argType reflect.Type) (any, error) {
 		if argVal.Literal == nil || argVal.Literal.Path == nil {
 			return nil, fmt.Errorf("must be a path")
 		}
-		np, err := p.newPath(argVal.Literal.Path)
-		if err != nil {
-			return nil, err
-		}
-		arg, err := p.parsePath(np)
+		arg, err := p.newGetSetter(argVal.Literal.Path, nil)
 		if err != nil {
 			return nil, err
 		}
@@ -2532,7 +2532,9 @@ func Test_newKey(t *testing.T) {
 			String: ottltest.Strp("bar"),
 		},
 	}
-	ks := newKeys[any](keys)
+	p := &Parser[any]{}
+	ks, err := p.newKeys(keys)
+	assert.NoError(t, err)
 
 	assert.Len(t, ks, 2)
 
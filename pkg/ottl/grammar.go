@@ -12,9 +12,10 @@ import (
 
 // parsedStatement represents a parsed statement. It is the entry point into the statement DSL.
 type parsedStatement struct {
-	Editor editor `parser:"(@@"`
+	ForEach *forEach `parser:"( @@"`
+	Editor  editor   `parser:"| @@"`
 	// If converter is matched then return error
-	Converter   *converter         `parser:"|@@)"`
+	Converter   *converter         `parser:"| @@ )"`
 	WhereClause *booleanExpression `parser:"( 'where' @@ )?"`
 }
 
@@ -22,7 +23,12 @@ func (p *parsedStatement) checkForCustomError() error {
 	if p.Converter != nil {
 		return fmt.Errorf("editor names must start with a lowercase letter but got '%v'", p.Converter.Function)
 	}
-	err := p.Editor.checkForCustomError()
+	var err error
+	if p.ForEach != nil {
+		err = p.ForEach.checkForCustomError()
+	} else {
+		err = p.Editor.checkForCustomError()
+	}
 	if err != nil {
 		return err
 	}
@@ -32,6 +38,23 @@ func (p *parsedStatement) checkForCustomError() error {
 	return nil
 }
 
+// forEach represents a loop running a statement for each item of a list or map. The value of the item is bound to
+// the Value variable, and its index or key to the optional Key variable.
+type forEach struct {
+	Key    *string          `parser:"'for' 'each' ( @Lowercase ',' )?"`
+	Value  string           `parser:"@Lowercase 'in'"`
+	Target value            `parser:"@@"`
+	Body   *parsedStatement `parser:"'{' @@ '}'"`
+}
+
+func (f *forEach) checkForCustomError() error {
+	err := f.Target.checkForCustomError()
+	if err != nil {
+		return err
+	}
+	return f.Body.checkForCustomError()
+}
+
 type constExpr struct {
 	Boolean   *boolean   `parser:"( @Boolean"`
 	Converter *converter `parser:"| @@ )"`
@@ -265,7 +288,9 @@ type field struct {
 
 type key struct {
 	String *string `parser:"'[' (@String "`
-	Int    *int64  `parser:"| @Int) ']'"`
+	Int    *int64  `parser:"| @Int"`
+	// Variable is the name of a loop variable or a parameter of a user-defined function.
+	Variable *string `parser:"| @Lowercase) ']'"`
 }
 
 type list struct {
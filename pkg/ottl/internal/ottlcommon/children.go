@@ -0,0 +1,138 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlcommon // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/internal/ottlcommon"
+
+import (
+	"encoding/hex"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// ChildSlice returns a copy of the span events, span links or data points held by val, as a slice of maps. The keys
+// of each map are named after the paths of the span event and data point contexts, so that child items are read the
+// same way from their parent context. The second return value is false if val is not one of these collections.
+func ChildSlice(val any) (pcommon.Slice, bool) {
+	s := pcommon.NewSlice()
+	switch v := val.(type) {
+	case ptrace.SpanEventSlice:
+		s.EnsureCapacity(v.Len())
+		for i := 0; i < v.Len(); i++ {
+			putSpanEvent(s.AppendEmpty().SetEmptyMap(), v.At(i))
+		}
+	case ptrace.SpanLinkSlice:
+		s.EnsureCapacity(v.Len())
+		for i := 0; i < v.Len(); i++ {
+			putSpanLink(s.AppendEmpty().SetEmptyMap(), v.At(i))
+		}
+	case pmetric.NumberDataPointSlice:
+		s.EnsureCapacity(v.Len())
+		for i := 0; i < v.Len(); i++ {
+			putNumberDataPoint(s.AppendEmpty().SetEmptyMap(), v.At(i))
+		}
+	case pmetric.HistogramDataPointSlice:
+		s.EnsureCapacity(v.Len())
+		for i := 0; i < v.Len(); i++ {
+			putHistogramDataPoint(s.AppendEmpty().SetEmptyMap(), v.At(i))
+		}
+	case pmetric.ExponentialHistogramDataPointSlice:
+		s.EnsureCapacity(v.Len())
+		for i := 0; i < v.Len(); i++ {
+			putExponentialHistogramDataPoint(s.AppendEmpty().SetEmptyMap(), v.At(i))
+		}
+	case pmetric.SummaryDataPointSlice:
+		s.EnsureCapacity(v.Len())
+		for i := 0; i < v.Len(); i++ {
+			putSummaryDataPoint(s.AppendEmpty().SetEmptyMap(), v.At(i))
+		}
+	default:
+		return pcommon.Slice{}, false
+	}
+	return s, true
+}
+
+func putSpanEvent(m pcommon.Map, event ptrace.SpanEvent) {
+	m.PutStr("name", event.Name())
+	m.PutInt("time_unix_nano", event.Timestamp().AsTime().UnixNano())
+	event.Attributes().CopyTo(m.PutEmptyMap("attributes"))
+	m.PutInt("dropped_attributes_count", int64(event.DroppedAttributesCount()))
+}
+
+func putSpanLink(m pcommon.Map, link ptrace.SpanLink) {
+	traceID := link.TraceID()
+	m.PutStr("trace_id", hex.EncodeToString(traceID[:]))
+	spanID := link.SpanID()
+	m.PutStr("span_id", hex.EncodeToString(spanID[:]))
+	m.PutStr("trace_state", link.TraceState().AsRaw())
+	m.PutInt("flags", int64(link.Flags()))
+	link.Attributes().CopyTo(m.PutEmptyMap("attributes"))
+	m.PutInt("dropped_attributes_count", int64(link.DroppedAttributesCount()))
+}
+
+func putDataPointTimes(m pcommon.Map, attributes pcommon.Map, start, timestamp pcommon.Timestamp, flags pmetric.DataPointFlags) {
+	attributes.CopyTo(m.PutEmptyMap("attributes"))
+	m.PutInt("start_time_unix_nano", start.AsTime().UnixNano())
+	m.PutInt("time_unix_nano", timestamp.AsTime().UnixNano())
+	m.PutInt("flags", int64(flags))
+}
+
+func putNumberDataPoint(m pcommon.Map, dp pmetric.NumberDataPoint) {
+	putDataPointTimes(m, dp.Attributes(), dp.StartTimestamp(), dp.Timestamp(), dp.Flags())
+	switch dp.ValueType() {
+	case pmetric.NumberDataPointValueTypeDouble:
+		m.PutDouble("value_double", dp.DoubleValue())
+	case pmetric.NumberDataPointValueTypeInt:
+		m.PutInt("value_int", dp.IntValue())
+	}
+}
+
+func putHistogramDataPoint(m pcommon.Map, dp pmetric.HistogramDataPoint) {
+	putDataPointTimes(m, dp.Attributes(), dp.StartTimestamp(), dp.Timestamp(), dp.Flags())
+	m.PutInt("count", int64(dp.Count()))
+	if dp.HasSum() {
+		m.PutDouble("sum", dp.Sum())
+	}
+	putUInts(m.PutEmptySlice("bucket_counts"), dp.BucketCounts())
+	bounds := m.PutEmptySlice("explicit_bounds")
+	for _, b := range dp.ExplicitBounds().AsRaw() {
+		bounds.AppendEmpty().SetDouble(b)
+	}
+}
+
+func putExponentialHistogramDataPoint(m pcommon.Map, dp pmetric.ExponentialHistogramDataPoint) {
+	putDataPointTimes(m, dp.Attributes(), dp.StartTimestamp(), dp.Timestamp(), dp.Flags())
+	m.PutInt("count", int64(dp.Count()))
+	if dp.HasSum() {
+		m.PutDouble("sum", dp.Sum())
+	}
+	m.PutInt("scale", int64(dp.Scale()))
+	m.PutInt("zero_count", int64(dp.ZeroCount()))
+	putBuckets(m.PutEmptyMap("positive"), dp.Positive())
+	putBuckets(m.PutEmptyMap("negative"), dp.Negative())
+}
+
+func putBuckets(m pcommon.Map, buckets pmetric.ExponentialHistogramDataPointBuckets) {
+	m.PutInt("offset", int64(buckets.Offset()))
+	putUInts(m.PutEmptySlice("bucket_counts"), buckets.BucketCounts())
+}
+
+func putSummaryDataPoint(m pcommon.Map, dp pmetric.SummaryDataPoint) {
+	putDataPointTimes(m, dp.Attributes(), dp.StartTimestamp(), dp.Timestamp(), dp.Flags())
+	m.PutInt("count", int64(dp.Count()))
+	m.PutDouble("sum", dp.Sum())
+	quantiles := m.PutEmptySlice("quantile_values")
+	for i := 0; i < dp.QuantileValues().Len(); i++ {
+		q := dp.QuantileValues().At(i)
+		qm := quantiles.AppendEmpty().SetEmptyMap()
+		qm.PutDouble("quantile", q.Quantile())
+		qm.PutDouble("value", q.Value())
+	}
+}
+
+func putUInts(s pcommon.Slice, values pcommon.UInt64Slice) {
+	for _, v := range values.AsRaw() {
+		s.AppendEmpty().SetInt(int64(v))
+	}
+}
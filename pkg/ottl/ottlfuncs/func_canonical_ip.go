@@ -0,0 +1,44 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+type CanonicalIPArguments[K any] struct {
+	Target ottl.StringGetter[K]
+}
+
+func NewCanonicalIPFactory[K any]() ottl.Factory[K] {
+	return ottl.NewFactory("CanonicalIP", &CanonicalIPArguments[K]{}, createCanonicalIPFunction[K])
+}
+
+func createCanonicalIPFunction[K any](_ ottl.FunctionContext, oArgs ottl.Arguments) (ottl.ExprFunc[K], error) {
+	args, ok := oArgs.(*CanonicalIPArguments[K])
+
+	if !ok {
+		return nil, fmt.Errorf("CanonicalIPFactory args must be of type *CanonicalIPArguments[K]")
+	}
+
+	return canonicalIP(args.Target), nil
+}
+
+func canonicalIP[K any](target ottl.StringGetter[K]) ottl.ExprFunc[K] {
+	return func(ctx context.Context, tCtx K) (any, error) {
+		val, err := target.Get(ctx, tCtx)
+		if err != nil {
+			return nil, err
+		}
+		addr, err := netip.ParseAddr(val)
+		if err != nil {
+			return nil, fmt.Errorf("invalid IP address %q: %w", val, err)
+		}
+		return addr.Unmap().String(), nil
+	}
+}
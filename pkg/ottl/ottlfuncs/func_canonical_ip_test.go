@@ -0,0 +1,71 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_canonicalIP(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   string
+		expected string
+	}{
+		{
+			name:     "ipv4",
+			target:   "10.1.2.3",
+			expected: "10.1.2.3",
+		},
+		{
+			name:     "ipv6 uppercase",
+			target:   "2001:DB8:0:0:0:0:0:1",
+			expected: "2001:db8::1",
+		},
+		{
+			name:     "ipv6 leading zeros",
+			target:   "2001:0db8:0000:0000:0001:0000:0000:0001",
+			expected: "2001:db8::1:0:0:1",
+		},
+		{
+			name:     "ipv4-mapped ipv6",
+			target:   "::ffff:10.1.2.3",
+			expected: "10.1.2.3",
+		},
+		{
+			name:     "ipv6 with zone",
+			target:   "FE80::1%eth0",
+			expected: "fe80::1%eth0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exprFunc := canonicalIP[any](&ottl.StandardStringGetter[any]{
+				Getter: func(_ context.Context, _ any) (any, error) {
+					return tt.target, nil
+				},
+			})
+			result, err := exprFunc(context.Background(), nil)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func Test_canonicalIP_error(t *testing.T) {
+	for _, target := range []string{"", "localhost", "10.1.2", "010.1.2.3"} {
+		exprFunc := canonicalIP[any](&ottl.StandardStringGetter[any]{
+			Getter: func(_ context.Context, _ any) (any, error) {
+				return target, nil
+			},
+		})
+		_, err := exprFunc(context.Background(), nil)
+		assert.ErrorContains(t, err, "invalid IP address", target)
+	}
+}
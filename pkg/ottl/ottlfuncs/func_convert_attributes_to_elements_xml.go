@@ -0,0 +1,70 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/antchfx/xmlquery"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+type ConvertAttributesToElementsXMLArguments[K any] struct {
+	Target ottl.GetSetter[K]
+	XPath  ottl.Optional[string]
+}
+
+func NewConvertAttributesToElementsXMLFactory[K any]() ottl.Factory[K] {
+	return ottl.NewFactory("convert_attributes_to_elements_xml", &ConvertAttributesToElementsXMLArguments[K]{}, createConvertAttributesToElementsXMLFunction[K])
+}
+
+func createConvertAttributesToElementsXMLFunction[K any](_ ottl.FunctionContext, oArgs ottl.Arguments) (ottl.ExprFunc[K], error) {
+	args, ok := oArgs.(*ConvertAttributesToElementsXMLArguments[K])
+
+	if !ok {
+		return nil, fmt.Errorf("ConvertAttributesToElementsXMLFactory args must be of type *ConvertAttributesToElementsXMLArguments[K]")
+	}
+
+	xPath := "//*"
+	if !args.XPath.IsEmpty() {
+		xPath = args.XPath.Get()
+	}
+
+	return convertAttributesToElementsXML(args.Target, xPath)
+}
+
+// convertAttributesToElementsXML replaces the attributes of the matching elements with child elements, appended after
+// the existing children. Namespace declarations are kept as attributes.
+func convertAttributesToElementsXML[K any](target ottl.GetSetter[K], xPath string) (ottl.ExprFunc[K], error) {
+	compiled, err := compileXPath("convert_attributes_to_elements_xml", xPath)
+	if err != nil {
+		return nil, err
+	}
+	return editXML(target, func(_ context.Context, _ K, doc *xmlquery.Node) error {
+		for _, n := range xmlquery.QuerySelectorAll(doc, compiled) {
+			if n.Type != xmlquery.ElementNode {
+				continue
+			}
+			var kept []xmlquery.Attr
+			for _, attr := range n.Attr {
+				if isXMLNamespaceDeclaration(attr) {
+					kept = append(kept, attr)
+					continue
+				}
+				element := &xmlquery.Node{
+					Type:         xmlquery.ElementNode,
+					Data:         attr.Name.Local,
+					Prefix:       attr.Name.Space,
+					NamespaceURI: attr.NamespaceURI,
+				}
+				xmlquery.AddChild(element, &xmlquery.Node{Type: xmlquery.TextNode, Data: attr.Value})
+				xmlquery.AddChild(n, element)
+			}
+			n.Attr = kept
+		}
+		return nil
+	}), nil
+}
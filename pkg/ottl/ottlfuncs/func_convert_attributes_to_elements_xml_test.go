@@ -0,0 +1,63 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+func Test_convertAttributesToElementsXML(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		xPath    string
+		expected string
+	}{
+		{
+			name:     "all elements",
+			document: `<a id="1"><b k="v">x</b></a>`,
+			xPath:    "//*",
+			expected: `<a><b>x<k>v</k></b><id>1</id></a>`,
+		},
+		{
+			name:     "matching elements",
+			document: `<a id="1"><b k="v">x</b></a>`,
+			xPath:    "/a",
+			expected: `<a><b k="v">x</b><id>1</id></a>`,
+		},
+		{
+			name:     "namespaces",
+			document: `<a xmlns:x="urn:x" x:id="1"><b>x</b></a>`,
+			xPath:    "//*",
+			expected: `<a xmlns:x="urn:x"><b>x</b><x:id>1</x:id></a>`,
+		},
+		{
+			name:     "no attributes",
+			document: `<a><b>x</b></a>`,
+			xPath:    "//*",
+			expected: `<a><b>x</b></a>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exprFunc, err := convertAttributesToElementsXML[pcommon.Value](xmlTestTarget, tt.xPath)
+			require.NoError(t, err)
+
+			value := pcommon.NewValueStr(tt.document)
+			_, err = exprFunc(context.Background(), value)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, value.Str())
+		})
+	}
+}
+
+func Test_convertAttributesToElementsXML_error(t *testing.T) {
+	_, err := convertAttributesToElementsXML[pcommon.Value](xmlTestTarget, "//[")
+	assert.ErrorContains(t, err, "supplied to convert_attributes_to_elements_xml is invalid")
+}
@@ -0,0 +1,55 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/antchfx/xmlquery"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+type DeleteXMLArguments[K any] struct {
+	Target ottl.GetSetter[K]
+	XPath  string
+}
+
+func NewDeleteXMLFactory[K any]() ottl.Factory[K] {
+	return ottl.NewFactory("delete_xml", &DeleteXMLArguments[K]{}, createDeleteXMLFunction[K])
+}
+
+func createDeleteXMLFunction[K any](_ ottl.FunctionContext, oArgs ottl.Arguments) (ottl.ExprFunc[K], error) {
+	args, ok := oArgs.(*DeleteXMLArguments[K])
+
+	if !ok {
+		return nil, fmt.Errorf("DeleteXMLFactory args must be of type *DeleteXMLArguments[K]")
+	}
+
+	return deleteXML(args.Target, args.XPath)
+}
+
+func deleteXML[K any](target ottl.GetSetter[K], xPath string) (ottl.ExprFunc[K], error) {
+	compiled, err := compileXPath("delete_xml", xPath)
+	if err != nil {
+		return nil, err
+	}
+	return editXML(target, func(_ context.Context, _ K, doc *xmlquery.Node) error {
+		for _, n := range queryXML(doc, compiled) {
+			switch n.Type {
+			case xmlquery.AttributeNode:
+				if i := xmlAttrIndex(n); i >= 0 {
+					n.Parent.Attr = slices.Delete(n.Parent.Attr, i, i+1)
+				}
+			case xmlquery.DocumentNode:
+				// The document itself cannot be removed.
+			default:
+				xmlquery.RemoveFromTree(n)
+			}
+		}
+		return nil
+	}), nil
+}
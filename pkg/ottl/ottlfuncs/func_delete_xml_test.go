@@ -0,0 +1,93 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+func Test_deleteXML(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		xPath    string
+		expected string
+	}{
+		{
+			name:     "element",
+			document: `<a><b>1</b><c>2</c></a>`,
+			xPath:    "/a/b",
+			expected: `<a><c>2</c></a>`,
+		},
+		{
+			name:     "all matching elements",
+			document: `<a><b>1</b><c>2</c><d><b>3</b></d></a>`,
+			xPath:    "//b",
+			expected: `<a><c>2</c><d></d></a>`,
+		},
+		{
+			name:     "attribute",
+			document: `<a id="1" name="x"><b>1</b></a>`,
+			xPath:    "/a/@id",
+			expected: `<a name="x"><b>1</b></a>`,
+		},
+		{
+			name:     "attribute with namespace",
+			document: `<a xmlns:x="urn:x" id="1" x:id="2"></a>`,
+			xPath:    "/a/@x:id",
+			expected: `<a xmlns:x="urn:x" id="1"></a>`,
+		},
+		{
+			name:     "comment",
+			document: `<a><!--secret--><b>1</b></a>`,
+			xPath:    "//comment()",
+			expected: `<a><b>1</b></a>`,
+		},
+		{
+			name:     "no match",
+			document: `<a><b>1</b></a>`,
+			xPath:    "/a/c",
+			expected: `<a><b>1</b></a>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exprFunc, err := deleteXML[pcommon.Value](xmlTestTarget, tt.xPath)
+			require.NoError(t, err)
+
+			value := pcommon.NewValueStr(tt.document)
+			result, err := exprFunc(context.Background(), value)
+			assert.NoError(t, err)
+			assert.Nil(t, result)
+			assert.Equal(t, tt.expected, value.Str())
+		})
+	}
+}
+
+func Test_deleteXML_non_string(t *testing.T) {
+	exprFunc, err := deleteXML[pcommon.Value](xmlTestTarget, "/a")
+	require.NoError(t, err)
+
+	value := pcommon.NewValueInt(1)
+	_, err = exprFunc(context.Background(), value)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), value.Int())
+}
+
+func Test_deleteXML_error(t *testing.T) {
+	_, err := deleteXML[pcommon.Value](xmlTestTarget, "//[")
+	assert.ErrorContains(t, err, "the XPath \"//[\" supplied to delete_xml is invalid")
+
+	exprFunc, err := deleteXML[pcommon.Value](xmlTestTarget, "/a")
+	require.NoError(t, err)
+	value := pcommon.NewValueStr("<a><b></a>")
+	_, err = exprFunc(context.Background(), value)
+	assert.ErrorContains(t, err, "unable to parse XML document")
+	assert.Equal(t, "<a><b></a>", value.Str())
+}
@@ -0,0 +1,198 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"regexp"
+	"sync"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/internal/ottlcommon"
+)
+
+type FilterArguments[K any] struct {
+	Target   ottl.Getter[K]
+	Criteria ottl.PMapGetter[K]
+}
+
+func NewFilterFactory[K any]() ottl.Factory[K] {
+	return ottl.NewFactory("Filter", &FilterArguments[K]{}, createFilterFunction[K])
+}
+
+func createFilterFunction[K any](_ ottl.FunctionContext, oArgs ottl.Arguments) (ottl.ExprFunc[K], error) {
+	args, ok := oArgs.(*FilterArguments[K])
+
+	if !ok {
+		return nil, fmt.Errorf("FilterFactory args must be of type *FilterArguments[K]")
+	}
+
+	return filter(args.Target, args.Criteria), nil
+}
+
+func filter[K any](target ottl.Getter[K], criteria ottl.PMapGetter[K]) ottl.ExprFunc[K] {
+	patterns := newFilterPatterns()
+	return func(ctx context.Context, tCtx K) (any, error) {
+		val, err := target.Get(ctx, tCtx)
+		if err != nil {
+			return nil, err
+		}
+		c, err := criteria.Get(ctx, tCtx)
+		if err != nil {
+			return nil, err
+		}
+
+		items, err := filterItems(val)
+		if err != nil {
+			return nil, err
+		}
+		result := pcommon.NewSlice()
+		for i := 0; i < items.Len(); i++ {
+			item := items.At(i)
+			if item.Type() != pcommon.ValueTypeMap {
+				continue
+			}
+			matched, err := patterns.matchMap(c, item.Map())
+			if err != nil {
+				return nil, err
+			}
+			if matched {
+				item.CopyTo(result.AppendEmpty())
+			}
+		}
+		return result, nil
+	}
+}
+
+// filterItems returns the items of a list. Span events, span links and data points are converted to maps named
+// after the paths of their context.
+func filterItems(val any) (pcommon.Slice, error) {
+	if s, ok := ottlcommon.ChildSlice(val); ok {
+		return s, nil
+	}
+	switch v := val.(type) {
+	case nil:
+		return pcommon.NewSlice(), nil
+	case pcommon.Slice:
+		return v, nil
+	case pcommon.Value:
+		if v.Type() == pcommon.ValueTypeSlice {
+			return v.Slice(), nil
+		}
+		return pcommon.Slice{}, ottl.TypeError(fmt.Sprintf("Filter target must be a list, got %v", v.Type()))
+	case []any:
+		s := pcommon.NewSlice()
+		if err := s.FromRaw(v); err != nil {
+			return pcommon.Slice{}, err
+		}
+		return s, nil
+	}
+	return pcommon.Slice{}, ottl.TypeError(fmt.Sprintf("Filter target must be a list, got %T", val))
+}
+
+// maxFilterPatterns bounds the number of regular expressions cached by a Filter function.
+const maxFilterPatterns = 256
+
+// filterPatterns caches the regular expressions of the criteria, which are usually the same for every evaluation.
+// As the criteria may be read from the telemetry, the least recently used patterns are evicted once maxFilterPatterns
+// are cached.
+type filterPatterns struct {
+	mu       sync.Mutex
+	compiled map[string]*list.Element
+	// recent holds the cached patterns, most recently used first.
+	recent *list.List
+}
+
+type filterPattern struct {
+	pattern string
+	re      *regexp.Regexp
+}
+
+func newFilterPatterns() *filterPatterns {
+	return &filterPatterns{compiled: make(map[string]*list.Element), recent: list.New()}
+}
+
+// matchMap reports whether the item has every key of the criteria, with a matching value.
+func (p *filterPatterns) matchMap(criteria pcommon.Map, item pcommon.Map) (bool, error) {
+	matched := true
+	var err error
+	criteria.Range(func(k string, c pcommon.Value) bool {
+		v, ok := item.Get(k)
+		if !ok {
+			matched = false
+			return false
+		}
+		matched, err = p.matchValue(c, v)
+		return matched && err == nil
+	})
+	return matched, err
+}
+
+// matchValue reports whether the value matches the criterion. String criteria are regular expressions which must match
+// the whole value, map criteria match nested maps, and other criteria must be equal to the value.
+// nolint:exhaustive
+func (p *filterPatterns) matchValue(criterion pcommon.Value, val pcommon.Value) (bool, error) {
+	switch criterion.Type() {
+	case pcommon.ValueTypeStr:
+		if val.Type() != pcommon.ValueTypeStr {
+			return false, nil
+		}
+		re, err := p.regexp(criterion.Str())
+		if err != nil {
+			return false, err
+		}
+		return re.MatchString(val.Str()), nil
+	case pcommon.ValueTypeMap:
+		if val.Type() != pcommon.ValueTypeMap {
+			return false, nil
+		}
+		return p.matchMap(criterion.Map(), val.Map())
+	case pcommon.ValueTypeInt, pcommon.ValueTypeDouble:
+		c, cok := numberValue(criterion)
+		v, vok := numberValue(val)
+		return cok && vok && c == v, nil
+	}
+	return val.Type() == criterion.Type() && val.AsString() == criterion.AsString(), nil
+}
+
+func (p *filterPatterns) regexp(pattern string) (*regexp.Regexp, error) {
+	p.mu.Lock()
+	if e, ok := p.compiled[pattern]; ok {
+		p.recent.MoveToFront(e)
+		p.mu.Unlock()
+		return e.Value.(*filterPattern).re, nil
+	}
+	p.mu.Unlock()
+
+	re, err := regexp.Compile("^(?:" + pattern + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("the criterion %q supplied to Filter is not a valid regexp pattern: %w", pattern, err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.compiled[pattern]; !ok {
+		p.compiled[pattern] = p.recent.PushFront(&filterPattern{pattern: pattern, re: re})
+		if p.recent.Len() > maxFilterPatterns {
+			oldest := p.recent.Remove(p.recent.Back()).(*filterPattern)
+			delete(p.compiled, oldest.pattern)
+		}
+	}
+	return re, nil
+}
+
+// nolint:exhaustive
+func numberValue(val pcommon.Value) (float64, bool) {
+	switch val.Type() {
+	case pcommon.ValueTypeInt:
+		return float64(val.Int()), true
+	case pcommon.ValueTypeDouble:
+		return val.Double(), true
+	}
+	return 0, false
+}
@@ -0,0 +1,187 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_filter(t *testing.T) {
+	events := ptrace.NewSpanEventSlice()
+	exception := events.AppendEmpty()
+	exception.SetName("exception")
+	exception.Attributes().PutStr("exception.type", "TimeoutError")
+	exception.Attributes().PutInt("retries", 3)
+	other := events.AppendEmpty()
+	other.SetName("exception")
+	other.Attributes().PutStr("exception.type", "ValueError")
+	events.AppendEmpty().SetName("message")
+
+	dataPoints := pmetric.NewNumberDataPointSlice()
+	dataPoints.AppendEmpty().SetIntValue(1)
+	dataPoints.AppendEmpty().SetDoubleValue(2)
+
+	tests := []struct {
+		name     string
+		target   any
+		criteria map[string]any
+		want     []any
+	}{
+		{
+			name:     "span event names",
+			target:   events,
+			criteria: map[string]any{"name": "exception"},
+			want:     []any{"TimeoutError", "ValueError"},
+		},
+		{
+			name:   "span event attributes",
+			target: events,
+			criteria: map[string]any{
+				"name":       "exception",
+				"attributes": map[string]any{"exception.type": "Timeout.*"},
+			},
+			want: []any{"TimeoutError"},
+		},
+		{
+			name:     "patterns match the whole value",
+			target:   events,
+			criteria: map[string]any{"name": "except"},
+			want:     []any{},
+		},
+		{
+			name:     "numbers",
+			target:   events,
+			criteria: map[string]any{"attributes": map[string]any{"retries": 3.0}},
+			want:     []any{"TimeoutError"},
+		},
+		{
+			name:     "missing key",
+			target:   events,
+			criteria: map[string]any{"attributes": map[string]any{"missing": "value"}},
+			want:     []any{},
+		},
+		{
+			name:     "no criteria",
+			target:   events,
+			criteria: map[string]any{},
+			want:     []any{"TimeoutError", "ValueError", nil},
+		},
+		{
+			name:     "nil target",
+			target:   nil,
+			criteria: map[string]any{"name": "exception"},
+			want:     []any{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exprFunc := filter[any](
+				ottl.StandardGetSetter[any]{
+					Getter: func(context.Context, any) (any, error) {
+						return tt.target, nil
+					},
+				},
+				ottl.StandardPMapGetter[any]{
+					Getter: func(context.Context, any) (any, error) {
+						return tt.criteria, nil
+					},
+				},
+			)
+			result, err := exprFunc(context.Background(), nil)
+			require.NoError(t, err)
+
+			s, ok := result.(pcommon.Slice)
+			require.True(t, ok)
+			got := make([]any, s.Len())
+			for i := 0; i < s.Len(); i++ {
+				got[i] = s.At(i).Map().AsRaw()["attributes"].(map[string]any)["exception.type"]
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("data points", func(t *testing.T) {
+		exprFunc := filter[any](
+			ottl.StandardGetSetter[any]{
+				Getter: func(context.Context, any) (any, error) {
+					return dataPoints, nil
+				},
+			},
+			ottl.StandardPMapGetter[any]{
+				Getter: func(context.Context, any) (any, error) {
+					return map[string]any{"value_double": 2}, nil
+				},
+			},
+		)
+		result, err := exprFunc(context.Background(), nil)
+		require.NoError(t, err)
+		require.Equal(t, 1, result.(pcommon.Slice).Len())
+		assert.Equal(t, 2.0, result.(pcommon.Slice).At(0).Map().AsRaw()["value_double"])
+	})
+}
+
+func Test_filter_error(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   any
+		criteria map[string]any
+	}{
+		{
+			name:     "target is not a list",
+			target:   "exception",
+			criteria: map[string]any{"name": "exception"},
+		},
+		{
+			name:     "invalid pattern",
+			target:   []any{map[string]any{"name": "exception"}},
+			criteria: map[string]any{"name": "(exception"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exprFunc := filter[any](
+				ottl.StandardGetSetter[any]{
+					Getter: func(context.Context, any) (any, error) {
+						return tt.target, nil
+					},
+				},
+				ottl.StandardPMapGetter[any]{
+					Getter: func(context.Context, any) (any, error) {
+						return tt.criteria, nil
+					},
+				},
+			)
+			_, err := exprFunc(context.Background(), nil)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func Test_filterPatterns_bounded(t *testing.T) {
+	patterns := newFilterPatterns()
+	first, err := patterns.regexp("pattern-0")
+	require.NoError(t, err)
+	for i := 1; i <= 2*maxFilterPatterns; i++ {
+		_, err = patterns.regexp(fmt.Sprintf("pattern-%d", i))
+		require.NoError(t, err)
+		// keep the first pattern recently used
+		re, err := patterns.regexp("pattern-0")
+		require.NoError(t, err)
+		assert.Same(t, first, re)
+	}
+	assert.Len(t, patterns.compiled, maxFilterPatterns)
+	assert.Equal(t, maxFilterPatterns, patterns.recent.Len())
+	assert.NotContains(t, patterns.compiled, "pattern-1")
+	assert.Contains(t, patterns.compiled, fmt.Sprintf("pattern-%d", 2*maxFilterPatterns))
+}
@@ -0,0 +1,57 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/antchfx/xmlquery"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+type FlattenXMLNamespacesArguments[K any] struct {
+	Target ottl.GetSetter[K]
+}
+
+func NewFlattenXMLNamespacesFactory[K any]() ottl.Factory[K] {
+	return ottl.NewFactory("flatten_xml_namespaces", &FlattenXMLNamespacesArguments[K]{}, createFlattenXMLNamespacesFunction[K])
+}
+
+func createFlattenXMLNamespacesFunction[K any](_ ottl.FunctionContext, oArgs ottl.Arguments) (ottl.ExprFunc[K], error) {
+	args, ok := oArgs.(*FlattenXMLNamespacesArguments[K])
+
+	if !ok {
+		return nil, fmt.Errorf("FlattenXMLNamespacesFactory args must be of type *FlattenXMLNamespacesArguments[K]")
+	}
+
+	return flattenXMLNamespaces(args.Target), nil
+}
+
+func flattenXMLNamespaces[K any](target ottl.GetSetter[K]) ottl.ExprFunc[K] {
+	return editXML(target, func(_ context.Context, _ K, doc *xmlquery.Node) error {
+		removeXMLNamespaces(doc)
+		return nil
+	})
+}
+
+// removeXMLNamespaces removes the prefixes of the elements and attributes below n, and the namespace declarations.
+func removeXMLNamespaces(n *xmlquery.Node) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == xmlquery.ElementNode {
+			c.Prefix, c.NamespaceURI = "", ""
+			attrs := c.Attr[:0]
+			for _, attr := range c.Attr {
+				if isXMLNamespaceDeclaration(attr) {
+					continue
+				}
+				attr.Name.Space, attr.NamespaceURI = "", ""
+				attrs = append(attrs, attr)
+			}
+			c.Attr = attrs
+		}
+		removeXMLNamespaces(c)
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+func Test_flattenXMLNamespaces(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		expected string
+	}{
+		{
+			name:     "prefixes and declarations",
+			document: `<s:Envelope xmlns:s="urn:s" xmlns="urn:d"><s:Body s:id="1"><Item>x</Item></s:Body></s:Envelope>`,
+			expected: `<Envelope><Body id="1"><Item>x</Item></Body></Envelope>`,
+		},
+		{
+			name:     "no namespaces",
+			document: `<a id="1"><b>x</b></a>`,
+			expected: `<a id="1"><b>x</b></a>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exprFunc := flattenXMLNamespaces[pcommon.Value](xmlTestTarget)
+
+			value := pcommon.NewValueStr(tt.document)
+			_, err := exprFunc(context.Background(), value)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, value.Str())
+		})
+	}
+}
+
+func Test_flattenXMLNamespaces_non_string(t *testing.T) {
+	exprFunc := flattenXMLNamespaces[pcommon.Value](xmlTestTarget)
+
+	value := pcommon.NewValueEmpty()
+	_, err := exprFunc(context.Background(), value)
+	assert.NoError(t, err)
+	assert.Equal(t, pcommon.ValueTypeEmpty, value.Type())
+}
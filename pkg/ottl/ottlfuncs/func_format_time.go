@@ -0,0 +1,69 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/timeutils"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+var fractionalSecondsLayout = regexp.MustCompile(`[.,]9+`)
+
+type FormatTimeArguments[K any] struct {
+	Time     ottl.TimeGetter[K]
+	Format   string
+	Location ottl.Optional[string]
+}
+
+func NewFormatTimeFactory[K any]() ottl.Factory[K] {
+	return ottl.NewFactory("FormatTime", &FormatTimeArguments[K]{}, createFormatTimeFunction[K])
+}
+
+func createFormatTimeFunction[K any](_ ottl.FunctionContext, oArgs ottl.Arguments) (ottl.ExprFunc[K], error) {
+	args, ok := oArgs.(*FormatTimeArguments[K])
+
+	if !ok {
+		return nil, fmt.Errorf("FormatTimeFactory args must be of type *FormatTimeArguments[K]")
+	}
+
+	return FormatTime(args.Time, args.Format, args.Location)
+}
+
+func FormatTime[K any](inputTime ottl.TimeGetter[K], format string, location ottl.Optional[string]) (ottl.ExprFunc[K], error) {
+	if format == "" {
+		return nil, fmt.Errorf("format cannot be nil")
+	}
+	layout, err := timeutils.StrptimeToGotime(format)
+	if err != nil {
+		return nil, err
+	}
+	// Fractional seconds are zero-padded, as they are when parsed, rather than trimmed.
+	layout = fractionalSecondsLayout.ReplaceAllStringFunc(layout, func(s string) string {
+		return s[:1] + strings.Repeat("0", len(s)-1)
+	})
+	var loc *time.Location
+	if !location.IsEmpty() {
+		loc, err = time.LoadLocation(location.Get())
+		if err != nil {
+			return nil, fmt.Errorf("failed to load location %s: %w", location.Get(), err)
+		}
+	}
+
+	return func(ctx context.Context, tCtx K) (any, error) {
+		t, err := inputTime.Get(ctx, tCtx)
+		if err != nil {
+			return nil, err
+		}
+		if loc != nil {
+			t = t.In(loc)
+		}
+		return t.Format(layout), nil
+	}, nil
+}
@@ -0,0 +1,101 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_FormatTime(t *testing.T) {
+	tests := []struct {
+		name     string
+		time     time.Time
+		format   string
+		location ottl.Optional[string]
+		expected string
+	}{
+		{
+			name:     "date",
+			time:     time.Date(2023, 2, 4, 12, 30, 45, 0, time.UTC),
+			format:   "%Y-%m-%d",
+			expected: "2023-02-04",
+		},
+		{
+			name:     "date and time",
+			time:     time.Date(2023, 2, 4, 12, 30, 45, 0, time.UTC),
+			format:   "%Y-%m-%dT%H:%M:%S%z",
+			expected: "2023-02-04T12:30:45Z",
+		},
+		{
+			name:     "milliseconds are zero-padded",
+			time:     time.Date(2023, 2, 4, 12, 30, 45, 10000000, time.UTC),
+			format:   "%H:%M:%S.%L",
+			expected: "12:30:45.010",
+		},
+		{
+			name:     "microseconds",
+			time:     time.Date(2023, 2, 4, 12, 30, 45, 123456789, time.UTC),
+			format:   "%H:%M:%S,%f",
+			expected: "12:30:45,123456",
+		},
+		{
+			name:     "names",
+			time:     time.Date(2023, 2, 4, 12, 30, 45, 0, time.UTC),
+			format:   "%a %b %d %Y",
+			expected: "Sat Feb 04 2023",
+		},
+		{
+			name:     "location",
+			time:     time.Date(2023, 2, 4, 12, 30, 45, 0, time.UTC),
+			format:   "%Y-%m-%d %H:%M %Z",
+			location: ottl.NewTestingOptional("America/New_York"),
+			expected: "2023-02-04 07:30 EST",
+		},
+		{
+			name:     "time location is kept",
+			time:     time.Date(2023, 2, 4, 12, 30, 45, 0, time.FixedZone("", 3600)),
+			format:   "%H:%M%z",
+			expected: "12:30+0100",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exprFunc, err := FormatTime(&ottl.StandardTimeGetter[any]{
+				Getter: func(_ context.Context, _ any) (any, error) {
+					return tt.time, nil
+				},
+			}, tt.format, tt.location)
+			require.NoError(t, err)
+			result, err := exprFunc(context.Background(), nil)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func Test_FormatTime_error(t *testing.T) {
+	getter := &ottl.StandardTimeGetter[any]{
+		Getter: func(_ context.Context, _ any) (any, error) {
+			return "not a time", nil
+		},
+	}
+
+	_, err := FormatTime(getter, "", ottl.Optional[string]{})
+	assert.ErrorContains(t, err, "format cannot be nil")
+
+	_, err = FormatTime(getter, "%Y", ottl.NewTestingOptional("Mars/Olympus_Mons"))
+	assert.ErrorContains(t, err, "failed to load location")
+
+	exprFunc, err := FormatTime(getter, "%Y", ottl.Optional[string]{})
+	require.NoError(t, err)
+	_, err = exprFunc(context.Background(), nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,59 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/antchfx/xmlquery"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+type GetXMLArguments[K any] struct {
+	Target ottl.StringGetter[K]
+	XPath  string
+}
+
+func NewGetXMLFactory[K any]() ottl.Factory[K] {
+	return ottl.NewFactory("GetXML", &GetXMLArguments[K]{}, createGetXMLFunction[K])
+}
+
+func createGetXMLFunction[K any](_ ottl.FunctionContext, oArgs ottl.Arguments) (ottl.ExprFunc[K], error) {
+	args, ok := oArgs.(*GetXMLArguments[K])
+
+	if !ok {
+		return nil, fmt.Errorf("GetXMLFactory args must be of type *GetXMLArguments[K]")
+	}
+
+	return getXML(args.Target, args.XPath)
+}
+
+func getXML[K any](target ottl.StringGetter[K], xPath string) (ottl.ExprFunc[K], error) {
+	compiled, err := compileXPath("GetXML", xPath)
+	if err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context, tCtx K) (any, error) {
+		val, err := target.Get(ctx, tCtx)
+		if err != nil {
+			return nil, err
+		}
+		doc, err := parseXMLDocument(val)
+		if err != nil {
+			return nil, err
+		}
+		var result strings.Builder
+		for _, n := range xmlquery.QuerySelectorAll(doc, compiled) {
+			if n.Type == xmlquery.ElementNode {
+				result.WriteString(n.OutputXML(true))
+			} else {
+				result.WriteString(n.InnerText())
+			}
+		}
+		return result.String(), nil
+	}, nil
+}
@@ -0,0 +1,76 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_getXML(t *testing.T) {
+	document := `<a><b id="1">x</b><b id="2">y</b><c>z</c></a>`
+	tests := []struct {
+		name     string
+		xPath    string
+		expected string
+	}{
+		{
+			name:     "element",
+			xPath:    "/a/c",
+			expected: "<c>z</c>",
+		},
+		{
+			name:     "multiple elements",
+			xPath:    "/a/b",
+			expected: `<b id="1">x</b><b id="2">y</b>`,
+		},
+		{
+			name:     "attributes",
+			xPath:    "//b/@id",
+			expected: "12",
+		},
+		{
+			name:     "text",
+			xPath:    "//b/text()",
+			expected: "xy",
+		},
+		{
+			name:     "no match",
+			xPath:    "/a/d",
+			expected: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exprFunc, err := getXML[any](&ottl.StandardStringGetter[any]{
+				Getter: func(_ context.Context, _ any) (any, error) {
+					return document, nil
+				},
+			}, tt.xPath)
+			require.NoError(t, err)
+			result, err := exprFunc(context.Background(), nil)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func Test_getXML_error(t *testing.T) {
+	_, err := getXML[any](&ottl.StandardStringGetter[any]{}, "//[")
+	assert.ErrorContains(t, err, "invalid")
+
+	exprFunc, err := getXML[any](&ottl.StandardStringGetter[any]{
+		Getter: func(_ context.Context, _ any) (any, error) {
+			return "<a><b></a>", nil
+		},
+	}, "/a")
+	require.NoError(t, err)
+	_, err = exprFunc(context.Background(), nil)
+	assert.ErrorContains(t, err, "unable to parse XML document")
+}
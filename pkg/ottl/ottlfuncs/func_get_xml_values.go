@@ -0,0 +1,55 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/antchfx/xmlquery"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+type GetXMLValuesArguments[K any] struct {
+	Target ottl.StringGetter[K]
+	XPath  string
+}
+
+func NewGetXMLValuesFactory[K any]() ottl.Factory[K] {
+	return ottl.NewFactory("GetXMLValues", &GetXMLValuesArguments[K]{}, createGetXMLValuesFunction[K])
+}
+
+func createGetXMLValuesFunction[K any](_ ottl.FunctionContext, oArgs ottl.Arguments) (ottl.ExprFunc[K], error) {
+	args, ok := oArgs.(*GetXMLValuesArguments[K])
+
+	if !ok {
+		return nil, fmt.Errorf("GetXMLValuesFactory args must be of type *GetXMLValuesArguments[K]")
+	}
+
+	return getXMLValues(args.Target, args.XPath)
+}
+
+func getXMLValues[K any](target ottl.StringGetter[K], xPath string) (ottl.ExprFunc[K], error) {
+	compiled, err := compileXPath("GetXMLValues", xPath)
+	if err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context, tCtx K) (any, error) {
+		val, err := target.Get(ctx, tCtx)
+		if err != nil {
+			return nil, err
+		}
+		doc, err := parseXMLDocument(val)
+		if err != nil {
+			return nil, err
+		}
+		nodes := xmlquery.QuerySelectorAll(doc, compiled)
+		values := make([]any, len(nodes))
+		for i, n := range nodes {
+			values[i] = n.InnerText()
+		}
+		return values, nil
+	}, nil
+}
@@ -0,0 +1,66 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_getXMLValues(t *testing.T) {
+	document := `<a><b id="1">x</b><b id="2">y<c>z</c></b></a>`
+	tests := []struct {
+		name     string
+		xPath    string
+		expected []any
+	}{
+		{
+			name:     "elements",
+			xPath:    "/a/b",
+			expected: []any{"x", "yz"},
+		},
+		{
+			name:     "attributes",
+			xPath:    "//b/@id",
+			expected: []any{"1", "2"},
+		},
+		{
+			name:     "no match",
+			xPath:    "/a/d",
+			expected: []any{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exprFunc, err := getXMLValues[any](&ottl.StandardStringGetter[any]{
+				Getter: func(_ context.Context, _ any) (any, error) {
+					return document, nil
+				},
+			}, tt.xPath)
+			require.NoError(t, err)
+			result, err := exprFunc(context.Background(), nil)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func Test_getXMLValues_error(t *testing.T) {
+	_, err := getXMLValues[any](&ottl.StandardStringGetter[any]{}, "//[")
+	assert.ErrorContains(t, err, "invalid")
+
+	exprFunc, err := getXMLValues[any](&ottl.StandardStringGetter[any]{
+		Getter: func(_ context.Context, _ any) (any, error) {
+			return 1, nil
+		},
+	}, "/a")
+	require.NoError(t, err)
+	_, err = exprFunc(context.Background(), nil)
+	assert.Error(t, err)
+}
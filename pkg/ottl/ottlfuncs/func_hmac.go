@@ -0,0 +1,56 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+type HMACArguments[K any] struct {
+	Target ottl.StringGetter[K]
+	KeyEnv string
+}
+
+func NewHMACFactory[K any]() ottl.Factory[K] {
+	return ottl.NewFactory("HMAC", &HMACArguments[K]{}, createHMACFunction[K])
+}
+
+func createHMACFunction[K any](_ ottl.FunctionContext, oArgs ottl.Arguments) (ottl.ExprFunc[K], error) {
+	args, ok := oArgs.(*HMACArguments[K])
+
+	if !ok {
+		return nil, fmt.Errorf("HMACFactory args must be of type *HMACArguments[K]")
+	}
+
+	// The key is read from the environment rather than passed in the statement, as statements are logged.
+	key := os.Getenv(args.KeyEnv)
+	if key == "" {
+		return nil, fmt.Errorf("the HMAC key environment variable %q is not set or empty", args.KeyEnv)
+	}
+
+	return HMACSHA256(args.Target, []byte(key)), nil
+}
+
+// HMACSHA256 returns the hex encoded HMAC-SHA256 of the target, keyed with the given key.
+func HMACSHA256[K any](target ottl.StringGetter[K], key []byte) ottl.ExprFunc[K] {
+	return func(ctx context.Context, tCtx K) (any, error) {
+		val, err := target.Get(ctx, tCtx)
+		if err != nil {
+			return nil, err
+		}
+		mac := hmac.New(sha256.New, key)
+		_, err = mac.Write([]byte(val))
+		if err != nil {
+			return nil, err
+		}
+		return hex.EncodeToString(mac.Sum(nil)), nil
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_HMACSHA256(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   string
+		key      string
+		expected string
+	}{
+		{
+			name:     "value",
+			target:   "user-123",
+			key:      "secret",
+			expected: "73ea9a4ea270455073276422e7ff65be4435c4c01af927bb09a44c36622382da",
+		},
+		{
+			name:     "other key",
+			target:   "user-123",
+			key:      "other",
+			expected: "5933afe63058286ff99411429a7384da92a38b551b12c12535762cf85823f46a",
+		},
+		{
+			name:     "empty value",
+			target:   "",
+			key:      "secret",
+			expected: "f9e66e179b6747ae54108f82f8ade8b3c25d76fd30afde6c395822c530196169",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exprFunc := HMACSHA256[any](
+				&ottl.StandardStringGetter[any]{
+					Getter: func(_ context.Context, _ any) (any, error) {
+						return tt.target, nil
+					},
+				},
+				[]byte(tt.key),
+			)
+			result, err := exprFunc(context.Background(), nil)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func Test_HMACSHA256_error(t *testing.T) {
+	exprFunc := HMACSHA256[any](
+		&ottl.StandardStringGetter[any]{
+			Getter: func(_ context.Context, _ any) (any, error) {
+				return 123, nil
+			},
+		},
+		[]byte("secret"),
+	)
+	_, err := exprFunc(context.Background(), nil)
+	assert.Error(t, err)
+}
+
+func Test_createHMACFunction(t *testing.T) {
+	t.Setenv("OTTL_TEST_HMAC_KEY", "secret")
+	target := &ottl.StandardStringGetter[any]{
+		Getter: func(_ context.Context, _ any) (any, error) {
+			return "user-123", nil
+		},
+	}
+
+	exprFunc, err := createHMACFunction[any](ottl.FunctionContext{}, &HMACArguments[any]{Target: target, KeyEnv: "OTTL_TEST_HMAC_KEY"})
+	require.NoError(t, err)
+	result, err := exprFunc(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "73ea9a4ea270455073276422e7ff65be4435c4c01af927bb09a44c36622382da", result)
+
+	t.Setenv("OTTL_TEST_HMAC_EMPTY_KEY", "")
+	for _, env := range []string{"OTTL_TEST_HMAC_EMPTY_KEY", "OTTL_TEST_HMAC_UNSET_KEY"} {
+		_, err = createHMACFunction[any](ottl.FunctionContext{}, &HMACArguments[any]{Target: target, KeyEnv: env})
+		assert.ErrorContains(t, err, env)
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/antchfx/xmlquery"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+type InsertXMLArguments[K any] struct {
+	Target ottl.GetSetter[K]
+	XPath  string
+	XML    ottl.StringGetter[K]
+}
+
+func NewInsertXMLFactory[K any]() ottl.Factory[K] {
+	return ottl.NewFactory("insert_xml", &InsertXMLArguments[K]{}, createInsertXMLFunction[K])
+}
+
+func createInsertXMLFunction[K any](_ ottl.FunctionContext, oArgs ottl.Arguments) (ottl.ExprFunc[K], error) {
+	args, ok := oArgs.(*InsertXMLArguments[K])
+
+	if !ok {
+		return nil, fmt.Errorf("InsertXMLFactory args must be of type *InsertXMLArguments[K]")
+	}
+
+	return insertXML(args.Target, args.XPath, args.XML)
+}
+
+func insertXML[K any](target ottl.GetSetter[K], xPath string, fragment ottl.StringGetter[K]) (ottl.ExprFunc[K], error) {
+	compiled, err := compileXPath("insert_xml", xPath)
+	if err != nil {
+		return nil, err
+	}
+	return editXML(target, func(ctx context.Context, tCtx K, doc *xmlquery.Node) error {
+		value, err := fragment.Get(ctx, tCtx)
+		if err != nil {
+			return err
+		}
+		for _, n := range xmlquery.QuerySelectorAll(doc, compiled) {
+			if n.Type != xmlquery.ElementNode && n.Type != xmlquery.DocumentNode {
+				continue
+			}
+			// Each element gets its own copy of the fragment.
+			nodes, err := parseXMLDocument(value)
+			if err != nil {
+				return err
+			}
+			var children []*xmlquery.Node
+			for c := nodes.FirstChild; c != nil; c = c.NextSibling {
+				if c.Type != xmlquery.DeclarationNode {
+					children = append(children, c)
+				}
+			}
+			for _, c := range children {
+				c.Parent, c.PrevSibling, c.NextSibling = nil, nil, nil
+				xmlquery.AddChild(n, c)
+			}
+		}
+		return nil
+	}), nil
+}
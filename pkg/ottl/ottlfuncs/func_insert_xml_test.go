@@ -0,0 +1,86 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_insertXML(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		xPath    string
+		fragment string
+		expected string
+	}{
+		{
+			name:     "element",
+			document: `<a><b>1</b></a>`,
+			xPath:    "/a",
+			fragment: `<c>2</c>`,
+			expected: `<a><b>1</b><c>2</c></a>`,
+		},
+		{
+			name:     "multiple elements",
+			document: `<a><b>1</b></a>`,
+			xPath:    "/a",
+			fragment: `<c>2</c><d>3</d>`,
+			expected: `<a><b>1</b><c>2</c><d>3</d></a>`,
+		},
+		{
+			name:     "multiple matches",
+			document: `<a><b>1</b><b>2</b></a>`,
+			xPath:    "/a/b",
+			fragment: `<c>3</c>`,
+			expected: `<a><b>1<c>3</c></b><b>2<c>3</c></b></a>`,
+		},
+		{
+			name:     "attribute match",
+			document: `<a id="1"><b>1</b></a>`,
+			xPath:    "/a/@id",
+			fragment: `<c>2</c>`,
+			expected: `<a id="1"><b>1</b></a>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exprFunc, err := insertXML[pcommon.Value](xmlTestTarget, tt.xPath, ottl.StandardStringGetter[pcommon.Value]{
+				Getter: func(context.Context, pcommon.Value) (any, error) {
+					return tt.fragment, nil
+				},
+			})
+			require.NoError(t, err)
+
+			value := pcommon.NewValueStr(tt.document)
+			_, err = exprFunc(context.Background(), value)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, value.Str())
+		})
+	}
+}
+
+func Test_insertXML_error(t *testing.T) {
+	fragment := ottl.StandardStringGetter[pcommon.Value]{
+		Getter: func(context.Context, pcommon.Value) (any, error) {
+			return "<c>", nil
+		},
+	}
+	_, err := insertXML[pcommon.Value](xmlTestTarget, "//[", fragment)
+	assert.ErrorContains(t, err, "supplied to insert_xml is invalid")
+
+	exprFunc, err := insertXML[pcommon.Value](xmlTestTarget, "/a", fragment)
+	require.NoError(t, err)
+	value := pcommon.NewValueStr("<a><b>1</b></a>")
+	_, err = exprFunc(context.Background(), value)
+	assert.ErrorContains(t, err, "unable to parse XML document")
+	assert.Equal(t, "<a><b>1</b></a>", value.Str())
+}
@@ -0,0 +1,63 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+type IsInCIDRArguments[K any] struct {
+	Target ottl.StringGetter[K]
+	CIDRs  []string
+}
+
+func NewIsInCIDRFactory[K any]() ottl.Factory[K] {
+	return ottl.NewFactory("IsInCIDR", &IsInCIDRArguments[K]{}, createIsInCIDRFunction[K])
+}
+
+func createIsInCIDRFunction[K any](_ ottl.FunctionContext, oArgs ottl.Arguments) (ottl.ExprFunc[K], error) {
+	args, ok := oArgs.(*IsInCIDRArguments[K])
+
+	if !ok {
+		return nil, fmt.Errorf("IsInCIDRFactory args must be of type *IsInCIDRArguments[K]")
+	}
+
+	return isInCIDR(args.Target, args.CIDRs)
+}
+
+func isInCIDR[K any](target ottl.StringGetter[K], cidrs []string) (ottl.ExprFunc[K], error) {
+	if len(cidrs) == 0 {
+		return nil, fmt.Errorf("IsInCIDR requires at least one CIDR")
+	}
+	prefixes := make([]netip.Prefix, len(cidrs))
+	for i, cidr := range cidrs {
+		prefix, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("the CIDR %q supplied to IsInCIDR is invalid: %w", cidr, err)
+		}
+		prefixes[i] = prefix.Masked()
+	}
+	return func(ctx context.Context, tCtx K) (any, error) {
+		val, err := target.Get(ctx, tCtx)
+		if err != nil {
+			return nil, err
+		}
+		addr, err := netip.ParseAddr(val)
+		if err != nil {
+			return false, nil
+		}
+		// IPv4-mapped IPv6 addresses belong to IPv4 ranges.
+		addr = addr.Unmap().WithZone("")
+		for _, prefix := range prefixes {
+			if prefix.Contains(addr) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}, nil
+}
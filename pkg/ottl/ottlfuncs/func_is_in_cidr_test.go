@@ -0,0 +1,114 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_isInCIDR(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   string
+		cidrs    []string
+		expected bool
+	}{
+		{
+			name:     "ipv4 in range",
+			target:   "10.1.2.3",
+			cidrs:    []string{"10.0.0.0/8"},
+			expected: true,
+		},
+		{
+			name:     "ipv4 out of range",
+			target:   "192.168.1.1",
+			cidrs:    []string{"10.0.0.0/8", "172.16.0.0/12"},
+			expected: false,
+		},
+		{
+			name:     "any of the ranges",
+			target:   "172.20.0.1",
+			cidrs:    []string{"10.0.0.0/8", "172.16.0.0/12"},
+			expected: true,
+		},
+		{
+			name:     "unmasked range",
+			target:   "10.1.2.3",
+			cidrs:    []string{"10.1.2.200/24"},
+			expected: true,
+		},
+		{
+			name:     "ipv6 in range",
+			target:   "2001:db8::1",
+			cidrs:    []string{"2001:db8::/32"},
+			expected: true,
+		},
+		{
+			name:     "ipv4-mapped ipv6",
+			target:   "::ffff:10.1.2.3",
+			cidrs:    []string{"10.0.0.0/8"},
+			expected: true,
+		},
+		{
+			name:     "ipv6 with zone",
+			target:   "fe80::1%eth0",
+			cidrs:    []string{"fe80::/10"},
+			expected: true,
+		},
+		{
+			name:     "ipv4 against ipv6 range",
+			target:   "10.1.2.3",
+			cidrs:    []string{"2001:db8::/32"},
+			expected: false,
+		},
+		{
+			name:     "not an ip",
+			target:   "localhost",
+			cidrs:    []string{"127.0.0.0/8"},
+			expected: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exprFunc, err := isInCIDR[any](&ottl.StandardStringGetter[any]{
+				Getter: func(_ context.Context, _ any) (any, error) {
+					return tt.target, nil
+				},
+			}, tt.cidrs)
+			require.NoError(t, err)
+			result, err := exprFunc(context.Background(), nil)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func Test_isInCIDR_error(t *testing.T) {
+	target := &ottl.StandardStringGetter[any]{
+		Getter: func(_ context.Context, _ any) (any, error) {
+			return "10.1.2.3", nil
+		},
+	}
+
+	_, err := isInCIDR[any](target, nil)
+	assert.ErrorContains(t, err, "at least one CIDR")
+
+	_, err = isInCIDR[any](target, []string{"10.0.0.0"})
+	assert.ErrorContains(t, err, `the CIDR "10.0.0.0" supplied to IsInCIDR is invalid`)
+
+	exprFunc, err := isInCIDR[any](&ottl.StandardStringGetter[any]{
+		Getter: func(_ context.Context, _ any) (any, error) {
+			return 1, nil
+		},
+	}, []string{"10.0.0.0/8"})
+	require.NoError(t, err)
+	_, err = exprFunc(context.Background(), nil)
+	assert.Error(t, err)
+}
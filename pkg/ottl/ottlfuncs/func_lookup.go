@@ -0,0 +1,101 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+type LookupArguments[K any] struct {
+	Table string
+	Key   ottl.StringLikeGetter[K]
+}
+
+type LookupEditorArguments[K any] struct {
+	Target ottl.Setter[K]
+	Table  string
+	Key    ottl.StringLikeGetter[K]
+}
+
+func NewLookupFactory[K any]() ottl.Factory[K] {
+	return ottl.NewFactory("Lookup", &LookupArguments[K]{}, createLookupFunction[K])
+}
+
+func NewLookupEditorFactory[K any]() ottl.Factory[K] {
+	return ottl.NewFactory("lookup", &LookupEditorArguments[K]{}, createLookupEditorFunction[K])
+}
+
+func createLookupFunction[K any](fCtx ottl.FunctionContext, oArgs ottl.Arguments) (ottl.ExprFunc[K], error) {
+	args, ok := oArgs.(*LookupArguments[K])
+	if !ok {
+		return nil, fmt.Errorf("LookupFactory args must be of type *LookupArguments[K]")
+	}
+
+	table, err := newLookupTable(args.Table, fCtx)
+	if err != nil {
+		return nil, err
+	}
+	return lookup(table, args.Key), nil
+}
+
+func createLookupEditorFunction[K any](fCtx ottl.FunctionContext, oArgs ottl.Arguments) (ottl.ExprFunc[K], error) {
+	args, ok := oArgs.(*LookupEditorArguments[K])
+	if !ok {
+		return nil, fmt.Errorf("LookupEditorFactory args must be of type *LookupEditorArguments[K]")
+	}
+
+	table, err := newLookupTable(args.Table, fCtx)
+	if err != nil {
+		return nil, err
+	}
+	get := lookup(table, args.Key)
+	return func(ctx context.Context, tCtx K) (any, error) {
+		value, err := get(ctx, tCtx)
+		if err != nil || value == nil {
+			return nil, err
+		}
+		return nil, args.Target.Set(ctx, tCtx, value)
+	}, nil
+}
+
+// lookup returns the value of the key in the table, or nil if the key is not in the table.
+func lookup[K any](table ottl.LookupTable, key ottl.StringLikeGetter[K]) ottl.ExprFunc[K] {
+	return func(ctx context.Context, tCtx K) (any, error) {
+		k, err := key.Get(ctx, tCtx)
+		if err != nil || k == nil {
+			return nil, err
+		}
+		value, ok, err := table.Get(ctx, *k)
+		if err != nil || !ok {
+			return nil, err
+		}
+		return lookupResult(value), nil
+	}
+}
+
+// lookupResult converts a value of a table to the type returned by the function. Maps and slices are copied, so
+// that statements cannot modify the table.
+func lookupResult(value pcommon.Value) any {
+	switch value.Type() {
+	case pcommon.ValueTypeMap:
+		m := pcommon.NewMap()
+		value.Map().CopyTo(m)
+		return m
+	case pcommon.ValueTypeSlice:
+		s := pcommon.NewSlice()
+		value.Slice().CopyTo(s)
+		return s
+	case pcommon.ValueTypeBytes:
+		return value.Bytes().AsRaw()
+	case pcommon.ValueTypeEmpty:
+		return nil
+	default:
+		return value.AsRaw()
+	}
+}
@@ -0,0 +1,232 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func writeLookupTable(t *testing.T, name string, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func Test_Lookup(t *testing.T) {
+	csvPath := writeLookupTable(t, "owners.csv", "namespace,team\ncheckout,payments\nsearch,discovery\n")
+	wideCSVPath := writeLookupTable(t, "owners_wide.csv", "namespace,team,channel\ncheckout,payments,#payments\n")
+	jsonPath := writeLookupTable(t, "codes.json", `{"E42": "disk full", "E43": {"severity": "critical", "retry": false}, "E44": 3}`)
+
+	tests := []struct {
+		name  string
+		table string
+		key   string
+		want  func() any
+	}{
+		{
+			name:  "csv",
+			table: csvPath,
+			key:   "checkout",
+			want:  func() any { return "payments" },
+		},
+		{
+			name:  "csv with file prefix",
+			table: "file:" + csvPath,
+			key:   "search",
+			want:  func() any { return "discovery" },
+		},
+		{
+			name:  "csv missing key",
+			table: csvPath,
+			key:   "unknown",
+			want:  func() any { return nil },
+		},
+		{
+			name:  "csv with several columns",
+			table: wideCSVPath,
+			key:   "checkout",
+			want: func() any {
+				m := pcommon.NewMap()
+				m.PutStr("namespace", "checkout")
+				m.PutStr("team", "payments")
+				m.PutStr("channel", "#payments")
+				return m
+			},
+		},
+		{
+			name:  "json string",
+			table: jsonPath,
+			key:   "E42",
+			want:  func() any { return "disk full" },
+		},
+		{
+			name:  "json map",
+			table: jsonPath,
+			key:   "E43",
+			want: func() any {
+				m := pcommon.NewMap()
+				m.PutStr("severity", "critical")
+				m.PutBool("retry", false)
+				return m
+			},
+		},
+		{
+			name:  "json number",
+			table: jsonPath,
+			key:   "E44",
+			want:  func() any { return float64(3) },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exprFunc, err := createLookupFunction[any](ottl.FunctionContext{}, &LookupArguments[any]{
+				Table: tt.table,
+				Key: ottl.StandardStringLikeGetter[any]{
+					Getter: func(_ context.Context, _ any) (any, error) {
+						return tt.key, nil
+					},
+				},
+			})
+			require.NoError(t, err)
+
+			result, err := exprFunc(context.Background(), nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want(), result)
+		})
+	}
+}
+
+func Test_Lookup_returns_copies(t *testing.T) {
+	path := writeLookupTable(t, "codes.json", `{"E43": {"severity": "critical"}}`)
+	exprFunc := lookupWithKey(t, path, "E43")
+
+	result, err := exprFunc(context.Background(), nil)
+	require.NoError(t, err)
+	result.(pcommon.Map).PutStr("severity", "changed")
+
+	result, err = exprFunc(context.Background(), nil)
+	require.NoError(t, err)
+	severity, _ := result.(pcommon.Map).Get("severity")
+	assert.Equal(t, "critical", severity.Str())
+}
+
+func Test_Lookup_configured_table(t *testing.T) {
+	fCtx := ottl.FunctionContext{
+		LookupTables: map[string]ottl.LookupTable{
+			"owners": staticLookupTable{"checkout": "payments"},
+		},
+	}
+	exprFunc, err := createLookupFunction[any](fCtx, &LookupArguments[any]{
+		Table: "owners",
+		Key: ottl.StandardStringLikeGetter[any]{
+			Getter: func(_ context.Context, _ any) (any, error) {
+				return "checkout", nil
+			},
+		},
+	})
+	require.NoError(t, err)
+	result, err := exprFunc(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "payments", result)
+
+	_, err = createLookupFunction[any](fCtx, &LookupArguments[any]{
+		Table: "codes",
+	})
+	assert.ErrorContains(t, err, `lookup table "codes" is not configured`)
+}
+
+func Test_Lookup_invalid_table(t *testing.T) {
+	_, err := createLookupFunction[any](ottl.FunctionContext{}, &LookupArguments[any]{
+		Table: filepath.Join(t.TempDir(), "missing.csv"),
+	})
+	assert.ErrorContains(t, err, "failed to read lookup table")
+
+	_, err = createLookupFunction[any](ottl.FunctionContext{}, &LookupArguments[any]{
+		Table: "file:" + writeLookupTable(t, "owners.txt", "namespace,team\n"),
+	})
+	assert.ErrorContains(t, err, "must have a .csv or .json extension")
+
+	_, err = createLookupFunction[any](ottl.FunctionContext{}, &LookupArguments[any]{
+		Table: writeLookupTable(t, "owners.json", `["checkout"]`),
+	})
+	assert.ErrorContains(t, err, "failed to parse lookup table")
+}
+
+func Test_lookup_editor(t *testing.T) {
+	path := writeLookupTable(t, "owners.csv", "namespace,team\ncheckout,payments\n")
+
+	tests := []struct {
+		name string
+		key  string
+		want string
+	}{
+		{
+			name: "key found",
+			key:  "checkout",
+			want: "payments",
+		},
+		{
+			name: "key not found",
+			key:  "search",
+			want: "original",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := &ottl.StandardGetSetter[pcommon.Value]{
+				Setter: func(_ context.Context, tCtx pcommon.Value, val any) error {
+					tCtx.SetStr(val.(string))
+					return nil
+				},
+			}
+			exprFunc, err := createLookupEditorFunction[pcommon.Value](ottl.FunctionContext{}, &LookupEditorArguments[pcommon.Value]{
+				Target: target,
+				Table:  path,
+				Key: ottl.StandardStringLikeGetter[pcommon.Value]{
+					Getter: func(_ context.Context, _ pcommon.Value) (any, error) {
+						return tt.key, nil
+					},
+				},
+			})
+			require.NoError(t, err)
+
+			value := pcommon.NewValueStr("original")
+			result, err := exprFunc(context.Background(), value)
+			require.NoError(t, err)
+			assert.Nil(t, result)
+			assert.Equal(t, tt.want, value.Str())
+		})
+	}
+}
+
+func lookupWithKey(t *testing.T, table string, key string) ottl.ExprFunc[any] {
+	t.Helper()
+	exprFunc, err := createLookupFunction[any](ottl.FunctionContext{}, &LookupArguments[any]{
+		Table: table,
+		Key: ottl.StandardStringLikeGetter[any]{
+			Getter: func(_ context.Context, _ any) (any, error) {
+				return key, nil
+			},
+		},
+	})
+	require.NoError(t, err)
+	return exprFunc
+}
+
+type staticLookupTable map[string]string
+
+func (s staticLookupTable) Get(_ context.Context, key string) (pcommon.Value, bool, error) {
+	v, ok := s[key]
+	return pcommon.NewValueStr(v), ok, nil
+}
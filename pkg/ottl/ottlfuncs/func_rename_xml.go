@@ -0,0 +1,57 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/antchfx/xmlquery"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+type RenameXMLArguments[K any] struct {
+	Target ottl.GetSetter[K]
+	XPath  string
+	Name   string
+}
+
+func NewRenameXMLFactory[K any]() ottl.Factory[K] {
+	return ottl.NewFactory("rename_xml", &RenameXMLArguments[K]{}, createRenameXMLFunction[K])
+}
+
+func createRenameXMLFunction[K any](_ ottl.FunctionContext, oArgs ottl.Arguments) (ottl.ExprFunc[K], error) {
+	args, ok := oArgs.(*RenameXMLArguments[K])
+
+	if !ok {
+		return nil, fmt.Errorf("RenameXMLFactory args must be of type *RenameXMLArguments[K]")
+	}
+
+	return renameXML(args.Target, args.XPath, args.Name)
+}
+
+func renameXML[K any](target ottl.GetSetter[K], xPath string, name string) (ottl.ExprFunc[K], error) {
+	compiled, err := compileXPath("rename_xml", xPath)
+	if err != nil {
+		return nil, err
+	}
+	newName := xmlName(name)
+	if newName.Local == "" {
+		return nil, fmt.Errorf("the name %q supplied to rename_xml is invalid", name)
+	}
+	return editXML(target, func(_ context.Context, _ K, doc *xmlquery.Node) error {
+		for _, n := range queryXML(doc, compiled) {
+			switch n.Type {
+			case xmlquery.ElementNode:
+				n.Prefix, n.Data = newName.Space, newName.Local
+			case xmlquery.AttributeNode:
+				if i := xmlAttrIndex(n); i >= 0 {
+					n.Parent.Attr[i].Name = newName
+				}
+			}
+		}
+		return nil
+	}), nil
+}
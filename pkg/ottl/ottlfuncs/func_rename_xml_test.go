@@ -0,0 +1,78 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+func Test_renameXML(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		xPath    string
+		newName  string
+		expected string
+	}{
+		{
+			name:     "element",
+			document: `<a><b>1</b><b>2</b></a>`,
+			xPath:    "/a/b",
+			newName:  "c",
+			expected: `<a><c>1</c><c>2</c></a>`,
+		},
+		{
+			name:     "element with prefix",
+			document: `<a xmlns:x="urn:x"><b>1</b></a>`,
+			xPath:    "/a/b",
+			newName:  "x:b",
+			expected: `<a xmlns:x="urn:x"><x:b>1</x:b></a>`,
+		},
+		{
+			name:     "attribute",
+			document: `<a id="1" name="x"><b>1</b></a>`,
+			xPath:    "/a/@id",
+			newName:  "key",
+			expected: `<a key="1" name="x"><b>1</b></a>`,
+		},
+		{
+			name:     "attribute with namespace",
+			document: `<a xmlns:x="urn:x" id="1" x:id="2"></a>`,
+			xPath:    "/a/@x:id",
+			newName:  "key",
+			expected: `<a xmlns:x="urn:x" id="1" key="2"></a>`,
+		},
+		{
+			name:     "no match",
+			document: `<a><b>1</b></a>`,
+			xPath:    "/a/c",
+			newName:  "d",
+			expected: `<a><b>1</b></a>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exprFunc, err := renameXML[pcommon.Value](xmlTestTarget, tt.xPath, tt.newName)
+			require.NoError(t, err)
+
+			value := pcommon.NewValueStr(tt.document)
+			_, err = exprFunc(context.Background(), value)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, value.Str())
+		})
+	}
+}
+
+func Test_renameXML_error(t *testing.T) {
+	_, err := renameXML[pcommon.Value](xmlTestTarget, "//[", "a")
+	assert.ErrorContains(t, err, "supplied to rename_xml is invalid")
+
+	_, err = renameXML[pcommon.Value](xmlTestTarget, "/a", "")
+	assert.ErrorContains(t, err, "the name \"\" supplied to rename_xml is invalid")
+}
@@ -12,14 +12,20 @@ func StandardFuncs[K any]() map[string]ottl.Factory[K] {
 		// Editors
 		NewDeleteKeyFactory[K](),
 		NewDeleteMatchingKeysFactory[K](),
+		NewDeleteXMLFactory[K](),
 		NewKeepMatchingKeysFactory[K](),
+		NewConvertAttributesToElementsXMLFactory[K](),
 		NewFlattenFactory[K](),
+		NewFlattenXMLNamespacesFactory[K](),
+		NewInsertXMLFactory[K](),
 		NewKeepKeysFactory[K](),
 		NewLimitFactory[K](),
+		NewLookupEditorFactory[K](),
 		NewMergeMapsFactory[K](),
 		NewReplaceAllMatchesFactory[K](),
 		NewReplaceAllPatternsFactory[K](),
 		NewReplaceMatchFactory[K](),
+		NewRenameXMLFactory[K](),
 		NewReplacePatternFactory[K](),
 		NewSetFactory[K](),
 		NewTruncateAllFactory[K](),
@@ -38,6 +44,7 @@ func converters[K any]() []ottl.Factory[K] {
 		// Converters
 		NewBase64DecodeFactory[K](),
 		NewDecodeFactory[K](),
+		NewCanonicalIPFactory[K](),
 		NewConcatFactory[K](),
 		NewConvertCaseFactory[K](),
 		NewDayFactory[K](),
@@ -45,12 +52,18 @@ func converters[K any]() []ottl.Factory[K] {
 		NewDurationFactory[K](),
 		NewExtractPatternsFactory[K](),
 		NewExtractGrokPatternsFactory[K](),
+		NewFilterFactory[K](),
 		NewFnvFactory[K](),
+		NewFormatTimeFactory[K](),
+		NewGetXMLFactory[K](),
+		NewGetXMLValuesFactory[K](),
+		NewHMACFactory[K](),
 		NewHourFactory[K](),
 		NewHoursFactory[K](),
 		NewIntFactory[K](),
 		NewIsBoolFactory[K](),
 		NewIsDoubleFactory[K](),
+		NewIsInCIDRFactory[K](),
 		NewIsListFactory[K](),
 		NewIsIntFactory[K](),
 		NewIsMapFactory[K](),
@@ -58,6 +71,7 @@ func converters[K any]() []ottl.Factory[K] {
 		NewIsStringFactory[K](),
 		NewLenFactory[K](),
 		NewLogFactory[K](),
+		NewLookupFactory[K](),
 		NewMD5Factory[K](),
 		NewMicrosecondsFactory[K](),
 		NewMillisecondsFactory[K](),
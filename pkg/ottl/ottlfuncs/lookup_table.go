@@ -0,0 +1,187 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+const lookupFilePrefix = "file:"
+
+// lookupReloadCheckInterval is the minimum interval between two checks for
+// changes of the file of a file lookup table.
+var lookupReloadCheckInterval = time.Second
+
+// newLookupTable returns the table referenced by name. Names prefixed with
+// `file:`, or ending with `.csv` or `.json`, are paths to file tables. Other
+// names reference the lookup tables provided by the component.
+func newLookupTable(name string, fCtx ottl.FunctionContext) (ottl.LookupTable, error) {
+	path, isFile := strings.CutPrefix(name, lookupFilePrefix)
+	ext := strings.ToLower(filepath.Ext(path))
+	if isFile || ext == ".csv" || ext == ".json" {
+		return newFileLookupTable(path, fCtx.Set.Logger)
+	}
+	table, ok := fCtx.LookupTables[name]
+	if !ok {
+		return nil, fmt.Errorf("lookup table %q is not configured", name)
+	}
+	return table, nil
+}
+
+// fileLookupTable is a table read from a CSV or JSON file. The file is read
+// again when it changes.
+type fileLookupTable struct {
+	path   string
+	parse  func(io.Reader) (map[string]pcommon.Value, error)
+	logger *zap.Logger
+
+	mu        sync.RWMutex
+	entries   map[string]pcommon.Value
+	modTime   time.Time
+	size      int64
+	lastCheck time.Time
+}
+
+func newFileLookupTable(path string, logger *zap.Logger) (*fileLookupTable, error) {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	t := &fileLookupTable{
+		path:   path,
+		logger: logger,
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		t.parse = parseCSVLookupTable
+	case ".json":
+		t.parse = parseJSONLookupTable
+	default:
+		return nil, fmt.Errorf("lookup table file %q must have a .csv or .json extension", path)
+	}
+
+	if err := t.reload(time.Now()); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (t *fileLookupTable) Get(_ context.Context, key string) (pcommon.Value, bool, error) {
+	now := time.Now()
+	t.mu.RLock()
+	check := now.Sub(t.lastCheck) >= lookupReloadCheckInterval
+	t.mu.RUnlock()
+	if check {
+		if err := t.reload(now); err != nil {
+			// Keep using the last version of the table until the file is fixed
+			t.logger.Warn("failed to reload lookup table", zap.String("path", t.path), zap.Error(err))
+		}
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	value, ok := t.entries[key]
+	return value, ok, nil
+}
+
+// reload reads the file again if its modification time or size changed.
+func (t *fileLookupTable) reload(now time.Time) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.lastCheck = now
+
+	info, err := os.Stat(t.path)
+	if err != nil {
+		return fmt.Errorf("failed to read lookup table: %w", err)
+	}
+	if t.entries != nil && info.ModTime().Equal(t.modTime) && info.Size() == t.size {
+		return nil
+	}
+
+	f, err := os.Open(t.path)
+	if err != nil {
+		return fmt.Errorf("failed to read lookup table: %w", err)
+	}
+	defer f.Close()
+
+	entries, err := t.parse(f)
+	if err != nil {
+		return fmt.Errorf("failed to parse lookup table %q: %w", t.path, err)
+	}
+	t.entries = entries
+	t.modTime = info.ModTime()
+	t.size = info.Size()
+	return nil
+}
+
+// parseCSVLookupTable reads a CSV table with a header row. The first column is
+// the key. The value is the second column if the table has two columns, or a
+// map of all the columns by their header otherwise.
+func parseCSVLookupTable(r io.Reader) (map[string]pcommon.Value, error) {
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("missing header row")
+		}
+		return nil, err
+	}
+	if len(header) < 2 {
+		return nil, errors.New("at least two columns are required")
+	}
+
+	entries := make(map[string]pcommon.Value)
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if len(header) == 2 {
+			entries[row[0]] = pcommon.NewValueStr(row[1])
+			continue
+		}
+		value := pcommon.NewValueMap()
+		value.Map().EnsureCapacity(len(header))
+		for i, name := range header {
+			value.Map().PutStr(name, row[i])
+		}
+		entries[row[0]] = value
+	}
+}
+
+// parseJSONLookupTable reads a JSON object, mapping each key to its value.
+func parseJSONLookupTable(r io.Reader) (map[string]pcommon.Value, error) {
+	var raw map[string]any
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, err
+	}
+
+	entries := make(map[string]pcommon.Value, len(raw))
+	for key, v := range raw {
+		value := pcommon.NewValueEmpty()
+		if err := value.FromRaw(v); err != nil {
+			return nil, fmt.Errorf("invalid value of key %q: %w", key, err)
+		}
+		entries[key] = value
+	}
+	return entries, nil
+}
@@ -0,0 +1,63 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func Test_fileLookupTable_reload(t *testing.T) {
+	path := writeLookupTable(t, "owners.csv", "namespace,team\ncheckout,payments\n")
+	table, err := newFileLookupTable(path, zap.NewNop())
+	require.NoError(t, err)
+
+	value, ok, err := table.Get(context.Background(), "checkout")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "payments", value.Str())
+
+	// The file is not checked again before the check interval
+	require.NoError(t, os.WriteFile(path, []byte("namespace,team\ncheckout,billing\nsearch,discovery\n"), 0600))
+	value, _, _ = table.Get(context.Background(), "checkout")
+	assert.Equal(t, "payments", value.Str())
+
+	table.lastCheck = time.Time{}
+	value, ok, err = table.Get(context.Background(), "checkout")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "billing", value.Str())
+	_, ok, _ = table.Get(context.Background(), "search")
+	assert.True(t, ok)
+
+	// The last version of the table is kept if the file becomes invalid
+	require.NoError(t, os.WriteFile(path, []byte("namespace,team\ncheckout,billing,extra\n"), 0600))
+	table.lastCheck = time.Time{}
+	value, ok, err = table.Get(context.Background(), "checkout")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "billing", value.Str())
+
+	require.NoError(t, os.Remove(path))
+	table.lastCheck = time.Time{}
+	_, ok, err = table.Get(context.Background(), "search")
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func Test_parseCSVLookupTable_errors(t *testing.T) {
+	for content, expectedErr := range map[string]string{
+		"":                    "missing header row",
+		"namespace\ncheckout": "at least two columns are required",
+	} {
+		_, err := newFileLookupTable(writeLookupTable(t, "table.csv", content), nil)
+		assert.ErrorContains(t, err, expectedErr)
+	}
+}
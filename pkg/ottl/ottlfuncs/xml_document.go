@@ -0,0 +1,112 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/antchfx/xmlquery"
+	"github.com/antchfx/xpath"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+// compileXPath compiles the XPath expression supplied to an XML function.
+func compileXPath(function string, expr string) (*xpath.Expr, error) {
+	compiled, err := xpath.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("the XPath %q supplied to %s is invalid: %w", expr, function, err)
+	}
+	return compiled, nil
+}
+
+// parseXMLDocument parses an XML document. The XML declaration, which is added by the parser when it is missing, is
+// only kept if the document has one.
+func parseXMLDocument(s string) (*xmlquery.Node, error) {
+	doc, err := xmlquery.Parse(strings.NewReader(s))
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse XML document: %w", err)
+	}
+	if declaration := doc.FirstChild; declaration != nil && declaration.Type == xmlquery.DeclarationNode &&
+		!strings.HasPrefix(strings.TrimSpace(s), "<?xml") {
+		xmlquery.RemoveFromTree(declaration)
+	}
+	return doc, nil
+}
+
+// editXML parses the XML document held by the target, applies the edit and sets the target to the edited document.
+// Targets which are not strings are left unchanged.
+func editXML[K any](target ottl.GetSetter[K], edit func(ctx context.Context, tCtx K, doc *xmlquery.Node) error) ottl.ExprFunc[K] {
+	return func(ctx context.Context, tCtx K) (any, error) {
+		val, err := target.Get(ctx, tCtx)
+		if err != nil {
+			return nil, err
+		}
+		s, ok := val.(string)
+		if !ok {
+			return nil, nil
+		}
+		doc, err := parseXMLDocument(s)
+		if err != nil {
+			return nil, err
+		}
+		if err = edit(ctx, tCtx, doc); err != nil {
+			return nil, err
+		}
+		return nil, target.Set(ctx, tCtx, doc.OutputXML(false))
+	}
+}
+
+// queryXML returns the nodes selected by the XPath expression. Unlike xmlquery.QuerySelectorAll, the attribute
+// nodes it returns carry the prefix and the namespace URI of the attribute they select.
+func queryXML(doc *xmlquery.Node, expr *xpath.Expr) []*xmlquery.Node {
+	var nodes []*xmlquery.Node
+	for it := expr.Select(xmlquery.CreateXPathNavigator(doc)); it.MoveNext(); {
+		nav := it.Current().(*xmlquery.NodeNavigator)
+		if nav.NodeType() != xpath.AttributeNode {
+			nodes = append(nodes, nav.Current())
+			continue
+		}
+		value := &xmlquery.Node{Type: xmlquery.TextNode, Data: nav.Value()}
+		nodes = append(nodes, &xmlquery.Node{
+			Parent:       nav.Current(),
+			Type:         xmlquery.AttributeNode,
+			Data:         nav.LocalName(),
+			Prefix:       nav.Prefix(),
+			NamespaceURI: nav.NamespaceURL(),
+			FirstChild:   value,
+			LastChild:    value,
+		})
+	}
+	return nodes
+}
+
+// xmlAttrIndex returns the index of the attribute selected by an attribute node returned by queryXML, or -1 if it is
+// not found. Attributes are identified by their namespace URI and local name, as their prefixes may differ.
+func xmlAttrIndex(n *xmlquery.Node) int {
+	if n.Parent == nil {
+		return -1
+	}
+	for i, attr := range n.Parent.Attr {
+		if attr.Name.Local == n.Data && attr.NamespaceURI == n.NamespaceURI && !isXMLNamespaceDeclaration(attr) {
+			return i
+		}
+	}
+	return -1
+}
+
+func isXMLNamespaceDeclaration(attr xmlquery.Attr) bool {
+	return attr.Name.Space == "xmlns" || (attr.Name.Space == "" && attr.Name.Local == "xmlns")
+}
+
+// xmlName splits a possibly prefixed name, like `soap:Body`, into its prefix and local name.
+func xmlName(name string) xml.Name {
+	if prefix, local, ok := strings.Cut(name, ":"); ok {
+		return xml.Name{Space: prefix, Local: local}
+	}
+	return xml.Name{Local: name}
+}
@@ -0,0 +1,31 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs
+
+import (
+	"context"
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+// xmlTestTarget is the target of the XML editor tests: it gets the raw value and sets strings.
+var xmlTestTarget = &ottl.StandardGetSetter[pcommon.Value]{
+	Getter: func(_ context.Context, tCtx pcommon.Value) (any, error) {
+		return tCtx.AsRaw(), nil
+	},
+	Setter: func(_ context.Context, tCtx pcommon.Value, val any) error {
+		tCtx.SetStr(val.(string))
+		return nil
+	},
+}
+
+func Test_xmlName(t *testing.T) {
+	assert.Equal(t, xml.Name{Local: "Body"}, xmlName("Body"))
+	assert.Equal(t, xml.Name{Space: "soap", Local: "Body"}, xmlName("soap:Body"))
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 
 	"github.com/alecthomas/participle/v2"
 	"go.opentelemetry.io/collector/component"
@@ -51,6 +52,18 @@ func (c *Condition[K]) Eval(ctx context.Context, tCtx K) (bool, error) {
 	return c.condition.Eval(ctx, tCtx)
 }
 
+// ValueExpression holds a top level value expression. A ValueExpression is a literal, a path, a converter call or a
+// math expression that resolves to a value of any type.
+type ValueExpression[K any] struct {
+	getter   Getter[K]
+	origText string
+}
+
+// Eval returns the value the expression resolves to for the given TransformContext.
+func (e *ValueExpression[K]) Eval(ctx context.Context, tCtx K) (any, error) {
+	return e.getter.Get(ctx, tCtx)
+}
+
 // Parser provides the means to parse OTTL StatementSequence and Conditions given a specific set of functions,
 // a PathExpressionParser, and an EnumParser.
 type Parser[K any] struct {
@@ -59,6 +72,10 @@ type Parser[K any] struct {
 	enumParser        EnumParser
 	telemetrySettings component.TelemetrySettings
 	pathContextNames  map[string]struct{}
+	userFunctions     map[string]*userFunction
+	lookupTables      map[string]LookupTable
+	bindings          map[string]binding[K]
+	callStack         []string
 }
 
 func NewParser[K any](
@@ -81,6 +98,9 @@ func NewParser[K any](
 	for _, opt := range options {
 		opt(&p)
 	}
+	if err := p.checkUserFunctions(); err != nil {
+		return Parser[K]{}, err
+	}
 	return p, nil
 }
 
@@ -108,6 +128,27 @@ func WithPathContextNames[K any](contexts []string) Option[K] {
 	}
 }
 
+// WithFunctionDefinitions declares user-defined functions, which can be called like editors by the statements
+// parsed by the Parser. The names of the functions must not be already used by the functions of the Parser.
+// Invalid definitions are reported by NewParser, or when the function is called if the option is applied later.
+func WithFunctionDefinitions[K any](definitions []FunctionDefinition) Option[K] {
+	return func(p *Parser[K]) {
+		userFunctions := make(map[string]*userFunction, len(p.userFunctions)+len(definitions))
+		maps.Copy(userFunctions, p.userFunctions)
+		for _, definition := range definitions {
+			userFunctions[definition.Name] = newUserFunction(definition, userFunctions)
+		}
+		p.userFunctions = userFunctions
+	}
+}
+
+// WithLookupTables provides lookup tables, by name, to the functions of the statements parsed by the Parser.
+func WithLookupTables[K any](tables map[string]LookupTable) Option[K] {
+	return func(p *Parser[K]) {
+		p.lookupTables = tables
+	}
+}
+
 // ParseStatements parses string statements into ottl.Statement objects ready for execution.
 // Returns a slice of statements and a nil error on successful parsing.
 // If parsing fails, returns nil and a joined error containing each error per failed statement.
@@ -139,7 +180,17 @@ func (p *Parser[K]) ParseStatement(statement string) (*Statement[K], error) {
 	if err != nil {
 		return nil, err
 	}
-	function, err := p.newFunctionCall(parsed.Editor)
+	return p.newStatement(parsed, statement)
+}
+
+func (p *Parser[K]) newStatement(parsed *parsedStatement, origText string) (*Statement[K], error) {
+	var function Expr[K]
+	var err error
+	if parsed.ForEach != nil {
+		function, err = p.newForEach(parsed.ForEach)
+	} else {
+		function, err = p.newFunctionCall(parsed.Editor)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -150,7 +201,7 @@ func (p *Parser[K]) ParseStatement(statement string) (*Statement[K], error) {
 	return &Statement[K]{
 		function:  function,
 		condition: expression,
-		origText:  statement,
+		origText:  origText,
 	}, nil
 }
 
@@ -195,8 +246,27 @@ func (p *Parser[K]) ParseCondition(condition string) (*Condition[K], error) {
 	}, nil
 }
 
+// ParseValueExpression parses a single string expression into a ValueExpression ready for evaluation.
+// Returns a ValueExpression and a nil error on successful parsing.
+// If parsing fails, returns nil and an error.
+func (p *Parser[K]) ParseValueExpression(expression string) (*ValueExpression[K], error) {
+	parsed, err := parseValueExpression(expression)
+	if err != nil {
+		return nil, err
+	}
+	getter, err := p.newGetter(*parsed)
+	if err != nil {
+		return nil, err
+	}
+	return &ValueExpression[K]{
+		getter:   getter,
+		origText: expression,
+	}, nil
+}
+
 var parser = newParser[parsedStatement]()
 var conditionParser = newParser[booleanExpression]()
+var valueExpressionParser = newParser[value]()
 
 func parseStatement(raw string) (*parsedStatement, error) {
 	parsed, err := parser.ParseString("", raw)
@@ -226,6 +296,20 @@ func parseCondition(raw string) (*booleanExpression, error) {
 	return parsed, nil
 }
 
+func parseValueExpression(raw string) (*value, error) {
+	parsed, err := valueExpressionParser.ParseString("", raw)
+
+	if err != nil {
+		return nil, fmt.Errorf("value expression has invalid syntax: %w", err)
+	}
+	err = parsed.checkForCustomError()
+	if err != nil {
+		return nil, err
+	}
+
+	return parsed, nil
+}
+
 // newParser returns a parser that can be used to read a string into a parsedStatement. An error will be returned if the string
 // is not formatted for the DSL.
 func newParser[G any]() *participle.Parser[G] {
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottltest"
@@ -1242,6 +1243,66 @@ func Test_parse(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:      "for each with key and value",
+			statement: `for each k, v in attributes { set(attributes[k], v) }`,
+			expected: &parsedStatement{
+				ForEach: &forEach{
+					Key:   ottltest.Strp("k"),
+					Value: "v",
+					Target: value{
+						Literal: &mathExprLiteral{
+							Path: &path{
+								Fields: []field{
+									{
+										Name: "attributes",
+									},
+								},
+							},
+						},
+					},
+					Body: &parsedStatement{
+						Editor: editor{
+							Function: "set",
+							Arguments: []argument{
+								{
+									Value: value{
+										Literal: &mathExprLiteral{
+											Path: &path{
+												Fields: []field{
+													{
+														Name: "attributes",
+														Keys: []key{
+															{
+																Variable: ottltest.Strp("k"),
+															},
+														},
+													},
+												},
+											},
+										},
+									},
+								},
+								{
+									Value: value{
+										Literal: &mathExprLiteral{
+											Path: &path{
+												Fields: []field{
+													{
+														Name: "v",
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				WhereClause: nil,
+			},
+		},
 		{
 			name:      "editor with named arg",
 			statement: `set(name="foo")`,
@@ -2076,6 +2137,18 @@ func Test_parseStatement(t *testing.T) {
 		{`test() where one() == 1`, true},
 		{`test(fail())`, true},
 		{`Test()`, true},
+		{`set(attributes[key], "dog")`, false},
+		{`set(attributes[Key], "dog")`, true},
+		{`for each v in attributes { set(name, v) }`, false},
+		{`for each k, v in attributes { set(attributes[k], v) where v == "dog" } where name == "fido"`, false},
+		{`for each k, v in attributes { for each i, item in v { set(attributes[k], item) } }`, false},
+		{`for each v in Split(name, ",") { test(v) }`, false},
+		{`for each v in attributes["list"] set(name, v)`, true},
+		{`for each v in attributes { }`, true},
+		{`for each v in attributes { Set(name, v) }`, true},
+		{`for each in attributes { set(name, v) }`, true},
+		{`for v in attributes { set(name, v) }`, true},
+		{`for each k, v, x in attributes { set(name, v) }`, true},
 	}
 	pat := regexp.MustCompile("[^a-zA-Z0-9]+")
 	for _, tt := range tests {
@@ -2151,6 +2224,91 @@ func Test_parseCondition(t *testing.T) {
 	}
 }
 
+// This test doesn't validate parser results, simply checks whether the parse succeeds or not.
+func Test_parseValueExpression(t *testing.T) {
+	tests := []struct {
+		expression string
+		wantErr    bool
+	}{
+		{`name`, false},
+		{`attributes["foo"]`, false},
+		{`"foo"`, false},
+		{`1 + 2`, false},
+		{`Concat([name, "foo"], ".")`, false},
+		{`{"foo": name}`, false},
+		{`nil`, false},
+		{`set(name, "foo")`, true},
+		{`name == "foo"`, true},
+		{`name where name == "foo"`, true},
+		{`"foo`, true},
+		{`(`, true},
+		{``, true},
+	}
+	pat := regexp.MustCompile("[^a-zA-Z0-9]+")
+	for _, tt := range tests {
+		name := pat.ReplaceAllString(tt.expression, "_")
+		t.Run(name, func(t *testing.T) {
+			ast, err := parseValueExpression(tt.expression)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseValueExpression(%s) error = %v, wantErr %v", tt.expression, err, tt.wantErr)
+				t.Errorf("AST: %+v", ast)
+				return
+			}
+		})
+	}
+}
+
+func Test_ParseValueExpression(t *testing.T) {
+	p, err := NewParser(
+		CreateFactoryMap[any](createFactory("Hello", &struct{}{}, hello)),
+		testParsePath[any],
+		componenttest.NewNopTelemetrySettings(),
+		WithEnumParser[any](testParseEnum),
+	)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		expression string
+		tCtx       any
+		want       any
+	}{
+		{
+			name:       "path",
+			expression: `name`,
+			tCtx:       "fido",
+			want:       "fido",
+		},
+		{
+			name:       "literal",
+			expression: `"fido"`,
+			want:       "fido",
+		},
+		{
+			name:       "math expression",
+			expression: `1 + 2 * 3`,
+			want:       int64(7),
+		},
+		{
+			name:       "converter",
+			expression: `Hello()`,
+			want:       "world",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := p.ParseValueExpression(tt.expression)
+			require.NoError(t, err)
+			got, err := expr.Eval(context.Background(), tt.tCtx)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err = p.ParseValueExpression(`Unknown()`)
+	assert.Error(t, err)
+}
+
 func Test_Statement_Execute(t *testing.T) {
 	tests := []struct {
 		name              string
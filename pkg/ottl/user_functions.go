@@ -0,0 +1,171 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottl // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+)
+
+var (
+	userFunctionNameRegexp = regexp.MustCompile(`^[a-z][a-zA-Z0-9_]*$`)
+	parameterNameRegexp    = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+	reservedNames          = []string{"nil", "true", "false", "not", "and", "or", "where", "for", "each", "in"}
+)
+
+// FunctionDefinition declares a user-defined function: a named list of statements which can be called like an editor.
+// When the function is called, each parameter is bound to the corresponding argument of the call. Parameters are
+// referenced by name in the statements, and may be indexed and set when they are bound to paths.
+type FunctionDefinition struct {
+	Name       string   `mapstructure:"name"`
+	Parameters []string `mapstructure:"params"`
+	Statements []string `mapstructure:"statements"`
+}
+
+// Validate checks that the name, the parameters and the syntax of the statements of the function are valid.
+func (d FunctionDefinition) Validate() error {
+	if !userFunctionNameRegexp.MatchString(d.Name) {
+		return fmt.Errorf("invalid function name %q, names must start with a lowercase letter followed by letters, digits or underscores", d.Name)
+	}
+	for i, param := range d.Parameters {
+		if !parameterNameRegexp.MatchString(param) || slices.Contains(reservedNames, param) {
+			return fmt.Errorf("invalid parameter name %q of function %q, names must be lowercase letters, digits or underscores", param, d.Name)
+		}
+		if slices.Contains(d.Parameters[:i], param) {
+			return fmt.Errorf("duplicate parameter %q of function %q", param, d.Name)
+		}
+	}
+	if len(d.Statements) == 0 {
+		return fmt.Errorf("function %q has no statements", d.Name)
+	}
+	var errs error
+	for _, statement := range d.Statements {
+		if _, err := parseStatement(statement); err != nil {
+			errs = errors.Join(errs, fmt.Errorf("unable to parse OTTL statement %q of function %q: %w", statement, d.Name, err))
+		}
+	}
+	return errs
+}
+
+type userFunction struct {
+	FunctionDefinition
+	statements []*parsedStatement
+	// err is the error found while defining the function, returned when it is called.
+	err error
+}
+
+// newUserFunction validates the definition and parses its statements. Functions already defined are used to find
+// duplicate definitions.
+func newUserFunction(definition FunctionDefinition, defined map[string]*userFunction) *userFunction {
+	uf := &userFunction{FunctionDefinition: definition}
+	if _, ok := defined[definition.Name]; ok {
+		uf.err = fmt.Errorf("duplicate user-defined function %q", definition.Name)
+		return uf
+	}
+	if uf.err = definition.Validate(); uf.err != nil {
+		return uf
+	}
+	uf.statements = make([]*parsedStatement, len(definition.Statements))
+	for i, statement := range definition.Statements {
+		// Validate checked the syntax of the statements already.
+		uf.statements[i], _ = parseStatement(statement)
+	}
+	return uf
+}
+
+// checkUserFunctions returns the errors of the user-defined functions, and checks their names are not used by other
+// functions.
+func (p *Parser[K]) checkUserFunctions() error {
+	var errs error
+	for _, uf := range p.userFunctions {
+		errs = errors.Join(errs, p.checkUserFunction(uf))
+	}
+	return errs
+}
+
+func (p *Parser[K]) checkUserFunction(uf *userFunction) error {
+	if uf.err != nil {
+		return uf.err
+	}
+	if _, ok := p.functions[uf.Name]; ok {
+		return fmt.Errorf("user-defined function %q conflicts with an existing function", uf.Name)
+	}
+	return nil
+}
+
+// newUserFunctionCall builds a call to a user-defined function. The statements of the function are built for each
+// call, with the parameters bound to the arguments of the call, and run in order when the call is evaluated. The
+// statements only see the parameters of the function, not the bindings of the caller.
+func (p *Parser[K]) newUserFunctionCall(uf *userFunction, ed editor) (Expr[K], error) {
+	if err := p.checkUserFunction(uf); err != nil {
+		return Expr[K]{}, err
+	}
+	if slices.Contains(p.callStack, uf.Name) {
+		return Expr[K]{}, fmt.Errorf("recursive call to user-defined function %q", uf.Name)
+	}
+	args, err := uf.bindArguments(ed.Arguments)
+	if err != nil {
+		return Expr[K]{}, fmt.Errorf("error while parsing arguments for call to %q: %w", uf.Name, err)
+	}
+
+	bindings := make(map[string]binding[K], len(args))
+	for i, param := range uf.Parameters {
+		bindings[param] = &parameter[K]{name: param, arg: args[i], caller: p}
+	}
+	scoped := *p
+	scoped.bindings = bindings
+	scoped.callStack = append(slices.Clone(p.callStack), uf.Name)
+
+	statements := make([]*Statement[K], len(uf.statements))
+	for i, parsed := range uf.statements {
+		statements[i], err = scoped.newStatement(parsed, uf.Statements[i])
+		if err != nil {
+			return Expr[K]{}, fmt.Errorf("error in statement %q of function %q: %w", uf.Statements[i], uf.Name, err)
+		}
+	}
+
+	return Expr[K]{exprFunc: func(ctx context.Context, tCtx K) (any, error) {
+		for _, statement := range statements {
+			if _, _, err := statement.Execute(ctx, tCtx); err != nil {
+				return nil, fmt.Errorf("failed to execute statement %q of function %q: %w", statement.origText, uf.Name, err)
+			}
+		}
+		return nil, nil
+	}}, nil
+}
+
+// bindArguments returns the argument of each parameter, in the order of the parameters. Arguments are positional,
+// or named after the parameters.
+func (uf *userFunction) bindArguments(arguments []argument) ([]value, error) {
+	if len(arguments) != len(uf.Parameters) {
+		return nil, fmt.Errorf("incorrect number of arguments. Expected: %d Received: %d", len(uf.Parameters), len(arguments))
+	}
+	args := make([]value, len(arguments))
+	bound := make([]bool, len(arguments))
+	seenNamed := false
+	for i, arg := range arguments {
+		if arg.FunctionName != nil {
+			return nil, fmt.Errorf("functions cannot be passed to user-defined functions, got %q", *arg.FunctionName)
+		}
+		idx := i
+		if arg.Name != "" {
+			seenNamed = true
+			idx = slices.Index(uf.Parameters, arg.Name)
+			if idx < 0 {
+				return nil, fmt.Errorf("no such parameter: %s", arg.Name)
+			}
+		} else if seenNamed {
+			return nil, errors.New("unnamed argument used after named argument")
+		}
+		if bound[idx] {
+			return nil, fmt.Errorf("parameter %q is bound more than once", uf.Parameters[idx])
+		}
+		bound[idx] = true
+		args[idx] = arg.Value
+	}
+	return args, nil
+}
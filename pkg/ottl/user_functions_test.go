@@ -0,0 +1,297 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottl
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+func Test_FunctionDefinition_Validate(t *testing.T) {
+	tests := []struct {
+		name       string
+		definition FunctionDefinition
+		err        string
+	}{
+		{
+			name: "valid",
+			definition: FunctionDefinition{
+				Name:       "copy_to",
+				Parameters: []string{"target", "value"},
+				Statements: []string{`set(target, value)`},
+			},
+		},
+		{
+			name: "no parameters",
+			definition: FunctionDefinition{
+				Name:       "setDefaults",
+				Statements: []string{`set(attributes["a"], "b")`},
+			},
+		},
+		{
+			name: "uppercase name",
+			definition: FunctionDefinition{
+				Name:       "CopyTo",
+				Statements: []string{`set(attributes["a"], "b")`},
+			},
+			err: `invalid function name "CopyTo"`,
+		},
+		{
+			name: "invalid parameter",
+			definition: FunctionDefinition{
+				Name:       "copy_to",
+				Parameters: []string{"Target"},
+				Statements: []string{`set(attributes["a"], "b")`},
+			},
+			err: `invalid parameter name "Target"`,
+		},
+		{
+			name: "reserved parameter",
+			definition: FunctionDefinition{
+				Name:       "copy_to",
+				Parameters: []string{"where"},
+				Statements: []string{`set(attributes["a"], "b")`},
+			},
+			err: `invalid parameter name "where"`,
+		},
+		{
+			name: "duplicate parameter",
+			definition: FunctionDefinition{
+				Name:       "copy_to",
+				Parameters: []string{"target", "target"},
+				Statements: []string{`set(target, "b")`},
+			},
+			err: `duplicate parameter "target"`,
+		},
+		{
+			name: "no statements",
+			definition: FunctionDefinition{
+				Name: "copy_to",
+			},
+			err: `function "copy_to" has no statements`,
+		},
+		{
+			name: "invalid statement",
+			definition: FunctionDefinition{
+				Name:       "copy_to",
+				Statements: []string{`set(`},
+			},
+			err: `unable to parse OTTL statement "set("`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.definition.Validate()
+			if tt.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+var testFunctionDefinitions = []FunctionDefinition{
+	{
+		Name:       "copy_to",
+		Parameters: []string{"target", "value"},
+		Statements: []string{`set(target, value)`},
+	},
+	{
+		Name:       "copy_all",
+		Parameters: []string{"source"},
+		Statements: []string{`for each k, v in source { copy_to(attributes[k], v) }`},
+	},
+	{
+		Name:       "copy_first",
+		Parameters: []string{"list", "key"},
+		Statements: []string{`set(attributes[key], list[0])`},
+	},
+	{
+		Name:       "mark",
+		Statements: []string{`set(attributes["marked"], true)`, `set(attributes["count"], 1)`},
+	},
+	{
+		Name:       "loop",
+		Statements: []string{`loop()`},
+	},
+}
+
+func Test_UserFunctions(t *testing.T) {
+	tests := []struct {
+		name      string
+		statement string
+		want      func(attrs pcommon.Map)
+	}{
+		{
+			name:      "positional arguments",
+			statement: `copy_to(attributes["copy"], attributes["str"])`,
+			want: func(attrs pcommon.Map) {
+				attrs.PutStr("copy", "value")
+			},
+		},
+		{
+			name:      "named arguments",
+			statement: `copy_to(value = "literal", target = attributes["copy"])`,
+			want: func(attrs pcommon.Map) {
+				attrs.PutStr("copy", "literal")
+			},
+		},
+		{
+			name:      "no arguments",
+			statement: `mark()`,
+			want: func(attrs pcommon.Map) {
+				attrs.PutBool("marked", true)
+				attrs.PutInt("count", 1)
+			},
+		},
+		{
+			name:      "where clause",
+			statement: `mark() where attributes["str"] == "other"`,
+			want:      func(pcommon.Map) {},
+		},
+		{
+			name:      "loop calling a function",
+			statement: `copy_all(attributes["src"])`,
+			want: func(attrs pcommon.Map) {
+				attrs.PutStr("a", "1")
+				attrs.PutStr("b", "2")
+			},
+		},
+		{
+			name:      "function called in a loop",
+			statement: `for each v in attributes["list"] { copy_to(attributes[v], v) }`,
+			want: func(attrs pcommon.Map) {
+				attrs.PutStr("x", "x")
+				attrs.PutStr("y", "y")
+			},
+		},
+		{
+			name:      "indexed parameters",
+			statement: `copy_first(attributes["list"], "first")`,
+			want: func(attrs pcommon.Map) {
+				attrs.PutStr("first", "x")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newAttributesParser(t, WithFunctionDefinitions[pcommon.Map](testFunctionDefinitions))
+			statement, err := p.ParseStatement(tt.statement)
+			require.NoError(t, err)
+
+			attrs := newLoopAttributes()
+			_, _, err = statement.Execute(context.Background(), attrs)
+			require.NoError(t, err)
+
+			expected := newLoopAttributes()
+			tt.want(expected)
+			assert.Equal(t, expected.AsRaw(), attrs.AsRaw())
+		})
+	}
+}
+
+func Test_UserFunctions_ParseError(t *testing.T) {
+	tests := []struct {
+		name      string
+		statement string
+		err       string
+	}{
+		{
+			name:      "recursive call",
+			statement: `loop()`,
+			err:       `recursive call to user-defined function "loop"`,
+		},
+		{
+			name:      "missing argument",
+			statement: `copy_to(attributes["copy"])`,
+			err:       `incorrect number of arguments. Expected: 2 Received: 1`,
+		},
+		{
+			name:      "unknown named argument",
+			statement: `copy_to(target = attributes["copy"], val = "x")`,
+			err:       `no such parameter: val`,
+		},
+		{
+			name:      "argument bound twice",
+			statement: `copy_to(attributes["copy"], target = attributes["copy"])`,
+			err:       `parameter "target" is bound more than once`,
+		},
+		{
+			name:      "setting a literal",
+			statement: `copy_to("target", "x")`,
+			err:       `parameter "target" must be bound to a path to be set`,
+		},
+		{
+			name:      "indexing a literal",
+			statement: `copy_first("list", "first")`,
+			err:       `parameter "list" cannot be indexed`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newAttributesParser(t, WithFunctionDefinitions[pcommon.Map](testFunctionDefinitions))
+			_, err := p.ParseStatement(tt.statement)
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func Test_UserFunctions_Scope(t *testing.T) {
+	p := newAttributesParser(t, WithFunctionDefinitions[pcommon.Map]([]FunctionDefinition{
+		{
+			Name:       "uses_v",
+			Statements: []string{`set(attributes["x"], v)`},
+		},
+	}))
+	_, err := p.ParseStatement(`for each v in attributes["list"] { uses_v() }`)
+	assert.ErrorContains(t, err, "bad path")
+}
+
+func Test_NewParser_UserFunctionsError(t *testing.T) {
+	tests := []struct {
+		name        string
+		definitions []FunctionDefinition
+		err         string
+	}{
+		{
+			name: "conflicting name",
+			definitions: []FunctionDefinition{
+				{Name: "set", Statements: []string{`set(attributes["a"], "b")`}},
+			},
+			err: `user-defined function "set" conflicts with an existing function`,
+		},
+		{
+			name: "duplicate name",
+			definitions: []FunctionDefinition{
+				{Name: "mark", Statements: []string{`set(attributes["a"], "b")`}},
+				{Name: "mark", Statements: []string{`set(attributes["a"], "c")`}},
+			},
+			err: `duplicate user-defined function "mark"`,
+		},
+		{
+			name: "invalid definition",
+			definitions: []FunctionDefinition{
+				{Name: "mark"},
+			},
+			err: `function "mark" has no statements`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewParser[pcommon.Map](
+				CreateFactoryMap(newSetFactory()),
+				attributesParsePath,
+				componenttest.NewNopTelemetrySettings(),
+				WithFunctionDefinitions[pcommon.Map](tt.definitions),
+			)
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
@@ -0,0 +1,258 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package deltatocumulativeprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/deltatocumulativeprocessor"
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/exp/metrics/identity"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/deltatocumulativeprocessor/internal/data"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/deltatocumulativeprocessor/internal/streams"
+)
+
+const (
+	checkpointSumsKey = "sums"
+	checkpointExpoKey = "expo"
+	checkpointHistKey = "hist"
+)
+
+var errCorruptedCheckpoint = errors.New("corrupted checkpoint")
+
+// checkpointer persists the stream state of the processor to a storage extension.
+type checkpointer struct {
+	storageID component.ID
+	id        component.ID
+	interval  time.Duration
+	maxStale  time.Duration
+
+	client storage.Client
+}
+
+// restore loads the stream state of the last checkpoint. Checkpoints older than max_stale are
+// ignored, as their streams would have been removed by now.
+func (p *Processor) restore(ctx context.Context) error {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	now := time.Now()
+	return errors.Join(
+		restorePipeline(ctx, p.checkpoint, checkpointSumsKey, p.sums, now),
+		restorePipeline(ctx, p.checkpoint, checkpointExpoKey, p.expo, now),
+		restorePipeline(ctx, p.checkpoint, checkpointHistKey, p.hist, now),
+	)
+}
+
+// save writes the current stream state in a single batch. The state is copied under the lock,
+// and encoded once the lock is released so that the processing of metrics isn't held by it.
+func (p *Processor) save(ctx context.Context) error {
+	p.mtx.Lock()
+	now := time.Now()
+	sumsSnapshot := snapshotStreams(p.sums.aggr.Map, now)
+	expoSnapshot := snapshotStreams(p.expo.aggr.Map, now)
+	histSnapshot := snapshotStreams(p.hist.aggr.Map, now)
+	p.mtx.Unlock()
+
+	sums, errs := sumsSnapshot.encode()
+	expo, err := expoSnapshot.encode()
+	errs = errors.Join(errs, err)
+	hist, err := histSnapshot.encode()
+	errs = errors.Join(errs, err)
+	if errs != nil {
+		return errs
+	}
+
+	return p.checkpoint.client.Batch(ctx,
+		storage.SetOperation(checkpointSumsKey, sums),
+		storage.SetOperation(checkpointExpoKey, expo),
+		storage.SetOperation(checkpointHistKey, hist),
+	)
+}
+
+func (p *Processor) saveEvery(interval time.Duration) {
+	tick := time.NewTicker(interval)
+	defer tick.Stop()
+	for {
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-tick.C:
+			if err := p.save(p.ctx); err != nil {
+				p.log.Warn("failed to checkpoint the stream state", zap.Error(err))
+			}
+		}
+	}
+}
+
+func restorePipeline[D data.Point[D]](ctx context.Context, cp *checkpointer, key string, pipe Pipeline[D], now time.Time) error {
+	buf, err := cp.client.Get(ctx, key)
+	if err != nil || buf == nil {
+		return err
+	}
+	at, ids, dps, err := decodeStreams[D](buf)
+	if err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+	if cp.maxStale > 0 && now.Sub(at) >= cp.maxStale {
+		return nil
+	}
+
+	var errs error
+	for i, id := range ids {
+		if err := pipe.aggr.Map.Store(id, dps[i]); err != nil {
+			errs = errors.Join(errs, err)
+		}
+	}
+	return errs
+}
+
+// streamsSnapshot is a copy of the streams of a pipeline, taken at a checkpoint.
+type streamsSnapshot struct {
+	at    time.Time
+	ids   []identity.Stream
+	md    pmetric.Metrics
+	count uint64
+}
+
+// snapshotStreams copies the identities and the datapoints of the streams of m.
+func snapshotStreams[D data.Point[D]](m streams.Map[D], now time.Time) streamsSnapshot {
+	md := pmetric.NewMetrics()
+	metric := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	s := streamsSnapshot{at: now, md: md}
+	m.Items()(func(id identity.Stream, dp D) bool {
+		s.ids = append(s.ids, id)
+		appendPoint(metric, dp)
+		return true
+	})
+	s.count = uint64(len(s.ids))
+	return s
+}
+
+// encode encodes the checkpoint time, the number of streams and their identities,
+// followed by their datapoints as a single proto encoded metric.
+func (s streamsSnapshot) encode() ([]byte, error) {
+	var (
+		ids  []byte
+		errs error
+	)
+	for _, id := range s.ids {
+		b, err := id.MarshalBinary()
+		if err != nil {
+			errs = errors.Join(errs, err)
+			continue
+		}
+		ids = binary.AppendUvarint(ids, uint64(len(b)))
+		ids = append(ids, b...)
+	}
+	if errs != nil {
+		return nil, errs
+	}
+
+	points, err := (&pmetric.ProtoMarshaler{}).MarshalMetrics(s.md)
+	if err != nil {
+		return nil, err
+	}
+	buf := binary.BigEndian.AppendUint64(nil, uint64(s.at.UnixNano()))
+	buf = binary.AppendUvarint(buf, s.count)
+	buf = append(buf, ids...)
+	return append(buf, points...), nil
+}
+
+func decodeStreams[D data.Point[D]](buf []byte) (time.Time, []identity.Stream, []D, error) {
+	if len(buf) < 8 {
+		return time.Time{}, nil, nil, errCorruptedCheckpoint
+	}
+	at := time.Unix(0, int64(binary.BigEndian.Uint64(buf)))
+	buf = buf[8:]
+
+	count, n := binary.Uvarint(buf)
+	if n <= 0 || count > uint64(len(buf)) {
+		return at, nil, nil, errCorruptedCheckpoint
+	}
+	buf = buf[n:]
+
+	ids := make([]identity.Stream, count)
+	for i := range ids {
+		size, n := binary.Uvarint(buf)
+		if n <= 0 || uint64(len(buf)-n) < size {
+			return at, nil, nil, errCorruptedCheckpoint
+		}
+		buf = buf[n:]
+		if err := ids[i].UnmarshalBinary(buf[:size]); err != nil {
+			return at, nil, nil, err
+		}
+		buf = buf[size:]
+	}
+
+	md, err := (&pmetric.ProtoUnmarshaler{}).UnmarshalMetrics(buf)
+	if err != nil {
+		return at, nil, nil, err
+	}
+	var dps []D
+	if md.MetricCount() == 1 {
+		dps = pointsOf[D](md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0))
+	}
+	if len(dps) != len(ids) {
+		return at, nil, nil, errCorruptedCheckpoint
+	}
+	return at, ids, dps, nil
+}
+
+func appendPoint[D data.Point[D]](m pmetric.Metric, dp D) {
+	switch dp := any(dp).(type) {
+	case data.Number:
+		if m.Type() != pmetric.MetricTypeSum {
+			m.SetEmptySum()
+		}
+		dp.NumberDataPoint.CopyTo(m.Sum().DataPoints().AppendEmpty())
+	case data.Histogram:
+		if m.Type() != pmetric.MetricTypeHistogram {
+			m.SetEmptyHistogram()
+		}
+		dp.HistogramDataPoint.CopyTo(m.Histogram().DataPoints().AppendEmpty())
+	case data.ExpHistogram:
+		if m.Type() != pmetric.MetricTypeExponentialHistogram {
+			m.SetEmptyExponentialHistogram()
+		}
+		dp.DataPoint.CopyTo(m.ExponentialHistogram().DataPoints().AppendEmpty())
+	}
+}
+
+// pointsOf returns the datapoints of m, if they are of type D.
+func pointsOf[D data.Point[D]](m pmetric.Metric) []D {
+	var dps []D
+	var zero D
+	switch any(zero).(type) {
+	case data.Number:
+		if m.Type() != pmetric.MetricTypeSum {
+			return nil
+		}
+		for i := 0; i < m.Sum().DataPoints().Len(); i++ {
+			dps = append(dps, any(data.Number{NumberDataPoint: m.Sum().DataPoints().At(i)}).(D))
+		}
+	case data.Histogram:
+		if m.Type() != pmetric.MetricTypeHistogram {
+			return nil
+		}
+		for i := 0; i < m.Histogram().DataPoints().Len(); i++ {
+			dps = append(dps, any(data.Histogram{HistogramDataPoint: m.Histogram().DataPoints().At(i)}).(D))
+		}
+	case data.ExpHistogram:
+		if m.Type() != pmetric.MetricTypeExponentialHistogram {
+			return nil
+		}
+		for i := 0; i < m.ExponentialHistogram().DataPoints().Len(); i++ {
+			dps = append(dps, any(data.ExpHistogram{DataPoint: m.ExponentialHistogram().DataPoints().At(i)}).(D))
+		}
+	}
+	return dps
+}
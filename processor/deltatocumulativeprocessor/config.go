@@ -15,6 +15,22 @@ var _ component.ConfigValidator = (*Config)(nil)
 type Config struct {
 	MaxStale   time.Duration `mapstructure:"max_stale"`
 	MaxStreams int           `mapstructure:"max_streams"`
+
+	// MaxBuckets limits the positive and negative bucket counts of exponential
+	// histograms each. Larger histograms are downscaled until they fit.
+	MaxBuckets int `mapstructure:"max_buckets"`
+
+	Checkpoint CheckpointConfig `mapstructure:"checkpoint"`
+}
+
+// CheckpointConfig persists the stream state to a storage extension, so that it
+// is restored on start instead of every stream starting over.
+type CheckpointConfig struct {
+	// Storage is the storage extension to persist the stream state to. If unset,
+	// the stream state is kept in memory only.
+	Storage *component.ID `mapstructure:"storage"`
+	// Interval between checkpoints. The stream state is also persisted on shutdown.
+	Interval time.Duration `mapstructure:"interval"`
 }
 
 func (c *Config) Validate() error {
@@ -24,6 +40,12 @@ func (c *Config) Validate() error {
 	if c.MaxStreams < 0 {
 		return fmt.Errorf("max_streams must be a positive number (got %d)", c.MaxStreams)
 	}
+	if c.MaxBuckets < 0 {
+		return fmt.Errorf("max_buckets must be a positive number (got %d)", c.MaxBuckets)
+	}
+	if c.Checkpoint.Storage != nil && c.Checkpoint.Interval <= 0 {
+		return fmt.Errorf("checkpoint::interval must be a positive duration (got %s)", c.Checkpoint.Interval)
+	}
 	return nil
 }
 
@@ -34,5 +56,13 @@ func createDefaultConfig() component.Config {
 		// disable. TODO: find good default
 		// https://github.com/open-telemetry/opentelemetry-collector-contrib/issues/31603
 		MaxStreams: 0,
+
+		// same as the default max_size of the exponential histogram aggregation
+		// of the OpenTelemetry SDKs
+		MaxBuckets: 160,
+
+		Checkpoint: CheckpointConfig{
+			Interval: time.Minute,
+		},
 	}
 }
@@ -21,6 +21,7 @@ func TestLoadConfig(t *testing.T) {
 
 	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
 	require.NoError(t, err)
+	storageID := component.MustNewID("file_storage")
 
 	tests := []struct {
 		id       component.ID
@@ -31,6 +32,8 @@ func TestLoadConfig(t *testing.T) {
 			expected: &Config{
 				MaxStale:   1 * time.Minute,
 				MaxStreams: 10,
+				MaxBuckets: 160,
+				Checkpoint: CheckpointConfig{Interval: time.Minute},
 			},
 		},
 		{
@@ -38,6 +41,8 @@ func TestLoadConfig(t *testing.T) {
 			expected: &Config{
 				MaxStale:   2 * time.Minute,
 				MaxStreams: 0,
+				MaxBuckets: 160,
+				Checkpoint: CheckpointConfig{Interval: time.Minute},
 			},
 		},
 		{
@@ -45,6 +50,29 @@ func TestLoadConfig(t *testing.T) {
 			expected: &Config{
 				MaxStale:   5 * time.Minute,
 				MaxStreams: 20,
+				MaxBuckets: 160,
+				Checkpoint: CheckpointConfig{Interval: time.Minute},
+			},
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "set-valid-max_buckets"),
+			expected: &Config{
+				MaxStale:   5 * time.Minute,
+				MaxStreams: 0,
+				MaxBuckets: 20,
+				Checkpoint: CheckpointConfig{Interval: time.Minute},
+			},
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "checkpoint"),
+			expected: &Config{
+				MaxStale:   5 * time.Minute,
+				MaxStreams: 0,
+				MaxBuckets: 160,
+				Checkpoint: CheckpointConfig{
+					Storage:  &storageID,
+					Interval: 30 * time.Second,
+				},
 			},
 		},
 	}
@@ -63,3 +91,13 @@ func TestLoadConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateCheckpointInterval(t *testing.T) {
+	storageID := component.MustNewID("file_storage")
+	cfg := createDefaultConfig().(*Config)
+	cfg.Checkpoint.Storage = &storageID
+	require.NoError(t, cfg.Validate())
+
+	cfg.Checkpoint.Interval = 0
+	require.ErrorContains(t, cfg.Validate(), "checkpoint::interval must be a positive duration")
+}
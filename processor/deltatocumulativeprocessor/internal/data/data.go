@@ -19,6 +19,7 @@ type Point[Self any] interface {
 	CopyTo(Self)
 
 	Add(Self) Self
+	Resets(Self) string
 }
 
 type Typed[Self any] interface {
@@ -0,0 +1,59 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package expo // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/deltatocumulativeprocessor/internal/data/expo"
+
+// MinScale is the smallest scale allowed by the specification. At this scale,
+// just a few buckets already cover the full float64 range.
+const MinScale Scale = -10
+
+// Limit downscales dp until both its positive and negative bucket counts fit
+// into size buckets each. A size of 0 or less disables the limit.
+//
+// The scale does not go below [MinScale]. Zero buckets at either end of the
+// bucket counts are trimmed once downscaling is required, as they would
+// otherwise still count towards the size.
+func Limit(dp DataPoint, size int) {
+	pos, neg := dp.Positive(), dp.Negative()
+	fits := func() bool {
+		return pos.BucketCounts().Len() <= size && neg.BucketCounts().Len() <= size
+	}
+	if size <= 0 || fits() {
+		return
+	}
+
+	Trim(pos)
+	Trim(neg)
+	for Scale(dp.Scale()) > MinScale && !fits() {
+		Collapse(pos)
+		Collapse(neg)
+		Trim(pos)
+		Trim(neg)
+		dp.SetScale(dp.Scale() - 1)
+	}
+}
+
+// Trim drops leading and trailing zero buckets:
+//
+//	before:	0 0 1 2 0 3 0 0
+//	after:	    1 2 0 3
+//
+// If all buckets are zero, the bucket counts are emptied and the offset reset.
+func Trim(bs Buckets) {
+	abs := Abs(bs)
+	lo, up := abs.Lower(), abs.Upper()
+	for lo < up && abs.Abs(lo) == 0 {
+		lo++
+	}
+	for up > lo && abs.Abs(up-1) == 0 {
+		up--
+	}
+
+	switch {
+	case lo == up:
+		bs.BucketCounts().FromRaw(nil)
+		bs.SetOffset(0)
+	case lo != abs.Lower() || up != abs.Upper():
+		abs.Slice(lo, up)
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package expo_test
+
+import (
+	"testing"
+
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/deltatocumulativeprocessor/internal/data/datatest"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/deltatocumulativeprocessor/internal/data/expo"
+)
+
+func TestLimit(t *testing.T) {
+	type Bkt struct {
+		Offset int32
+		Counts []uint64
+	}
+	type Case struct {
+		name  string
+		size  int
+		scale int32
+		pos   Bkt
+		neg   Bkt
+
+		wantScale int32
+		wantPos   Bkt
+		wantNeg   Bkt
+	}
+
+	cases := []Case{{
+		name:  "fits",
+		size:  4,
+		scale: 2,
+		pos:   Bkt{Offset: 0, Counts: []uint64{0, 1, 1, 0}},
+		neg:   Bkt{Offset: 3, Counts: []uint64{1}},
+
+		wantScale: 2,
+		wantPos:   Bkt{Offset: 0, Counts: []uint64{0, 1, 1, 0}},
+		wantNeg:   Bkt{Offset: 3, Counts: []uint64{1}},
+	}, {
+		name:  "once",
+		size:  4,
+		scale: 2,
+		pos:   Bkt{Offset: 0, Counts: []uint64{1, 1, 1, 1, 1, 1, 1, 1}},
+		neg:   Bkt{Offset: 2, Counts: []uint64{1, 1}},
+
+		wantScale: 1,
+		wantPos:   Bkt{Offset: 0, Counts: []uint64{2, 2, 2, 2}},
+		wantNeg:   Bkt{Offset: 1, Counts: []uint64{2}},
+	}, {
+		name:  "odd-offset",
+		size:  3,
+		scale: 0,
+		pos:   Bkt{Offset: 1, Counts: []uint64{1, 1, 1, 1, 1}},
+
+		wantScale: -1,
+		wantPos:   Bkt{Offset: 0, Counts: []uint64{1, 2, 2}},
+	}, {
+		name:  "repeated",
+		size:  1,
+		scale: 0,
+		pos:   Bkt{Offset: 1, Counts: []uint64{1, 1, 1, 1, 1}},
+
+		wantScale: -3,
+		wantPos:   Bkt{Offset: 0, Counts: []uint64{5}},
+	}, {
+		name:  "trim-only",
+		size:  2,
+		scale: 0,
+		pos:   Bkt{Offset: -2, Counts: []uint64{0, 0, 1, 1, 0}},
+		neg:   Bkt{Offset: 4, Counts: []uint64{0, 0, 0}},
+
+		wantScale: 0,
+		wantPos:   Bkt{Offset: 0, Counts: []uint64{1, 1}},
+		wantNeg:   Bkt{Offset: 0},
+	}, {
+		name:  "min-scale",
+		size:  1,
+		scale: -9,
+		pos:   Bkt{Offset: 0, Counts: []uint64{1, 1, 1, 1}},
+
+		wantScale: -10,
+		wantPos:   Bkt{Offset: 0, Counts: []uint64{2, 2}},
+	}}
+
+	into := func(b Bkt, bs expo.Buckets) {
+		bs.SetOffset(b.Offset)
+		bs.BucketCounts().FromRaw(b.Counts)
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			is := datatest.New(t)
+
+			dp := pmetric.NewExponentialHistogramDataPoint()
+			dp.SetScale(cs.scale)
+			into(cs.pos, dp.Positive())
+			into(cs.neg, dp.Negative())
+
+			// start from a copy, so that emptied bucket counts compare equal
+			want := pmetric.NewExponentialHistogramDataPoint()
+			dp.CopyTo(want)
+			want.SetScale(cs.wantScale)
+			into(cs.wantPos, want.Positive())
+			into(cs.wantNeg, want.Negative())
+
+			expo.Limit(dp, cs.size)
+			is.Equal(want, dp)
+		})
+	}
+}
+
+func TestTrim(t *testing.T) {
+	bs := bins{ø, 0, 1, 2, 0, 3, 0, ø}.Into()
+	expo.Trim(bs)
+
+	is := datatest.New(t)
+	is.Equal(bins{ø, ø, 1, 2, 0, 3, ø, ø}.Into(), bs)
+}
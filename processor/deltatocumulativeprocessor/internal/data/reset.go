@@ -0,0 +1,38 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package data // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/deltatocumulativeprocessor/internal/data"
+
+import (
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/deltatocumulativeprocessor/internal/putil/pslice"
+)
+
+// Reasons for a stream to be reset, meaning its accumulated state is replaced
+// by the current sample instead of adding both.
+const (
+	ResetValueType   = "value-type"  // sum switched between int and double values
+	ResetBounds      = "bounds"      // histogram bucket boundaries changed
+	ResetTemporality = "temporality" // stream switched from delta to cumulative
+)
+
+// Resets returns why in cannot be added to dp, or an empty string if it can.
+func (dp Number) Resets(in Number) string {
+	if dp.ValueType() != in.ValueType() {
+		return ResetValueType
+	}
+	return ""
+}
+
+// Resets returns why in cannot be added to dp, or an empty string if it can.
+func (dp Histogram) Resets(in Histogram) string {
+	if !pslice.Equal(dp.ExplicitBounds(), in.ExplicitBounds()) {
+		return ResetBounds
+	}
+	return ""
+}
+
+// Resets always returns an empty string, because exponential histograms of
+// differing scales are downscaled to a common one.
+func (dp ExpHistogram) Resets(ExpHistogram) string {
+	return ""
+}
@@ -44,6 +44,14 @@ func (a Accumulator[D]) Store(id streams.Ident, dp D) error {
 		return ErrOutOfOrder{Last: aggr.Timestamp(), Sample: dp.Timestamp()}
 	}
 
+	// incompatible samples: start over from the sample
+	if reason := aggr.Resets(dp); reason != "" {
+		if err := a.Map.Store(id, dp.Clone()); err != nil {
+			return err
+		}
+		return ErrReset{Reason: reason}
+	}
+
 	// detect gaps
 	var gap error
 	if dp.StartTimestamp() > aggr.Timestamp() {
@@ -82,3 +90,13 @@ type ErrGap struct {
 func (e ErrGap) Error() string {
 	return fmt.Sprintf("gap in stream from %s to %s. samples were likely lost in transit", e.From, e.To)
 }
+
+// ErrReset signals the stream was reset to the sample, because the sample
+// could not be added to the accumulated state. See [data.ResetBounds] and others.
+type ErrReset struct {
+	Reason string
+}
+
+func (e ErrReset) Error() string {
+	return fmt.Sprintf("stream reset: sample can not be added to accumulated state (reason: %s)", e.Reason)
+}
@@ -225,6 +225,39 @@ func TestErrs(t *testing.T) {
 
 }
 
+// TestReset verifies the accumulated state is replaced by samples that can not
+// be added to it.
+func TestReset(t *testing.T) {
+	acc := aggr[data.Histogram]()
+	id, dp := random.Histogram().Stream()
+
+	first := dp.Clone()
+	first.SetStartTimestamp(time(1000))
+	first.SetTimestamp(time(1100))
+	first.ExplicitBounds().FromRaw([]float64{1, 2})
+	first.BucketCounts().FromRaw([]uint64{1, 2, 3})
+	first.SetCount(6)
+
+	_, err := acc.Aggregate(id, first)
+	require.NoError(t, err)
+
+	second := dp.Clone()
+	second.SetStartTimestamp(time(1100))
+	second.SetTimestamp(time(1200))
+	second.ExplicitBounds().FromRaw([]float64{5, 10})
+	second.BucketCounts().FromRaw([]uint64{4, 0, 1})
+	second.SetCount(5)
+
+	res, err := acc.Aggregate(id, second)
+	require.ErrorIs(t, err, delta.ErrReset{Reason: data.ResetBounds})
+
+	require.Equal(t, second.StartTimestamp(), res.StartTimestamp())
+	require.Equal(t, second.Timestamp(), res.Timestamp())
+	require.Equal(t, second.ExplicitBounds().AsRaw(), res.ExplicitBounds().AsRaw())
+	require.Equal(t, second.BucketCounts().AsRaw(), res.BucketCounts().AsRaw())
+	require.Equal(t, second.Count(), res.Count())
+}
+
 func time(ts int) pcommon.Timestamp {
 	return pcommon.Timestamp(ts)
 }
@@ -35,6 +35,7 @@ type TelemetryBuilder struct {
 	DeltatocumulativeStreamsEvicted      metric.Int64Counter
 	DeltatocumulativeStreamsLimit        metric.Int64Gauge
 	DeltatocumulativeStreamsMaxStale     metric.Int64Gauge
+	DeltatocumulativeStreamsReset        metric.Int64Counter
 	DeltatocumulativeStreamsTracked      metric.Int64UpDownCounter
 	meters                               map[configtelemetry.Level]metric.Meter
 }
@@ -87,6 +88,12 @@ func NewTelemetryBuilder(settings component.TelemetrySettings, options ...teleme
 		metric.WithUnit("s"),
 	)
 	errs = errors.Join(errs, err)
+	builder.DeltatocumulativeStreamsReset, err = builder.meters[configtelemetry.LevelBasic].Int64Counter(
+		"otelcol_deltatocumulative.streams.reset",
+		metric.WithDescription("number of streams reset due to given 'reason'"),
+		metric.WithUnit("{stream}"),
+	)
+	errs = errors.Join(errs, err)
 	builder.DeltatocumulativeStreamsTracked, err = builder.meters[configtelemetry.LevelBasic].Int64UpDownCounter(
 		"otelcol_deltatocumulative.streams.tracked",
 		metric.WithDescription("number of streams tracked"),
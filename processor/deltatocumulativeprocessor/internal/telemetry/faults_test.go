@@ -84,6 +84,21 @@ func TestFaults(t *testing.T) {
 			Err:  delta.ErrGap{From: ts(20), To: ts(30)},
 			Want: nil,
 		},
+		{
+			Name: "reset",
+			Pre: func(dps Map, id identity.Stream, dp data.Number) error {
+				dp.SetTimestamp(ts(10))
+				dp.SetIntValue(1)
+				return dps.Store(id, dp)
+			},
+			Bad: func(dps Map, id identity.Stream, dp data.Number) error {
+				dp.SetTimestamp(ts(20))
+				dp.SetDoubleValue(1.5)
+				return dps.Store(id, dp)
+			},
+			Err:  delta.ErrReset{Reason: data.ResetValueType},
+			Want: nil,
+		},
 		{
 			Name: "limit",
 			Map:  streams.Limit(delta.New[data.Number](), 1),
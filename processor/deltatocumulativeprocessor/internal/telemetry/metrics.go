@@ -26,6 +26,7 @@ func New(telb *metadata.TelemetryBuilder) Telemetry {
 			tracked: telb.DeltatocumulativeStreamsTracked,
 			limit:   telb.DeltatocumulativeStreamsLimit,
 			evicted: telb.DeltatocumulativeStreamsEvicted,
+			reset:   telb.DeltatocumulativeStreamsReset,
 			stale:   telb.DeltatocumulativeStreamsMaxStale,
 		},
 		dps: Datapoints{
@@ -40,6 +41,7 @@ type Streams struct {
 	tracked metric.Int64UpDownCounter
 	limit   metric.Int64Gauge
 	evicted metric.Int64Counter
+	reset   metric.Int64Counter
 	stale   metric.Int64Gauge
 }
 
@@ -63,6 +65,11 @@ func (tel Telemetry) WithStale(max time.Duration) {
 	tel.streams.stale.Record(context.Background(), int64(max.Seconds()))
 }
 
+// Reset records a stream being reset for the given reason
+func (tel Telemetry) Reset(why string) {
+	inc(tel.streams.reset, reason(why))
+}
+
 func ObserveItems[T any](items streams.Map[T], metrics *Metrics) Items[T] {
 	return Items[T]{
 		Map:     items,
@@ -111,6 +118,7 @@ func (f Faults[T]) Store(id streams.Ident, v T) error {
 		gap        delta.ErrGap
 		limit      streams.ErrLimit
 		evict      streams.ErrEvicted
+		reset      delta.ErrReset
 	)
 
 	err := f.Map.Store(id, v)
@@ -129,6 +137,8 @@ func (f Faults[T]) Store(id streams.Ident, v T) error {
 		return streams.Drop
 	case errors.As(err, &evict):
 		inc(f.streams.evicted)
+	case errors.As(err, &reset):
+		inc(f.streams.reset, reason(reset.Reason))
 	case errors.As(err, &gap):
 		from := gap.From.AsTime()
 		to := gap.To.AsTime()
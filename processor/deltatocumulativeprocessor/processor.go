@@ -15,8 +15,11 @@ import (
 	"go.opentelemetry.io/collector/processor"
 	"go.uber.org/zap"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storageclient"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/exp/metrics/identity"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/exp/metrics/staleness"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/deltatocumulativeprocessor/internal/data"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/deltatocumulativeprocessor/internal/data/expo"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/deltatocumulativeprocessor/internal/delta"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/deltatocumulativeprocessor/internal/maybe"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/deltatocumulativeprocessor/internal/metadata"
@@ -38,10 +41,18 @@ type Processor struct {
 	expo Pipeline[data.ExpHistogram]
 	hist Pipeline[data.Histogram]
 
+	maxBuckets int
+	tel        telemetry.Telemetry
+
+	// checkpoint is set if the stream state is persisted to a storage extension
+	checkpoint *checkpointer
+	// saving tracks the periodic checkpoints, which must be done before the last one on shutdown
+	saving sync.WaitGroup
+
 	mtx sync.Mutex
 }
 
-func newProcessor(cfg *Config, log *zap.Logger, telb *metadata.TelemetryBuilder, next consumer.Metrics) *Processor {
+func newProcessor(cfg *Config, id component.ID, log *zap.Logger, telb *metadata.TelemetryBuilder, next consumer.Metrics) *Processor {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	tel := telemetry.New(telb)
@@ -54,13 +65,24 @@ func newProcessor(cfg *Config, log *zap.Logger, telb *metadata.TelemetryBuilder,
 		sums: pipeline[data.Number](cfg, &tel),
 		expo: pipeline[data.ExpHistogram](cfg, &tel),
 		hist: pipeline[data.Histogram](cfg, &tel),
+
+		maxBuckets: cfg.MaxBuckets,
+		tel:        tel,
+	}
+	if cfg.Checkpoint.Storage != nil {
+		proc.checkpoint = &checkpointer{
+			storageID: *cfg.Checkpoint.Storage,
+			id:        id,
+			interval:  cfg.Checkpoint.Interval,
+			maxStale:  cfg.MaxStale,
+		}
 	}
 
 	return &proc
 }
 
 type Pipeline[D data.Point[D]] struct {
-	aggr  streams.Aggregator[D]
+	aggr  streams.MapAggr[D]
 	stale maybe.Ptr[staleness.Staleness[D]]
 }
 
@@ -92,7 +114,24 @@ func pipeline[D data.Point[D]](cfg *Config, tel *telemetry.Telemetry) Pipeline[D
 	return pipe
 }
 
-func (p *Processor) Start(_ context.Context, _ component.Host) error {
+func (p *Processor) Start(ctx context.Context, host component.Host) error {
+	if p.checkpoint != nil {
+		client, err := storageclient.Get(ctx, host, p.checkpoint.storageID, component.KindProcessor, p.checkpoint.id, "")
+		if err != nil {
+			return err
+		}
+		p.checkpoint.client = client
+		if err := p.restore(ctx); err != nil {
+			// streams that can't be restored start over, as they would without a checkpoint
+			p.log.Warn("failed to restore the stream state", zap.Error(err))
+		}
+		p.saving.Add(1)
+		go func() {
+			defer p.saving.Done()
+			p.saveEvery(p.checkpoint.interval)
+		}()
+	}
+
 	sums, sok := p.sums.stale.Try()
 	expo, eok := p.expo.stale.Try()
 	hist, hok := p.hist.stale.Try()
@@ -118,9 +157,13 @@ func (p *Processor) Start(_ context.Context, _ component.Host) error {
 	return nil
 }
 
-func (p *Processor) Shutdown(_ context.Context) error {
+func (p *Processor) Shutdown(ctx context.Context) error {
 	p.cancel()
-	return nil
+	if p.checkpoint == nil || p.checkpoint.client == nil {
+		return nil
+	}
+	p.saving.Wait()
+	return errors.Join(p.save(ctx), p.checkpoint.client.Close(ctx))
 }
 
 func (p *Processor) Capabilities() consumer.Capabilities {
@@ -145,6 +188,8 @@ func (p *Processor) ConsumeMetrics(ctx context.Context, md pmetric.Metrics) erro
 				err := streams.Apply(metrics.Sum(m), p.sums.aggr.Aggregate)
 				errs = errors.Join(errs, err)
 				sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+			} else {
+				resetDelta(p, metrics.Sum(m), p.sums)
 			}
 			n = sum.DataPoints().Len()
 		case pmetric.MetricTypeHistogram:
@@ -153,16 +198,25 @@ func (p *Processor) ConsumeMetrics(ctx context.Context, md pmetric.Metrics) erro
 				err := streams.Apply(metrics.Histogram(m), p.hist.aggr.Aggregate)
 				errs = errors.Join(errs, err)
 				hist.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+			} else {
+				resetDelta(p, metrics.Histogram(m), p.hist)
 			}
 			n = hist.DataPoints().Len()
 		case pmetric.MetricTypeExponentialHistogram:
 			expo := m.ExponentialHistogram()
 			if expo.AggregationTemporality() == pmetric.AggregationTemporalityDelta {
-				err := streams.Apply(metrics.ExpHistogram(m), p.expo.aggr.Aggregate)
+				err := streams.Apply(metrics.ExpHistogram(m), p.aggregateExpo)
 				errs = errors.Join(errs, err)
 				expo.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+			} else {
+				resetDelta(p, metrics.ExpHistogram(m), p.expo)
 			}
 			n = expo.DataPoints().Len()
+		case pmetric.MetricTypeGauge:
+			// gauges and summaries have no temporality and are passed as-is
+			n = m.Gauge().DataPoints().Len()
+		case pmetric.MetricTypeSummary:
+			n = m.Summary().DataPoints().Len()
 		}
 		return n > 0
 	})
@@ -175,3 +229,29 @@ func (p *Processor) ConsumeMetrics(ctx context.Context, md pmetric.Metrics) erro
 	}
 	return p.next.ConsumeMetrics(ctx, md)
 }
+
+// aggregateExpo accumulates exponential histograms, downscaling the result to
+// at most max_buckets buckets.
+func (p *Processor) aggregateExpo(id streams.Ident, dp data.ExpHistogram) (data.ExpHistogram, error) {
+	res, err := p.expo.aggr.Aggregate(id, dp)
+	if err != nil {
+		return res, err
+	}
+	// res is the stored state, so this also limits future accumulation
+	expo.Limit(res.DataPoint, p.maxBuckets)
+	return res, nil
+}
+
+// resetDelta removes the delta streams of a metric that switched to cumulative
+// temporality, so they start over instead of continuing the previous
+// accumulation if the metric switches back.
+func resetDelta[D data.Point[D], List metrics.Data[D]](p *Processor, dps List, pipe Pipeline[D]) {
+	mid := dps.Ident().WithTemporality(pmetric.AggregationTemporalityDelta)
+	for i := 0; i < dps.Len(); i++ {
+		id := identity.OfStream(mid, dps.At(i))
+		if _, ok := pipe.aggr.Load(id); ok {
+			pipe.aggr.Delete(id)
+			p.tel.Reset(data.ResetTemporality)
+		}
+	}
+}
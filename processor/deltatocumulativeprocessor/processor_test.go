@@ -19,6 +19,7 @@ import (
 	"go.opentelemetry.io/collector/processor"
 	"go.opentelemetry.io/collector/processor/processortest"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storagetest"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/exp/metrics/identity"
 	self "github.com/open-telemetry/opentelemetry-collector-contrib/processor/deltatocumulativeprocessor"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/deltatocumulativeprocessor/internal/data"
@@ -30,6 +31,13 @@ import (
 
 func setup(t *testing.T, cfg *self.Config) (processor.Metrics, *consumertest.MetricsSink) {
 	t.Helper()
+	return setupWithSettings(t, processortest.NewNopSettings(), cfg)
+}
+
+// setupWithSettings creates the processor with the given settings, so that a restarted
+// processor keeps the same ID.
+func setupWithSettings(t *testing.T, set processor.Settings, cfg *self.Config) (processor.Metrics, *consumertest.MetricsSink) {
+	t.Helper()
 
 	next := &consumertest.MetricsSink{}
 	if cfg == nil {
@@ -38,7 +46,7 @@ func setup(t *testing.T, cfg *self.Config) (processor.Metrics, *consumertest.Met
 
 	proc, err := self.NewFactory().CreateMetricsProcessor(
 		context.Background(),
-		processortest.NewNopSettings(),
+		set,
 		cfg,
 		next,
 	)
@@ -233,6 +241,150 @@ func (s SumBuilder) resourceMetrics(metrics ...copyable) pmetric.Metrics {
 	return md
 }
 
+// TestTemporalityReset verifies a stream switching to cumulative and back to
+// delta starts over instead of continuing the previous accumulation.
+func TestTemporalityReset(t *testing.T) {
+	proc, sink := setup(t, nil)
+	sb := stream()
+
+	consume := func(in, want pmetric.Metric) {
+		t.Helper()
+		sink.Reset()
+		err := proc.ConsumeMetrics(context.Background(), sb.resourceMetrics(in))
+		require.NoError(t, err)
+		if diff := compare.Diff([]pmetric.Metrics{sb.resourceMetrics(want)}, sink.AllMetrics()); diff != "" {
+			t.Fatal(diff)
+		}
+	}
+
+	consume(sb.delta(sb.point(1000, 1100, 5)), sb.cumul(sb.point(1000, 1100, 5)))
+	consume(sb.cumul(sb.point(1000, 1200, 7)), sb.cumul(sb.point(1000, 1200, 7)))
+	consume(sb.delta(sb.point(1200, 1300, 3)), sb.cumul(sb.point(1200, 1300, 3)))
+}
+
+// TestPassthrough verifies metrics without temporality are passed unchanged
+func TestPassthrough(t *testing.T) {
+	proc, sink := setup(t, nil)
+
+	md := pmetric.NewMetrics()
+	ms := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics()
+	gauge := ms.AppendEmpty()
+	gauge.SetName("gauge")
+	gauge.SetEmptyGauge().DataPoints().AppendEmpty().SetIntValue(42)
+	summary := ms.AppendEmpty()
+	summary.SetName("summary")
+	summary.SetEmptySummary().DataPoints().AppendEmpty().SetCount(3)
+
+	want := pmetric.NewMetrics()
+	md.CopyTo(want)
+
+	require.NoError(t, proc.ConsumeMetrics(context.Background(), md))
+	if diff := compare.Diff([]pmetric.Metrics{want}, sink.AllMetrics()); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+// TestExpoMaxBuckets verifies exponential histograms are downscaled to fit into
+// max_buckets.
+func TestExpoMaxBuckets(t *testing.T) {
+	proc, sink := setup(t, &self.Config{MaxStale: 5 * time.Minute, MaxBuckets: 4})
+
+	md := pmetric.NewMetrics()
+	m := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	m.SetName("latency")
+	hist := m.SetEmptyExponentialHistogram()
+	hist.SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+	dp := hist.DataPoints().AppendEmpty()
+	dp.SetScale(2)
+	dp.SetCount(8)
+	dp.Positive().BucketCounts().FromRaw([]uint64{1, 1, 1, 1, 1, 1, 1, 1})
+
+	require.NoError(t, proc.ConsumeMetrics(context.Background(), md))
+
+	out := sink.AllMetrics()
+	require.Len(t, out, 1)
+	got := out[0].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).ExponentialHistogram().DataPoints().At(0)
+	require.Equal(t, int32(1), got.Scale())
+	require.Equal(t, []uint64{2, 2, 2, 2}, got.Positive().BucketCounts().AsRaw())
+	require.Equal(t, uint64(8), got.Count())
+}
+
+// TestCheckpoint verifies the stream state is restored from the storage extension
+// on start, so that accumulation continues across restarts.
+func TestCheckpoint(t *testing.T) {
+	ext := storagetest.NewFileBackedStorageExtension("test", t.TempDir())
+	host := storagetest.NewStorageHost().WithExtension(ext.ID, ext)
+	cfg := &self.Config{
+		MaxStale:   5 * time.Minute,
+		Checkpoint: self.CheckpointConfig{Storage: &ext.ID, Interval: time.Hour},
+	}
+	ctx := context.Background()
+	set := processortest.NewNopSettings()
+	sb := stream()
+
+	proc, sink := setupWithSettings(t, set, cfg)
+	require.NoError(t, proc.Start(ctx, host))
+	require.NoError(t, proc.ConsumeMetrics(ctx, sb.resourceMetrics(sb.delta(sb.point(1000, 1100, 5)))))
+	require.NoError(t, proc.Shutdown(ctx))
+	require.Len(t, sink.AllMetrics(), 1)
+
+	// the restarted processor continues the stream
+	proc, sink = setupWithSettings(t, set, cfg)
+	require.NoError(t, proc.Start(ctx, host))
+	defer func() {
+		require.NoError(t, proc.Shutdown(ctx))
+	}()
+	require.NoError(t, proc.ConsumeMetrics(ctx, sb.resourceMetrics(sb.delta(sb.point(1100, 1200, 3)))))
+
+	want := []pmetric.Metrics{sb.resourceMetrics(sb.cumul(sb.point(1000, 1200, 8)))}
+	if diff := compare.Diff(want, sink.AllMetrics()); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestCheckpointPeriodically(t *testing.T) {
+	ext := storagetest.NewFileBackedStorageExtension("test", t.TempDir())
+	host := storagetest.NewStorageHost().WithExtension(ext.ID, ext)
+	cfg := &self.Config{
+		MaxStale:   5 * time.Minute,
+		Checkpoint: self.CheckpointConfig{Storage: &ext.ID, Interval: time.Millisecond},
+	}
+	ctx := context.Background()
+	set := processortest.NewNopSettings()
+	sb := stream()
+
+	// the periodic checkpoints run concurrently with the processing, and are done before shutting down
+	proc, _ := setupWithSettings(t, set, cfg)
+	require.NoError(t, proc.Start(ctx, host))
+	for i := 0; i < 10; i++ {
+		start := pcommon.Timestamp(1000 + 100*i)
+		require.NoError(t, proc.ConsumeMetrics(ctx, sb.resourceMetrics(sb.delta(sb.point(start, start+100, 1)))))
+		time.Sleep(time.Millisecond)
+	}
+	require.NoError(t, proc.Shutdown(ctx))
+
+	proc, sink := setupWithSettings(t, set, cfg)
+	require.NoError(t, proc.Start(ctx, host))
+	defer func() {
+		require.NoError(t, proc.Shutdown(ctx))
+	}()
+	require.NoError(t, proc.ConsumeMetrics(ctx, sb.resourceMetrics(sb.delta(sb.point(2000, 2100, 1)))))
+
+	want := []pmetric.Metrics{sb.resourceMetrics(sb.cumul(sb.point(1000, 2100, 11)))}
+	if diff := compare.Diff(want, sink.AllMetrics()); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestCheckpointStorageNotFound(t *testing.T) {
+	storageID := storagetest.NewStorageID("test")
+	proc, _ := setup(t, &self.Config{
+		MaxStale:   5 * time.Minute,
+		Checkpoint: self.CheckpointConfig{Storage: &storageID, Interval: time.Hour},
+	})
+	require.Error(t, proc.Start(context.Background(), storagetest.NewStorageHost()))
+}
+
 type SumBuilder struct {
 	random.Metric[data.Number]
 	base data.Number
@@ -11,12 +11,17 @@ import (
 	"go.opentelemetry.io/collector/processor"
 	"go.opentelemetry.io/collector/processor/processorhelper"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/lookuptable"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/sharedcomponent"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/filterprocessor/internal/metadata"
 )
 
 var processorCapabilities = consumer.Capabilities{MutatesData: true}
 
+// lookupTables holds the storage lookup tables of the processors, shared by their traces, metrics and logs instances
+var lookupTables = sharedcomponent.NewSharedComponents()
+
 // NewFactory returns a new factory for the Filter processor.
 func NewFactory() processor.Factory {
 	return processor.NewFactory(
@@ -40,7 +45,8 @@ func createMetricsProcessor(
 	cfg component.Config,
 	nextConsumer consumer.Metrics,
 ) (processor.Metrics, error) {
-	fp, err := newFilterMetricProcessor(set, cfg.(*Config))
+	tables, tableOptions := storageLookupTables(cfg.(*Config), set)
+	fp, err := newFilterMetricProcessor(set, cfg.(*Config), tables)
 	if err != nil {
 		return nil, err
 	}
@@ -50,7 +56,7 @@ func createMetricsProcessor(
 		cfg,
 		nextConsumer,
 		fp.processMetrics,
-		processorhelper.WithCapabilities(processorCapabilities))
+		append(tableOptions, processorhelper.WithCapabilities(processorCapabilities))...)
 }
 
 func createLogsProcessor(
@@ -59,7 +65,8 @@ func createLogsProcessor(
 	cfg component.Config,
 	nextConsumer consumer.Logs,
 ) (processor.Logs, error) {
-	fp, err := newFilterLogsProcessor(set, cfg.(*Config))
+	tables, tableOptions := storageLookupTables(cfg.(*Config), set)
+	fp, err := newFilterLogsProcessor(set, cfg.(*Config), tables)
 	if err != nil {
 		return nil, err
 	}
@@ -69,7 +76,7 @@ func createLogsProcessor(
 		cfg,
 		nextConsumer,
 		fp.processLogs,
-		processorhelper.WithCapabilities(processorCapabilities))
+		append(tableOptions, processorhelper.WithCapabilities(processorCapabilities))...)
 }
 
 func createTracesProcessor(
@@ -78,7 +85,8 @@ func createTracesProcessor(
 	cfg component.Config,
 	nextConsumer consumer.Traces,
 ) (processor.Traces, error) {
-	fp, err := newFilterSpansProcessor(set, cfg.(*Config))
+	tables, tableOptions := storageLookupTables(cfg.(*Config), set)
+	fp, err := newFilterSpansProcessor(set, cfg.(*Config), tables)
 	if err != nil {
 		return nil, err
 	}
@@ -88,5 +96,29 @@ func createTracesProcessor(
 		cfg,
 		nextConsumer,
 		fp.processTraces,
-		processorhelper.WithCapabilities(processorCapabilities))
+		append(tableOptions, processorhelper.WithCapabilities(processorCapabilities))...)
+}
+
+// storageLookupTables returns the storage lookup tables of the processor, shared by its traces, metrics and logs
+// instances, and the options starting and stopping them.
+func storageLookupTables(cfg *Config, set processor.Settings) (map[string]ottl.LookupTable, []processorhelper.Option) {
+	if len(cfg.LookupTables) == 0 {
+		return nil, nil
+	}
+	tables := lookupTables.GetOrAdd(cfg, func() component.Component {
+		return lookuptable.NewTables(cfg.LookupTables, component.KindProcessor, set.ID)
+	})
+	return ottlLookupTables(tables.Unwrap().(*lookuptable.Tables)), []processorhelper.Option{
+		processorhelper.WithStart(tables.Start),
+		processorhelper.WithShutdown(tables.Shutdown),
+	}
+}
+
+// ottlLookupTables returns the storage lookup tables as OTTL lookup tables.
+func ottlLookupTables(tables *lookuptable.Tables) map[string]ottl.LookupTable {
+	ottlTables := make(map[string]ottl.LookupTable, len(tables.Tables()))
+	for name, table := range tables.Tables() {
+		ottlTables[name] = table
+	}
+	return ottlTables
 }
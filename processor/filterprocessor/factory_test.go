@@ -11,11 +11,14 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/confmap/confmaptest"
 	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/processor/processortest"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storagetest"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/filterprocessor/internal/metadata"
 )
@@ -116,3 +119,41 @@ func TestCreateProcessors(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateProcessorsWithLookupTables(t *testing.T) {
+	ext := storagetest.NewFileBackedStorageExtension("test", t.TempDir())
+	host := storagetest.NewStorageHost().WithExtension(ext.ID, ext)
+	set := processortest.NewNopSettings()
+
+	// Populate the table before the processor is started
+	client, err := ext.GetClient(context.Background(), component.KindProcessor, set.ID, "blocked")
+	require.NoError(t, err)
+	require.NoError(t, client.Set(context.Background(), "checkout", []byte("true")))
+	require.NoError(t, client.Close(context.Background()))
+
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.LookupTables = map[string]component.ID{"blocked": ext.ID}
+	cfg.Logs.LogConditions = []string{`Lookup("blocked", body) == true`}
+	require.NoError(t, component.ValidateConfig(cfg))
+
+	sink := new(consumertest.LogsSink)
+	lp, err := factory.CreateLogsProcessor(context.Background(), set, cfg, sink)
+	require.NoError(t, err)
+	require.NoError(t, lp.Start(context.Background(), host))
+
+	ld := plog.NewLogs()
+	logs := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+	logs.AppendEmpty().Body().SetStr("checkout")
+	logs.AppendEmpty().Body().SetStr("search")
+	require.NoError(t, lp.ConsumeLogs(context.Background(), ld))
+	require.Len(t, sink.AllLogs(), 1)
+	kept := sink.AllLogs()[0].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords()
+	require.Equal(t, 1, kept.Len())
+	assert.Equal(t, "search", kept.At(0).Body().Str())
+	require.NoError(t, lp.Shutdown(context.Background()))
+
+	// The tables are only provided to the conditions of the processor configuring them
+	cfg.LookupTables = nil
+	assert.ErrorContains(t, component.ValidateConfig(cfg), `lookup table "blocked" is not configured`)
+}
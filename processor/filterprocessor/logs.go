@@ -17,6 +17,7 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/filter/filterconfig"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/filter/filterlog"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/filter/filterottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottllog"
 )
 
@@ -26,7 +27,7 @@ type filterLogProcessor struct {
 	logger    *zap.Logger
 }
 
-func newFilterLogsProcessor(set processor.Settings, cfg *Config) (*filterLogProcessor, error) {
+func newFilterLogsProcessor(set processor.Settings, cfg *Config, lookupTables map[string]ottl.LookupTable) (*filterLogProcessor, error) {
 	flp := &filterLogProcessor{
 		logger: set.Logger,
 	}
@@ -38,7 +39,7 @@ func newFilterLogsProcessor(set processor.Settings, cfg *Config) (*filterLogProc
 	flp.telemetry = fpt
 
 	if cfg.Logs.LogConditions != nil {
-		skipExpr, errBoolExpr := filterottl.NewBoolExprForLog(cfg.Logs.LogConditions, filterottl.StandardLogFuncs(), cfg.ErrorMode, set.TelemetrySettings)
+		skipExpr, errBoolExpr := filterottl.NewBoolExprForLog(cfg.Logs.LogConditions, filterottl.StandardLogFuncs(), cfg.ErrorMode, set.TelemetrySettings, ottllog.WithLookupTables(lookupTables))
 		if errBoolExpr != nil {
 			return nil, errBoolExpr
 		}
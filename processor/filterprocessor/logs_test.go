@@ -749,7 +749,7 @@ func TestFilterLogProcessorWithOTTL(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			processor, err := newFilterLogsProcessor(processortest.NewNopSettings(), &Config{Logs: LogFilters{LogConditions: tt.conditions}})
+			processor, err := newFilterLogsProcessor(processortest.NewNopSettings(), &Config{Logs: LogFilters{LogConditions: tt.conditions}}, nil)
 			assert.NoError(t, err)
 
 			got, err := processor.processLogs(context.Background(), constructLogs())
@@ -769,7 +769,7 @@ func TestFilterLogProcessorTelemetry(t *testing.T) {
 	tel := setupTestTelemetry()
 	processor, err := newFilterLogsProcessor(tel.NewSettings(), &Config{
 		Logs: LogFilters{LogConditions: []string{`IsMatch(body, "operationA")`}},
-	})
+	}, nil)
 	assert.NoError(t, err)
 
 	_, err = processor.processLogs(context.Background(), constructLogs())
@@ -265,7 +265,7 @@ func TestFilterTraceProcessorWithOTTL(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			processor, err := newFilterSpansProcessor(processortest.NewNopSettings(), &Config{Traces: tt.conditions, ErrorMode: tt.errorMode})
+			processor, err := newFilterSpansProcessor(processortest.NewNopSettings(), &Config{Traces: tt.conditions, ErrorMode: tt.errorMode}, nil)
 			assert.NoError(t, err)
 
 			got, err := processor.processTraces(context.Background(), constructTraces())
@@ -290,7 +290,7 @@ func TestFilterTraceProcessorTelemetry(t *testing.T) {
 				`name == "operationA"`,
 			},
 		}, ErrorMode: ottl.IgnoreError,
-	})
+	}, nil)
 	assert.NoError(t, err)
 
 	_, err = processor.processTraces(context.Background(), constructTraces())
@@ -4,7 +4,15 @@
 package groupbytraceprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/groupbytraceprocessor"
 
 import (
+	"errors"
 	"time"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+var (
+	errDiskStorageWithoutExtension = errors.New("option 'store_on_disk' requires a 'storage' extension")
+	errNegativeMaxOrphanSpans      = errors.New("option 'max_orphan_spans' must not be negative")
 )
 
 // Config is the configuration for the processor.
@@ -25,12 +33,31 @@ type Config struct {
 	// DiscardOrphans instructs the processor to discard traces without the root span.
 	// This typically indicates that the trace is incomplete.
 	// Default: false.
-	// Not yet implemented, and an error will be returned when this option is used.
 	DiscardOrphans bool `mapstructure:"discard_orphans"`
 
+	// MaxOrphanSpans is the number of spans a trace can hold without a root span before it is discarded,
+	// without waiting for the duration. It only applies when DiscardOrphans is set.
+	// Default: 0, orphan traces are discarded once the duration expires.
+	MaxOrphanSpans int `mapstructure:"max_orphan_spans"`
+
 	// StoreOnDisk tells the processor to keep only the trace ID in memory, serializing the trace spans to disk.
 	// Useful when the duration to wait for traces to complete is high.
 	// Default: false.
-	// Not yet implemented, and an error will be returned when this option is used.
 	StoreOnDisk bool `mapstructure:"store_on_disk"`
+
+	// StorageID is the ID of the storage extension used to serialize the trace spans when StoreOnDisk is set.
+	StorageID *component.ID `mapstructure:"storage"`
+}
+
+var _ component.Config = (*Config)(nil)
+
+// Validate checks if the processor configuration is valid
+func (cfg *Config) Validate() error {
+	if cfg.StoreOnDisk && cfg.StorageID == nil {
+		return errDiskStorageWithoutExtension
+	}
+	if cfg.MaxOrphanSpans < 0 {
+		return errNegativeMaxOrphanSpans
+	}
+	return nil
 }
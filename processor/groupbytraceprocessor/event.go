@@ -98,6 +98,7 @@ func newEventMachine(logger *zap.Logger, bufferSize int, numWorkers int, numTrac
 		em.workers[i] = &eventMachineWorker{
 			machine: em,
 			buffer:  newRingBuffer(numTraces / numWorkers),
+			orphans: make(map[pcommon.TraceID]*orphanTrace),
 			events:  make(chan event, bufferSize/numWorkers),
 		}
 	}
@@ -307,6 +308,9 @@ type eventMachineWorker struct {
 	// the ring buffer holds the IDs for all the in-flight traces
 	buffer *ringBuffer
 
+	// orphans tracks the in-flight traces while their root span wasn't received
+	orphans map[pcommon.TraceID]*orphanTrace
+
 	events chan event
 }
 
@@ -5,7 +5,6 @@ package groupbytraceprocessor // import "github.com/open-telemetry/opentelemetry
 
 import (
 	"context"
-	"fmt"
 	"time"
 
 	"go.opentelemetry.io/collector/component"
@@ -23,11 +22,6 @@ const (
 	defaultStoreOnDisk    = false
 )
 
-var (
-	errDiskStorageNotSupported    = fmt.Errorf("option 'disk storage' not supported in this release")
-	errDiscardOrphansNotSupported = fmt.Errorf("option 'discard orphans' not supported in this release")
-)
-
 // NewFactory returns a new factory for the Filter processor.
 func NewFactory() processor.Factory {
 
@@ -44,7 +38,6 @@ func createDefaultConfig() component.Config {
 		NumWorkers:   defaultNumWorkers,
 		WaitDuration: defaultWaitDuration,
 
-		// not supported for now
 		DiscardOrphans: defaultDiscardOrphans,
 		StoreOnDisk:    defaultStoreOnDisk,
 	}
@@ -59,17 +52,10 @@ func createTracesProcessor(
 
 	oCfg := cfg.(*Config)
 
-	var st storage
-	if oCfg.StoreOnDisk {
-		return nil, errDiskStorageNotSupported
-	}
-	if oCfg.DiscardOrphans {
-		return nil, errDiscardOrphansNotSupported
-	}
-
 	processor := newGroupByTraceProcessor(params, nextConsumer, *oCfg)
-	// the only supported storage for now
-	st = newMemoryStorage(processor.telemetryBuilder)
-	processor.st = st
+	// the disk storage needs a client from the storage extension, it is created when the processor starts
+	if !oCfg.StoreOnDisk {
+		processor.st = newMemoryStorage(processor.telemetryBuilder)
+	}
 	return processor, nil
 }
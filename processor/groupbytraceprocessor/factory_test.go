@@ -10,6 +10,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/processor/processortest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storagetest"
 )
 
 func TestDefaultConfiguration(t *testing.T) {
@@ -35,32 +37,47 @@ func TestCreateTestProcessor(t *testing.T) {
 	assert.NotNil(t, p)
 }
 
-func TestCreateTestProcessorWithNotImplementedOptions(t *testing.T) {
+func TestCreateTestProcessorWithOptions(t *testing.T) {
 	// prepare
 	f := NewFactory()
+	storageID := storagetest.NewStorageID("test")
 
 	// test
-	for _, tt := range []struct {
-		config      *Config
-		expectedErr error
-	}{
+	for _, config := range []*Config{
 		{
-			&Config{
-				DiscardOrphans: true,
-			},
-			errDiscardOrphansNotSupported,
+			DiscardOrphans: true,
 		},
 		{
-			&Config{
-				StoreOnDisk: true,
-			},
-			errDiskStorageNotSupported,
+			StoreOnDisk: true,
+			StorageID:   &storageID,
 		},
 	} {
-		p, err := f.CreateTracesProcessor(context.Background(), processortest.NewNopSettings(), tt.config, consumertest.NewNop())
+		p, err := f.CreateTracesProcessor(context.Background(), processortest.NewNopSettings(), config, consumertest.NewNop())
 
 		// verify
-		assert.ErrorIs(t, tt.expectedErr, err)
-		assert.Nil(t, p)
+		assert.NoError(t, err)
+		assert.NotNil(t, p)
 	}
 }
+
+func TestDiskStorageRequiresExtension(t *testing.T) {
+	// prepare
+	c := &Config{StoreOnDisk: true}
+
+	// test
+	err := c.Validate()
+
+	// verify
+	assert.ErrorIs(t, err, errDiskStorageWithoutExtension)
+}
+
+func TestNegativeMaxOrphanSpans(t *testing.T) {
+	// prepare
+	c := &Config{DiscardOrphans: true, MaxOrphanSpans: -1}
+
+	// test
+	err := c.Validate()
+
+	// verify
+	assert.ErrorIs(t, err, errNegativeMaxOrphanSpans)
+}
@@ -28,16 +28,17 @@ func Tracer(settings component.TelemetrySettings) trace.Tracer {
 // TelemetryBuilder provides an interface for components to report telemetry
 // as defined in metadata and user config.
 type TelemetryBuilder struct {
-	meter                                   metric.Meter
-	ProcessorGroupbytraceConfNumTraces      metric.Int64Gauge
-	ProcessorGroupbytraceEventLatency       metric.Int64Histogram
-	ProcessorGroupbytraceIncompleteReleases metric.Int64Counter
-	ProcessorGroupbytraceNumEventsInQueue   metric.Int64Gauge
-	ProcessorGroupbytraceNumTracesInMemory  metric.Int64Gauge
-	ProcessorGroupbytraceSpansReleased      metric.Int64Counter
-	ProcessorGroupbytraceTracesEvicted      metric.Int64Counter
-	ProcessorGroupbytraceTracesReleased     metric.Int64Counter
-	meters                                  map[configtelemetry.Level]metric.Meter
+	meter                                      metric.Meter
+	ProcessorGroupbytraceConfNumTraces         metric.Int64Gauge
+	ProcessorGroupbytraceEventLatency          metric.Int64Histogram
+	ProcessorGroupbytraceIncompleteReleases    metric.Int64Counter
+	ProcessorGroupbytraceNumEventsInQueue      metric.Int64Gauge
+	ProcessorGroupbytraceNumTracesInMemory     metric.Int64Gauge
+	ProcessorGroupbytraceOrphanTracesDiscarded metric.Int64Counter
+	ProcessorGroupbytraceSpansReleased         metric.Int64Counter
+	ProcessorGroupbytraceTracesEvicted         metric.Int64Counter
+	ProcessorGroupbytraceTracesReleased        metric.Int64Counter
+	meters                                     map[configtelemetry.Level]metric.Meter
 }
 
 // telemetryBuilderOption applies changes to default builder.
@@ -82,6 +83,12 @@ func NewTelemetryBuilder(settings component.TelemetrySettings, options ...teleme
 		metric.WithUnit("1"),
 	)
 	errs = errors.Join(errs, err)
+	builder.ProcessorGroupbytraceOrphanTracesDiscarded, err = builder.meters[configtelemetry.LevelBasic].Int64Counter(
+		"otelcol_processor_groupbytrace_orphan_traces_discarded",
+		metric.WithDescription("Traces discarded because their root span was not received"),
+		metric.WithUnit("1"),
+	)
+	errs = errors.Join(errs, err)
 	builder.ProcessorGroupbytraceSpansReleased, err = builder.meters[configtelemetry.LevelBasic].Int64Counter(
 		"otelcol_processor_groupbytrace_spans_released",
 		metric.WithDescription("Spans released to the next consumer"),
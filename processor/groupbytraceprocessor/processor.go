@@ -16,6 +16,7 @@ import (
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storageclient"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/batchpersignal"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/groupbytraceprocessor/internal/metadata"
 )
@@ -33,6 +34,7 @@ import (
 // Each worker in the eventMachine also uses a ring buffer to hold the in-flight trace IDs, so that we don't hold more than the given maximum number
 // of traces in memory/storage. Items that are evicted from the buffer are discarded without warning.
 type groupByTraceProcessor struct {
+	id               component.ID
 	nextConsumer     consumer.Traces
 	config           Config
 	logger           *zap.Logger
@@ -59,6 +61,7 @@ func newGroupByTraceProcessor(set processor.Settings, nextConsumer consumer.Trac
 	eventMachine := newEventMachine(set.Logger, 10000, config.NumWorkers, config.NumTraces, telemetryBuilder)
 
 	sp := &groupByTraceProcessor{
+		id:               set.ID,
 		logger:           set.Logger,
 		nextConsumer:     nextConsumer,
 		config:           config,
@@ -88,7 +91,15 @@ func (sp *groupByTraceProcessor) Capabilities() consumer.Capabilities {
 }
 
 // Start is invoked during service startup.
-func (sp *groupByTraceProcessor) Start(context.Context, component.Host) error {
+func (sp *groupByTraceProcessor) Start(ctx context.Context, host component.Host) error {
+	if sp.st == nil {
+		client, err := storageclient.Get(ctx, host, *sp.config.StorageID, component.KindProcessor, sp.id, "")
+		if err != nil {
+			return fmt.Errorf("couldn't get the storage client: %w", err)
+		}
+		sp.st = newDiskStorage(client, sp.telemetryBuilder)
+	}
+
 	// start these metrics, as it might take a while for them to receive their first event
 	sp.telemetryBuilder.ProcessorGroupbytraceTracesEvicted.Add(context.Background(), 0)
 	sp.telemetryBuilder.ProcessorGroupbytraceIncompleteReleases.Add(context.Background(), 0)
@@ -103,15 +114,27 @@ func (sp *groupByTraceProcessor) Shutdown(_ context.Context) error {
 	return sp.st.shutdown()
 }
 
+// orphanTrace counts the spans received for an in-flight trace, until its root span is received.
+type orphanTrace struct {
+	spans     int
+	discarded bool
+}
+
 func (sp *groupByTraceProcessor) onTraceReceived(trace tracesWithID, worker *eventMachineWorker) error {
 	traceID := trace.id
 	if worker.buffer.contains(traceID) {
 		sp.logger.Debug("trace is already in memory storage")
 
+		if orphan, ok := worker.orphans[traceID]; ok && orphan.discarded {
+			// the trace was discarded as an orphan, its spans are dropped until the duration expires
+			return nil
+		}
+
 		// it exists in memory already, just append the spans to the trace in the storage
 		if err := sp.addSpans(traceID, trace.td); err != nil {
 			return fmt.Errorf("couldn't add spans to existing trace: %w", err)
 		}
+		sp.checkOrphan(traceID, trace.td, worker)
 
 		// we are done with this trace, move on
 		return nil
@@ -123,6 +146,7 @@ func (sp *groupByTraceProcessor) onTraceReceived(trace tracesWithID, worker *eve
 	// place the trace ID in the buffer, and check if an item had to be evicted
 	evicted := worker.buffer.put(traceID)
 	if !evicted.IsEmpty() {
+		delete(worker.orphans, evicted)
 		// delete from the storage
 		worker.fire(event{
 			typ:     traceRemoved,
@@ -138,6 +162,10 @@ func (sp *groupByTraceProcessor) onTraceReceived(trace tracesWithID, worker *eve
 	if err := sp.addSpans(traceID, trace.td); err != nil {
 		return fmt.Errorf("couldn't add spans to existing trace: %w", err)
 	}
+	if sp.config.DiscardOrphans && sp.config.MaxOrphanSpans > 0 {
+		worker.orphans[traceID] = &orphanTrace{}
+		sp.checkOrphan(traceID, trace.td, worker)
+	}
 
 	sp.logger.Debug("scheduled to release trace", zap.Duration("duration", sp.config.WaitDuration))
 
@@ -164,6 +192,13 @@ func (sp *groupByTraceProcessor) onTraceExpired(traceID pcommon.TraceID, worker
 
 	// delete from the map and erase its memory entry
 	worker.buffer.delete(traceID)
+	if orphan, ok := worker.orphans[traceID]; ok {
+		delete(worker.orphans, traceID)
+		if orphan.discarded {
+			// the trace was already removed from the storage
+			return nil
+		}
+	}
 
 	// this might block, but we don't need to wait
 	sp.logger.Debug("marking the trace as released", zap.Stringer("traceID", traceID))
@@ -174,6 +209,32 @@ func (sp *groupByTraceProcessor) onTraceExpired(traceID pcommon.TraceID, worker
 	return nil
 }
 
+// checkOrphan discards the spans of a trace once it reached MaxOrphanSpans without a root span, so
+// that incomplete traces don't hold memory or storage until the duration expires. The trace stays
+// in-flight, so that its late spans are discarded too.
+func (sp *groupByTraceProcessor) checkOrphan(traceID pcommon.TraceID, td ptrace.Traces, worker *eventMachineWorker) {
+	orphan, ok := worker.orphans[traceID]
+	if !ok {
+		return
+	}
+	if hasRootSpan(resourceSpansOf(td)) {
+		delete(worker.orphans, traceID)
+		return
+	}
+	orphan.spans += td.SpanCount()
+	if orphan.spans < sp.config.MaxOrphanSpans {
+		return
+	}
+
+	sp.logger.Debug("discarding orphan trace reaching the maximum number of spans", zap.Stringer("traceID", traceID))
+	sp.telemetryBuilder.ProcessorGroupbytraceOrphanTracesDiscarded.Add(context.Background(), 1)
+	orphan.discarded = true
+	worker.fire(event{
+		typ:     traceRemoved,
+		payload: traceID,
+	})
+}
+
 func (sp *groupByTraceProcessor) markAsReleased(traceID pcommon.TraceID, fire func(...event)) error {
 	// #get is a potentially blocking operation
 	trace, err := sp.st.get(traceID)
@@ -185,6 +246,16 @@ func (sp *groupByTraceProcessor) markAsReleased(traceID pcommon.TraceID, fire fu
 		return fmt.Errorf("the trace %q couldn't be found at the storage", traceID)
 	}
 
+	if sp.config.DiscardOrphans && !hasRootSpan(trace) {
+		sp.logger.Debug("discarding orphan trace", zap.Stringer("traceID", traceID))
+		sp.telemetryBuilder.ProcessorGroupbytraceOrphanTracesDiscarded.Add(context.Background(), 1)
+		fire(event{
+			typ:     traceRemoved,
+			payload: traceID,
+		})
+		return nil
+	}
+
 	// signal that the trace is ready to be released
 	sp.logger.Debug("trace marked as released", zap.Stringer("traceID", traceID))
 
@@ -236,3 +307,27 @@ func (sp *groupByTraceProcessor) addSpans(traceID pcommon.TraceID, trace ptrace.
 	sp.logger.Debug("creating trace at the storage", zap.Stringer("traceID", traceID))
 	return sp.st.createOrAppend(traceID, trace)
 }
+
+// resourceSpansOf returns the resource spans of the traces.
+func resourceSpansOf(td ptrace.Traces) []ptrace.ResourceSpans {
+	rss := make([]ptrace.ResourceSpans, td.ResourceSpans().Len())
+	for i := range rss {
+		rss[i] = td.ResourceSpans().At(i)
+	}
+	return rss
+}
+
+// hasRootSpan returns whether the resource spans contain a span without a parent.
+func hasRootSpan(rss []ptrace.ResourceSpans) bool {
+	for _, rs := range rss {
+		for i := 0; i < rs.ScopeSpans().Len(); i++ {
+			spans := rs.ScopeSpans().At(i).Spans()
+			for j := 0; j < spans.Len(); j++ {
+				if spans.At(j).ParentSpanID().IsEmpty() {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
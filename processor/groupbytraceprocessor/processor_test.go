@@ -21,6 +21,7 @@ import (
 	"go.opentelemetry.io/collector/processor/processortest"
 	"go.uber.org/zap"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storagetest"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/batchpersignal"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/groupbytraceprocessor/internal/metadata"
 )
@@ -552,6 +553,163 @@ func TestAsyncOnRelease(t *testing.T) {
 	close(blockCh)
 }
 
+func TestDiscardOrphans(t *testing.T) {
+	// prepare
+	config := Config{
+		WaitDuration:   time.Nanosecond,
+		NumTraces:      10,
+		NumWorkers:     1,
+		DiscardOrphans: true,
+	}
+	rootTraceID := pcommon.TraceID([16]byte{1, 2, 3, 4})
+	orphanTraceID := pcommon.TraceID([16]byte{2, 3, 4, 5})
+	orphan := simpleTracesWithID(orphanTraceID)
+	orphan.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).SetParentSpanID([8]byte{1, 2, 3, 4})
+
+	receivedCh := make(chan pcommon.TraceID, 2)
+	next := &mockProcessor{
+		onTraces: func(_ context.Context, received ptrace.Traces) error {
+			receivedCh <- received.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).TraceID()
+			return nil
+		},
+	}
+	wgDeleted := &sync.WaitGroup{}
+
+	p := newGroupByTraceProcessor(processortest.NewNopSettings(), next, config)
+	backing := newMemoryStorage(p.telemetryBuilder)
+	p.st = &mockStorage{
+		onCreateOrAppend: backing.createOrAppend,
+		onGet:            backing.get,
+		onDelete: func(traceID pcommon.TraceID) ([]ptrace.ResourceSpans, error) {
+			wgDeleted.Done()
+			return backing.delete(traceID)
+		},
+	}
+	ctx := context.Background()
+	assert.NoError(t, p.Start(ctx, nil))
+	defer func() {
+		assert.NoError(t, p.Shutdown(ctx))
+	}()
+
+	// test
+	wgDeleted.Add(2) // both traces are removed from the storage
+	assert.NoError(t, p.ConsumeTraces(ctx, orphan))
+	assert.NoError(t, p.ConsumeTraces(ctx, simpleTracesWithID(rootTraceID)))
+
+	// verify
+	wgDeleted.Wait()
+	assert.Equal(t, rootTraceID, <-receivedCh)
+	assert.Equal(t, 0, backing.count())
+	select {
+	case traceID := <-receivedCh:
+		t.Errorf("unexpected trace %s released", traceID)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestDiscardOrphansReachingMaxSpans(t *testing.T) {
+	// prepare
+	config := Config{
+		WaitDuration:   time.Hour,
+		NumTraces:      10,
+		NumWorkers:     1,
+		DiscardOrphans: true,
+		MaxOrphanSpans: 2,
+	}
+	rootTraceID := pcommon.TraceID([16]byte{1, 2, 3, 4})
+	orphanTraceID := pcommon.TraceID([16]byte{2, 3, 4, 5})
+	child := func(traceID pcommon.TraceID) ptrace.Traces {
+		td := simpleTracesWithID(traceID)
+		td.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).SetParentSpanID([8]byte{1, 2, 3, 4})
+		return td
+	}
+
+	appendedCh := make(chan pcommon.TraceID, 10)
+	deletedCh := make(chan pcommon.TraceID, 10)
+	p := newGroupByTraceProcessor(processortest.NewNopSettings(), consumertest.NewNop(), config)
+	backing := newMemoryStorage(p.telemetryBuilder)
+	p.st = &mockStorage{
+		onCreateOrAppend: func(traceID pcommon.TraceID, td ptrace.Traces) error {
+			appendedCh <- traceID
+			return backing.createOrAppend(traceID, td)
+		},
+		onGet: backing.get,
+		onDelete: func(traceID pcommon.TraceID) ([]ptrace.ResourceSpans, error) {
+			deletedCh <- traceID
+			return backing.delete(traceID)
+		},
+	}
+	ctx := context.Background()
+	assert.NoError(t, p.Start(ctx, nil))
+	defer func() {
+		assert.NoError(t, p.Shutdown(ctx))
+	}()
+
+	// test
+	assert.NoError(t, p.ConsumeTraces(ctx, simpleTracesWithID(rootTraceID)))
+	assert.NoError(t, p.ConsumeTraces(ctx, child(rootTraceID)))
+	assert.NoError(t, p.ConsumeTraces(ctx, child(rootTraceID)))
+	assert.NoError(t, p.ConsumeTraces(ctx, child(orphanTraceID)))
+	assert.NoError(t, p.ConsumeTraces(ctx, child(orphanTraceID)))
+	for i := 0; i < 5; i++ {
+		<-appendedCh
+	}
+
+	// verify: the orphan is removed before the duration expires
+	assert.Equal(t, orphanTraceID, <-deletedCh)
+
+	// the late spans of the discarded orphan are dropped
+	assert.NoError(t, p.ConsumeTraces(ctx, child(orphanTraceID)))
+	assert.NoError(t, p.ConsumeTraces(ctx, child(rootTraceID)))
+	assert.Equal(t, rootTraceID, <-appendedCh)
+	assert.Equal(t, 1, backing.count())
+	rss, err := backing.get(rootTraceID)
+	require.NoError(t, err)
+	assert.Len(t, rss, 4)
+	assert.Empty(t, appendedCh)
+	assert.Empty(t, deletedCh)
+}
+
+func TestDiskStorage(t *testing.T) {
+	// prepare
+	ext := storagetest.NewInMemoryStorageExtension("test")
+	storageID := ext.ID
+	config := Config{
+		WaitDuration: time.Nanosecond,
+		NumTraces:    10,
+		NumWorkers:   1,
+		StoreOnDisk:  true,
+		StorageID:    &storageID,
+	}
+	traces := simpleTraces()
+
+	wgReceived := &sync.WaitGroup{}
+	next := &mockProcessor{
+		onTraces: func(_ context.Context, received ptrace.Traces) error {
+			assert.Equal(t, traces, received)
+			wgReceived.Done()
+			return nil
+		},
+	}
+
+	p, err := createTracesProcessor(context.Background(), processortest.NewNopSettings(), &config, next)
+	require.NoError(t, err)
+	ctx := context.Background()
+	require.Error(t, p.Start(ctx, storagetest.NewStorageHost()))
+	require.NoError(t, p.Start(ctx, storagetest.NewStorageHost().WithExtension(ext.ID, ext)))
+	defer func() {
+		assert.NoError(t, p.Shutdown(ctx))
+	}()
+	assert.IsType(t, &diskStorage{}, p.(*groupByTraceProcessor).st)
+
+	// test
+	wgReceived.Add(1)
+	assert.NoError(t, p.ConsumeTraces(ctx, traces))
+
+	// verify
+	wgReceived.Wait()
+}
+
 func BenchmarkConsumeTracesCompleteOnFirstBatch(b *testing.B) {
 	// prepare
 	config := Config{
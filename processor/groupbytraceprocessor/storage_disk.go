@@ -0,0 +1,172 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package groupbytraceprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/groupbytraceprocessor"
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	extensionstorage "go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/multierr"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/groupbytraceprocessor/internal/metadata"
+)
+
+// diskStorage keeps only the trace IDs in memory, along with the number of batches received for each trace.
+// Each batch is serialized to the storage client under its own key, so that appending spans to a trace
+// doesn't require reading the trace back.
+type diskStorage struct {
+	sync.Mutex
+	client                    extensionstorage.Client
+	batches                   map[pcommon.TraceID]int
+	telemetry                 *metadata.TelemetryBuilder
+	stopped                   bool
+	stoppedLock               sync.RWMutex
+	metricsCollectionInterval time.Duration
+	marshaler                 ptrace.ProtoMarshaler
+	unmarshaler               ptrace.ProtoUnmarshaler
+}
+
+var _ storage = (*diskStorage)(nil)
+
+func newDiskStorage(client extensionstorage.Client, telemetry *metadata.TelemetryBuilder) *diskStorage {
+	return &diskStorage{
+		client:                    client,
+		batches:                   make(map[pcommon.TraceID]int),
+		metricsCollectionInterval: time.Second,
+		telemetry:                 telemetry,
+	}
+}
+
+func (st *diskStorage) createOrAppend(traceID pcommon.TraceID, td ptrace.Traces) error {
+	data, err := st.marshaler.MarshalTraces(td)
+	if err != nil {
+		return err
+	}
+
+	// reserve the key for this batch, the write itself happens outside of the lock
+	st.Lock()
+	batch := st.batches[traceID]
+	st.batches[traceID] = batch + 1
+	st.Unlock()
+
+	return st.client.Set(context.Background(), batchKey(traceID, batch), data)
+}
+
+func (st *diskStorage) get(traceID pcommon.TraceID) ([]ptrace.ResourceSpans, error) {
+	st.Lock()
+	batches, ok := st.batches[traceID]
+	st.Unlock()
+	if !ok {
+		return nil, nil
+	}
+
+	var result []ptrace.ResourceSpans
+	for i := 0; i < batches; i++ {
+		data, err := st.client.Get(context.Background(), batchKey(traceID, i))
+		if err != nil {
+			return nil, err
+		}
+		if data == nil {
+			// the batch was reserved but not written yet
+			continue
+		}
+		td, err := st.unmarshaler.UnmarshalTraces(data)
+		if err != nil {
+			return nil, err
+		}
+		for j := 0; j < td.ResourceSpans().Len(); j++ {
+			result = append(result, td.ResourceSpans().At(j))
+		}
+	}
+
+	return result, nil
+}
+
+// delete removes the trace from the storage, returning its spans.
+func (st *diskStorage) delete(traceID pcommon.TraceID) ([]ptrace.ResourceSpans, error) {
+	result, err := st.get(traceID)
+	if err != nil {
+		return nil, err
+	}
+
+	st.Lock()
+	batches, ok := st.batches[traceID]
+	delete(st.batches, traceID)
+	st.Unlock()
+	if !ok {
+		return nil, nil
+	}
+
+	if err := st.deleteBatches(traceID, batches); err != nil {
+		return nil, err
+	}
+	if result == nil {
+		// the trace exists, even if none of its batches could be read
+		result = []ptrace.ResourceSpans{}
+	}
+	return result, nil
+}
+
+func (st *diskStorage) deleteBatches(traceID pcommon.TraceID, batches int) error {
+	ops := make([]extensionstorage.Operation, batches)
+	for i := range ops {
+		ops[i] = extensionstorage.DeleteOperation(batchKey(traceID, i))
+	}
+	return st.client.Batch(context.Background(), ops...)
+}
+
+func (st *diskStorage) start() error {
+	go st.periodicMetrics()
+	return nil
+}
+
+// shutdown removes the traces that are still in the storage, as their IDs are only known in memory,
+// and closes the storage client.
+func (st *diskStorage) shutdown() error {
+	st.stoppedLock.Lock()
+	st.stopped = true
+	st.stoppedLock.Unlock()
+
+	st.Lock()
+	batches := st.batches
+	st.batches = make(map[pcommon.TraceID]int)
+	st.Unlock()
+
+	var errs error
+	for traceID, n := range batches {
+		errs = multierr.Append(errs, st.deleteBatches(traceID, n))
+	}
+	return multierr.Append(errs, st.client.Close(context.Background()))
+}
+
+func (st *diskStorage) periodicMetrics() {
+	numTraces := st.count()
+	st.telemetry.ProcessorGroupbytraceNumTracesInMemory.Record(context.Background(), int64(numTraces))
+
+	st.stoppedLock.RLock()
+	stopped := st.stopped
+	st.stoppedLock.RUnlock()
+	if stopped {
+		return
+	}
+
+	time.AfterFunc(st.metricsCollectionInterval, func() {
+		st.periodicMetrics()
+	})
+}
+
+func (st *diskStorage) count() int {
+	st.Lock()
+	defer st.Unlock()
+	return len(st.batches)
+}
+
+func batchKey(traceID pcommon.TraceID, batch int) string {
+	return traceID.String() + "/" + strconv.Itoa(batch)
+}
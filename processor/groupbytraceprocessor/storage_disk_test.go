@@ -0,0 +1,131 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package groupbytraceprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/processor/processortest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storagetest"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/groupbytraceprocessor/internal/metadata"
+)
+
+func newTestDiskStorage() (*diskStorage, *storagetest.TestClient) {
+	set := processortest.NewNopSettings()
+	tel, _ := metadata.NewTelemetryBuilder(set.TelemetrySettings)
+	client := storagetest.NewInMemoryClient(component.KindProcessor, set.ID, "")
+	return newDiskStorage(client, tel), client
+}
+
+func TestDiskCreateAndGetTrace(t *testing.T) {
+	st, _ := newTestDiskStorage()
+
+	traceIDs := []pcommon.TraceID{
+		pcommon.TraceID([16]byte{1, 2, 3, 4}),
+		pcommon.TraceID([16]byte{2, 3, 4, 5}),
+	}
+
+	// test
+	for _, traceID := range traceIDs {
+		assert.NoError(t, st.createOrAppend(traceID, simpleTracesWithID(traceID)))
+	}
+
+	// verify
+	assert.Equal(t, 2, st.count())
+	for _, traceID := range traceIDs {
+		expected := []ptrace.ResourceSpans{simpleTracesWithID(traceID).ResourceSpans().At(0)}
+
+		retrieved, err := st.get(traceID)
+		require.NoError(t, err)
+		assert.Equal(t, expected, retrieved)
+	}
+
+	retrieved, err := st.get(pcommon.TraceID([16]byte{9}))
+	require.NoError(t, err)
+	assert.Nil(t, retrieved)
+}
+
+func TestDiskAppendSpans(t *testing.T) {
+	st, _ := newTestDiskStorage()
+	traceID := pcommon.TraceID([16]byte{1, 2, 3, 4})
+
+	first := simpleTracesWithID(traceID)
+	first.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).SetName("first-name")
+	second := simpleTracesWithID(traceID)
+	second.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).SetName("second-name")
+
+	// test
+	require.NoError(t, st.createOrAppend(traceID, first))
+	require.NoError(t, st.createOrAppend(traceID, second))
+
+	// override something in the second span, to make sure we are storing a copy
+	second.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).SetName("changed-second-name")
+
+	// verify
+	retrieved, err := st.get(traceID)
+	require.NoError(t, err)
+	require.Len(t, retrieved, 2)
+	assert.Equal(t, "first-name", retrieved[0].ScopeSpans().At(0).Spans().At(0).Name())
+	assert.Equal(t, "second-name", retrieved[1].ScopeSpans().At(0).Spans().At(0).Name())
+	assert.Equal(t, 1, st.count())
+}
+
+func TestDiskDeleteTrace(t *testing.T) {
+	st, client := newTestDiskStorage()
+	traceID := pcommon.TraceID([16]byte{1, 2, 3, 4})
+	trace := simpleTracesWithID(traceID)
+
+	require.NoError(t, st.createOrAppend(traceID, trace))
+	require.NoError(t, st.createOrAppend(traceID, trace))
+
+	// test
+	deleted, err := st.delete(traceID)
+
+	// verify
+	require.NoError(t, err)
+	assert.Len(t, deleted, 2)
+	assert.Equal(t, 0, st.count())
+
+	for i := 0; i < 2; i++ {
+		data, err := client.Get(context.Background(), batchKey(traceID, i))
+		require.NoError(t, err)
+		assert.Nil(t, data)
+	}
+
+	retrieved, err := st.get(traceID)
+	require.NoError(t, err)
+	assert.Nil(t, retrieved)
+
+	deleted, err = st.delete(traceID)
+	require.NoError(t, err)
+	assert.Nil(t, deleted)
+}
+
+func TestDiskShutdownRemovesTraces(t *testing.T) {
+	set := processortest.NewNopSettings()
+	tel, _ := metadata.NewTelemetryBuilder(set.TelemetrySettings)
+	storageDir := t.TempDir()
+	st := newDiskStorage(storagetest.NewFileBackedClient(component.KindProcessor, set.ID, "", storageDir), tel)
+
+	traceID := pcommon.TraceID([16]byte{1, 2, 3, 4})
+	require.NoError(t, st.createOrAppend(traceID, simpleTracesWithID(traceID)))
+	require.NoError(t, st.start())
+
+	// test
+	require.NoError(t, st.shutdown())
+
+	// verify
+	assert.Equal(t, 0, st.count())
+	client := storagetest.NewFileBackedClient(component.KindProcessor, set.ID, "", storageDir)
+	data, err := client.Get(context.Background(), batchKey(traceID, 0))
+	require.NoError(t, err)
+	assert.Nil(t, data)
+}
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"go.opentelemetry.io/collector/component"
+	"go.uber.org/zap"
 )
 
 // Config defaults
@@ -36,6 +37,7 @@ var (
 	errInvalidLogCountAttribute = errors.New("log_count_attribute must be set")
 	errInvalidInterval          = errors.New("interval must be greater than 0")
 	errCannotExcludeBody        = errors.New("cannot exclude the entire body")
+	errInvalidSampleSize        = errors.New("sample_differing_fields must not be negative")
 )
 
 // Config is the config of the processor.
@@ -44,6 +46,14 @@ type Config struct {
 	Interval          time.Duration `mapstructure:"interval"`
 	Timezone          string        `mapstructure:"timezone"`
 	ExcludeFields     []string      `mapstructure:"exclude_fields"`
+	// DedupKeys are OTTL value expressions, in the log context, whose values identify duplicate logs instead of the
+	// body, severity and attributes of the log record.
+	DedupKeys []string `mapstructure:"dedup_keys"`
+	// SampleDifferingFields is the maximum number of distinct values kept for each field that differs between
+	// the deduplicated logs. Zero disables the sampling.
+	SampleDifferingFields int `mapstructure:"sample_differing_fields"`
+	// StorageID is the storage extension used to persist the open aggregation window across restarts.
+	StorageID *component.ID `mapstructure:"storage"`
 }
 
 // createDefaultConfig returns the default config for the processor.
@@ -71,6 +81,14 @@ func (c Config) Validate() error {
 		return fmt.Errorf("timezone is invalid: %w", err)
 	}
 
+	if c.SampleDifferingFields < 0 {
+		return errInvalidSampleSize
+	}
+
+	if _, err := newDedupKeys(c.DedupKeys, component.TelemetrySettings{Logger: zap.NewNop()}); err != nil {
+		return err
+	}
+
 	return c.validateExcludeFields()
 }
 
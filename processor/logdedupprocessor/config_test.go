@@ -94,6 +94,37 @@ func TestValidateConfig(t *testing.T) {
 			},
 			expectedErr: nil,
 		},
+		{
+			desc: "invalid sample differing fields",
+			cfg: &Config{
+				LogCountAttribute:     defaultLogCountAttribute,
+				Interval:              defaultInterval,
+				Timezone:              defaultTimezone,
+				SampleDifferingFields: -1,
+			},
+			expectedErr: errInvalidSampleSize,
+		},
+		{
+			desc: "invalid dedup key",
+			cfg: &Config{
+				LogCountAttribute: defaultLogCountAttribute,
+				Interval:          defaultInterval,
+				Timezone:          defaultTimezone,
+				DedupKeys:         []string{`body["message"]`, `attributes[`},
+			},
+			expectedErr: errors.New("invalid dedup_keys expression"),
+		},
+		{
+			desc: "valid config with dedup keys",
+			cfg: &Config{
+				LogCountAttribute:     defaultLogCountAttribute,
+				Interval:              defaultInterval,
+				Timezone:              defaultTimezone,
+				DedupKeys:             []string{`body["message"]`, `ConvertCase(attributes["level"], "lower")`},
+				SampleDifferingFields: 5,
+			},
+			expectedErr: nil,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -20,6 +20,13 @@ const (
 	lastObservedTSAttr  = "last_observed_timestamp"
 )
 
+// Attributes names for the first and last timestamps of the deduplicated logs, and the sample of their differing fields
+const (
+	firstTSAttr         = "first_timestamp"
+	lastTSAttr          = "last_timestamp"
+	differingFieldsAttr = "differing_fields"
+)
+
 // timeNow can be reassigned for testing
 var timeNow = time.Now
 
@@ -28,15 +35,17 @@ type logAggregator struct {
 	resources         map[uint64]*resourceAggregator
 	logCountAttribute string
 	timezone          *time.Location
+	sampleSize        int
 	telemetryBuilder  *metadata.TelemetryBuilder
 }
 
 // newLogAggregator creates a new LogCounter.
-func newLogAggregator(logCountAttribute string, timezone *time.Location, telemetryBuilder *metadata.TelemetryBuilder) *logAggregator {
+func newLogAggregator(logCountAttribute string, timezone *time.Location, sampleSize int, telemetryBuilder *metadata.TelemetryBuilder) *logAggregator {
 	return &logAggregator{
 		resources:         make(map[uint64]*resourceAggregator),
 		logCountAttribute: logCountAttribute,
 		timezone:          timezone,
+		sampleSize:        sampleSize,
 		telemetryBuilder:  telemetryBuilder,
 	}
 }
@@ -65,12 +74,22 @@ func (l *logAggregator) Export(ctx context.Context) plog.Logs {
 				lr.SetObservedTimestamp(pcommon.NewTimestampFromTime(logAggregator.firstObservedTimestamp))
 
 				// Add attributes for log count and first/last observed timestamps
-				lr.Attributes().EnsureCapacity(lr.Attributes().Len() + 3)
+				lr.Attributes().EnsureCapacity(lr.Attributes().Len() + 6)
 				lr.Attributes().PutInt(l.logCountAttribute, logAggregator.count)
 				firstTimestampStr := logAggregator.firstObservedTimestamp.In(l.timezone).Format(time.RFC3339)
 				lr.Attributes().PutStr(firstObservedTSAttr, firstTimestampStr)
 				lastTimestampStr := logAggregator.lastObservedTimestamp.In(l.timezone).Format(time.RFC3339)
 				lr.Attributes().PutStr(lastObservedTSAttr, lastTimestampStr)
+
+				// Add attributes for the first/last timestamps of the logs, if they have any
+				if logAggregator.firstTimestamp != 0 {
+					lr.Attributes().PutStr(firstTSAttr, logAggregator.firstTimestamp.AsTime().In(l.timezone).Format(time.RFC3339))
+					lr.Attributes().PutStr(lastTSAttr, logAggregator.lastTimestamp.AsTime().In(l.timezone).Format(time.RFC3339))
+				}
+
+				if logAggregator.differingFields.Len() > 0 {
+					logAggregator.differingFields.CopyTo(lr.Attributes().PutEmptyMap(differingFieldsAttr))
+				}
 			}
 		}
 	}
@@ -80,13 +99,24 @@ func (l *logAggregator) Export(ctx context.Context) plog.Logs {
 
 // Add adds the logRecord to the resource aggregator that is identified by the resource attributes
 func (l *logAggregator) Add(resource pcommon.Resource, scope pcommon.InstrumentationScope, logRecord plog.LogRecord) {
+	l.AddWithKey(resource, scope, getLogKey(logRecord), logRecord)
+}
+
+// AddWithKey adds the logRecord to the resource aggregator, logs are deduplicated by the given key
+// instead of their content
+func (l *logAggregator) AddWithKey(resource pcommon.Resource, scope pcommon.InstrumentationScope, key uint64, logRecord plog.LogRecord) {
+	l.scopeAggregator(resource, scope).Add(key, logRecord, l.sampleSize)
+}
+
+// scopeAggregator returns the aggregator of the resource and scope, creating it if needed
+func (l *logAggregator) scopeAggregator(resource pcommon.Resource, scope pcommon.InstrumentationScope) *scopeAggregator {
 	key := getResourceKey(resource)
 	resourceAggregator, ok := l.resources[key]
 	if !ok {
 		resourceAggregator = newResourceAggregator(resource)
 		l.resources[key] = resourceAggregator
 	}
-	resourceAggregator.Add(scope, logRecord)
+	return resourceAggregator.scopeAggregator(scope)
 }
 
 // Reset resets the counter.
@@ -108,15 +138,15 @@ func newResourceAggregator(resource pcommon.Resource) *resourceAggregator {
 	}
 }
 
-// Add increments the counter that the logRecord matches.
-func (r *resourceAggregator) Add(scope pcommon.InstrumentationScope, logRecord plog.LogRecord) {
+// scopeAggregator returns the aggregator of the scope, creating it if needed
+func (r *resourceAggregator) scopeAggregator(scope pcommon.InstrumentationScope) *scopeAggregator {
 	key := getScopeKey(scope)
 	scopeAggregator, ok := r.scopeCounters[key]
 	if !ok {
 		scopeAggregator = newScopeAggregator(scope)
 		r.scopeCounters[key] = scopeAggregator
 	}
-	scopeAggregator.Add(logRecord)
+	return scopeAggregator
 }
 
 // scopeAggregator dimensions the counter by scope.
@@ -133,14 +163,17 @@ func newScopeAggregator(scope pcommon.InstrumentationScope) *scopeAggregator {
 	}
 }
 
-// Add increments the counter that the logRecord matches.
-func (s *scopeAggregator) Add(logRecord plog.LogRecord) {
-	key := getLogKey(logRecord)
+// Add increments the counter identified by the key. Up to sampleSize distinct values are sampled for each
+// field that differs from the first logRecord of the counter.
+func (s *scopeAggregator) Add(key uint64, logRecord plog.LogRecord, sampleSize int) {
 	lc, ok := s.logCounters[key]
 	if !ok {
 		lc = newLogCounter(logRecord)
 		s.logCounters[key] = lc
+	} else if sampleSize > 0 {
+		lc.sampleDifferingFields(logRecord, sampleSize)
 	}
+	lc.observeTimestamp(logRecord)
 	lc.Increment()
 }
 
@@ -149,6 +182,9 @@ type logCounter struct {
 	logRecord              plog.LogRecord
 	firstObservedTimestamp time.Time
 	lastObservedTimestamp  time.Time
+	firstTimestamp         pcommon.Timestamp
+	lastTimestamp          pcommon.Timestamp
+	differingFields        pcommon.Map
 	count                  int64
 }
 
@@ -159,6 +195,7 @@ func newLogCounter(logRecord plog.LogRecord) *logCounter {
 		count:                  0,
 		firstObservedTimestamp: timeNow().UTC(),
 		lastObservedTimestamp:  timeNow().UTC(),
+		differingFields:        pcommon.NewMap(),
 	}
 }
 
@@ -168,6 +205,85 @@ func (a *logCounter) Increment() {
 	a.count++
 }
 
+// observeTimestamp tracks the first and last timestamps of the logRecords. The observed timestamp is used for logs
+// without a timestamp.
+func (a *logCounter) observeTimestamp(logRecord plog.LogRecord) {
+	ts := logRecord.Timestamp()
+	if ts == 0 {
+		ts = logRecord.ObservedTimestamp()
+	}
+	if ts == 0 {
+		return
+	}
+	if a.firstTimestamp == 0 || ts < a.firstTimestamp {
+		a.firstTimestamp = ts
+	}
+	if ts > a.lastTimestamp {
+		a.lastTimestamp = ts
+	}
+}
+
+// sampleDifferingFields samples the attributes and body fields of the logRecord that differ from the first logRecord
+func (a *logCounter) sampleDifferingFields(logRecord plog.LogRecord, sampleSize int) {
+	logRecord.Attributes().Range(func(k string, v pcommon.Value) bool {
+		if first, ok := a.logRecord.Attributes().Get(k); !ok || !valuesEqual(first, v) {
+			a.sample(v, sampleSize, attributeField, k)
+		}
+		return true
+	})
+
+	body, firstBody := logRecord.Body(), a.logRecord.Body()
+	if body.Type() != pcommon.ValueTypeMap || firstBody.Type() != pcommon.ValueTypeMap {
+		if !valuesEqual(firstBody, body) {
+			a.sample(body, sampleSize, bodyField)
+		}
+		return
+	}
+	body.Map().Range(func(k string, v pcommon.Value) bool {
+		if first, ok := firstBody.Map().Get(k); !ok || !valuesEqual(first, v) {
+			a.sample(v, sampleSize, bodyField, k)
+		}
+		return true
+	})
+}
+
+// sample adds the value to the distinct values of the field at the given path, until there are sampleSize of them
+func (a *logCounter) sample(value pcommon.Value, sampleSize int, path ...string) {
+	fields := a.differingFields
+	for _, key := range path[:len(path)-1] {
+		v, ok := fields.Get(key)
+		if !ok {
+			v = fields.PutEmpty(key)
+			v.SetEmptyMap()
+		}
+		if v.Type() != pcommon.ValueTypeMap {
+			return
+		}
+		fields = v.Map()
+	}
+
+	last := path[len(path)-1]
+	values, ok := fields.Get(last)
+	if !ok {
+		values = fields.PutEmpty(last)
+		values.SetEmptySlice()
+	}
+	if values.Type() != pcommon.ValueTypeSlice || values.Slice().Len() >= sampleSize {
+		return
+	}
+	for i := 0; i < values.Slice().Len(); i++ {
+		if valuesEqual(values.Slice().At(i), value) {
+			return
+		}
+	}
+	value.CopyTo(values.Slice().AppendEmpty())
+}
+
+// valuesEqual compares two values by their hash
+func valuesEqual(a, b pcommon.Value) bool {
+	return pdatautil.Hash64(pdatautil.WithValue(a)) == pdatautil.Hash64(pdatautil.WithValue(b))
+}
+
 // getResourceKey creates a unique hash for the resource to use as a map key
 func getResourceKey(resource pcommon.Resource) uint64 {
 	return pdatautil.Hash64(
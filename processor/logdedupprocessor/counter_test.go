@@ -22,7 +22,7 @@ func Test_newLogAggregator(t *testing.T) {
 	telemetryBuilder, err := metadata.NewTelemetryBuilder(componenttest.NewNopTelemetrySettings())
 	require.NoError(t, err)
 
-	aggregator := newLogAggregator(cfg.LogCountAttribute, time.UTC, telemetryBuilder)
+	aggregator := newLogAggregator(cfg.LogCountAttribute, time.UTC, 0, telemetryBuilder)
 	require.Equal(t, cfg.LogCountAttribute, aggregator.logCountAttribute)
 	require.Equal(t, time.UTC, aggregator.timezone)
 	require.NotNil(t, aggregator.resources)
@@ -44,7 +44,7 @@ func Test_logAggregatorAdd(t *testing.T) {
 	require.NoError(t, err)
 
 	// Setup aggregator
-	aggregator := newLogAggregator("log_count", time.UTC, telemetryBuilder)
+	aggregator := newLogAggregator("log_count", time.UTC, 0, telemetryBuilder)
 	logRecord := plog.NewLogRecord()
 
 	resource := pcommon.NewResource()
@@ -94,7 +94,7 @@ func Test_logAggregatorReset(t *testing.T) {
 	telemetryBuilder, err := metadata.NewTelemetryBuilder(componenttest.NewNopTelemetrySettings())
 	require.NoError(t, err)
 
-	aggregator := newLogAggregator("log_count", time.UTC, telemetryBuilder)
+	aggregator := newLogAggregator("log_count", time.UTC, 0, telemetryBuilder)
 	for i := 0; i < 2; i++ {
 		resource := pcommon.NewResource()
 		resource.Attributes().PutInt("i", int64(i))
@@ -129,7 +129,7 @@ func Test_logAggregatorExport(t *testing.T) {
 	telemetryBuilder, err := metadata.NewTelemetryBuilder(componenttest.NewNopTelemetrySettings())
 	require.NoError(t, err)
 
-	aggregator := newLogAggregator(defaultLogCountAttribute, location, telemetryBuilder)
+	aggregator := newLogAggregator(defaultLogCountAttribute, location, 0, telemetryBuilder)
 	resource := pcommon.NewResource()
 	resource.Attributes().PutStr("one", "two")
 	expectedHash := pdatautil.MapHash(resource.Attributes())
@@ -185,6 +185,74 @@ func Test_logAggregatorExport(t *testing.T) {
 	require.Equal(t, expectedTimestampStr, actualLastObserved)
 }
 
+func Test_logAggregatorAddWithKey(t *testing.T) {
+	telemetryBuilder, err := metadata.NewTelemetryBuilder(componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+
+	aggregator := newLogAggregator(defaultLogCountAttribute, time.UTC, 2, telemetryBuilder)
+	resource := pcommon.NewResource()
+	scope := pcommon.NewInstrumentationScope()
+
+	first := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	newLog := func(user string, ts time.Time) plog.LogRecord {
+		logRecord := plog.NewLogRecord()
+		body := logRecord.Body().SetEmptyMap()
+		body.PutStr("message", "login failed")
+		body.PutStr("user", user)
+		logRecord.Attributes().PutStr("host", "host-"+user)
+		logRecord.SetTimestamp(pcommon.NewTimestampFromTime(ts))
+		return logRecord
+	}
+
+	aggregator.AddWithKey(resource, scope, 1, newLog("alice", first.Add(time.Minute)))
+	aggregator.AddWithKey(resource, scope, 1, newLog("bob", first))
+	aggregator.AddWithKey(resource, scope, 1, newLog("bob", first.Add(2*time.Minute)))
+	aggregator.AddWithKey(resource, scope, 1, newLog("carol", first.Add(time.Second)))
+	aggregator.AddWithKey(resource, scope, 1, newLog("dave", first.Add(time.Second)))
+
+	exportedLogs := aggregator.Export(context.Background())
+	require.Equal(t, 1, exportedLogs.LogRecordCount())
+
+	attrs := exportedLogs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Attributes().AsRaw()
+	require.Equal(t, int64(5), attrs[defaultLogCountAttribute])
+	require.Equal(t, first.Format(time.RFC3339), attrs[firstTSAttr])
+	require.Equal(t, first.Add(2*time.Minute).Format(time.RFC3339), attrs[lastTSAttr])
+	require.Equal(t, map[string]any{
+		attributeField: map[string]any{"host": []any{"host-bob", "host-carol"}},
+		bodyField:      map[string]any{"user": []any{"bob", "carol"}},
+	}, attrs[differingFieldsAttr])
+}
+
+func Test_logAggregatorAddWithKeyNoSampling(t *testing.T) {
+	telemetryBuilder, err := metadata.NewTelemetryBuilder(componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+
+	aggregator := newLogAggregator(defaultLogCountAttribute, time.UTC, 0, telemetryBuilder)
+	resource := pcommon.NewResource()
+	scope := pcommon.NewInstrumentationScope()
+
+	aggregator.AddWithKey(resource, scope, 1, generateTestLogRecord(t, "Body of the log"))
+	aggregator.AddWithKey(resource, scope, 1, generateTestLogRecord(t, "A different Body of the log"))
+
+	logRecord := plog.NewLogRecord()
+	logRecord.Body().SetStr("Log without timestamps")
+	aggregator.AddWithKey(resource, scope, 2, logRecord)
+
+	exportedLogs := aggregator.Export(context.Background())
+	require.Equal(t, 2, exportedLogs.LogRecordCount())
+
+	records := exportedLogs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords()
+	for i := 0; i < records.Len(); i++ {
+		attrs := records.At(i).Attributes()
+		_, ok := attrs.Get(differingFieldsAttr)
+		require.False(t, ok)
+
+		count, _ := attrs.Get(defaultLogCountAttribute)
+		_, ok = attrs.Get(firstTSAttr)
+		require.Equal(t, count.Int() == 2, ok)
+	}
+}
+
 func Test_newResourceAggregator(t *testing.T) {
 	resource := pcommon.NewResource()
 	resource.Attributes().PutStr("one", "two")
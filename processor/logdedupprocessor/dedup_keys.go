@@ -0,0 +1,83 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package logdedupprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/logdedupprocessor"
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottllog"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/pdatautil"
+)
+
+// dedupKeys identifies duplicate logs by the values of the dedup_keys expressions.
+type dedupKeys struct {
+	expressions []*ottl.ValueExpression[ottllog.TransformContext]
+}
+
+// newDedupKeys parses the dedup_keys expressions. It returns nil if there are none, in which case logs are
+// identified by their body, severity and attributes.
+func newDedupKeys(expressions []string, settings component.TelemetrySettings) (*dedupKeys, error) {
+	if len(expressions) == 0 {
+		return nil, nil
+	}
+
+	parser, err := ottllog.NewParser(ottlfuncs.StandardConverters[ottllog.TransformContext](), settings)
+	if err != nil {
+		return nil, err
+	}
+
+	valueExpressions := make([]*ottl.ValueExpression[ottllog.TransformContext], 0, len(expressions))
+	for _, expression := range expressions {
+		valueExpression, err := parser.ParseValueExpression(expression)
+		if err != nil {
+			return nil, fmt.Errorf("invalid dedup_keys expression %q: %w", expression, err)
+		}
+		valueExpressions = append(valueExpressions, valueExpression)
+	}
+	return &dedupKeys{expressions: valueExpressions}, nil
+}
+
+// Key creates a unique hash of the values of the dedup_keys expressions for the log record
+func (d *dedupKeys) Key(ctx context.Context, rl plog.ResourceLogs, sl plog.ScopeLogs, logRecord plog.LogRecord) (uint64, error) {
+	tCtx := ottllog.NewTransformContext(logRecord, sl.Scope(), rl.Resource(), sl, rl)
+
+	opts := make([]pdatautil.HashOption, 0, len(d.expressions))
+	for _, expression := range d.expressions {
+		value, err := expression.Eval(ctx, tCtx)
+		if err != nil {
+			return 0, err
+		}
+		opts = append(opts, pdatautil.WithValue(toValue(value)))
+	}
+	return pdatautil.Hash64(opts...), nil
+}
+
+// toValue converts the result of an OTTL expression to a pcommon.Value
+func toValue(v any) pcommon.Value {
+	switch v := v.(type) {
+	case pcommon.Value:
+		return v
+	case pcommon.Map:
+		value := pcommon.NewValueMap()
+		v.CopyTo(value.Map())
+		return value
+	case pcommon.Slice:
+		value := pcommon.NewValueSlice()
+		v.CopyTo(value.Slice())
+		return value
+	}
+
+	value := pcommon.NewValueEmpty()
+	if err := value.FromRaw(v); err != nil {
+		value.SetStr(fmt.Sprint(v))
+	}
+	return value
+}
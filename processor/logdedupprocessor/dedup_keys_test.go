@@ -0,0 +1,67 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package logdedupprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+func Test_newDedupKeys(t *testing.T) {
+	keys, err := newDedupKeys(nil, componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+	require.Nil(t, keys)
+
+	keys, err = newDedupKeys([]string{`body["message"]`, `resource.attributes["service.name"]`}, componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+	require.Len(t, keys.expressions, 2)
+
+	_, err = newDedupKeys([]string{`body[`}, componenttest.NewNopTelemetrySettings())
+	require.ErrorContains(t, err, "invalid dedup_keys expression")
+
+	_, err = newDedupKeys([]string{`UnknownFunction(body)`}, componenttest.NewNopTelemetrySettings())
+	require.ErrorContains(t, err, "invalid dedup_keys expression")
+}
+
+func Test_dedupKeysKey(t *testing.T) {
+	keys, err := newDedupKeys([]string{`body["message"]`, `ConvertCase(attributes["level"], "lower")`}, componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+
+	logs := plog.NewLogs()
+	rl := logs.ResourceLogs().AppendEmpty()
+	sl := rl.ScopeLogs().AppendEmpty()
+	newLog := func(message, level, user string) plog.LogRecord {
+		lr := sl.LogRecords().AppendEmpty()
+		body := lr.Body().SetEmptyMap()
+		body.PutStr("message", message)
+		body.PutStr("user", user)
+		lr.Attributes().PutStr("level", level)
+		return lr
+	}
+
+	key := func(lr plog.LogRecord) uint64 {
+		k, err := keys.Key(context.Background(), rl, sl, lr)
+		require.NoError(t, err)
+		return k
+	}
+
+	first := key(newLog("login failed", "ERROR", "alice"))
+	require.Equal(t, first, key(newLog("login failed", "error", "bob")))
+	require.NotEqual(t, first, key(newLog("login succeeded", "error", "alice")))
+	require.NotEqual(t, first, key(newLog("login failed", "warn", "alice")))
+
+	// missing values are part of the key as well
+	withoutMessage := func() plog.LogRecord {
+		lr := newLog("", "error", "alice")
+		lr.Body().Map().Remove("message")
+		return lr
+	}
+	missing := key(withoutMessage())
+	require.NotEqual(t, first, missing)
+	require.Equal(t, missing, key(withoutMessage()))
+}
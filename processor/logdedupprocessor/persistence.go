@@ -0,0 +1,141 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package logdedupprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/logdedupprocessor"
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+// windowStorageKey is the storage key of the open aggregation window
+const windowStorageKey = "window"
+
+// Names of the attributes holding the state of a log counter in a persisted window
+const (
+	stateKey             = "key"
+	stateCount           = "count"
+	stateFirstObserved   = "first_observed"
+	stateLastObserved    = "last_observed"
+	stateFirstTimestamp  = "first_timestamp"
+	stateLastTimestamp   = "last_timestamp"
+	stateDifferingFields = "differing_fields"
+)
+
+var errCorruptedWindow = errors.New("corrupted aggregation window")
+
+// Marshal encodes the open aggregation window. Every aggregated log record is followed by a log record holding
+// the state of its counter in its attributes.
+func (l *logAggregator) Marshal() ([]byte, error) {
+	logs := plog.NewLogs()
+
+	for _, resourceAggregator := range l.resources {
+		rl := logs.ResourceLogs().AppendEmpty()
+		resourceAggregator.resource.CopyTo(rl.Resource())
+
+		for _, scopeAggregator := range resourceAggregator.scopeCounters {
+			sl := rl.ScopeLogs().AppendEmpty()
+			scopeAggregator.scope.CopyTo(sl.Scope())
+
+			for key, lc := range scopeAggregator.logCounters {
+				lc.logRecord.CopyTo(sl.LogRecords().AppendEmpty())
+
+				state := sl.LogRecords().AppendEmpty().Attributes()
+				state.PutInt(stateKey, int64(key))
+				state.PutInt(stateCount, lc.count)
+				state.PutInt(stateFirstObserved, lc.firstObservedTimestamp.UnixNano())
+				state.PutInt(stateLastObserved, lc.lastObservedTimestamp.UnixNano())
+				state.PutInt(stateFirstTimestamp, int64(lc.firstTimestamp))
+				state.PutInt(stateLastTimestamp, int64(lc.lastTimestamp))
+				lc.differingFields.CopyTo(state.PutEmptyMap(stateDifferingFields))
+			}
+		}
+	}
+
+	return (&plog.ProtoMarshaler{}).MarshalLogs(logs)
+}
+
+// Unmarshal restores an aggregation window encoded by Marshal.
+func (l *logAggregator) Unmarshal(buf []byte) error {
+	logs, err := (&plog.ProtoUnmarshaler{}).UnmarshalLogs(buf)
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < logs.ResourceLogs().Len(); i++ {
+		rl := logs.ResourceLogs().At(i)
+
+		for j := 0; j < rl.ScopeLogs().Len(); j++ {
+			sl := rl.ScopeLogs().At(j)
+			records := sl.LogRecords()
+			if records.Len()%2 != 0 {
+				return errCorruptedWindow
+			}
+
+			scopeAggregator := l.scopeAggregator(rl.Resource(), sl.Scope())
+			for k := 0; k < records.Len(); k += 2 {
+				key, lc, err := decodeLogCounter(records.At(k), records.At(k+1).Attributes())
+				if err != nil {
+					return err
+				}
+				scopeAggregator.logCounters[key] = lc
+			}
+		}
+	}
+	return nil
+}
+
+func decodeLogCounter(logRecord plog.LogRecord, state pcommon.Map) (uint64, *logCounter, error) {
+	ints := make(map[string]int64, 6)
+	for _, name := range []string{stateKey, stateCount, stateFirstObserved, stateLastObserved, stateFirstTimestamp, stateLastTimestamp} {
+		v, ok := state.Get(name)
+		if !ok || v.Type() != pcommon.ValueTypeInt {
+			return 0, nil, errCorruptedWindow
+		}
+		ints[name] = v.Int()
+	}
+
+	lc := newLogCounter(logRecord)
+	lc.count = ints[stateCount]
+	lc.firstObservedTimestamp = time.Unix(0, ints[stateFirstObserved]).UTC()
+	lc.lastObservedTimestamp = time.Unix(0, ints[stateLastObserved]).UTC()
+	lc.firstTimestamp = pcommon.Timestamp(ints[stateFirstTimestamp])
+	lc.lastTimestamp = pcommon.Timestamp(ints[stateLastTimestamp])
+	if v, ok := state.Get(stateDifferingFields); ok && v.Type() == pcommon.ValueTypeMap {
+		v.Map().CopyTo(lc.differingFields)
+	}
+	return uint64(ints[stateKey]), lc, nil
+}
+
+// saveWindow persists the open aggregation window instead of exporting it.
+func (p *logDedupProcessor) saveWindow(ctx context.Context) error {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	if len(p.aggregator.resources) == 0 {
+		return p.storageClient.Delete(ctx, windowStorageKey)
+	}
+	buf, err := p.aggregator.Marshal()
+	if err != nil {
+		return err
+	}
+	return p.storageClient.Set(ctx, windowStorageKey, buf)
+}
+
+// restoreWindow restores the aggregation window persisted on the last shutdown. The persisted window is deleted,
+// so that its counts are only exported once even if the collector does not shut down gracefully.
+func (p *logDedupProcessor) restoreWindow(ctx context.Context) error {
+	buf, err := p.storageClient.Get(ctx, windowStorageKey)
+	if err != nil || buf == nil {
+		return err
+	}
+
+	p.mux.Lock()
+	err = p.aggregator.Unmarshal(buf)
+	p.mux.Unlock()
+	return errors.Join(err, p.storageClient.Delete(ctx, windowStorageKey))
+}
@@ -0,0 +1,82 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package logdedupprocessor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/logdedupprocessor/internal/metadata"
+)
+
+func Test_logAggregatorMarshal(t *testing.T) {
+	telemetryBuilder, err := metadata.NewTelemetryBuilder(componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+
+	aggregator := newLogAggregator(defaultLogCountAttribute, time.UTC, 2, telemetryBuilder)
+	resource := pcommon.NewResource()
+	resource.Attributes().PutStr("one", "two")
+	scope := pcommon.NewInstrumentationScope()
+	scope.SetName("scope")
+
+	logRecord1 := generateTestLogRecord(t, "Body of the log")
+	logRecord2 := generateTestLogRecord(t, "Body of the log")
+	logRecord2.Attributes().PutStr("str", "other attr str")
+	logRecord3 := generateTestLogRecord(t, "Another body")
+
+	aggregator.AddWithKey(resource, scope, 1, logRecord1)
+	aggregator.AddWithKey(resource, scope, 1, logRecord2)
+	aggregator.AddWithKey(resource, scope, 2, logRecord3)
+
+	buf, err := aggregator.Marshal()
+	require.NoError(t, err)
+
+	restored := newLogAggregator(defaultLogCountAttribute, time.UTC, 2, telemetryBuilder)
+	require.NoError(t, restored.Unmarshal(buf))
+
+	expected := aggregator.resources[getResourceKey(resource)].scopeCounters[getScopeKey(scope)]
+	actual := restored.resources[getResourceKey(resource)].scopeCounters[getScopeKey(scope)]
+	require.Equal(t, expected.scope.Name(), actual.scope.Name())
+	require.Len(t, actual.logCounters, 2)
+	for key, lc := range expected.logCounters {
+		restoredCounter, ok := actual.logCounters[key]
+		require.True(t, ok)
+		require.Equal(t, lc.logRecord.Body().AsRaw(), restoredCounter.logRecord.Body().AsRaw())
+		require.Equal(t, lc.logRecord.Attributes().AsRaw(), restoredCounter.logRecord.Attributes().AsRaw())
+		require.Equal(t, lc.count, restoredCounter.count)
+		require.Equal(t, lc.firstObservedTimestamp.UnixNano(), restoredCounter.firstObservedTimestamp.UnixNano())
+		require.Equal(t, lc.lastObservedTimestamp.UnixNano(), restoredCounter.lastObservedTimestamp.UnixNano())
+		require.Equal(t, lc.firstTimestamp, restoredCounter.firstTimestamp)
+		require.Equal(t, lc.lastTimestamp, restoredCounter.lastTimestamp)
+		require.Equal(t, lc.differingFields.AsRaw(), restoredCounter.differingFields.AsRaw())
+	}
+}
+
+func Test_logAggregatorUnmarshalCorrupted(t *testing.T) {
+	telemetryBuilder, err := metadata.NewTelemetryBuilder(componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+
+	// log record without its state
+	logs := plog.NewLogs()
+	records := logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+	records.AppendEmpty().Body().SetStr("Body of the log")
+	buf, err := (&plog.ProtoMarshaler{}).MarshalLogs(logs)
+	require.NoError(t, err)
+
+	aggregator := newLogAggregator(defaultLogCountAttribute, time.UTC, 0, telemetryBuilder)
+	require.ErrorIs(t, aggregator.Unmarshal(buf), errCorruptedWindow)
+
+	// state with a missing count
+	records.AppendEmpty().Attributes().PutInt(stateKey, 1)
+	buf, err = (&plog.ProtoMarshaler{}).MarshalLogs(logs)
+	require.NoError(t, err)
+	require.ErrorIs(t, aggregator.Unmarshal(buf), errCorruptedWindow)
+
+	require.Error(t, aggregator.Unmarshal([]byte{0xff}))
+}
@@ -5,29 +5,38 @@ package logdedupprocessor // import "github.com/open-telemetry/opentelemetry-col
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/processor"
 	"go.uber.org/zap"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storageclient"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/logdedupprocessor/internal/metadata"
 )
 
 // logDedupProcessor is a logDedupProcessor that counts duplicate instances of logs.
 type logDedupProcessor struct {
+	id           component.ID
 	emitInterval time.Duration
 	aggregator   *logAggregator
 	remover      *fieldRemover
+	keys         *dedupKeys
 	nextConsumer consumer.Logs
 	logger       *zap.Logger
 	cancel       context.CancelFunc
 	wg           sync.WaitGroup
 	mux          sync.Mutex
+
+	storageID *component.ID
+	// storageClient is set if the open aggregation window is persisted across restarts
+	storageClient storage.Client
 }
 
 func newProcessor(cfg *Config, nextConsumer consumer.Logs, settings processor.Settings) (*logDedupProcessor, error) {
@@ -42,17 +51,37 @@ func newProcessor(cfg *Config, nextConsumer consumer.Logs, settings processor.Se
 		return nil, fmt.Errorf("invalid timezone: %w", err)
 	}
 
+	keys, err := newDedupKeys(cfg.DedupKeys, settings.TelemetrySettings)
+	if err != nil {
+		return nil, err
+	}
+
 	return &logDedupProcessor{
+		id:           settings.ID,
 		emitInterval: cfg.Interval,
-		aggregator:   newLogAggregator(cfg.LogCountAttribute, timezone, telemetryBuilder),
+		aggregator:   newLogAggregator(cfg.LogCountAttribute, timezone, cfg.SampleDifferingFields, telemetryBuilder),
 		remover:      newFieldRemover(cfg.ExcludeFields),
+		keys:         keys,
 		nextConsumer: nextConsumer,
 		logger:       settings.Logger,
+		storageID:    cfg.StorageID,
 	}, nil
 }
 
 // Start starts the processor.
-func (p *logDedupProcessor) Start(ctx context.Context, _ component.Host) error {
+func (p *logDedupProcessor) Start(ctx context.Context, host component.Host) error {
+	if p.storageID != nil {
+		client, err := storageclient.Get(ctx, host, *p.storageID, component.KindProcessor, p.id, "")
+		if err != nil {
+			return err
+		}
+		p.storageClient = client
+
+		if err := p.restoreWindow(ctx); err != nil {
+			p.logger.Warn("failed to restore the aggregation window", zap.Error(err))
+		}
+	}
+
 	ctx, cancel := context.WithCancel(ctx)
 	p.cancel = cancel
 
@@ -68,17 +97,22 @@ func (p *logDedupProcessor) Capabilities() consumer.Capabilities {
 }
 
 // Shutdown stops the processor.
-func (p *logDedupProcessor) Shutdown(_ context.Context) error {
+func (p *logDedupProcessor) Shutdown(ctx context.Context) error {
 	if p.cancel != nil {
 		// Call cancel to stop the export interval goroutine and wait for it to finish.
 		p.cancel()
 		p.wg.Wait()
 	}
-	return nil
+
+	if p.storageClient == nil {
+		return nil
+	}
+	// The open aggregation window is persisted instead of being exported
+	return errors.Join(p.saveWindow(ctx), p.storageClient.Close(ctx))
 }
 
 // ConsumeLogs processes the logs.
-func (p *logDedupProcessor) ConsumeLogs(_ context.Context, pl plog.Logs) error {
+func (p *logDedupProcessor) ConsumeLogs(ctx context.Context, pl plog.Logs) error {
 	p.mux.Lock()
 	defer p.mux.Unlock()
 
@@ -92,11 +126,23 @@ func (p *logDedupProcessor) ConsumeLogs(_ context.Context, pl plog.Logs) error {
 
 			for k := 0; k < sl.LogRecords().Len(); k++ {
 				logRecord := sl.LogRecords().At(k)
-				// Remove excluded fields if any
-				p.remover.RemoveFields(logRecord)
+				if p.keys == nil {
+					// Remove excluded fields if any
+					p.remover.RemoveFields(logRecord)
 
-				// Add the log to the aggregator
-				p.aggregator.Add(resource, scope, logRecord)
+					// Add the log to the aggregator
+					p.aggregator.Add(resource, scope, logRecord)
+					continue
+				}
+
+				// The dedup keys are evaluated before excluded fields are removed, so that they can use them
+				key, err := p.keys.Key(ctx, rl, sl, logRecord)
+				p.remover.RemoveFields(logRecord)
+				if err != nil {
+					p.logger.Debug("failed to evaluate the dedup keys, using the log record as key", zap.Error(err))
+					key = getLogKey(logRecord)
+				}
+				p.aggregator.AddWithKey(resource, scope, key, logRecord)
 			}
 		}
 	}
@@ -114,8 +160,10 @@ func (p *logDedupProcessor) handleExportInterval(ctx context.Context) {
 	for {
 		select {
 		case <-ctx.Done():
-			// Export any remaining logs
-			p.exportLogs(ctx)
+			// Export any remaining logs, unless they are persisted on shutdown
+			if p.storageClient == nil {
+				p.exportLogs(ctx)
+			}
 			if err := ctx.Err(); err != context.Canceled {
 				p.logger.Error("context error", zap.Error(err))
 			}
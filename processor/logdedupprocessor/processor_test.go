@@ -11,12 +11,15 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/processor/processortest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storagetest"
 )
 
 func Test_newProcessor(t *testing.T) {
@@ -221,3 +224,126 @@ func Test_unsetLogsAreExportedOnShutdown(t *testing.T) {
 	exportedLogs := logsSink.AllLogs()
 	require.Len(t, exportedLogs, 1)
 }
+
+func TestProcessorConsumeWithDedupKeys(t *testing.T) {
+	logsSink := &consumertest.LogsSink{}
+	cfg := &Config{
+		LogCountAttribute:     defaultLogCountAttribute,
+		Interval:              1 * time.Second,
+		Timezone:              defaultTimezone,
+		DedupKeys:             []string{`body`},
+		SampleDifferingFields: 2,
+		ExcludeFields: []string{
+			fmt.Sprintf("%s.remove_me", attributeField),
+		},
+	}
+
+	p, err := newProcessor(cfg, logsSink, processortest.NewNopSettings())
+	require.NoError(t, err)
+	err = p.Start(context.Background(), componenttest.NewNopHost())
+	require.NoError(t, err)
+
+	// Same body, but different attributes
+	logRecord1 := generateTestLogRecord(t, "Body of the log")
+	logRecord2 := generateTestLogRecord(t, "Body of the log")
+	logRecord2.Attributes().PutStr("str", "other attr str")
+	logRecord2.Attributes().PutBool("remove_me", false)
+	logRecord3 := generateTestLogRecord(t, "A different Body of the log")
+
+	logs := plog.NewLogs()
+	sl := logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty()
+	logRecord1.CopyTo(sl.LogRecords().AppendEmpty())
+	logRecord2.CopyTo(sl.LogRecords().AppendEmpty())
+	logRecord3.CopyTo(sl.LogRecords().AppendEmpty())
+
+	err = p.ConsumeLogs(context.Background(), logs)
+	require.NoError(t, err)
+
+	err = p.Shutdown(context.Background())
+	require.NoError(t, err)
+
+	allSinkLogs := logsSink.AllLogs()
+	require.Len(t, allSinkLogs, 1)
+	require.Equal(t, 2, allSinkLogs[0].LogRecordCount())
+
+	records := allSinkLogs[0].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords()
+	for i := 0; i < records.Len(); i++ {
+		attrs := records.At(i).Attributes().AsRaw()
+		if records.At(i).Body().Str() != "Body of the log" {
+			require.Equal(t, int64(1), attrs[defaultLogCountAttribute])
+			continue
+		}
+		require.Equal(t, int64(2), attrs[defaultLogCountAttribute])
+		require.Equal(t, map[string]any{
+			attributeField: map[string]any{"str": []any{"other attr str"}},
+		}, attrs[differingFieldsAttr])
+	}
+}
+
+func TestProcessorPersistsWindow(t *testing.T) {
+	ext := storagetest.NewFileBackedStorageExtension("test", t.TempDir())
+	host := storagetest.NewStorageHost().WithExtension(ext.ID, ext)
+	cfg := &Config{
+		LogCountAttribute: defaultLogCountAttribute,
+		Interval:          1 * time.Hour,
+		Timezone:          defaultTimezone,
+		StorageID:         &ext.ID,
+	}
+
+	consume := func(p *logDedupProcessor) {
+		logs := plog.NewLogs()
+		sl := logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty()
+		generateTestLogRecord(t, "Body of the log").CopyTo(sl.LogRecords().AppendEmpty())
+		require.NoError(t, p.ConsumeLogs(context.Background(), logs))
+	}
+
+	// The open window is persisted on shutdown, instead of being exported.
+	// The same settings are used across the restart, as the storage is per component ID.
+	set := processortest.NewNopSettings()
+	logsSink := &consumertest.LogsSink{}
+	p, err := newProcessor(cfg, logsSink, set)
+	require.NoError(t, err)
+	require.NoError(t, p.Start(context.Background(), host))
+	consume(p)
+	consume(p)
+	require.NoError(t, p.Shutdown(context.Background()))
+	require.Empty(t, logsSink.AllLogs())
+
+	// The window is restored on start, and counts the logs received before the restart
+	p, err = newProcessor(cfg, logsSink, set)
+	require.NoError(t, err)
+	require.NoError(t, p.Start(context.Background(), host))
+	consume(p)
+	p.exportLogs(context.Background())
+
+	allSinkLogs := logsSink.AllLogs()
+	require.Len(t, allSinkLogs, 1)
+	require.Equal(t, 1, allSinkLogs[0].LogRecordCount())
+	countVal, ok := allSinkLogs[0].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Attributes().Get(defaultLogCountAttribute)
+	require.True(t, ok)
+	require.Equal(t, int64(3), countVal.Int())
+
+	// Nothing is left in the storage once the window is exported
+	require.NoError(t, p.Shutdown(context.Background()))
+	client, err := ext.GetClient(context.Background(), component.KindProcessor, p.id, "")
+	require.NoError(t, err)
+	buf, err := client.Get(context.Background(), windowStorageKey)
+	require.NoError(t, err)
+	require.Nil(t, buf)
+	require.NoError(t, client.Close(context.Background()))
+}
+
+func TestProcessorStorageNotFound(t *testing.T) {
+	storageID := storagetest.NewStorageID("test")
+	cfg := &Config{
+		LogCountAttribute: defaultLogCountAttribute,
+		Interval:          defaultInterval,
+		Timezone:          defaultTimezone,
+		StorageID:         &storageID,
+	}
+
+	p, err := newProcessor(cfg, &consumertest.LogsSink{}, processortest.NewNopSettings())
+	require.NoError(t, err)
+	require.ErrorContains(t, p.Start(context.Background(), storagetest.NewStorageHost()), "storage extension 'test_storage/test' not found")
+	require.NoError(t, p.Shutdown(context.Background()))
+}
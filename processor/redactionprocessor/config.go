@@ -3,6 +3,23 @@
 
 package redactionprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/redactionprocessor"
 
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configopaque"
+)
+
+var (
+	errNoHashKey         = errors.New("hash_key must be set to use the hash, tokenize or vault modes")
+	errNoVaultStorage    = errors.New("storage must be set to use the vault mode")
+	errEmptyModeRule     = errors.New("mode_rules entries must set keys or blocked_values")
+	errUnknownBlockedVal = errors.New("mode_rules blocked_values must be listed in blocked_values")
+)
+
 type Config struct {
 
 	// AllowAllKeys is a flag to allow all span attribute keys. Setting this
@@ -30,4 +47,81 @@ type Config struct {
 	// information, while it is valuable when integrating and testing a new
 	// configuration. Possible values are `debug`, `info`, and `silent`.
 	Summary string `mapstructure:"summary"`
+
+	// Mode controls how the values matching BlockedValues are replaced.
+	// Possible values are `mask` (the default), `hash`, `tokenize` and
+	// `vault`. The mode can be overridden per attribute key or blocked value
+	// with ModeRules.
+	Mode string `mapstructure:"mode"`
+
+	// ModeRules overrides Mode for the attributes whose key matches one of
+	// the Keys regular expressions, or for the values matching one of the
+	// BlockedValues patterns. The first matching rule applies.
+	ModeRules []ModeRule `mapstructure:"mode_rules"`
+
+	// HashKey is the secret key of the HMAC-SHA256 digests used by the
+	// `hash`, `tokenize` and `vault` modes. Keeping the key stable keeps the
+	// replaced values joinable across signals and restarts.
+	HashKey configopaque.String `mapstructure:"hash_key"`
+
+	// StorageID is the storage extension in which the `vault` mode records
+	// the original value of each token, so that tokens can be reversed.
+	StorageID *component.ID `mapstructure:"storage"`
+}
+
+// ModeRule selects the mode of the values blocked in some attributes.
+type ModeRule struct {
+	// Mode is the mode applied by the rule.
+	Mode string `mapstructure:"mode"`
+
+	// Keys is a list of regular expressions matching the attribute keys the
+	// rule applies to.
+	Keys []string `mapstructure:"keys"`
+
+	// BlockedValues is a list of patterns from Config.BlockedValues the rule
+	// applies to.
+	BlockedValues []string `mapstructure:"blocked_values"`
+}
+
+// Validate checks if the processor configuration is valid
+func (cfg *Config) Validate() error {
+	modes := []string{cfg.Mode}
+	if err := validateMode(cfg.Mode); err != nil {
+		return err
+	}
+	for _, rule := range cfg.ModeRules {
+		if err := validateMode(rule.Mode); err != nil {
+			return err
+		}
+		if len(rule.Keys) == 0 && len(rule.BlockedValues) == 0 {
+			return errEmptyModeRule
+		}
+		for _, key := range rule.Keys {
+			if _, err := regexp.Compile(key); err != nil {
+				return fmt.Errorf("error compiling regex in mode_rules keys: %w", err)
+			}
+		}
+		for _, pattern := range rule.BlockedValues {
+			if !slices.Contains(cfg.BlockedValues, pattern) {
+				return fmt.Errorf("%w: %q", errUnknownBlockedVal, pattern)
+			}
+		}
+		modes = append(modes, rule.Mode)
+	}
+
+	if cfg.HashKey == "" && (slices.Contains(modes, hashMode) || slices.Contains(modes, tokenizeMode) || slices.Contains(modes, vaultMode)) {
+		return errNoHashKey
+	}
+	if cfg.StorageID == nil && slices.Contains(modes, vaultMode) {
+		return errNoVaultStorage
+	}
+	return nil
+}
+
+func validateMode(mode string) error {
+	switch mode {
+	case "", maskMode, hashMode, tokenizeMode, vaultMode:
+		return nil
+	}
+	return fmt.Errorf("unknown mode %q, must be one of %q, %q, %q or %q", mode, maskMode, hashMode, tokenizeMode, vaultMode)
 }
@@ -18,6 +18,8 @@ import (
 func TestLoadConfig(t *testing.T) {
 	t.Parallel()
 
+	fileStorageID := component.MustNewID("file_storage")
+
 	tests := []struct {
 		id       component.ID
 		expected component.Config
@@ -36,6 +38,20 @@ func TestLoadConfig(t *testing.T) {
 			id:       component.NewIDWithName(metadata.Type, "empty"),
 			expected: createDefaultConfig(),
 		},
+		{
+			id: component.NewIDWithName(metadata.Type, "modes"),
+			expected: &Config{
+				AllowAllKeys:  true,
+				BlockedValues: []string{"4[0-9]{12}(?:[0-9]{3})?", "[a-z0-9._%+-]+@[a-z0-9.-]+\\.[a-z]{2,}"},
+				Mode:          hashMode,
+				HashKey:       "secret",
+				StorageID:     &fileStorageID,
+				ModeRules: []ModeRule{
+					{Mode: vaultMode, BlockedValues: []string{"4[0-9]{12}(?:[0-9]{3})?"}},
+					{Mode: maskMode, Keys: []string{"^debug\\."}},
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -55,3 +71,72 @@ func TestLoadConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateConfig(t *testing.T) {
+	storageID := component.MustNewID("file_storage")
+	tests := []struct {
+		name        string
+		cfg         *Config
+		expectedErr string
+	}{
+		{
+			name: "default",
+			cfg:  &Config{},
+		},
+		{
+			name:        "unknown mode",
+			cfg:         &Config{Mode: "encrypt"},
+			expectedErr: `unknown mode "encrypt"`,
+		},
+		{
+			name:        "unknown rule mode",
+			cfg:         &Config{ModeRules: []ModeRule{{Mode: "encrypt", Keys: []string{"id"}}}},
+			expectedErr: `unknown mode "encrypt"`,
+		},
+		{
+			name:        "empty rule",
+			cfg:         &Config{ModeRules: []ModeRule{{Mode: maskMode}}},
+			expectedErr: errEmptyModeRule.Error(),
+		},
+		{
+			name:        "invalid rule key",
+			cfg:         &Config{ModeRules: []ModeRule{{Mode: maskMode, Keys: []string{"("}}}},
+			expectedErr: "error compiling regex in mode_rules keys",
+		},
+		{
+			name:        "unknown rule blocked value",
+			cfg:         &Config{BlockedValues: []string{"a+"}, ModeRules: []ModeRule{{Mode: maskMode, BlockedValues: []string{"b+"}}}},
+			expectedErr: errUnknownBlockedVal.Error(),
+		},
+		{
+			name:        "hash without key",
+			cfg:         &Config{Mode: hashMode},
+			expectedErr: errNoHashKey.Error(),
+		},
+		{
+			name:        "rule tokenize without key",
+			cfg:         &Config{ModeRules: []ModeRule{{Mode: tokenizeMode, Keys: []string{"id"}}}},
+			expectedErr: errNoHashKey.Error(),
+		},
+		{
+			name:        "vault without storage",
+			cfg:         &Config{Mode: vaultMode, HashKey: "secret"},
+			expectedErr: errNoVaultStorage.Error(),
+		},
+		{
+			name: "vault",
+			cfg:  &Config{Mode: vaultMode, HashKey: "secret", StorageID: &storageID},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.expectedErr)
+			}
+		})
+	}
+}
@@ -53,7 +53,11 @@ func createTracesProcessor(
 		cfg,
 		next,
 		redaction.processTraces,
-		processorhelper.WithCapabilities(consumer.Capabilities{MutatesData: true}))
+		processorhelper.WithCapabilities(consumer.Capabilities{MutatesData: true}),
+		processorhelper.WithStart(func(ctx context.Context, host component.Host) error {
+			return redaction.start(ctx, host, set.ID)
+		}),
+		processorhelper.WithShutdown(redaction.shutdown))
 }
 
 // createLogsProcessor creates an instance of redaction for processing logs
@@ -75,7 +79,11 @@ func createLogsProcessor(
 		cfg,
 		next,
 		red.processLogs,
-		processorhelper.WithCapabilities(consumer.Capabilities{MutatesData: true}))
+		processorhelper.WithCapabilities(consumer.Capabilities{MutatesData: true}),
+		processorhelper.WithStart(func(ctx context.Context, host component.Host) error {
+			return red.start(ctx, host, set.ID)
+		}),
+		processorhelper.WithShutdown(red.shutdown))
 }
 
 // createMetricsProcessor creates an instance of redaction for processing metrics
@@ -97,5 +105,9 @@ func createMetricsProcessor(
 		cfg,
 		next,
 		red.processMetrics,
-		processorhelper.WithCapabilities(consumer.Capabilities{MutatesData: true}))
+		processorhelper.WithCapabilities(consumer.Capabilities{MutatesData: true}),
+		processorhelper.WithStart(func(ctx context.Context, host component.Host) error {
+			return red.start(ctx, host, set.ID)
+		}),
+		processorhelper.WithShutdown(red.shutdown))
 }
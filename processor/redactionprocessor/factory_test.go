@@ -8,8 +8,11 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/processor/processortest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storagetest"
 )
 
 func TestDefaultConfiguration(t *testing.T) {
@@ -44,3 +47,25 @@ func TestCreateTestMetricsProcessor(t *testing.T) {
 	assert.NotNil(t, tp)
 	assert.True(t, tp.Capabilities().MutatesData)
 }
+
+func TestCreateVaultProcessors(t *testing.T) {
+	ext := storagetest.NewInMemoryStorageExtension("test")
+	host := storagetest.NewStorageHost().WithExtension(ext.ID, ext)
+	cfg := &Config{Mode: vaultMode, HashKey: "secret", StorageID: &ext.ID}
+
+	// the signals of a processor share its vault
+	set := processortest.NewNopSettings()
+	tp, err := createTracesProcessor(context.Background(), set, cfg, consumertest.NewNop())
+	require.NoError(t, err)
+	lp, err := createLogsProcessor(context.Background(), set, cfg, consumertest.NewNop())
+	require.NoError(t, err)
+	mp, err := createMetricsProcessor(context.Background(), set, cfg, consumertest.NewNop())
+	require.NoError(t, err)
+
+	require.NoError(t, tp.Start(context.Background(), host))
+	require.NoError(t, lp.Start(context.Background(), host))
+	require.NoError(t, mp.Start(context.Background(), host))
+	require.NoError(t, tp.Shutdown(context.Background()))
+	require.NoError(t, lp.Shutdown(context.Background()))
+	require.NoError(t, mp.Shutdown(context.Background()))
+}
@@ -0,0 +1,477 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package redactionprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/redactionprocessor"
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/hashicorp/golang-lru/v2/simplelru"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storageclient"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/sharedcomponent"
+)
+
+const (
+	// maskMode replaces blocked values with a fixed length of asterisks
+	maskMode = "mask"
+	// hashMode replaces blocked values with their keyed HMAC-SHA256 digest
+	hashMode = "hash"
+	// tokenizeMode replaces blocked values with a keyed token of the same format
+	tokenizeMode = "tokenize"
+	// vaultMode replaces blocked values like tokenizeMode, and records the
+	// original value of each token in a storage extension
+	vaultMode = "vault"
+
+	mask = "****"
+
+	// maxVaultRounds is the number of alternative tokens tried for a value
+	// whose token is already recorded for another value
+	maxVaultRounds = 16
+
+	// vaultCacheSize is the number of tokens whose value is kept in memory
+	vaultCacheSize = 10000
+
+	// vaultTokenLocks is the number of locks the tokens are spread over
+	vaultTokenLocks = 64
+)
+
+var (
+	errVaultNotStarted = errors.New("the vault storage is not available")
+	errVaultCollision  = errors.New("no free token left for the value in the vault")
+)
+
+// modeRule is a compiled ModeRule
+type modeRule struct {
+	mode          string
+	keys          []*regexp.Regexp
+	blockedValues map[string]struct{}
+}
+
+// makeModeRules precompiles the key patterns of the mode rules
+func makeModeRules(config *Config) ([]modeRule, error) {
+	rules := make([]modeRule, 0, len(config.ModeRules))
+	for _, rule := range config.ModeRules {
+		compiled := modeRule{
+			mode:          rule.Mode,
+			blockedValues: make(map[string]struct{}, len(rule.BlockedValues)),
+		}
+		for _, key := range rule.Keys {
+			re, err := regexp.Compile(key)
+			if err != nil {
+				return nil, fmt.Errorf("error compiling regex in mode rule keys: %w", err)
+			}
+			compiled.keys = append(compiled.keys, re)
+		}
+		for _, pattern := range rule.BlockedValues {
+			compiled.blockedValues[pattern] = struct{}{}
+		}
+		rules = append(rules, compiled)
+	}
+	return rules, nil
+}
+
+// modeOf returns the mode of the values matching the blocked pattern in the
+// attribute with the given key
+func (s *redaction) modeOf(key, pattern string) string {
+	for _, rule := range s.modeRules {
+		if _, ok := rule.blockedValues[pattern]; ok {
+			return rule.mode
+		}
+		for _, re := range rule.keys {
+			if re.MatchString(key) {
+				return rule.mode
+			}
+		}
+	}
+	if s.config.Mode == "" {
+		return maskMode
+	}
+	return s.config.Mode
+}
+
+// replaceBlocked replaces the matches of the blocked patterns in the value of
+// the attribute with the given key, according to their mode, and returns
+// whether any pattern matched. The patterns are matched against the original
+// value in the order of the configuration, and the matches overlapping the
+// match of a previous pattern are skipped, so that a replacement is never
+// replaced again.
+func (s *redaction) replaceBlocked(ctx context.Context, key, value string) (string, bool) {
+	type replacement struct {
+		start, end int
+		mode       string
+	}
+	var replacements []replacement
+	for _, blocked := range s.blockRegexList {
+		var mode string
+		for _, loc := range blocked.re.FindAllStringIndex(value, -1) {
+			if slices.ContainsFunc(replacements, func(r replacement) bool {
+				return loc[0] < r.end && r.start < loc[1]
+			}) {
+				continue
+			}
+			if mode == "" {
+				mode = s.modeOf(key, blocked.pattern)
+			}
+			replacements = append(replacements, replacement{start: loc[0], end: loc[1], mode: mode})
+		}
+	}
+	if len(replacements) == 0 {
+		return value, false
+	}
+
+	sort.Slice(replacements, func(i, j int) bool {
+		return replacements[i].start < replacements[j].start
+	})
+	var replaced strings.Builder
+	last := 0
+	for _, r := range replacements {
+		replaced.WriteString(value[last:r.start])
+		replaced.WriteString(s.replace(ctx, value[r.start:r.end], r.mode))
+		last = r.end
+	}
+	replaced.WriteString(value[last:])
+	return replaced.String(), true
+}
+
+// replace returns the replacement of a blocked value according to the mode.
+// Values that cannot be replaced are masked.
+func (s *redaction) replace(ctx context.Context, match, mode string) string {
+	var replacement string
+	var err error
+	switch mode {
+	case hashMode:
+		replacement = s.hash(match)
+	case tokenizeMode:
+		replacement = s.tokenize(match, 0)
+	case vaultMode:
+		replacement, err = s.vaultToken(ctx, match)
+	default:
+		return mask
+	}
+	if err != nil {
+		s.logger.Warn("failed to replace a blocked value, masking it instead", zap.String("mode", mode), zap.Error(err))
+		return mask
+	}
+	return replacement
+}
+
+// hash returns the hex encoded HMAC-SHA256 digest of the value
+func (s *redaction) hash(value string) string {
+	mac := hmac.New(sha256.New, s.hashKey)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// tokenize returns a token with the format of the value. Letters, marks and
+// digits are replaced by characters of the same class, derived from
+// HMAC-SHA256 digests of the value, and other characters are kept. The round
+// is mixed into the digests to derive alternative tokens.
+func (s *redaction) tokenize(value string, round uint32) string {
+	var (
+		token  strings.Builder
+		stream []byte
+		block  uint32
+	)
+	next := func() uint32 {
+		if len(stream) == 0 {
+			var header [8]byte
+			binary.BigEndian.PutUint32(header[:4], round)
+			binary.BigEndian.PutUint32(header[4:], block)
+			mac := hmac.New(sha256.New, s.hashKey)
+			mac.Write(header[:])
+			mac.Write([]byte(value))
+			stream = mac.Sum(nil)
+			block++
+		}
+		n := binary.BigEndian.Uint32(stream)
+		stream = stream[4:]
+		return n
+	}
+	// uniform returns an integer in [0, n). The draws above the largest
+	// multiple of n are rejected, so that every integer is equally likely.
+	uniform := func(n uint32) uint32 {
+		limit := math.MaxUint32 - math.MaxUint32%n
+		for {
+			if x := next(); x < limit {
+				return x % n
+			}
+		}
+	}
+
+	token.Grow(len(value))
+	for _, r := range value {
+		token.WriteRune(substituteRune(r, uniform))
+	}
+	return token.String()
+}
+
+// runeClasses are the Unicode general categories of the characters replaced
+// by tokens, other than the decimal digits
+var runeClasses = []*unicode.RangeTable{
+	unicode.Lu, unicode.Ll, unicode.Lt, unicode.Lm, unicode.Lo,
+	unicode.Mn, unicode.Mc, unicode.Me, unicode.Nl, unicode.No,
+}
+
+// substituteRune returns a character of the same class as r, drawn with
+// uniform, or r if it is neither a letter, a mark nor a digit. ASCII digits
+// and letters are replaced by ASCII digits and letters of the same case,
+// other decimal digits by digits of the same digit set, and other characters
+// by characters of the same general category and script.
+func substituteRune(r rune, uniform func(n uint32) uint32) rune {
+	switch {
+	case r >= '0' && r <= '9':
+		return '0' + rune(uniform(10))
+	case r >= 'a' && r <= 'z':
+		return 'a' + rune(uniform(26))
+	case r >= 'A' && r <= 'Z':
+		return 'A' + rune(uniform(26))
+	case unicode.Is(unicode.Nd, r):
+		return r - digitValue(r) + rune(uniform(10))
+	}
+
+	var class *unicode.RangeTable
+	for _, table := range runeClasses {
+		if unicode.Is(table, r) {
+			class = table
+			break
+		}
+	}
+	if class == nil {
+		return r
+	}
+	candidates := class
+	for _, script := range unicode.Scripts {
+		if unicode.Is(script, r) {
+			candidates = script
+			break
+		}
+	}
+	count := tableSize(candidates)
+	for {
+		if c := tableRune(candidates, uniform(count)); unicode.Is(class, c) && (candidates == class || unicode.Is(candidates, c)) {
+			return c
+		}
+	}
+}
+
+// digitValue returns the value of a decimal digit. The decimal digits are
+// encoded in runs starting with the zero of each digit set.
+func digitValue(r rune) rune {
+	for _, rng := range unicode.Nd.R16 {
+		if r >= rune(rng.Lo) && r <= rune(rng.Hi) {
+			return (r - rune(rng.Lo)) % 10
+		}
+	}
+	for _, rng := range unicode.Nd.R32 {
+		if r >= rune(rng.Lo) && r <= rune(rng.Hi) {
+			return (r - rune(rng.Lo)) % 10
+		}
+	}
+	return 0
+}
+
+// tableSize returns the number of characters of the table
+func tableSize(table *unicode.RangeTable) uint32 {
+	var size uint32
+	for _, rng := range table.R16 {
+		size += uint32((rng.Hi-rng.Lo)/rng.Stride) + 1
+	}
+	for _, rng := range table.R32 {
+		size += (rng.Hi-rng.Lo)/rng.Stride + 1
+	}
+	return size
+}
+
+// tableRune returns the character of the table at the index, which must be
+// lower than the size of the table
+func tableRune(table *unicode.RangeTable, index uint32) rune {
+	for _, rng := range table.R16 {
+		if n := uint32((rng.Hi-rng.Lo)/rng.Stride) + 1; index >= n {
+			index -= n
+		} else {
+			return rune(rng.Lo) + rune(index)*rune(rng.Stride)
+		}
+	}
+	for _, rng := range table.R32 {
+		if n := (rng.Hi-rng.Lo)/rng.Stride + 1; index >= n {
+			index -= n
+		} else {
+			return rune(rng.Lo) + rune(index)*rune(rng.Stride)
+		}
+	}
+	return 0
+}
+
+// vaults holds the vault of each redaction processor, shared by its signals
+var vaults = sharedcomponent.NewSharedComponents()
+
+// vault records the original value of the tokens of the vault mode in a
+// storage extension. It is shared by the signals of a processor, so that they
+// use a single storage client.
+type vault struct {
+	storageID   component.ID
+	componentID component.ID
+
+	client    storage.Client
+	clientMux sync.RWMutex
+
+	// cache holds the most recently used tokens and their original values,
+	// sparing the storage lookups of the values seen over and over again
+	cache    *simplelru.LRU[string, string]
+	cacheMux sync.Mutex
+
+	// tokenMuxes serialize the lookups and records of the tokens spread over
+	// the same lock, so that two values never record the same token, without
+	// serializing the storage calls of unrelated tokens
+	tokenMuxes [vaultTokenLocks]sync.Mutex
+}
+
+func newVault(storageID, componentID component.ID) *vault {
+	cache, _ := simplelru.NewLRU[string, string](vaultCacheSize, nil)
+	return &vault{
+		storageID:   storageID,
+		componentID: componentID,
+		cache:       cache,
+	}
+}
+
+// Start opens the vault storage
+func (v *vault) Start(ctx context.Context, host component.Host) error {
+	client, err := storageclient.Get(ctx, host, v.storageID, component.KindProcessor, v.componentID, "")
+	if err != nil {
+		return err
+	}
+	v.clientMux.Lock()
+	defer v.clientMux.Unlock()
+	v.client = client
+	return nil
+}
+
+// Shutdown closes the vault storage
+func (v *vault) Shutdown(ctx context.Context) error {
+	v.clientMux.Lock()
+	defer v.clientMux.Unlock()
+	if v.client == nil {
+		return nil
+	}
+	err := v.client.Close(ctx)
+	v.client = nil
+	v.cacheMux.Lock()
+	v.cache.Purge()
+	v.cacheMux.Unlock()
+	return err
+}
+
+// token returns the token of the value, and records the value under the token.
+// If the token is already recorded for another value, the token of the next
+// round is used, so that every token maps to a single value.
+func (v *vault) token(ctx context.Context, value string, tokenize func(value string, round uint32) string) (string, error) {
+	// The client is only replaced once the calls using it are done
+	v.clientMux.RLock()
+	defer v.clientMux.RUnlock()
+	if v.client == nil {
+		return "", errVaultNotStarted
+	}
+
+	for round := uint32(0); round < maxVaultRounds; round++ {
+		token := tokenize(value, round)
+		recorded, err := v.record(ctx, token, value)
+		if err != nil {
+			return "", err
+		}
+		if recorded == value {
+			return token, nil
+		}
+	}
+	return "", errVaultCollision
+}
+
+// record records the value under the token, unless the token is already
+// recorded, and returns the value recorded under the token
+func (v *vault) record(ctx context.Context, token, value string) (string, error) {
+	v.cacheMux.Lock()
+	recorded, ok := v.cache.Get(token)
+	v.cacheMux.Unlock()
+	if ok {
+		return recorded, nil
+	}
+
+	tokenMux := &v.tokenMuxes[fnv32(token)%vaultTokenLocks]
+	tokenMux.Lock()
+	defer tokenMux.Unlock()
+	buf, err := v.client.Get(ctx, token)
+	if err != nil {
+		return "", err
+	}
+	if buf == nil {
+		if err = v.client.Set(ctx, token, []byte(value)); err != nil {
+			return "", err
+		}
+		recorded = value
+	} else {
+		recorded = string(buf)
+	}
+
+	v.cacheMux.Lock()
+	v.cache.Add(token, recorded)
+	v.cacheMux.Unlock()
+	return recorded, nil
+}
+
+// fnv32 returns the FNV-1a hash of the token
+func fnv32(token string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(token))
+	return h.Sum32()
+}
+
+// vaultToken returns the token of the value recorded in the vault
+func (s *redaction) vaultToken(ctx context.Context, value string) (string, error) {
+	if s.vault == nil {
+		return "", errVaultNotStarted
+	}
+	return s.vault.token(ctx, value, s.tokenize)
+}
+
+// start opens the vault storage, if the processor is configured with one.
+// The vault is shared by the signals of the processor with the given ID.
+func (s *redaction) start(ctx context.Context, host component.Host, id component.ID) error {
+	if s.config.StorageID == nil {
+		return nil
+	}
+	shared := vaults.GetOrAdd(id, func() component.Component {
+		return newVault(*s.config.StorageID, id)
+	})
+	if err := shared.Start(ctx, host); err != nil {
+		return err
+	}
+	s.sharedVault = shared
+	s.vault = shared.Unwrap().(*vault)
+	return nil
+}
+
+// shutdown closes the vault storage shared by the signals of the processor
+func (s *redaction) shutdown(ctx context.Context) error {
+	if s.sharedVault == nil {
+		return nil
+	}
+	return s.sharedVault.Shutdown(ctx)
+}
@@ -0,0 +1,236 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package redactionprocessor
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storagetest"
+)
+
+const visaPattern = "4[0-9]{12}(?:[0-9]{3})?"
+
+func TestHashMode(t *testing.T) {
+	config := &Config{
+		AllowAllKeys:  true,
+		BlockedValues: []string{visaPattern},
+		Mode:          hashMode,
+		HashKey:       "secret",
+	}
+	processor, err := newRedaction(context.Background(), config, zaptest.NewLogger(t))
+	require.NoError(t, err)
+
+	digest := processor.hash("4111111111111111")
+	assert.Len(t, digest, 64)
+	assert.Equal(t, digest, processor.hash("4111111111111111"))
+	assert.NotEqual(t, digest, processor.hash("4111111111111112"))
+
+	otherKey, err := newRedaction(context.Background(), &Config{HashKey: "other"}, zaptest.NewLogger(t))
+	require.NoError(t, err)
+	assert.NotEqual(t, digest, otherKey.hash("4111111111111111"))
+
+	attrs := pcommon.NewMap()
+	attrs.PutStr("card", "paid with 4111111111111111")
+	processor.processAttrs(context.Background(), attrs)
+	card, _ := attrs.Get("card")
+	assert.Equal(t, "paid with "+digest, card.Str())
+}
+
+func TestTokenizeMode(t *testing.T) {
+	processor, err := newRedaction(context.Background(), &Config{HashKey: "secret"}, zaptest.NewLogger(t))
+	require.NoError(t, err)
+
+	value := "Card 4111-1111-1111-1111 of jane.doe@example.com"
+	token := processor.tokenize(value, 0)
+	assert.Regexp(t, regexp.MustCompile(`^[A-Z][a-z]{3} [0-9]{4}-[0-9]{4}-[0-9]{4}-[0-9]{4} [a-z]{2} [a-z]{4}\.[a-z]{3}@[a-z]{7}\.[a-z]{3}$`), token)
+	assert.NotEqual(t, value, token)
+	assert.Equal(t, token, processor.tokenize(value, 0))
+	assert.NotEqual(t, token, processor.tokenize(value, 1))
+
+	// Tokens longer than a digest are derived from several digests
+	long := "12345678901234567890123456789012345678901234567890"
+	assert.Regexp(t, regexp.MustCompile(`^[0-9]{50}$`), processor.tokenize(long, 0))
+}
+
+func TestTokenizeModeUnicode(t *testing.T) {
+	processor, err := newRedaction(context.Background(), &Config{HashKey: "secret"}, zaptest.NewLogger(t))
+	require.NoError(t, err)
+
+	// Letters and digits of every script are replaced by characters of the
+	// same category and script, so that none of the value is kept
+	value := "Иван Петров 李小龙 José Ǆemal नमस्ते ٤٥٦"
+	token := processor.tokenize(value, 0)
+	assert.Regexp(t, regexp.MustCompile(`^\p{Cyrillic}{4} \p{Cyrillic}{6} \p{Han}{3} \p{Latin}{4} \p{Latin}{5} \p{Devanagari}{6} [٠-٩]{3}$`), token)
+	valueRunes, tokenRunes := []rune(value), []rune(token)
+	require.Len(t, tokenRunes, len(valueRunes))
+	for i, r := range valueRunes {
+		for _, class := range []*unicode.RangeTable{unicode.Lu, unicode.Ll, unicode.Lt, unicode.Lo, unicode.Mn, unicode.Mc, unicode.Nd} {
+			assert.Equal(t, unicode.Is(class, r), unicode.Is(class, tokenRunes[i]), "class of %q and %q", r, tokenRunes[i])
+		}
+	}
+	for _, word := range strings.Fields(value) {
+		assert.NotContains(t, token, word)
+	}
+	assert.Equal(t, token, processor.tokenize(value, 0))
+	assert.NotEqual(t, token, processor.tokenize(value, 1))
+}
+
+func TestVaultMode(t *testing.T) {
+	ext := storagetest.NewInMemoryStorageExtension("test")
+	host := storagetest.NewStorageHost().WithExtension(ext.ID, ext)
+	config := &Config{
+		AllowAllKeys:  true,
+		BlockedValues: []string{visaPattern},
+		Mode:          vaultMode,
+		HashKey:       "secret",
+		StorageID:     &ext.ID,
+	}
+	processor, err := newRedaction(context.Background(), config, zaptest.NewLogger(t))
+	require.NoError(t, err)
+
+	// Values are masked until the vault is started
+	attrs := pcommon.NewMap()
+	attrs.PutStr("card", "4111111111111111")
+	processor.processAttrs(context.Background(), attrs)
+	card, _ := attrs.Get("card")
+	assert.Equal(t, mask, card.Str())
+
+	require.NoError(t, processor.start(context.Background(), host, component.MustNewID("redaction")))
+
+	attrs.PutStr("card", "4111111111111111")
+	processor.processAttrs(context.Background(), attrs)
+	card, _ = attrs.Get("card")
+	token := card.Str()
+	assert.Equal(t, processor.tokenize("4111111111111111", 0), token)
+
+	original, err := processor.vault.client.Get(context.Background(), token)
+	require.NoError(t, err)
+	assert.Equal(t, "4111111111111111", string(original))
+
+	// The same value always maps to the same token
+	again, err := processor.vaultToken(context.Background(), "4111111111111111")
+	require.NoError(t, err)
+	assert.Equal(t, token, again)
+
+	// Another value whose token is already recorded gets the token of the next round
+	require.NoError(t, processor.vault.client.Set(context.Background(), processor.tokenize("4222222222222", 0), []byte("4333333333333")))
+	collided, err := processor.vaultToken(context.Background(), "4222222222222")
+	require.NoError(t, err)
+	assert.Equal(t, processor.tokenize("4222222222222", 1), collided)
+
+	// Recorded tokens are served from the cache
+	require.NoError(t, processor.vault.client.Delete(context.Background(), token))
+	again, err = processor.vaultToken(context.Background(), "4111111111111111")
+	require.NoError(t, err)
+	assert.Equal(t, token, again)
+
+	require.NoError(t, processor.shutdown(context.Background()))
+}
+
+func TestVaultSharedBySignals(t *testing.T) {
+	ext := storagetest.NewInMemoryStorageExtension("test")
+	host := storagetest.NewStorageHost().WithExtension(ext.ID, ext)
+	config := &Config{
+		AllowAllKeys:  true,
+		BlockedValues: []string{visaPattern},
+		Mode:          vaultMode,
+		HashKey:       "secret",
+		StorageID:     &ext.ID,
+	}
+	id := component.MustNewID("redaction")
+
+	traces, err := newRedaction(context.Background(), config, zaptest.NewLogger(t))
+	require.NoError(t, err)
+	logs, err := newRedaction(context.Background(), config, zaptest.NewLogger(t))
+	require.NoError(t, err)
+	require.NoError(t, traces.start(context.Background(), host, id))
+	require.NoError(t, logs.start(context.Background(), host, id))
+	assert.Same(t, traces.vault, logs.vault)
+
+	require.NoError(t, traces.shutdown(context.Background()))
+	require.NoError(t, logs.shutdown(context.Background()))
+}
+
+func TestVaultModeStorageNotFound(t *testing.T) {
+	storageID := storagetest.NewStorageID("test")
+	config := &Config{Mode: vaultMode, HashKey: "secret", StorageID: &storageID}
+	processor, err := newRedaction(context.Background(), config, zaptest.NewLogger(t))
+	require.NoError(t, err)
+
+	err = processor.start(context.Background(), storagetest.NewStorageHost(), component.MustNewID("redaction"))
+	require.ErrorContains(t, err, "storage extension 'test_storage/test' not found")
+	require.NoError(t, processor.shutdown(context.Background()))
+}
+
+func TestModeRules(t *testing.T) {
+	const emailPattern = "[a-z.]+@example.com"
+	config := &Config{
+		AllowAllKeys:  true,
+		BlockedValues: []string{visaPattern, emailPattern},
+		Mode:          hashMode,
+		HashKey:       "secret",
+		ModeRules: []ModeRule{
+			{Mode: tokenizeMode, BlockedValues: []string{visaPattern}},
+			{Mode: maskMode, Keys: []string{`^debug\.`}},
+		},
+	}
+	processor, err := newRedaction(context.Background(), config, zaptest.NewLogger(t))
+	require.NoError(t, err)
+
+	assert.Equal(t, tokenizeMode, processor.modeOf("card", visaPattern))
+	assert.Equal(t, tokenizeMode, processor.modeOf("debug.card", visaPattern))
+	assert.Equal(t, maskMode, processor.modeOf("debug.email", emailPattern))
+	assert.Equal(t, hashMode, processor.modeOf("email", emailPattern))
+
+	attrs := pcommon.NewMap()
+	attrs.PutStr("card", "4111111111111111")
+	attrs.PutStr("email", "jane.doe@example.com")
+	attrs.PutStr("debug.email", "jane.doe@example.com")
+	processor.processAttrs(context.Background(), attrs)
+
+	expected := map[string]any{
+		"card":        processor.tokenize("4111111111111111", 0),
+		"email":       processor.hash("jane.doe@example.com"),
+		"debug.email": mask,
+	}
+	for k, v := range expected {
+		actual, ok := attrs.Get(k)
+		require.True(t, ok)
+		assert.Equal(t, v, actual.Str(), k)
+	}
+
+	_, err = newRedaction(context.Background(), &Config{ModeRules: []ModeRule{{Mode: maskMode, Keys: []string{"("}}}}, zaptest.NewLogger(t))
+	assert.ErrorContains(t, err, "failed to process mode rules")
+}
+
+func TestBlockedValuesInConfigOrder(t *testing.T) {
+	config := &Config{
+		AllowAllKeys:  true,
+		BlockedValues: []string{"secret-[a-z]+", "[0-9]+"},
+		Mode:          hashMode,
+		HashKey:       "secret",
+		ModeRules: []ModeRule{
+			{Mode: maskMode, BlockedValues: []string{"[0-9]+"}},
+		},
+	}
+	processor, err := newRedaction(context.Background(), config, zaptest.NewLogger(t))
+	require.NoError(t, err)
+
+	// The digits of the digest replacing the first blocked value are not masked
+	attrs := pcommon.NewMap()
+	attrs.PutStr("value", "secret-abc and 42")
+	processor.processAttrs(context.Background(), attrs)
+	value, _ := attrs.Get("value")
+	assert.Equal(t, processor.hash("secret-abc")+" and "+mask, value.Str())
+}
@@ -15,6 +15,8 @@ import (
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/sharedcomponent"
 )
 
 const attrValuesSeparator = ","
@@ -24,8 +26,15 @@ type redaction struct {
 	allowList map[string]string
 	// Attribute keys ignored in a span
 	ignoreList map[string]string
-	// Attribute values blocked in a span
-	blockRegexList map[string]*regexp.Regexp
+	// Attribute values blocked in a span, in the order of the configuration
+	blockRegexList []blockedValue
+	// Modes of the blocked values, per attribute key or blocked value
+	modeRules []modeRule
+	// Secret key of the hash, tokenize and vault modes
+	hashKey []byte
+	// Storage of the original values of the vault tokens, shared by the signals
+	vault       *vault
+	sharedVault *sharedcomponent.SharedComponent
 	// Redaction processor configuration
 	config *Config
 	// Logger
@@ -41,11 +50,17 @@ func newRedaction(ctx context.Context, config *Config, logger *zap.Logger) (*red
 		// TODO: Placeholder for an error metric in the next PR
 		return nil, fmt.Errorf("failed to process block list: %w", err)
 	}
+	modeRules, err := makeModeRules(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to process mode rules: %w", err)
+	}
 
 	return &redaction{
 		allowList:      allowList,
 		ignoreList:     ignoreList,
 		blockRegexList: blockRegexList,
+		modeRules:      modeRules,
+		hashKey:        []byte(config.HashKey),
 		config:         config,
 		logger:         logger,
 	}, nil
@@ -155,7 +170,7 @@ func (s *redaction) processResourceMetric(ctx context.Context, rm pmetric.Resour
 }
 
 // processAttrs redacts the attributes of a resource span or a span
-func (s *redaction) processAttrs(_ context.Context, attributes pcommon.Map) {
+func (s *redaction) processAttrs(ctx context.Context, attributes pcommon.Map) {
 	// TODO: Use the context for recording metrics
 	var toDelete []string
 	var toBlock []string
@@ -187,20 +202,9 @@ func (s *redaction) processAttrs(_ context.Context, attributes pcommon.Map) {
 		}
 
 		// Mask any blocked values for the other attributes
-		strVal := value.Str()
-		var matched bool
-		for _, compiledRE := range s.blockRegexList {
-			match := compiledRE.MatchString(strVal)
-			if match {
-				if !matched {
-					matched = true
-					toBlock = append(toBlock, k)
-				}
-
-				maskedValue := compiledRE.ReplaceAllString(strVal, "****")
-				value.SetStr(maskedValue)
-				strVal = maskedValue
-			}
+		if maskedValue, matched := s.replaceBlocked(ctx, k, value.Str()); matched {
+			toBlock = append(toBlock, k)
+			value.SetStr(maskedValue)
 		}
 		return true
 	})
@@ -282,16 +286,22 @@ func makeIgnoreList(c *Config) map[string]string {
 	return ignoreList
 }
 
+// blockedValue is a compiled blocked value pattern
+type blockedValue struct {
+	pattern string
+	re      *regexp.Regexp
+}
+
 // makeBlockRegexList precompiles all the blocked regex patterns
-func makeBlockRegexList(_ context.Context, config *Config) (map[string]*regexp.Regexp, error) {
-	blockRegexList := make(map[string]*regexp.Regexp, len(config.BlockedValues))
+func makeBlockRegexList(_ context.Context, config *Config) ([]blockedValue, error) {
+	blockRegexList := make([]blockedValue, 0, len(config.BlockedValues))
 	for _, pattern := range config.BlockedValues {
 		re, err := regexp.Compile(pattern)
 		if err != nil {
 			// TODO: Placeholder for an error metric in the next PR
 			return nil, fmt.Errorf("error compiling regex in block list: %w", err)
 		}
-		blockRegexList[pattern] = re
+		blockRegexList = append(blockRegexList, blockedValue{pattern: pattern, re: re})
 	}
 	return blockRegexList, nil
 }
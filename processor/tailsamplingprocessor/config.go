@@ -6,6 +6,8 @@ package tailsamplingprocessor // import "github.com/open-telemetry/opentelemetry
 import (
 	"time"
 
+	"go.opentelemetry.io/collector/component"
+
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
 )
 
@@ -43,6 +45,12 @@ const (
 	// OTTLCondition sample traces which match user provided OpenTelemetry Transformation Language
 	// conditions.
 	OTTLCondition PolicyType = "ottl_condition"
+	// SolarwindsAPM samples traces the way Solarwinds APM agents do, using the settings
+	// provided by a solarwindsapmsettings extension.
+	SolarwindsAPM PolicyType = "solarwinds_apm"
+	// Adaptive samples traces with a probability adjusted to reach a target number of spans or
+	// traces per second.
+	Adaptive PolicyType = "adaptive"
 )
 
 // sharedPolicyCfg holds the common configuration to all policies that are used in derivative policy configurations
@@ -72,6 +80,10 @@ type sharedPolicyCfg struct {
 	BooleanAttributeCfg BooleanAttributeCfg `mapstructure:"boolean_attribute"`
 	// Configs for OTTL condition filter sampling policy evaluator
 	OTTLConditionCfg OTTLConditionCfg `mapstructure:"ottl_condition"`
+	// Configs for Solarwinds APM sampling policy evaluator.
+	SolarwindsAPMCfg SolarwindsAPMCfg `mapstructure:"solarwinds_apm"`
+	// Configs for adaptive sampling policy evaluator.
+	AdaptiveCfg AdaptiveCfg `mapstructure:"adaptive"`
 }
 
 // CompositeSubPolicyCfg holds the common configuration to all policies under composite policy.
@@ -220,12 +232,63 @@ type OTTLConditionCfg struct {
 	SpanEventConditions []string       `mapstructure:"spanevent"`
 }
 
+// SolarwindsAPMCfg holds the configurable settings to create a Solarwinds APM
+// sampling policy evaluator.
+type SolarwindsAPMCfg struct {
+	// SettingsExtension is the ID of the solarwindsapmsettings extension providing the sampling settings.
+	SettingsExtension component.ID `mapstructure:"settings_extension"`
+	// Service selects the settings of one of the services configured in the extension.
+	// If empty, the settings of the extension's first key are used.
+	Service string `mapstructure:"service"`
+}
+
+// AdaptiveCfg holds the configurable settings to create an adaptive sampling policy evaluator.
+type AdaptiveCfg struct {
+	// Key is the resource or span attribute between whose values the target is shared evenly.
+	// Defaults to service.name.
+	Key string `mapstructure:"key"`
+	// SpansPerSecond is the target number of sampled spans per second.
+	SpansPerSecond float64 `mapstructure:"spans_per_second"`
+	// TracesPerSecond is the target number of sampled traces per second.
+	// Exactly one of SpansPerSecond and TracesPerSecond must be set.
+	TracesPerSecond float64 `mapstructure:"traces_per_second"`
+	// AdjustmentInterval is how often the sampling probabilities are adjusted to the observed traffic.
+	// Defaults to 10s.
+	AdjustmentInterval time.Duration `mapstructure:"adjustment_interval"`
+}
+
 type DecisionCacheConfig struct {
 	// SampledCacheSize specifies the size of the cache that holds the sampled trace IDs
 	// This value will be the maximum amount of trace IDs that the cache can hold before overwriting previous IDs.
 	// For effective use, this value should be at least an order of magnitude higher than Config.NumTraces.
 	// If left as default 0, a no-op DecisionCache will be used.
 	SampledCacheSize int `mapstructure:"sampled_cache_size"`
+	// StorageID is the ID of a storage extension in which sampled decisions are also stored. When the
+	// storage is shared between collectors, e.g. with the Redis storage extension, a decision made by one
+	// of them applies to the late spans received by the others. The sampled decision cache is then used
+	// as a local cache in front of the storage.
+	StorageID *component.ID `mapstructure:"storage"`
+	// StorageTTL is how long the decisions are kept in the storage. The processor deletes the decisions it
+	// wrote once they expire, or once it wrote more of them than the larger of SampledCacheSize and
+	// Config.NumTraces, and expired decisions are ignored. Defaults to 1h.
+	StorageTTL time.Duration `mapstructure:"storage_ttl"`
+}
+
+// DecisionExplanationConfig configures how the sampling decisions are explained.
+type DecisionExplanationConfig struct {
+	// Attributes adds the final decision and the names of the policies which voted to sample the trace
+	// to the attributes of the spans of sampled traces.
+	Attributes bool `mapstructure:"attributes"`
+	// Log logs every decision at the debug level, including the traces which are not sampled, with the
+	// vote of each policy.
+	Log bool `mapstructure:"log"`
+}
+
+// PolicyReloadConfig configures the reload of the policies from a file.
+type PolicyReloadConfig struct {
+	// File is the path of a YAML file holding a `policies` list. When its content changes, its
+	// policies replace the ones in use without restarting the processor.
+	File string `mapstructure:"file"`
 }
 
 // Config holds the configuration for tail-based sampling.
@@ -244,4 +307,11 @@ type Config struct {
 	PolicyCfgs []PolicyCfg `mapstructure:"policies"`
 	// DecisionCache holds configuration for the decision cache(s)
 	DecisionCache DecisionCacheConfig `mapstructure:"decision_cache"`
+	// StorageID is the ID of a storage extension used to persist the traces kept in memory and
+	// their decisions, so that they survive a restart. If nil, nothing is persisted.
+	StorageID *component.ID `mapstructure:"storage"`
+	// DecisionExplanation configures how the sampling decisions are explained. Disabled by default.
+	DecisionExplanation DecisionExplanationConfig `mapstructure:"decision_explanation"`
+	// PolicyReload configures the reload of the policies from a file. Disabled by default.
+	PolicyReload PolicyReloadConfig `mapstructure:"policy_reload"`
 }
@@ -30,12 +30,17 @@ func TestLoadConfig(t *testing.T) {
 	require.NoError(t, err)
 	require.NoError(t, sub.Unmarshal(cfg))
 
+	storageID := component.MustNewID("file_storage")
+	decisionStorageID := component.MustNewID("redis_storage")
 	assert.Equal(t,
 		&Config{
 			DecisionWait:            10 * time.Second,
 			NumTraces:               100,
 			ExpectedNewTracesPerSec: 10,
-			DecisionCache:           DecisionCacheConfig{SampledCacheSize: 500},
+			DecisionCache:           DecisionCacheConfig{SampledCacheSize: 500, StorageID: &decisionStorageID, StorageTTL: 10 * time.Minute},
+			StorageID:               &storageID,
+			DecisionExplanation:     DecisionExplanationConfig{Attributes: true, Log: true},
+			PolicyReload:            PolicyReloadConfig{File: "/etc/otelcol/policies.yaml"},
 			PolicyCfgs: []PolicyCfg{
 				{
 					sharedPolicyCfg: sharedPolicyCfg{
@@ -117,6 +122,27 @@ func TestLoadConfig(t *testing.T) {
 						},
 					},
 				},
+				{
+					sharedPolicyCfg: sharedPolicyCfg{
+						Name: "test-policy-12",
+						Type: SolarwindsAPM,
+						SolarwindsAPMCfg: SolarwindsAPMCfg{
+							SettingsExtension: component.MustNewID("solarwindsapmsettings"),
+							Service:           "service1",
+						},
+					},
+				},
+				{
+					sharedPolicyCfg: sharedPolicyCfg{
+						Name: "test-policy-13",
+						Type: Adaptive,
+						AdaptiveCfg: AdaptiveCfg{
+							Key:                "service.name",
+							TracesPerSecond:    100,
+							AdjustmentInterval: 30 * time.Second,
+						},
+					},
+				},
 				{
 					sharedPolicyCfg: sharedPolicyCfg{
 						Name: "and-policy-1",
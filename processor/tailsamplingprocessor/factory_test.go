@@ -16,6 +16,7 @@ import (
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/processor/processortest"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storagetest"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/metadata"
 )
 
@@ -35,6 +36,8 @@ func TestCreateProcessor(t *testing.T) {
 	sub, err := cm.Sub(component.NewIDWithName(metadata.Type, "").String())
 	require.NoError(t, err)
 	require.NoError(t, sub.Unmarshal(cfg))
+	// the directory of the policies file must exist to be watched
+	cfg.(*Config).PolicyReload.File = filepath.Join(t.TempDir(), "policies.yaml")
 
 	params := processortest.NewNopSettings()
 	tp, err := factory.CreateTracesProcessor(context.Background(), params, cfg, consumertest.NewNop())
@@ -43,6 +46,24 @@ func TestCreateProcessor(t *testing.T) {
 
 	// this will cause the processor to properly initialize, so that we can later shutdown and
 	// have all the go routines cleanly shut down
-	assert.NoError(t, tp.Start(context.Background(), componenttest.NewNopHost()))
+	host := storagetest.NewStorageHost().
+		WithExtension(component.MustNewID("file_storage"), storagetest.NewInMemoryStorageExtension("file_storage")).
+		WithExtension(component.MustNewID("redis_storage"), storagetest.NewInMemoryStorageExtension("redis_storage")).
+		WithExtension(component.MustNewID("solarwindsapmsettings"), &nopSettingsExtension{})
+	assert.NoError(t, tp.Start(context.Background(), host))
 	assert.NoError(t, tp.Shutdown(context.Background()))
 }
+
+// nopSettingsExtension is a solarwindsapmsettings extension without settings.
+type nopSettingsExtension struct {
+	component.StartFunc
+	component.ShutdownFunc
+}
+
+func (*nopSettingsExtension) Settings(string) []byte {
+	return nil
+}
+
+func (*nopSettingsExtension) Subscribe(func(string, []byte)) func() {
+	return func() {}
+}
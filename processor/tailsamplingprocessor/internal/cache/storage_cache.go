@@ -0,0 +1,172 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package cache // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/cache"
+
+import (
+	"context"
+	"encoding/binary"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.uber.org/zap"
+)
+
+const (
+	decisionKeyPrefix = "decision/"
+	// decisionValueLen is the length of a stored decision: the decision, followed by its expiration
+	// time in nanoseconds since the epoch.
+	decisionValueLen = 9
+)
+
+// StorageDecisionCache is a Cache whose decisions are shared through a storage client. When the storage
+// is shared, e.g. a Redis storage extension, a decision made by one collector is visible to all the others.
+//
+// The storage is never accessed by Get, Put and Delete, which only use the local cache in front of the
+// storage, so that spans are not held by storage round trips. The decisions made by other collectors are
+// read in a batch by Lookup, and the decisions written by Put and Delete are stored in a batch by Flush.
+//
+// Stored decisions expire after a TTL. The keys written by the cache are deleted once they expire, or
+// once more than maxKeys keys were written after them, and the expired keys read by Lookup are deleted,
+// so that the storage does not grow without bound.
+type StorageDecisionCache struct {
+	client  storage.Client
+	local   Cache[bool]
+	ttl     time.Duration
+	maxKeys int
+	logger  *zap.Logger
+	now     func() time.Time
+
+	mu sync.Mutex
+	// pending holds the operations which are not flushed yet
+	pending []storage.Operation
+	// written holds the keys written by the cache, oldest first
+	written []writtenKey
+}
+
+type writtenKey struct {
+	key       string
+	expiresAt time.Time
+}
+
+var _ Cache[bool] = (*StorageDecisionCache)(nil)
+
+// NewStorageDecisionCache returns a cache storing decisions in the storage client for the TTL, in front
+// of which local is used. At most maxKeys keys written by the cache are kept in the storage. Storage
+// errors are logged and handled as cache misses.
+func NewStorageDecisionCache(client storage.Client, local Cache[bool], ttl time.Duration, maxKeys int, logger *zap.Logger) *StorageDecisionCache {
+	return &StorageDecisionCache{
+		client:  client,
+		local:   local,
+		ttl:     ttl,
+		maxKeys: maxKeys,
+		logger:  logger,
+		now:     time.Now,
+	}
+}
+
+// Get returns the decision of the trace from the local cache.
+func (c *StorageDecisionCache) Get(id pcommon.TraceID) (bool, bool) {
+	return c.local.Get(id)
+}
+
+// Put caches the decision of the trace, and stores it on the next Flush.
+func (c *StorageDecisionCache) Put(id pcommon.TraceID, v bool) {
+	c.local.Put(id, v)
+	key := decisionKey(id)
+	expiresAt := c.now().Add(c.ttl)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pending = append(c.pending, storage.SetOperation(key, encodeDecision(v, expiresAt)))
+	c.written = append(c.written, writtenKey{key: key, expiresAt: expiresAt})
+}
+
+// Delete removes the decision of the trace from the local cache, and from the storage on the next Flush.
+func (c *StorageDecisionCache) Delete(id pcommon.TraceID) {
+	c.local.Delete(id)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pending = append(c.pending, storage.DeleteOperation(decisionKey(id)))
+}
+
+// Lookup reads in a single batch the stored decisions of the traces which are not in the local cache,
+// and adds them to the local cache.
+func (c *StorageDecisionCache) Lookup(ctx context.Context, ids []pcommon.TraceID) {
+	var missing []pcommon.TraceID
+	var ops []storage.Operation
+	for _, id := range ids {
+		if _, ok := c.local.Get(id); !ok {
+			missing = append(missing, id)
+			ops = append(ops, storage.GetOperation(decisionKey(id)))
+		}
+	}
+	if len(ops) == 0 {
+		return
+	}
+	if err := c.client.Batch(ctx, ops...); err != nil {
+		c.logger.Debug("Failed to get decisions from storage", zap.Int("traces", len(ops)), zap.Error(err))
+		return
+	}
+
+	now := c.now()
+	var expired []storage.Operation
+	for i, op := range ops {
+		v, expiresAt, ok := decodeDecision(op.Value)
+		if !ok {
+			continue
+		}
+		if !now.Before(expiresAt) {
+			expired = append(expired, storage.DeleteOperation(op.Key))
+			continue
+		}
+		c.local.Put(missing[i], v)
+	}
+	if len(expired) > 0 {
+		c.mu.Lock()
+		c.pending = append(c.pending, expired...)
+		c.mu.Unlock()
+	}
+}
+
+// Flush stores the decisions put since the last flush, and deletes the expired and the oldest keys
+// written by the cache.
+func (c *StorageDecisionCache) Flush(ctx context.Context) {
+	now := c.now()
+	c.mu.Lock()
+	for len(c.written) > 0 && (len(c.written) > c.maxKeys || !now.Before(c.written[0].expiresAt)) {
+		c.pending = append(c.pending, storage.DeleteOperation(c.written[0].key))
+		c.written = c.written[1:]
+	}
+	ops := c.pending
+	c.pending = nil
+	c.mu.Unlock()
+
+	if len(ops) == 0 {
+		return
+	}
+	if err := c.client.Batch(ctx, ops...); err != nil {
+		c.logger.Warn("Failed to store decisions in storage", zap.Int("operations", len(ops)), zap.Error(err))
+	}
+}
+
+func decisionKey(id pcommon.TraceID) string {
+	return decisionKeyPrefix + id.String()
+}
+
+func encodeDecision(v bool, expiresAt time.Time) []byte {
+	data := make([]byte, decisionValueLen)
+	if v {
+		data[0] = 1
+	}
+	binary.BigEndian.PutUint64(data[1:], uint64(expiresAt.UnixNano()))
+	return data
+}
+
+func decodeDecision(data []byte) (bool, time.Time, bool) {
+	if len(data) != decisionValueLen {
+		return false, time.Time{}, false
+	}
+	return data[0] == 1, time.Unix(0, int64(binary.BigEndian.Uint64(data[1:]))), true
+}
@@ -0,0 +1,137 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storagetest"
+)
+
+func TestStorageCacheSharedDecision(t *testing.T) {
+	client := storagetest.NewInMemoryClient(component.KindProcessor, component.MustNewID("tail_sampling"), "decisions")
+	local1, err := NewLRUDecisionCache[bool](2)
+	require.NoError(t, err)
+	local2, err := NewLRUDecisionCache[bool](2)
+	require.NoError(t, err)
+	c1 := NewStorageDecisionCache(client, local1, time.Hour, 10, zap.NewNop())
+	c2 := NewStorageDecisionCache(client, local2, time.Hour, 10, zap.NewNop())
+
+	id, err := traceIDFromHex("12341234123412341234123412341234")
+	require.NoError(t, err)
+
+	c2.Lookup(context.Background(), []pcommon.TraceID{id})
+	_, ok := c2.Get(id)
+	assert.False(t, ok)
+
+	// the decision is only stored once flushed
+	c1.Put(id, true)
+	c2.Lookup(context.Background(), []pcommon.TraceID{id})
+	_, ok = c2.Get(id)
+	assert.False(t, ok)
+
+	c1.Flush(context.Background())
+	_, ok = c2.Get(id)
+	assert.False(t, ok, "the storage is only read by lookups")
+	c2.Lookup(context.Background(), []pcommon.TraceID{id})
+	v, ok := c2.Get(id)
+	assert.True(t, ok)
+	assert.True(t, v)
+
+	// the decision is now known locally
+	v, ok = local2.Get(id)
+	assert.True(t, ok)
+	assert.True(t, v)
+
+	c1.Delete(id)
+	c1.Flush(context.Background())
+	c3 := NewStorageDecisionCache(client, NewNopDecisionCache[bool](), time.Hour, 10, zap.NewNop())
+	c3.Lookup(context.Background(), []pcommon.TraceID{id})
+	_, ok = c3.Get(id)
+	assert.False(t, ok)
+}
+
+func TestStorageCacheExpiration(t *testing.T) {
+	client := storagetest.NewInMemoryClient(component.KindProcessor, component.MustNewID("tail_sampling"), "decisions")
+	local, err := NewLRUDecisionCache[bool](10)
+	require.NoError(t, err)
+	now := time.Now()
+	c := NewStorageDecisionCache(client, local, time.Minute, 2, zap.NewNop())
+	c.now = func() time.Time { return now }
+
+	id1, err := traceIDFromHex("00000000000000000000000000000001")
+	require.NoError(t, err)
+	id2, err := traceIDFromHex("00000000000000000000000000000002")
+	require.NoError(t, err)
+	id3, err := traceIDFromHex("00000000000000000000000000000003")
+	require.NoError(t, err)
+	stored := func(id pcommon.TraceID) bool {
+		data, err := client.Get(context.Background(), decisionKey(id))
+		require.NoError(t, err)
+		return data != nil
+	}
+
+	// the oldest keys are deleted once more than maxKeys keys are written
+	c.Put(id1, true)
+	c.Put(id2, true)
+	c.Put(id3, true)
+	c.Flush(context.Background())
+	assert.False(t, stored(id1))
+	assert.True(t, stored(id2))
+	assert.True(t, stored(id3))
+
+	// the keys are deleted once expired
+	now = now.Add(time.Minute)
+	c.Flush(context.Background())
+	assert.False(t, stored(id2))
+	assert.False(t, stored(id3))
+
+	// expired keys written by another cache are ignored and deleted by lookups
+	other := NewStorageDecisionCache(client, NewNopDecisionCache[bool](), time.Minute, 2, zap.NewNop())
+	other.now = func() time.Time { return now.Add(-time.Minute) }
+	other.Put(id1, true)
+	other.Flush(context.Background())
+	require.True(t, stored(id1))
+	reader := NewStorageDecisionCache(client, NewNopDecisionCache[bool](), time.Minute, 2, zap.NewNop())
+	reader.now = func() time.Time { return now }
+	reader.Lookup(context.Background(), []pcommon.TraceID{id1})
+	_, ok := reader.Get(id1)
+	assert.False(t, ok)
+	reader.Flush(context.Background())
+	assert.False(t, stored(id1))
+}
+
+func TestStorageCacheErrors(t *testing.T) {
+	client := storagetest.NewInMemoryClient(component.KindProcessor, component.MustNewID("tail_sampling"), "decisions")
+	c := NewStorageDecisionCache(client, NewNopDecisionCache[bool](), time.Hour, 10, zap.NewNop())
+	id, err := traceIDFromHex("12341234123412341234123412341234")
+	require.NoError(t, err)
+	local, err := NewLRUDecisionCache[bool](2)
+	require.NoError(t, err)
+	reader := NewStorageDecisionCache(client, local, time.Hour, 10, zap.NewNop())
+
+	c.Put(id, false)
+	c.Flush(context.Background())
+	reader.Lookup(context.Background(), []pcommon.TraceID{id})
+	v, ok := reader.Get(id)
+	assert.True(t, ok)
+	assert.False(t, v)
+
+	require.NoError(t, client.Close(context.Background()))
+	id2, err := traceIDFromHex("12341234123412341234123412341235")
+	require.NoError(t, err)
+	c.Put(id2, true)
+	c.Flush(context.Background())
+	reader.Lookup(context.Background(), []pcommon.TraceID{id2})
+	_, ok = reader.Get(id2)
+	assert.False(t, ok)
+}
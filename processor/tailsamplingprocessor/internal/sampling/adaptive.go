@@ -0,0 +1,270 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package sampling // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/sampling"
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+
+	otelsampling "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/sampling"
+)
+
+const (
+	defaultAdaptiveKey                = "service.name"
+	defaultAdaptiveAdjustmentInterval = 10 * time.Second
+
+	// maxAdaptiveKeys is the number of key values tracked on their own, the traffic of the
+	// other values is tracked together.
+	maxAdaptiveKeys = 1000
+)
+
+type adaptiveSampler struct {
+	logger     *zap.Logger
+	key        string
+	targetRate float64
+	countSpans bool
+	interval   time.Duration
+	now        func() time.Time
+
+	mu          sync.Mutex
+	windowStart time.Time
+	keys        map[string]*adaptiveKey
+	// overflow holds the traffic of the key values seen once keys is full.
+	overflow adaptiveKey
+}
+
+// adaptiveKey holds the traffic observed for a key value during the current window
+// and the threshold computed at the end of the previous one.
+type adaptiveKey struct {
+	observed  float64
+	threshold otelsampling.Threshold
+}
+
+var (
+	_ PolicyEvaluator     = (*adaptiveSampler)(nil)
+	_ consistentEvaluator = (*adaptiveSampler)(nil)
+)
+
+// NewAdaptive creates a policy evaluator sampling traces with a probability adjusted every
+// adjustmentInterval, so that the traces or spans sampled per second approach the target. The
+// target is shared evenly between the values of the given resource or span attribute key.
+// Exactly one of spansPerSecond and tracesPerSecond must be set.
+func NewAdaptive(settings component.TelemetrySettings, key string, spansPerSecond, tracesPerSecond float64, adjustmentInterval time.Duration) (PolicyEvaluator, error) {
+	if (spansPerSecond > 0) == (tracesPerSecond > 0) {
+		return nil, errors.New("exactly one of spans_per_second and traces_per_second must be greater than 0")
+	}
+	if adjustmentInterval < 0 {
+		return nil, errors.New("adjustment_interval must not be negative")
+	}
+	if key == "" {
+		key = defaultAdaptiveKey
+	}
+	if adjustmentInterval == 0 {
+		adjustmentInterval = defaultAdaptiveAdjustmentInterval
+	}
+
+	s := &adaptiveSampler{
+		logger:     settings.Logger,
+		key:        key,
+		targetRate: tracesPerSecond,
+		countSpans: spansPerSecond > 0,
+		interval:   adjustmentInterval,
+		now:        time.Now,
+		keys:       make(map[string]*adaptiveKey),
+		overflow:   adaptiveKey{threshold: otelsampling.AlwaysSampleThreshold},
+	}
+	if s.countSpans {
+		s.targetRate = spansPerSecond
+	}
+	return s, nil
+}
+
+// Evaluate looks at the trace data and returns a corresponding SamplingDecision. When the trace
+// is sampled, the threshold used is recorded in the trace, to be written by WriteSampledThreshold
+// if no other policy sampled the trace.
+func (s *adaptiveSampler) Evaluate(_ context.Context, traceID pcommon.TraceID, trace *TraceData) (Decision, error) {
+	trace.Lock()
+	defer trace.Unlock()
+	batches := trace.ReceivedBatches
+
+	observed := 1.0
+	if s.countSpans {
+		observed = float64(trace.SpanCount.Load())
+	}
+	threshold := s.observe(attributeValue(batches, s.key), observed)
+
+	if !threshold.ShouldSample(traceRandomness(traceID, batches)) {
+		return NotSampled, nil
+	}
+	trace.recordSampledThreshold(threshold)
+	return Sampled, nil
+}
+
+func (*adaptiveSampler) consistentProbability() {}
+
+// observe records the traffic of the key value and returns its current threshold.
+func (s *adaptiveSampler) observe(value string, observed float64) otelsampling.Threshold {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if s.windowStart.IsZero() {
+		s.windowStart = now
+	}
+	if elapsed := now.Sub(s.windowStart); elapsed >= s.interval {
+		s.adjust(elapsed)
+		s.windowStart = now
+	}
+
+	k, ok := s.keys[value]
+	switch {
+	case ok:
+	case len(s.keys) < maxAdaptiveKeys:
+		k = &adaptiveKey{threshold: otelsampling.AlwaysSampleThreshold}
+		s.keys[value] = k
+	default:
+		k = &s.overflow
+	}
+	k.observed += observed
+	return k.threshold
+}
+
+// adjust computes the threshold of every key value seen during the elapsed window, and forgets
+// the others. The target rate is shared evenly between the remaining key values, the values
+// tracked together getting a single share.
+func (s *adaptiveSampler) adjust(elapsed time.Duration) {
+	for value, k := range s.keys {
+		if k.observed == 0 {
+			delete(s.keys, value)
+		}
+	}
+	active := len(s.keys)
+	if s.overflow.observed > 0 {
+		active++
+	} else {
+		s.overflow.threshold = otelsampling.AlwaysSampleThreshold
+	}
+	if active == 0 {
+		return
+	}
+
+	share := s.targetRate / float64(active)
+	for value, k := range s.keys {
+		s.adjustKey(value, k, share, elapsed)
+	}
+	if s.overflow.observed > 0 {
+		s.adjustKey("", &s.overflow, share, elapsed)
+	}
+}
+
+// adjustKey computes the threshold of a key value from its share of the target rate, and resets
+// its observed traffic. The previous threshold is kept if the new one cannot be computed.
+func (s *adaptiveSampler) adjustKey(value string, k *adaptiveKey, share float64, elapsed time.Duration) {
+	rate := k.observed / elapsed.Seconds()
+	k.observed = 0
+	probability := math.Max(math.Min(1, share/rate), otelsampling.MinSamplingProbability)
+	threshold, err := otelsampling.ProbabilityToThreshold(probability)
+	if err != nil {
+		s.logger.Debug("Unable to compute threshold", zap.String("value", value), zap.Float64("probability", probability), zap.Error(err))
+		return
+	}
+	k.threshold = threshold
+}
+
+// WriteSampledThreshold writes the threshold recorded by the consistent probability policies in the
+// `th` value of the OpenTelemetry trace state of all spans, unless they already carry a higher
+// threshold. It must only be called when no other policy sampled the trace, as the trace is then
+// sampled with a probability of 1.
+func WriteSampledThreshold(logger *zap.Logger, trace *TraceData) {
+	trace.Lock()
+	defer trace.Unlock()
+	if trace.SampledThreshold == nil {
+		return
+	}
+	threshold := *trace.SampledThreshold
+	forEachSpan(trace.ReceivedBatches, func(span ptrace.Span) {
+		w3c, err := otelsampling.NewW3CTraceState(span.TraceState().AsRaw())
+		if err != nil {
+			logger.Debug("Invalid trace state", zap.Error(err))
+			return
+		}
+		otts := w3c.OTelValue()
+		if existing, ok := otts.TValueThreshold(); ok && !otelsampling.ThresholdGreater(threshold, existing) {
+			// The span was already sampled with a lower probability.
+			return
+		}
+		if err := otts.UpdateTValueWithSampling(threshold); err != nil {
+			logger.Debug("Unable to update trace state threshold", zap.Error(err))
+			return
+		}
+		var sb strings.Builder
+		if err := w3c.Serialize(&sb); err != nil {
+			logger.Debug("Unable to serialize trace state", zap.Error(err))
+			return
+		}
+		span.TraceState().FromRaw(sb.String())
+	})
+}
+
+// attributeValue returns the string value of the first resource or span attribute with the given key.
+func attributeValue(td ptrace.Traces, key string) string {
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		rs := td.ResourceSpans().At(i)
+		if v, ok := rs.Resource().Attributes().Get(key); ok {
+			return v.AsString()
+		}
+		ilss := rs.ScopeSpans()
+		for j := 0; j < ilss.Len(); j++ {
+			spans := ilss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				if v, ok := spans.At(k).Attributes().Get(key); ok {
+					return v.AsString()
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// traceRandomness returns the explicit randomness (`rv`) of the first span having one,
+// or the randomness of the trace ID.
+func traceRandomness(traceID pcommon.TraceID, td ptrace.Traces) otelsampling.Randomness {
+	rnd := otelsampling.TraceIDToRandomness(traceID)
+	found := false
+	forEachSpan(td, func(span ptrace.Span) {
+		if found {
+			return
+		}
+		w3c, err := otelsampling.NewW3CTraceState(span.TraceState().AsRaw())
+		if err != nil {
+			return
+		}
+		if r, ok := w3c.OTelValue().RValueRandomness(); ok {
+			rnd = r
+			found = true
+		}
+	})
+	return rnd
+}
+
+func forEachSpan(td ptrace.Traces, f func(span ptrace.Span)) {
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		ilss := td.ResourceSpans().At(i).ScopeSpans()
+		for j := 0; j < ilss.Len(); j++ {
+			spans := ilss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				f(spans.At(k))
+			}
+		}
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package sampling
+
+import (
+	"context"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+
+	otelsampling "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/sampling"
+)
+
+var (
+	// lowRandomnessTraceID is only sampled with a probability of 1.
+	lowRandomnessTraceID = pcommon.TraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8})
+	// highRandomnessTraceID is sampled with any probability.
+	highRandomnessTraceID = pcommon.TraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 0, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff})
+)
+
+func newAdaptiveTrace(service string, traceState string, spanCount int) *TraceData {
+	traces := ptrace.NewTraces()
+	rs := traces.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr("service.name", service)
+	spans := rs.ScopeSpans().AppendEmpty().Spans()
+	for i := 0; i < spanCount; i++ {
+		spans.AppendEmpty().TraceState().FromRaw(traceState)
+	}
+	count := &atomic.Int64{}
+	count.Store(int64(spanCount))
+	return &TraceData{ReceivedBatches: traces, SpanCount: count}
+}
+
+func newTestAdaptive(t *testing.T, spansPerSecond, tracesPerSecond float64, now *time.Time) *adaptiveSampler {
+	evaluator, err := NewAdaptive(componenttest.NewNopTelemetrySettings(), "", spansPerSecond, tracesPerSecond, 10*time.Second)
+	require.NoError(t, err)
+	s := evaluator.(*adaptiveSampler)
+	s.now = func() time.Time { return *now }
+	return s
+}
+
+func TestAdaptiveInvalidConfig(t *testing.T) {
+	_, err := NewAdaptive(componenttest.NewNopTelemetrySettings(), "", 0, 0, 0)
+	assert.Error(t, err)
+	_, err = NewAdaptive(componenttest.NewNopTelemetrySettings(), "", 1, 1, 0)
+	assert.Error(t, err)
+	_, err = NewAdaptive(componenttest.NewNopTelemetrySettings(), "", 1, 0, -time.Second)
+	assert.Error(t, err)
+}
+
+func TestAdaptiveSamplesEverythingInitially(t *testing.T) {
+	now := time.Unix(1000, 0)
+	s := newTestAdaptive(t, 0, 1, &now)
+
+	trace := newAdaptiveTrace("svc", "", 2)
+	decision, err := s.Evaluate(context.Background(), lowRandomnessTraceID, trace)
+	require.NoError(t, err)
+	assert.Equal(t, Sampled, decision)
+	assert.Empty(t, trace.ReceivedBatches.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(1).TraceState().AsRaw())
+	WriteSampledThreshold(zap.NewNop(), trace)
+	assert.Equal(t, "ot=th:0", trace.ReceivedBatches.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(1).TraceState().AsRaw())
+}
+
+func TestAdaptiveAdjustsProbability(t *testing.T) {
+	now := time.Unix(1000, 0)
+	s := newTestAdaptive(t, 0, 2, &now)
+
+	// 10 traces per second for each of two services, for a target of 2 traces per second.
+	for i := 0; i < 100; i++ {
+		for _, service := range []string{"a", "b"} {
+			decision, err := s.Evaluate(context.Background(), lowRandomnessTraceID, newAdaptiveTrace(service, "", 1))
+			require.NoError(t, err)
+			assert.Equal(t, Sampled, decision)
+		}
+	}
+	now = now.Add(10 * time.Second)
+
+	trace := newAdaptiveTrace("a", "", 1)
+	decision, err := s.Evaluate(context.Background(), lowRandomnessTraceID, trace)
+	require.NoError(t, err)
+	assert.Equal(t, NotSampled, decision)
+	assert.Empty(t, trace.ReceivedBatches.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).TraceState().AsRaw())
+
+	trace = newAdaptiveTrace("b", "", 1)
+	decision, err = s.Evaluate(context.Background(), highRandomnessTraceID, trace)
+	require.NoError(t, err)
+	assert.Equal(t, Sampled, decision)
+	WriteSampledThreshold(zap.NewNop(), trace)
+	w3c, err := otelsampling.NewW3CTraceState(trace.ReceivedBatches.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).TraceState().AsRaw())
+	require.NoError(t, err)
+	assert.InDelta(t, 10, w3c.OTelValue().AdjustedCount(), 0.001)
+
+	// an unknown service is sampled until the next adjustment
+	decision, err = s.Evaluate(context.Background(), lowRandomnessTraceID, newAdaptiveTrace("c", "", 1))
+	require.NoError(t, err)
+	assert.Equal(t, Sampled, decision)
+}
+
+func TestAdaptiveSpansPerSecond(t *testing.T) {
+	now := time.Unix(1000, 0)
+	s := newTestAdaptive(t, 50, 0, &now)
+
+	// 100 spans per second
+	for i := 0; i < 100; i++ {
+		_, err := s.Evaluate(context.Background(), lowRandomnessTraceID, newAdaptiveTrace("a", "", 10))
+		require.NoError(t, err)
+	}
+	now = now.Add(10 * time.Second)
+	threshold := s.observe("a", 0)
+	assert.InDelta(t, 0.5, threshold.Probability(), 0.001)
+}
+
+func TestAdaptiveKeepsLowerProbability(t *testing.T) {
+	now := time.Unix(1000, 0)
+	s := newTestAdaptive(t, 0, 1, &now)
+
+	// the spans were already sampled with a probability of 1/4
+	trace := newAdaptiveTrace("a", "ot=th:c;rv:ffffffffffffff", 1)
+	decision, err := s.Evaluate(context.Background(), lowRandomnessTraceID, trace)
+	require.NoError(t, err)
+	assert.Equal(t, Sampled, decision)
+	WriteSampledThreshold(zap.NewNop(), trace)
+	assert.Equal(t, "ot=th:c;rv:ffffffffffffff", trace.ReceivedBatches.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).TraceState().AsRaw())
+}
+
+func TestAdaptiveForgetsIdleKeys(t *testing.T) {
+	now := time.Unix(1000, 0)
+	s := newTestAdaptive(t, 0, 1, &now)
+
+	_, err := s.Evaluate(context.Background(), lowRandomnessTraceID, newAdaptiveTrace("a", "", 1))
+	require.NoError(t, err)
+	now = now.Add(10 * time.Second)
+	_, err = s.Evaluate(context.Background(), lowRandomnessTraceID, newAdaptiveTrace("b", "", 1))
+	require.NoError(t, err)
+	now = now.Add(10 * time.Second)
+	_, err = s.Evaluate(context.Background(), lowRandomnessTraceID, newAdaptiveTrace("b", "", 1))
+	require.NoError(t, err)
+
+	assert.NotContains(t, s.keys, "a")
+	assert.Contains(t, s.keys, "b")
+}
+
+func TestAdaptiveLimitsTrackedKeys(t *testing.T) {
+	now := time.Unix(1000, 0)
+	s := newTestAdaptive(t, 0, 2, &now)
+
+	// The values beyond the limit share the same threshold.
+	for i := 0; i < maxAdaptiveKeys+10; i++ {
+		_, err := s.Evaluate(context.Background(), lowRandomnessTraceID, newAdaptiveTrace(strconv.Itoa(i), "", 1))
+		require.NoError(t, err)
+	}
+	assert.Len(t, s.keys, maxAdaptiveKeys)
+	assert.InDelta(t, 10, s.overflow.observed, 0.001)
+
+	now = now.Add(10 * time.Second)
+	threshold := s.observe("overflowing", 0)
+	assert.Equal(t, s.overflow.threshold, threshold)
+	assert.Zero(t, s.overflow.observed)
+	assert.InDelta(t, 2.0/(maxAdaptiveKeys+1), threshold.Probability(), 0.0001)
+}
+
+func TestWriteSampledThresholdKeepsLowest(t *testing.T) {
+	trace := newAdaptiveTrace("a", "", 1)
+	trace.recordSampledThreshold(mustThreshold(t, 0.25))
+	trace.recordSampledThreshold(mustThreshold(t, 0.5))
+	trace.recordSampledThreshold(mustThreshold(t, 0.125))
+	WriteSampledThreshold(zap.NewNop(), trace)
+	assert.Equal(t, "ot=th:8", trace.ReceivedBatches.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).TraceState().AsRaw())
+}
+
+func mustThreshold(t *testing.T, probability float64) otelsampling.Threshold {
+	threshold, err := otelsampling.ProbabilityToThreshold(probability)
+	require.NoError(t, err)
+	return threshold
+}
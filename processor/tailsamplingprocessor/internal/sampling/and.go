@@ -5,7 +5,9 @@ package sampling // import "github.com/open-telemetry/opentelemetry-collector-co
 
 import (
 	"context"
+	"errors"
 
+	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.uber.org/zap"
 )
@@ -27,6 +29,25 @@ func NewAnd(
 	}
 }
 
+// Start starts the sub-policies.
+func (c *And) Start(ctx context.Context, host component.Host) error {
+	for _, sub := range c.subpolicies {
+		if err := StartEvaluator(ctx, host, sub); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Shutdown shuts down the sub-policies.
+func (c *And) Shutdown(ctx context.Context) error {
+	var errs error
+	for _, sub := range c.subpolicies {
+		errs = errors.Join(errs, ShutdownEvaluator(ctx, sub))
+	}
+	return errs
+}
+
 // Evaluate looks at the trace data and returns a corresponding SamplingDecision.
 func (c *And) Evaluate(ctx context.Context, traceID pcommon.TraceID, trace *TraceData) (Decision, error) {
 	// The policy iterates over all sub-policies and returns Sampled if all sub-policies returned a Sampled Decision.
@@ -5,7 +5,9 @@ package sampling // import "github.com/open-telemetry/opentelemetry-collector-co
 
 import (
 	"context"
+	"errors"
 
+	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.uber.org/zap"
 )
@@ -75,6 +77,25 @@ func NewComposite(
 	}
 }
 
+// Start starts the sub-policies.
+func (c *Composite) Start(ctx context.Context, host component.Host) error {
+	for _, sub := range c.subpolicies {
+		if err := StartEvaluator(ctx, host, sub.evaluator); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Shutdown shuts down the sub-policies.
+func (c *Composite) Shutdown(ctx context.Context) error {
+	var errs error
+	for _, sub := range c.subpolicies {
+		errs = errors.Join(errs, ShutdownEvaluator(ctx, sub.evaluator))
+	}
+	return errs
+}
+
 // Evaluate looks at the trace data and returns a corresponding SamplingDecision.
 func (c *Composite) Evaluate(ctx context.Context, traceID pcommon.TraceID, trace *TraceData) (Decision, error) {
 	// Rate limiting works by counting spans that are sampled during each 1 second
@@ -9,8 +9,11 @@ import (
 	"sync/atomic"
 	"time"
 
+	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	otelsampling "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/sampling"
 )
 
 // TraceData stores the sampling related trace data.
@@ -26,6 +29,9 @@ type TraceData struct {
 	ReceivedBatches ptrace.Traces
 	// FinalDecision.
 	FinalDecision Decision
+	// SampledThreshold is the lowest threshold with which consistent probability policies sampled
+	// the trace, nil if none of them did.
+	SampledThreshold *otelsampling.Threshold
 }
 
 // Decision gives the status of sampling decision.
@@ -61,3 +67,39 @@ type PolicyEvaluator interface {
 	// Evaluate looks at the trace data and returns a corresponding SamplingDecision.
 	Evaluate(ctx context.Context, traceID pcommon.TraceID, trace *TraceData) (Decision, error)
 }
+
+// StartEvaluator starts the evaluator if it needs to access the host, e.g. to look up an extension.
+func StartEvaluator(ctx context.Context, host component.Host, evaluator PolicyEvaluator) error {
+	if c, ok := evaluator.(component.Component); ok {
+		return c.Start(ctx, host)
+	}
+	return nil
+}
+
+// consistentEvaluator is implemented by the evaluators sampling traces with a consistent probability,
+// which record the threshold they sampled a trace with in its SampledThreshold.
+type consistentEvaluator interface {
+	consistentProbability()
+}
+
+// IsConsistent returns whether the evaluator samples traces with a consistent probability.
+func IsConsistent(evaluator PolicyEvaluator) bool {
+	_, ok := evaluator.(consistentEvaluator)
+	return ok
+}
+
+// recordSampledThreshold records the threshold a consistent probability policy sampled the trace with.
+// The trace is sampled by a policy or another, so the lowest threshold is kept. The caller holds the lock.
+func (td *TraceData) recordSampledThreshold(threshold otelsampling.Threshold) {
+	if td.SampledThreshold == nil || otelsampling.ThresholdLessThan(threshold, *td.SampledThreshold) {
+		td.SampledThreshold = &threshold
+	}
+}
+
+// ShutdownEvaluator shuts down the evaluator if it was started by StartEvaluator.
+func ShutdownEvaluator(ctx context.Context, evaluator PolicyEvaluator) error {
+	if c, ok := evaluator.(component.Component); ok {
+		return c.Shutdown(ctx)
+	}
+	return nil
+}
@@ -0,0 +1,279 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package sampling // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/sampling"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	tracesdk "go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+const (
+	// swMaxSampleRate is the sample rate meaning "sample every trace".
+	swMaxSampleRate = 1000000
+
+	swFlagSampleStart         = "SAMPLE_START"
+	swFlagSampleThroughAlways = "SAMPLE_THROUGH_ALWAYS"
+	swFlagTriggerTrace        = "TRIGGER_TRACE"
+
+	// swTraceStateKey is the trace_state key holding `<span id>-<flags>` set by Solarwinds APM agents.
+	swTraceStateKey = "sw"
+	// swOptionsResponseKey is the trace_state key holding the agent's answer to the X-Trace-Options header.
+	swOptionsResponseKey = "xtrace_options_response"
+	// swTriggeredTraceAttribute is the span attribute set by Solarwinds APM agents on trigger traces.
+	swTriggeredTraceAttribute = "TriggeredTrace"
+)
+
+// swSettingsProvider is the part of the solarwindsapmsettings extension used by the policy. It is declared here so
+// that the processor does not depend on the extension module.
+type swSettingsProvider interface {
+	// Settings returns the latest JSON encoded settings of the service, or nil if there are none.
+	Settings(service string) []byte
+	// Subscribe registers a listener called whenever the settings of a service change, and returns a function
+	// removing it.
+	Subscribe(listener func(service string, settings []byte)) func()
+}
+
+// swSetting is a setting as encoded by the solarwindsapmsettings extension.
+type swSetting struct {
+	Flags     string             `json:"flags"`
+	Value     int64              `json:"value"`
+	Arguments map[string]float64 `json:"arguments"`
+}
+
+type solarwindsAPM struct {
+	logger      *zap.Logger
+	extensionID component.ID
+	service     string
+	random      func() float64
+	now         func() time.Time
+	unsubscribe func()
+
+	mu sync.Mutex
+	// applied is the content of the settings currently applied.
+	applied        []byte
+	flags          map[string]bool
+	sampleRate     int64
+	bucket         *tokenBucket
+	relaxedTrigger *tokenBucket
+	strictTrigger  *tokenBucket
+}
+
+var _ PolicyEvaluator = (*solarwindsAPM)(nil)
+var _ component.Component = (*solarwindsAPM)(nil)
+
+// NewSolarwindsAPM creates a policy evaluator that samples traces the same way Solarwinds APM agents do,
+// using the settings provided by the solarwindsapmsettings extension with the given ID. Until settings
+// are available, no trace is sampled.
+func NewSolarwindsAPM(settings component.TelemetrySettings, extensionID component.ID, service string) PolicyEvaluator {
+	return &solarwindsAPM{
+		logger:      settings.Logger,
+		extensionID: extensionID,
+		service:     service,
+		random:      rand.Float64,
+		now:         time.Now,
+	}
+}
+
+// Start subscribes to the settings of the solarwindsapmsettings extension.
+func (s *solarwindsAPM) Start(_ context.Context, host component.Host) error {
+	ext, ok := host.GetExtensions()[s.extensionID]
+	if !ok {
+		return fmt.Errorf("extension %q not found", s.extensionID)
+	}
+	provider, ok := ext.(swSettingsProvider)
+	if !ok {
+		return fmt.Errorf("extension %q is not a solarwindsapmsettings extension", s.extensionID)
+	}
+	s.unsubscribe = provider.Subscribe(func(service string, content []byte) {
+		// The settings of other services are ignored. An empty service is the extension's first service,
+		// whose name is not known here: its settings are read again, and only applied if they changed.
+		if s.service != "" && service != s.service {
+			return
+		}
+		if s.service == "" {
+			content = provider.Settings("")
+		}
+		if err := s.update(content); err != nil {
+			s.logger.Warn("unable to apply Solarwinds APM settings", zap.Error(err))
+		}
+	})
+	return nil
+}
+
+// Shutdown unsubscribes from the settings of the solarwindsapmsettings extension.
+func (s *solarwindsAPM) Shutdown(context.Context) error {
+	if s.unsubscribe != nil {
+		s.unsubscribe()
+	}
+	return nil
+}
+
+// update applies JSON encoded settings, unless they are already applied. Nil settings disable sampling.
+func (s *solarwindsAPM) update(content []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if bytes.Equal(content, s.applied) {
+		return nil
+	}
+	var settings []swSetting
+	if content != nil {
+		if err := json.Unmarshal(content, &settings); err != nil {
+			return err
+		}
+	}
+	s.applied = content
+	if len(settings) == 0 {
+		s.flags = nil
+		return nil
+	}
+	setting := settings[0]
+	s.flags = make(map[string]bool)
+	for _, flag := range strings.Split(setting.Flags, ",") {
+		s.flags[strings.TrimSpace(flag)] = true
+	}
+	s.sampleRate = setting.Value
+	now := s.now()
+	s.bucket = s.bucket.update(setting.Arguments["BucketCapacity"], setting.Arguments["BucketRate"], now)
+	s.relaxedTrigger = s.relaxedTrigger.update(setting.Arguments["TriggerRelaxedBucketCapacity"], setting.Arguments["TriggerRelaxedBucketRate"], now)
+	s.strictTrigger = s.strictTrigger.update(setting.Arguments["TriggerStrictBucketCapacity"], setting.Arguments["TriggerStrictBucketRate"], now)
+	return nil
+}
+
+// Evaluate looks at the trace data and returns a corresponding SamplingDecision.
+func (s *solarwindsAPM) Evaluate(_ context.Context, _ pcommon.TraceID, trace *TraceData) (Decision, error) {
+	trace.Lock()
+	entry, isRoot, found := entrySpan(trace.ReceivedBatches)
+	var traceState tracesdk.TraceState
+	var triggered bool
+	if found {
+		traceState, _ = tracesdk.ParseTraceState(entry.TraceState().AsRaw())
+		if v, ok := entry.Attributes().Get(swTriggeredTraceAttribute); ok {
+			triggered = v.Bool()
+		}
+	}
+	trace.Unlock()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.flags == nil {
+		return NotSampled, nil
+	}
+	now := s.now()
+
+	// Continued traces follow the upstream decision.
+	if !isRoot && s.flags[swFlagSampleThroughAlways] {
+		if sw := traceState.Get(swTraceStateKey); sw != "" {
+			if strings.HasSuffix(sw, "-01") {
+				return Sampled, nil
+			}
+			return NotSampled, nil
+		}
+	}
+
+	if triggered {
+		if !s.flags[swFlagTriggerTrace] {
+			return NotSampled, nil
+		}
+		bucket := s.strictTrigger
+		if strings.Contains(traceState.Get(swOptionsResponseKey), "auth####ok") {
+			bucket = s.relaxedTrigger
+		}
+		if bucket.consume(now) {
+			return Sampled, nil
+		}
+		return NotSampled, nil
+	}
+
+	if !s.flags[swFlagSampleStart] {
+		return NotSampled, nil
+	}
+	if s.sampleRate < swMaxSampleRate && s.random()*swMaxSampleRate >= float64(s.sampleRate) {
+		return NotSampled, nil
+	}
+	if s.bucket.consume(now) {
+		return Sampled, nil
+	}
+	return NotSampled, nil
+}
+
+// entrySpan returns the root span of the trace and true, or, if the trace continues an upstream
+// trace, the earliest span and false.
+func entrySpan(td ptrace.Traces) (ptrace.Span, bool, bool) {
+	var earliest ptrace.Span
+	found := false
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		ilss := td.ResourceSpans().At(i).ScopeSpans()
+		for j := 0; j < ilss.Len(); j++ {
+			spans := ilss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				if span.ParentSpanID().IsEmpty() {
+					return span, true, true
+				}
+				if !found || span.StartTimestamp() < earliest.StartTimestamp() {
+					earliest = span
+					found = true
+				}
+			}
+		}
+	}
+	return earliest, false, found
+}
+
+// tokenBucket is a token bucket refilled at rate tokens per second, up to capacity.
+type tokenBucket struct {
+	capacity float64
+	rate     float64
+	tokens   float64
+	last     time.Time
+}
+
+// update returns the bucket with the new capacity and rate. A new bucket starts full.
+func (b *tokenBucket) update(capacity, rate float64, now time.Time) *tokenBucket {
+	if b == nil {
+		return &tokenBucket{capacity: capacity, rate: rate, tokens: capacity, last: now}
+	}
+	b.refill(now)
+	b.capacity = capacity
+	b.rate = rate
+	if b.tokens > capacity {
+		b.tokens = capacity
+	}
+	return b
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens += elapsed * b.rate
+		if b.tokens > b.capacity {
+			b.tokens = b.capacity
+		}
+	}
+	b.last = now
+}
+
+// consume takes a token from the bucket and reports whether there was one.
+func (b *tokenBucket) consume(now time.Time) bool {
+	if b == nil {
+		return false
+	}
+	b.refill(now)
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
@@ -0,0 +1,239 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package sampling
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+const swTestSettings = `[{"arguments":{"BucketCapacity":2,"BucketRate":1,"TriggerRelaxedBucketCapacity":1,"TriggerRelaxedBucketRate":1,"TriggerStrictBucketCapacity":0,"TriggerStrictBucketRate":0},"flags":"SAMPLE_START,SAMPLE_THROUGH_ALWAYS,TRIGGER_TRACE","timestamp":0,"ttl":120,"value":500000}]`
+
+type fakeSettingsProvider struct {
+	component.StartFunc
+	component.ShutdownFunc
+	settings []byte
+	listener func(service string, settings []byte)
+}
+
+func (p *fakeSettingsProvider) Settings(string) []byte {
+	return p.settings
+}
+
+func (p *fakeSettingsProvider) Subscribe(listener func(service string, settings []byte)) func() {
+	p.listener = listener
+	if p.settings != nil {
+		listener("", p.settings)
+	}
+	return func() {
+		p.listener = nil
+	}
+}
+
+type hostWithExtensions struct {
+	component.Host
+	extensions map[component.ID]component.Component
+}
+
+func (h hostWithExtensions) GetExtensions() map[component.ID]component.Component {
+	return h.extensions
+}
+
+func newSolarwindsAPMTrace(parent pcommon.SpanID, traceState string, triggered bool) *TraceData {
+	traces := ptrace.NewTraces()
+	span := traces.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	span.SetTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+	span.SetSpanID([8]byte{1, 2, 3, 4, 5, 6, 7, 8})
+	span.SetParentSpanID(parent)
+	span.TraceState().FromRaw(traceState)
+	if triggered {
+		span.Attributes().PutBool(swTriggeredTraceAttribute, true)
+	}
+	return &TraceData{ReceivedBatches: traces}
+}
+
+func TestSolarwindsAPMStart(t *testing.T) {
+	id := component.MustNewID("solarwindsapmsettings")
+	provider := &fakeSettingsProvider{settings: []byte(swTestSettings)}
+	host := hostWithExtensions{Host: componenttest.NewNopHost(), extensions: map[component.ID]component.Component{id: provider}}
+
+	missing := NewSolarwindsAPM(componenttest.NewNopTelemetrySettings(), component.MustNewID("other"), "")
+	require.Error(t, missing.(component.Component).Start(context.Background(), host))
+
+	wrongType := NewSolarwindsAPM(componenttest.NewNopTelemetrySettings(), id, "")
+	require.Error(t, wrongType.(component.Component).Start(context.Background(), hostWithExtensions{
+		Host: componenttest.NewNopHost(),
+		extensions: map[component.ID]component.Component{id: struct {
+			component.StartFunc
+			component.ShutdownFunc
+		}{}},
+	}))
+
+	evaluator := NewSolarwindsAPM(componenttest.NewNopTelemetrySettings(), id, "")
+	s := evaluator.(*solarwindsAPM)
+	s.random = func() float64 { return 0 }
+	require.NoError(t, s.Start(context.Background(), host))
+	decision, err := s.Evaluate(context.Background(), pcommon.TraceID{}, newSolarwindsAPMTrace(pcommon.SpanID{}, "", false))
+	require.NoError(t, err)
+	assert.Equal(t, Sampled, decision)
+
+	// settings expired
+	provider.settings = nil
+	provider.listener("", nil)
+	decision, err = s.Evaluate(context.Background(), pcommon.TraceID{}, newSolarwindsAPMTrace(pcommon.SpanID{}, "", false))
+	require.NoError(t, err)
+	assert.Equal(t, NotSampled, decision)
+
+	require.NoError(t, s.Shutdown(context.Background()))
+	assert.Nil(t, provider.listener)
+}
+
+func TestSolarwindsAPMStartService(t *testing.T) {
+	id := component.MustNewID("solarwindsapmsettings")
+	provider := &fakeSettingsProvider{}
+	host := hostWithExtensions{Host: componenttest.NewNopHost(), extensions: map[component.ID]component.Component{id: provider}}
+
+	s := NewSolarwindsAPM(componenttest.NewNopTelemetrySettings(), id, "svc").(*solarwindsAPM)
+	s.random = func() float64 { return 0 }
+	require.NoError(t, s.Start(context.Background(), host))
+
+	// settings of another service
+	provider.listener("other", []byte(swTestSettings))
+	decision, err := s.Evaluate(context.Background(), pcommon.TraceID{}, newSolarwindsAPMTrace(pcommon.SpanID{}, "", false))
+	require.NoError(t, err)
+	assert.Equal(t, NotSampled, decision)
+
+	provider.listener("svc", []byte(swTestSettings))
+	decision, err = s.Evaluate(context.Background(), pcommon.TraceID{}, newSolarwindsAPMTrace(pcommon.SpanID{}, "", false))
+	require.NoError(t, err)
+	assert.Equal(t, Sampled, decision)
+	require.NoError(t, s.Shutdown(context.Background()))
+}
+
+func TestSolarwindsAPMEvaluate(t *testing.T) {
+	now := time.Unix(1000, 0)
+	cases := []struct {
+		Desc     string
+		Settings string
+		Random   float64
+		Trace    *TraceData
+		Decision Decision
+	}{
+		{
+			Desc:     "no settings",
+			Random:   0,
+			Trace:    newSolarwindsAPMTrace(pcommon.SpanID{}, "", false),
+			Decision: NotSampled,
+		},
+		{
+			Desc:     "root trace inside sample rate",
+			Settings: swTestSettings,
+			Random:   0.4,
+			Trace:    newSolarwindsAPMTrace(pcommon.SpanID{}, "", false),
+			Decision: Sampled,
+		},
+		{
+			Desc:     "root trace outside sample rate",
+			Settings: swTestSettings,
+			Random:   0.6,
+			Trace:    newSolarwindsAPMTrace(pcommon.SpanID{}, "", false),
+			Decision: NotSampled,
+		},
+		{
+			Desc:     "sample start disabled",
+			Settings: `[{"arguments":{"BucketCapacity":2,"BucketRate":1},"flags":"SAMPLE_THROUGH_ALWAYS","value":1000000}]`,
+			Random:   0,
+			Trace:    newSolarwindsAPMTrace(pcommon.SpanID{}, "", false),
+			Decision: NotSampled,
+		},
+		{
+			Desc:     "continued sampled trace",
+			Settings: swTestSettings,
+			Random:   0.9,
+			Trace:    newSolarwindsAPMTrace(pcommon.SpanID{1}, "sw=0102030405060708-01", false),
+			Decision: Sampled,
+		},
+		{
+			Desc:     "continued not sampled trace",
+			Settings: swTestSettings,
+			Random:   0,
+			Trace:    newSolarwindsAPMTrace(pcommon.SpanID{1}, "sw=0102030405060708-00", false),
+			Decision: NotSampled,
+		},
+		{
+			Desc:     "trigger trace uses the strict bucket",
+			Settings: swTestSettings,
+			Random:   0.9,
+			Trace:    newSolarwindsAPMTrace(pcommon.SpanID{}, "", true),
+			Decision: NotSampled,
+		},
+		{
+			Desc:     "authenticated trigger trace uses the relaxed bucket",
+			Settings: swTestSettings,
+			Random:   0.9,
+			Trace:    newSolarwindsAPMTrace(pcommon.SpanID{}, "xtrace_options_response=auth####ok;trigger-trace####ok", true),
+			Decision: Sampled,
+		},
+		{
+			Desc:     "trigger trace disabled",
+			Settings: `[{"arguments":{"TriggerRelaxedBucketCapacity":1,"TriggerRelaxedBucketRate":1},"flags":"SAMPLE_START","value":1000000}]`,
+			Random:   0,
+			Trace:    newSolarwindsAPMTrace(pcommon.SpanID{}, "xtrace_options_response=auth####ok;trigger-trace####ok", true),
+			Decision: NotSampled,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+			s := NewSolarwindsAPM(componenttest.NewNopTelemetrySettings(), component.MustNewID("solarwindsapmsettings"), "").(*solarwindsAPM)
+			s.random = func() float64 { return c.Random }
+			s.now = func() time.Time { return now }
+			if c.Settings != "" {
+				require.NoError(t, s.update([]byte(c.Settings)))
+			}
+			decision, err := s.Evaluate(context.Background(), pcommon.TraceID{}, c.Trace)
+			require.NoError(t, err)
+			assert.Equal(t, c.Decision, decision)
+		})
+	}
+}
+
+func TestSolarwindsAPMBucket(t *testing.T) {
+	now := time.Unix(1000, 0)
+	s := NewSolarwindsAPM(componenttest.NewNopTelemetrySettings(), component.MustNewID("solarwindsapmsettings"), "").(*solarwindsAPM)
+	s.random = func() float64 { return 0 }
+	s.now = func() time.Time { return now }
+	require.NoError(t, s.update([]byte(swTestSettings)))
+
+	evaluate := func() Decision {
+		decision, err := s.Evaluate(context.Background(), pcommon.TraceID{}, newSolarwindsAPMTrace(pcommon.SpanID{}, "", false))
+		require.NoError(t, err)
+		return decision
+	}
+	// the bucket starts full with a capacity of 2
+	assert.Equal(t, Sampled, evaluate())
+	assert.Equal(t, Sampled, evaluate())
+	assert.Equal(t, NotSampled, evaluate())
+
+	// one token per second
+	now = now.Add(time.Second)
+	assert.Equal(t, Sampled, evaluate())
+	assert.Equal(t, NotSampled, evaluate())
+
+	// never more than the capacity
+	now = now.Add(time.Minute)
+	assert.Equal(t, Sampled, evaluate())
+	assert.Equal(t, Sampled, evaluate())
+	assert.Equal(t, NotSampled, evaluate())
+
+	require.Error(t, s.update([]byte("not json")))
+}
@@ -0,0 +1,152 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package tailsamplingprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor"
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+	"go.opentelemetry.io/collector/confmap"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+// policyFile is the content of a policies file.
+type policyFile struct {
+	PolicyCfgs []PolicyCfg `mapstructure:"policies"`
+}
+
+// policyFileWatcher reads a policies file whenever the file system reports a change to it, and
+// applies the policies it holds when its content changed.
+type policyFileWatcher struct {
+	path   string
+	logger *zap.Logger
+	apply  func(context.Context, []PolicyCfg) error
+
+	// mu serializes the checks of the file, and protects the last content applied.
+	mu      sync.Mutex
+	content []byte
+
+	watcher *fsnotify.Watcher
+	done    chan struct{}
+	wg      sync.WaitGroup
+}
+
+func newPolicyFileWatcher(cfg PolicyReloadConfig, logger *zap.Logger, apply func(context.Context, []PolicyCfg) error) *policyFileWatcher {
+	return &policyFileWatcher{
+		path:   cfg.File,
+		logger: logger.With(zap.String("file", cfg.File)),
+		apply:  apply,
+		done:   make(chan struct{}),
+	}
+}
+
+// start applies the policies of the file, if it exists, and watches it for changes. An invalid
+// file fails the start, while invalid changes are logged and leave the policies in use untouched.
+func (w *policyFileWatcher) start(ctx context.Context) error {
+	if _, err := w.check(ctx); err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to load policies file: %w", err)
+		}
+		w.logger.Warn("Policies file not found, using the configured policies until it is created")
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to watch policies file: %w", err)
+	}
+	// The directory is watched rather than the file, so that the file can be created later or
+	// replaced by a rename, as done by editors and for Kubernetes config maps.
+	if err = watcher.Add(filepath.Dir(w.path)); err != nil {
+		return errors.Join(fmt.Errorf("failed to watch policies file: %w", err), watcher.Close())
+	}
+	w.watcher = watcher
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		for {
+			select {
+			case <-w.done:
+				return
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				w.logger.Warn("Error watching policies file", zap.Error(err))
+			case _, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				// Other files of the directory are not filtered out, as the file may be a symbolic
+				// link to one of them. Unchanged content is not applied again.
+				reloaded, err := w.check(context.Background())
+				switch {
+				case err != nil && !errors.Is(err, os.ErrNotExist):
+					w.logger.Error("Failed to reload sampling policies, keeping the current ones", zap.Error(err))
+				case reloaded:
+					w.logger.Info("Sampling policies reloaded")
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+func (w *policyFileWatcher) stop() error {
+	close(w.done)
+	w.wg.Wait()
+	if w.watcher == nil {
+		return nil
+	}
+	return w.watcher.Close()
+}
+
+// check applies the policies of the file if its content changed since they were last applied.
+func (w *policyFileWatcher) check(ctx context.Context) (bool, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	content, err := os.ReadFile(w.path)
+	if err != nil {
+		return false, err
+	}
+	if w.content != nil && bytes.Equal(content, w.content) {
+		return false, nil
+	}
+
+	policies, err := parsePolicyFile(content)
+	if err != nil {
+		return false, err
+	}
+	if err = w.apply(ctx, policies); err != nil {
+		return false, err
+	}
+	// The content is only remembered once applied, so that a failed reload is retried on the
+	// next change, even when the file is written again with the same content.
+	w.content = content
+	return true, nil
+}
+
+// parsePolicyFile decodes a YAML document holding a `policies` list, using the same format
+// as the `policies` of the processor configuration.
+func parsePolicyFile(content []byte) ([]PolicyCfg, error) {
+	var raw map[string]any
+	if err := yaml.Unmarshal(content, &raw); err != nil {
+		return nil, err
+	}
+	var file policyFile
+	if err := confmap.NewFromStringMap(raw).Unmarshal(&file); err != nil {
+		return nil, err
+	}
+	if len(file.PolicyCfgs) == 0 {
+		return nil, errors.New("no policies defined")
+	}
+	return file.PolicyCfgs, nil
+}
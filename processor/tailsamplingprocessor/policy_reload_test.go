@@ -0,0 +1,138 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package tailsamplingprocessor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/processor/processortest"
+)
+
+const (
+	alwaysSamplePolicyFile = `
+policies:
+  - name: always
+    type: always_sample
+`
+	stringAttributePolicyFile = `
+policies:
+  - name: string
+    type: string_attribute
+    string_attribute: {key: key, values: [value]}
+`
+)
+
+func TestParsePolicyFile(t *testing.T) {
+	policies, err := parsePolicyFile([]byte(stringAttributePolicyFile))
+	require.NoError(t, err)
+	assert.Equal(t, []PolicyCfg{
+		{
+			sharedPolicyCfg: sharedPolicyCfg{
+				Name:               "string",
+				Type:               StringAttribute,
+				StringAttributeCfg: StringAttributeCfg{Key: "key", Values: []string{"value"}},
+			},
+		},
+	}, policies)
+
+	_, err = parsePolicyFile([]byte("policies: ["))
+	assert.Error(t, err)
+	_, err = parsePolicyFile([]byte("policies: []"))
+	assert.Error(t, err)
+	_, err = parsePolicyFile([]byte("unknown: true"))
+	assert.Error(t, err)
+}
+
+func TestPolicyReload(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "policies.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(stringAttributePolicyFile), 0600))
+
+	msp := new(consumertest.TracesSink)
+	p, err := newTracesProcessor(context.Background(), processortest.NewNopSettings(), msp, Config{
+		DecisionWait: defaultTestDecisionWait,
+		NumTraces:    defaultNumTraces,
+		PolicyCfgs:   testPolicy,
+		PolicyReload: PolicyReloadConfig{File: file},
+	}, withDecisionBatcher(newSyncIDBatcher()))
+	require.NoError(t, err)
+	require.NoError(t, p.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		require.NoError(t, p.Shutdown(context.Background()))
+	}()
+	tsp := p.(*tailSamplingSpanProcessor)
+
+	// the policies of the file replace the configured ones on start
+	require.Len(t, tsp.currentPolicies(), 1)
+	assert.Equal(t, "string", tsp.currentPolicies()[0].name)
+
+	require.NoError(t, p.ConsumeTraces(context.Background(), simpleTracesWithID(uInt64ToTraceID(1))))
+	tsp.policyTicker.OnTick() // the first tick always gets an empty batch
+	tsp.policyTicker.OnTick()
+	assert.Empty(t, msp.AllTraces())
+
+	// a trace received before the reload is evaluated against the new policies
+	require.NoError(t, p.ConsumeTraces(context.Background(), simpleTracesWithID(uInt64ToTraceID(2))))
+	require.NoError(t, os.WriteFile(file, []byte(alwaysSamplePolicyFile), 0600))
+	assert.Eventually(t, func() bool {
+		return tsp.currentPolicies()[0].name == "always"
+	}, 5*time.Second, 10*time.Millisecond)
+	tsp.policyTicker.OnTick()
+	tsp.policyTicker.OnTick()
+	require.Len(t, msp.AllTraces(), 1)
+	assert.Equal(t, uInt64ToTraceID(2), msp.AllTraces()[0].ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).TraceID())
+
+	// an unchanged file is not reloaded
+	reloaded, err := tsp.policyWatch.check(context.Background())
+	require.NoError(t, err)
+	assert.False(t, reloaded)
+
+	// invalid policies are ignored, and reported again until valid policies are applied
+	require.NoError(t, os.WriteFile(file, []byte("policies: [{name: unknown, type: unknown}]"), 0600))
+	_, err = tsp.policyWatch.check(context.Background())
+	assert.Error(t, err)
+	_, err = tsp.policyWatch.check(context.Background())
+	assert.Error(t, err)
+	require.Len(t, tsp.currentPolicies(), 1)
+	assert.Equal(t, "always", tsp.currentPolicies()[0].name)
+}
+
+func TestPolicyReloadStart(t *testing.T) {
+	dir := t.TempDir()
+	newProcessor := func(file string) *tailSamplingSpanProcessor {
+		p, err := newTracesProcessor(context.Background(), processortest.NewNopSettings(), consumertest.NewNop(), Config{
+			DecisionWait: defaultTestDecisionWait,
+			NumTraces:    defaultNumTraces,
+			PolicyCfgs:   testPolicy,
+			PolicyReload: PolicyReloadConfig{File: file},
+		}, withDecisionBatcher(newSyncIDBatcher()))
+		require.NoError(t, err)
+		return p.(*tailSamplingSpanProcessor)
+	}
+
+	// a missing file keeps the configured policies until it is created
+	missing := filepath.Join(dir, "missing.yaml")
+	tsp := newProcessor(missing)
+	require.NoError(t, tsp.Start(context.Background(), componenttest.NewNopHost()))
+	assert.Equal(t, "test-policy", tsp.currentPolicies()[0].name)
+	require.NoError(t, os.WriteFile(missing, []byte(alwaysSamplePolicyFile), 0600))
+	assert.Eventually(t, func() bool {
+		return tsp.currentPolicies()[0].name == "always"
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, tsp.Shutdown(context.Background()))
+
+	// an invalid file fails the start
+	invalid := filepath.Join(dir, "invalid.yaml")
+	require.NoError(t, os.WriteFile(invalid, []byte("policies: ["), 0600))
+	tsp = newProcessor(invalid)
+	assert.Error(t, tsp.Start(context.Background(), componenttest.NewNopHost()))
+	require.NoError(t, tsp.Shutdown(context.Background()))
+}
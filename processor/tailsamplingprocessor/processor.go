@@ -5,15 +5,18 @@ package tailsamplingprocessor // import "github.com/open-telemetry/opentelemetry
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
 	"runtime"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.opentelemetry.io/collector/processor"
@@ -21,6 +24,7 @@ import (
 	"go.opentelemetry.io/otel/metric"
 	"go.uber.org/zap"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storageclient"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/timeutils"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/cache"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/idbatcher"
@@ -50,6 +54,7 @@ type tailSamplingSpanProcessor struct {
 
 	nextConsumer    consumer.Traces
 	maxNumTraces    uint64
+	policiesMu      sync.RWMutex
 	policies        []*policy
 	idToTrace       sync.Map
 	policyTicker    timeutils.TTicker
@@ -58,6 +63,38 @@ type tailSamplingSpanProcessor struct {
 	sampledIDCache  cache.Cache[bool]
 	deleteChan      chan pcommon.TraceID
 	numTracesOnMap  *atomic.Uint64
+
+	componentID  component.ID
+	decisionWait time.Duration
+	storageID    *component.ID
+	storage      *traceStorage
+	maxDecisions int
+
+	decisionStorageID     *component.ID
+	decisionStorageTTL    time.Duration
+	decisionStorageClient storage.Client
+	// decisionStorage is the sampled decision cache when decisions are shared through a storage.
+	decisionStorage *cache.StorageDecisionCache
+
+	decisionExplanation DecisionExplanationConfig
+	// sampledPolicies holds the policies which sampled the recently decided traces, to explain
+	// the late spans of these traces too.
+	sampledPolicies cache.Cache[[]string]
+
+	settings     component.TelemetrySettings
+	host         component.Host
+	policyReload PolicyReloadConfig
+	policyWatch  *policyFileWatcher
+
+	// restoredTraces holds the restored traces waiting for a decision, ordered by arrival.
+	// It is only accessed on start and by the policy ticker.
+	restoredTraces []restoredTrace
+}
+
+// restoredTrace is a trace restored from the storage which is due for a decision at decideAt.
+type restoredTrace struct {
+	id       pcommon.TraceID
+	decideAt time.Time
 }
 
 // spanAndScope a structure for holding information about span and its instrumentation scope.
@@ -68,7 +105,33 @@ type spanAndScope struct {
 	instrumentationScope *pcommon.InstrumentationScope
 }
 
+// policyVote is the decision of a policy, kept to explain the final decision.
+type policyVote struct {
+	policy   string
+	decision sampling.Decision
+}
+
+const (
+	// explanationPoliciesAttribute lists the policies that voted to sample the trace.
+	explanationPoliciesAttribute = "tailsampling.policies"
+	// explanationDecisionAttribute is the final decision of the trace.
+	explanationDecisionAttribute = "tailsampling.decision"
+
+	// defaultDecisionStorageTTL is how long the decisions are kept in the decision storage by default.
+	defaultDecisionStorageTTL = time.Hour
+)
+
 var (
+	decisionNames = map[sampling.Decision]string{
+		sampling.Unspecified:      "unspecified",
+		sampling.Pending:          "pending",
+		sampling.Sampled:          "sampled",
+		sampling.NotSampled:       "not_sampled",
+		sampling.Dropped:          "dropped",
+		sampling.Error:            "error",
+		sampling.InvertSampled:    "inverted_sampled",
+		sampling.InvertNotSampled: "inverted_not_sampled",
+	}
 	attrSampledTrue     = metric.WithAttributes(attribute.String("sampled", "true"))
 	attrSampledFalse    = metric.WithAttributes(attribute.String("sampled", "false"))
 	decisionToAttribute = map[sampling.Decision]metric.MeasurementOption{
@@ -97,6 +160,14 @@ func newTracesProcessor(ctx context.Context, set processor.Settings, nextConsume
 		}
 	}
 
+	sampledPolicies := cache.NewNopDecisionCache[[]string]()
+	if cfg.DecisionExplanation.Attributes {
+		sampledPolicies, err = cache.NewLRUDecisionCache[[]string](max(int(cfg.NumTraces), cfg.DecisionCache.SampledCacheSize))
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	tsp := &tailSamplingSpanProcessor{
 		ctx:            ctx,
 		telemetry:      telemetry,
@@ -106,6 +177,19 @@ func newTracesProcessor(ctx context.Context, set processor.Settings, nextConsume
 		logger:         telemetrySettings.Logger,
 		numTracesOnMap: &atomic.Uint64{},
 		deleteChan:     make(chan pcommon.TraceID, cfg.NumTraces),
+		componentID:    set.ID,
+		decisionWait:   cfg.DecisionWait,
+		storageID:      cfg.StorageID,
+		maxDecisions:   cfg.DecisionCache.SampledCacheSize,
+
+		decisionStorageID:  cfg.DecisionCache.StorageID,
+		decisionStorageTTL: cfg.DecisionCache.StorageTTL,
+
+		decisionExplanation: cfg.DecisionExplanation,
+		sampledPolicies:     sampledPolicies,
+
+		settings:     telemetrySettings,
+		policyReload: cfg.PolicyReload,
 	}
 	tsp.policyTicker = &timeutils.PolicyTicker{OnTickFunc: tsp.samplingPolicyOnTick}
 
@@ -117,32 +201,14 @@ func newTracesProcessor(ctx context.Context, set processor.Settings, nextConsume
 		tsp.tickerFrequency = time.Second
 	}
 
-	if tsp.policies == nil {
-		policyNames := map[string]bool{}
-		tsp.policies = make([]*policy, len(cfg.PolicyCfgs))
-		componentID := set.ID.Name()
-		for i := range cfg.PolicyCfgs {
-			policyCfg := &cfg.PolicyCfgs[i]
-
-			if policyNames[policyCfg.Name] {
-				return nil, fmt.Errorf("duplicate policy name %q", policyCfg.Name)
-			}
-			policyNames[policyCfg.Name] = true
+	if tsp.decisionStorageTTL <= 0 {
+		tsp.decisionStorageTTL = defaultDecisionStorageTTL
+	}
 
-			eval, err := getPolicyEvaluator(telemetrySettings, policyCfg)
-			if err != nil {
-				return nil, err
-			}
-			uniquePolicyName := policyCfg.Name
-			if componentID != "" {
-				uniquePolicyName = fmt.Sprintf("%s.%s", componentID, policyCfg.Name)
-			}
-			p := &policy{
-				name:      policyCfg.Name,
-				evaluator: eval,
-				attribute: metric.WithAttributes(attribute.String("policy", uniquePolicyName)),
-			}
-			tsp.policies[i] = p
+	if tsp.policies == nil {
+		tsp.policies, err = tsp.newPolicies(cfg.PolicyCfgs)
+		if err != nil {
+			return nil, err
 		}
 	}
 
@@ -188,6 +254,80 @@ func withSampledDecisionCache(c cache.Cache[bool]) Option {
 	}
 }
 
+// newPolicies creates the policies described by the configuration.
+func (tsp *tailSamplingSpanProcessor) newPolicies(cfgs []PolicyCfg) ([]*policy, error) {
+	policyNames := map[string]bool{}
+	policies := make([]*policy, len(cfgs))
+	componentID := tsp.componentID.Name()
+	for i := range cfgs {
+		policyCfg := &cfgs[i]
+
+		if policyNames[policyCfg.Name] {
+			return nil, fmt.Errorf("duplicate policy name %q", policyCfg.Name)
+		}
+		policyNames[policyCfg.Name] = true
+
+		eval, err := getPolicyEvaluator(tsp.settings, policyCfg)
+		if err != nil {
+			return nil, err
+		}
+		uniquePolicyName := policyCfg.Name
+		if componentID != "" {
+			uniquePolicyName = fmt.Sprintf("%s.%s", componentID, policyCfg.Name)
+		}
+		p := &policy{
+			name:      policyCfg.Name,
+			evaluator: eval,
+			attribute: metric.WithAttributes(attribute.String("policy", uniquePolicyName)),
+		}
+		policies[i] = p
+	}
+	return policies, nil
+}
+
+// currentPolicies returns the policies in use, which may be replaced at any time by reloadPolicies.
+func (tsp *tailSamplingSpanProcessor) currentPolicies() []*policy {
+	tsp.policiesMu.RLock()
+	defer tsp.policiesMu.RUnlock()
+	return tsp.policies
+}
+
+// reloadPolicies replaces the policies in use by the ones described by the configuration. The
+// traces waiting for a decision are kept and evaluated against the new policies.
+func (tsp *tailSamplingSpanProcessor) reloadPolicies(ctx context.Context, cfgs []PolicyCfg) error {
+	policies, err := tsp.newPolicies(cfgs)
+	if err != nil {
+		return err
+	}
+	if err = startPolicies(ctx, tsp.host, policies); err != nil {
+		return errors.Join(err, shutdownPolicies(ctx, policies))
+	}
+
+	tsp.policiesMu.Lock()
+	previous := tsp.policies
+	tsp.policies = policies
+	tsp.policiesMu.Unlock()
+
+	return shutdownPolicies(ctx, previous)
+}
+
+func startPolicies(ctx context.Context, host component.Host, policies []*policy) error {
+	for _, p := range policies {
+		if err := sampling.StartEvaluator(ctx, host, p.evaluator); err != nil {
+			return fmt.Errorf("failed to start policy %q: %w", p.name, err)
+		}
+	}
+	return nil
+}
+
+func shutdownPolicies(ctx context.Context, policies []*policy) error {
+	var errs error
+	for _, p := range policies {
+		errs = errors.Join(errs, sampling.ShutdownEvaluator(ctx, p.evaluator))
+	}
+	return errs
+}
+
 func getPolicyEvaluator(settings component.TelemetrySettings, cfg *PolicyCfg) (sampling.PolicyEvaluator, error) {
 	switch cfg.Type {
 	case Composite:
@@ -235,6 +375,12 @@ func getSharedPolicyEvaluator(settings component.TelemetrySettings, cfg *sharedP
 	case OTTLCondition:
 		ottlfCfg := cfg.OTTLConditionCfg
 		return sampling.NewOTTLConditionFilter(settings, ottlfCfg.SpanConditions, ottlfCfg.SpanEventConditions, ottlfCfg.ErrorMode)
+	case SolarwindsAPM:
+		swCfg := cfg.SolarwindsAPMCfg
+		return sampling.NewSolarwindsAPM(settings, swCfg.SettingsExtension, swCfg.Service), nil
+	case Adaptive:
+		aCfg := cfg.AdaptiveCfg
+		return sampling.NewAdaptive(settings, aCfg.Key, aCfg.SpansPerSecond, aCfg.TracesPerSecond, aCfg.AdjustmentInterval)
 
 	default:
 		return nil, fmt.Errorf("unknown sampling policy type %s", cfg.Type)
@@ -250,8 +396,13 @@ func (tsp *tailSamplingSpanProcessor) samplingPolicyOnTick() {
 
 	startTime := time.Now()
 	batch, _ := tsp.decisionBatcher.CloseCurrentAndTakeFirstBatch()
+	batch = append(batch, tsp.takeDueRestoredTraces(startTime)...)
 	batchLen := len(batch)
 	tsp.logger.Debug("Sampling Policy Evaluation ticked")
+	if tsp.decisionStorage != nil {
+		// The decisions made by other collectors sharing the decision storage are read at once.
+		tsp.decisionStorage.Lookup(tsp.ctx, batch)
+	}
 	for _, id := range batch {
 		d, ok := tsp.idToTrace.Load(id)
 		if !ok {
@@ -261,7 +412,16 @@ func (tsp *tailSamplingSpanProcessor) samplingPolicyOnTick() {
 		trace := d.(*sampling.TraceData)
 		trace.DecisionTime = time.Now()
 
-		decision := tsp.makeDecision(id, trace, &metrics)
+		var decision sampling.Decision
+		var votes []policyVote
+		sampledElsewhere := false
+		if sampled, ok := tsp.sampledIDCache.Get(id); ok && sampled {
+			// Another collector sharing the decision storage already sampled the trace.
+			decision = sampling.Sampled
+			sampledElsewhere = true
+		} else {
+			decision, votes = tsp.makeDecision(id, trace, &metrics)
+		}
 		tsp.telemetry.ProcessorTailSamplingSamplingDecisionTimerLatency.Record(tsp.ctx, int64(time.Since(startTime)/time.Microsecond))
 		tsp.telemetry.ProcessorTailSamplingSamplingTraceDroppedTooEarly.Add(tsp.ctx, metrics.idNotFoundOnMapCount)
 		tsp.telemetry.ProcessorTailSamplingSamplingPolicyEvaluationError.Add(tsp.ctx, metrics.evaluateErrorCount)
@@ -274,11 +434,21 @@ func (tsp *tailSamplingSpanProcessor) samplingPolicyOnTick() {
 		trace.FinalDecision = decision
 		trace.ReceivedBatches = ptrace.NewTraces()
 		trace.Unlock()
+		tsp.traceChanged(id)
+		tsp.explainDecision(id, decision, votes, sampledElsewhere)
 
 		if decision == sampling.Sampled {
+			// The decision is cached once, late spans are released without updating the cache.
+			if !sampledElsewhere {
+				tsp.sampledIDCache.Put(id, true)
+			}
 			tsp.releaseSampledTrace(context.Background(), id, allSpans)
 		}
 	}
+	tsp.flushStorage()
+	if tsp.decisionStorage != nil {
+		tsp.decisionStorage.Flush(tsp.ctx)
+	}
 
 	tsp.logger.Debug("Sampling policy evaluation completed",
 		zap.Int("batch.len", batchLen),
@@ -289,7 +459,9 @@ func (tsp *tailSamplingSpanProcessor) samplingPolicyOnTick() {
 	)
 }
 
-func (tsp *tailSamplingSpanProcessor) makeDecision(id pcommon.TraceID, trace *sampling.TraceData, metrics *policyMetrics) sampling.Decision {
+// makeDecision evaluates all policies and returns the final decision. The decision of each policy
+// is also returned when decisions are explained.
+func (tsp *tailSamplingSpanProcessor) makeDecision(id pcommon.TraceID, trace *sampling.TraceData, metrics *policyMetrics) (sampling.Decision, []policyVote) {
 	finalDecision := sampling.NotSampled
 	samplingDecision := map[sampling.Decision]bool{
 		sampling.Error:            false,
@@ -299,6 +471,18 @@ func (tsp *tailSamplingSpanProcessor) makeDecision(id pcommon.TraceID, trace *sa
 		sampling.InvertNotSampled: false,
 	}
 
+	// Hold the policies until the decision is made, so that they are not shut down by a reload
+	// while being evaluated.
+	tsp.policiesMu.RLock()
+	defer tsp.policiesMu.RUnlock()
+
+	var votes []policyVote
+	if tsp.decisionExplanation.Attributes || tsp.decisionExplanation.Log {
+		votes = make([]policyVote, 0, len(tsp.policies))
+	}
+
+	// onlyConsistent is true while the policies sampling the trace sample with a consistent probability.
+	onlyConsistent := true
 	ctx := context.Background()
 	// Check all policies before making a final decision
 	for _, p := range tsp.policies {
@@ -309,6 +493,7 @@ func (tsp *tailSamplingSpanProcessor) makeDecision(id pcommon.TraceID, trace *sa
 			samplingDecision[sampling.Error] = true
 			metrics.evaluateErrorCount++
 			tsp.logger.Debug("Sampling policy error", zap.Error(err))
+			decision = sampling.Error
 		} else {
 			tsp.telemetry.ProcessorTailSamplingCountTracesSampled.Add(ctx, 1, p.attribute, decisionToAttribute[decision])
 			if telemetry.IsMetricStatCountSpansSampledEnabled() {
@@ -316,6 +501,12 @@ func (tsp *tailSamplingSpanProcessor) makeDecision(id pcommon.TraceID, trace *sa
 			}
 
 			samplingDecision[decision] = true
+			if (decision == sampling.Sampled || decision == sampling.InvertSampled) && !sampling.IsConsistent(p.evaluator) {
+				onlyConsistent = false
+			}
+		}
+		if votes != nil {
+			votes = append(votes, policyVote{policy: p.name, decision: decision})
 		}
 	}
 
@@ -329,7 +520,65 @@ func (tsp *tailSamplingSpanProcessor) makeDecision(id pcommon.TraceID, trace *sa
 		finalDecision = sampling.Sampled
 	}
 
-	return finalDecision
+	// The probability of a trace sampled by another policy is 1, the threshold is only kept
+	// when consistent probability policies alone sampled the trace.
+	if finalDecision == sampling.Sampled && onlyConsistent {
+		sampling.WriteSampledThreshold(tsp.logger, trace)
+	}
+
+	return finalDecision, votes
+}
+
+// explainDecision logs the decision with the vote of each policy, and keeps the policies which
+// voted to sample the trace to record them in the attributes of its spans, if enabled.
+func (tsp *tailSamplingSpanProcessor) explainDecision(id pcommon.TraceID, decision sampling.Decision, votes []policyVote, sampledElsewhere bool) {
+	if tsp.decisionExplanation.Log {
+		fields := make([]zap.Field, 0, len(votes))
+		for _, vote := range votes {
+			fields = append(fields, zap.String(vote.policy, decisionNames[vote.decision]))
+		}
+		tsp.logger.Debug("Sampling decision",
+			zap.Stringer("traceID", id),
+			zap.String("decision", decisionNames[decision]),
+			zap.Dict("policies", fields...))
+	}
+
+	// The policies of a trace sampled by another collector are unknown.
+	if !tsp.decisionExplanation.Attributes || decision != sampling.Sampled || sampledElsewhere {
+		return
+	}
+	policies := []string{}
+	for _, vote := range votes {
+		if vote.decision == sampling.Sampled || vote.decision == sampling.InvertSampled {
+			policies = append(policies, vote.policy)
+		}
+	}
+	tsp.sampledPolicies.Put(id, policies)
+}
+
+// addExplanationAttributes records the sampled decision, and the policies which voted to sample the
+// trace when they are still known, in the attributes of the spans.
+func (tsp *tailSamplingSpanProcessor) addExplanationAttributes(id pcommon.TraceID, td ptrace.Traces) {
+	policies, known := tsp.sampledPolicies.Get(id)
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		ilss := rss.At(i).ScopeSpans()
+		for j := 0; j < ilss.Len(); j++ {
+			spans := ilss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				attrs := spans.At(k).Attributes()
+				attrs.PutStr(explanationDecisionAttribute, decisionNames[sampling.Sampled])
+				if !known {
+					continue
+				}
+				names := attrs.PutEmptySlice(explanationPoliciesAttribute)
+				names.EnsureCapacity(len(policies))
+				for _, name := range policies {
+					names.AppendEmpty().SetStr(name)
+				}
+			}
+		}
+	}
 }
 
 // ConsumeTraces is required by the processor.Traces interface.
@@ -376,11 +625,6 @@ func (tsp *tailSamplingSpanProcessor) processTraces(resourceSpans ptrace.Resourc
 		}
 
 		lenSpans := int64(len(spans))
-		lenPolicies := len(tsp.policies)
-		initialDecisions := make([]sampling.Decision, lenPolicies)
-		for i := 0; i < lenPolicies; i++ {
-			initialDecisions[i] = sampling.Pending
-		}
 		d, loaded := tsp.idToTrace.Load(id)
 		if !loaded {
 			spanCount := &atomic.Int64{}
@@ -419,6 +663,7 @@ func (tsp *tailSamplingSpanProcessor) processTraces(resourceSpans ptrace.Resourc
 			// If the final decision hasn't been made, add the new spans under the lock.
 			appendToTraces(actualData.ReceivedBatches, resourceSpans, spans)
 			actualData.Unlock()
+			tsp.traceChanged(id)
 		} else {
 			actualData.Unlock()
 
@@ -445,16 +690,140 @@ func (tsp *tailSamplingSpanProcessor) Capabilities() consumer.Capabilities {
 }
 
 // Start is invoked during service startup.
-func (tsp *tailSamplingSpanProcessor) Start(context.Context, component.Host) error {
+func (tsp *tailSamplingSpanProcessor) Start(ctx context.Context, host component.Host) error {
+	if tsp.decisionStorageID != nil {
+		client, err := storageclient.Get(ctx, host, *tsp.decisionStorageID, component.KindProcessor, tsp.componentID, "decisions")
+		if err != nil {
+			return err
+		}
+		tsp.decisionStorageClient = client
+		// The storage keeps at most as many decisions written by the processor as it keeps in memory.
+		tsp.decisionStorage = cache.NewStorageDecisionCache(client, tsp.sampledIDCache, tsp.decisionStorageTTL,
+			max(tsp.maxDecisions, int(tsp.maxNumTraces)), tsp.logger)
+		tsp.sampledIDCache = tsp.decisionStorage
+	}
+	if tsp.storageID != nil {
+		client, err := storageclient.Get(ctx, host, *tsp.storageID, component.KindProcessor, tsp.componentID, "")
+		if err != nil {
+			return err
+		}
+		tsp.storage = newTraceStorage(client, tsp.maxDecisions)
+		tsp.restoreTraces(ctx)
+	}
+	tsp.host = host
+	if err := startPolicies(ctx, host, tsp.policies); err != nil {
+		return err
+	}
+	if tsp.policyReload.File != "" {
+		tsp.policyWatch = newPolicyFileWatcher(tsp.policyReload, tsp.logger, tsp.reloadPolicies)
+		if err := tsp.policyWatch.start(ctx); err != nil {
+			return err
+		}
+	}
 	tsp.policyTicker.Start(tsp.tickerFrequency)
 	return nil
 }
 
 // Shutdown is invoked during service shutdown.
-func (tsp *tailSamplingSpanProcessor) Shutdown(context.Context) error {
+func (tsp *tailSamplingSpanProcessor) Shutdown(ctx context.Context) error {
 	tsp.decisionBatcher.Stop()
 	tsp.policyTicker.Stop()
-	return nil
+	var errs error
+	if tsp.policyWatch != nil {
+		errs = tsp.policyWatch.stop()
+	}
+	errs = errors.Join(errs, shutdownPolicies(ctx, tsp.currentPolicies()))
+	if tsp.storage != nil {
+		errs = errors.Join(errs, tsp.storage.flush(ctx, tsp.lookupTrace), tsp.storage.close(ctx))
+	}
+	if tsp.decisionStorageClient != nil {
+		tsp.decisionStorage.Flush(ctx)
+		errs = errors.Join(errs, tsp.decisionStorageClient.Close(ctx))
+	}
+	return errs
+}
+
+// restoreTraces loads the traces persisted before the last shutdown. Traces still waiting for a
+// decision are evaluated once their decision wait elapsed, and sampled traces, including the ones
+// which were already dropped from memory, are added to the sampled decision cache.
+func (tsp *tailSamplingSpanProcessor) restoreTraces(ctx context.Context) {
+	traces, sampled, err := tsp.storage.load(ctx)
+	if err != nil {
+		tsp.logger.Warn("Failed to restore some traces from the storage", zap.Error(err))
+	}
+	for _, id := range sampled {
+		tsp.sampledIDCache.Put(id, true)
+	}
+
+	ids := make([]pcommon.TraceID, 0, len(traces))
+	for id := range traces {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return traces[ids[i]].ArrivalTime.Before(traces[ids[j]].ArrivalTime)
+	})
+
+	currTime := time.Now()
+	for _, id := range ids {
+		trace := traces[id]
+		tsp.idToTrace.Store(id, trace)
+		tsp.numTracesOnMap.Add(1)
+		postDeletion := false
+		for !postDeletion {
+			select {
+			case tsp.deleteChan <- id:
+				postDeletion = true
+			default:
+				traceKeyToDrop := <-tsp.deleteChan
+				tsp.dropTrace(traceKeyToDrop, currTime)
+			}
+		}
+
+		switch trace.FinalDecision {
+		case sampling.Unspecified:
+			tsp.restoredTraces = append(tsp.restoredTraces, restoredTrace{id: id, decideAt: trace.ArrivalTime.Add(tsp.decisionWait)})
+		case sampling.Sampled:
+			tsp.sampledIDCache.Put(id, true)
+		}
+	}
+	tsp.logger.Info("Restored traces from the storage",
+		zap.Int("traces", len(ids)),
+		zap.Int("pending", len(tsp.restoredTraces)),
+		zap.Int("sampledDecisions", len(sampled)))
+}
+
+// takeDueRestoredTraces returns the restored traces whose decision wait elapsed.
+func (tsp *tailSamplingSpanProcessor) takeDueRestoredTraces(now time.Time) []pcommon.TraceID {
+	var due []pcommon.TraceID
+	for len(tsp.restoredTraces) > 0 && !tsp.restoredTraces[0].decideAt.After(now) {
+		due = append(due, tsp.restoredTraces[0].id)
+		tsp.restoredTraces = tsp.restoredTraces[1:]
+	}
+	return due
+}
+
+func (tsp *tailSamplingSpanProcessor) lookupTrace(id pcommon.TraceID) (*sampling.TraceData, bool) {
+	d, ok := tsp.idToTrace.Load(id)
+	if !ok {
+		return nil, false
+	}
+	return d.(*sampling.TraceData), true
+}
+
+// traceChanged records that the trace needs to be persisted again, if a storage is configured.
+func (tsp *tailSamplingSpanProcessor) traceChanged(id pcommon.TraceID) {
+	if tsp.storage != nil {
+		tsp.storage.traceChanged(id)
+	}
+}
+
+func (tsp *tailSamplingSpanProcessor) flushStorage() {
+	if tsp.storage == nil {
+		return
+	}
+	if err := tsp.storage.flush(tsp.ctx, tsp.lookupTrace); err != nil {
+		tsp.logger.Warn("Failed to persist traces to the storage", zap.Error(err))
+	}
 }
 
 func (tsp *tailSamplingSpanProcessor) dropTrace(traceID pcommon.TraceID, deletionTime time.Time) {
@@ -464,6 +833,12 @@ func (tsp *tailSamplingSpanProcessor) dropTrace(traceID pcommon.TraceID, deletio
 		tsp.idToTrace.Delete(traceID)
 		// Subtract one from numTracesOnMap per https://godoc.org/sync/atomic#AddUint64
 		tsp.numTracesOnMap.Add(^uint64(0))
+		if tsp.storage != nil {
+			trace.Lock()
+			decision := trace.FinalDecision
+			trace.Unlock()
+			tsp.storage.traceDropped(traceID, decision)
+		}
 	}
 	if trace == nil {
 		tsp.logger.Debug("Attempt to delete traceID not on table")
@@ -474,10 +849,11 @@ func (tsp *tailSamplingSpanProcessor) dropTrace(traceID pcommon.TraceID, deletio
 }
 
 // releaseSampledTrace sends the trace data to the next consumer.
-// It additionally adds the trace ID to the cache of sampled trace IDs.
 // It does not (yet) delete the spans from the internal map.
 func (tsp *tailSamplingSpanProcessor) releaseSampledTrace(ctx context.Context, id pcommon.TraceID, td ptrace.Traces) {
-	tsp.sampledIDCache.Put(id, true)
+	if tsp.decisionExplanation.Attributes {
+		tsp.addExplanationAttributes(id, td)
+	}
 	if err := tsp.nextConsumer.ConsumeTraces(ctx, td); err != nil {
 		tsp.logger.Warn(
 			"Error sending spans to destination",
@@ -642,3 +642,132 @@ func simpleTracesWithID(traceID pcommon.TraceID) ptrace.Traces {
 	traces.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty().SetTraceID(traceID)
 	return traces
 }
+
+func TestSampledThresholdOfAdaptivePolicy(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		decision   sampling.Decision
+		traceState string
+	}{
+		{name: "adaptive only", decision: sampling.NotSampled, traceState: "ot=th:0"},
+		{name: "sampled by another policy", decision: sampling.Sampled, traceState: ""},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			adaptive, err := sampling.NewAdaptive(componenttest.NewNopTelemetrySettings(), "", 0, 1, time.Minute)
+			require.NoError(t, err)
+			policies := []*policy{
+				{name: "adaptive", evaluator: adaptive, attribute: metric.WithAttributes(attribute.String("policy", "adaptive"))},
+				{name: "mock-policy", evaluator: &mockPolicyEvaluator{NextDecision: tt.decision}, attribute: metric.WithAttributes(attribute.String("policy", "mock-policy"))},
+			}
+			sink := new(consumertest.TracesSink)
+			cfg := Config{DecisionWait: defaultTestDecisionWait, NumTraces: defaultNumTraces}
+			p, err := newTracesProcessor(context.Background(), processortest.NewNopSettings(), sink, cfg, withDecisionBatcher(newSyncIDBatcher()), withPolicies(policies))
+			require.NoError(t, err)
+			require.NoError(t, p.Start(context.Background(), componenttest.NewNopHost()))
+			defer func() {
+				require.NoError(t, p.Shutdown(context.Background()))
+			}()
+
+			require.NoError(t, p.ConsumeTraces(context.Background(), simpleTracesWithID(uInt64ToTraceID(1))))
+			tsp := p.(*tailSamplingSpanProcessor)
+			tsp.policyTicker.OnTick() // the first tick always gets an empty batch
+			tsp.policyTicker.OnTick()
+
+			// The threshold is only written when the adaptive policy alone sampled the trace.
+			require.Len(t, sink.AllTraces(), 1)
+			span := sink.AllTraces()[0].ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0)
+			assert.Equal(t, tt.traceState, span.TraceState().AsRaw())
+		})
+	}
+}
+
+func TestDecisionExplanation(t *testing.T) {
+	zc, logs := observer.New(zap.DebugLevel)
+	set := processortest.NewNopSettings()
+	set.Logger = zap.New(zc)
+	cfg := Config{
+		DecisionWait:        defaultTestDecisionWait,
+		NumTraces:           defaultNumTraces,
+		DecisionExplanation: DecisionExplanationConfig{Attributes: true, Log: true},
+	}
+	sampledPolicy := &mockPolicyEvaluator{NextDecision: sampling.Sampled}
+	notSampledPolicy := &mockPolicyEvaluator{NextDecision: sampling.NotSampled}
+	policies := []*policy{
+		{name: "sampled-policy", evaluator: sampledPolicy, attribute: metric.WithAttributes(attribute.String("policy", "sampled-policy"))},
+		{name: "not-sampled-policy", evaluator: notSampledPolicy, attribute: metric.WithAttributes(attribute.String("policy", "not-sampled-policy"))},
+	}
+	msp := new(consumertest.TracesSink)
+	p, err := newTracesProcessor(context.Background(), set, msp, cfg, withDecisionBatcher(newSyncIDBatcher()), withPolicies(policies))
+	require.NoError(t, err)
+	require.NoError(t, p.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		require.NoError(t, p.Shutdown(context.Background()))
+	}()
+	tsp := p.(*tailSamplingSpanProcessor)
+
+	// sampled trace
+	require.NoError(t, p.ConsumeTraces(context.Background(), simpleTracesWithID(uInt64ToTraceID(1))))
+	tsp.policyTicker.OnTick() // the first tick always gets an empty batch
+	tsp.policyTicker.OnTick()
+
+	require.Len(t, msp.AllTraces(), 1)
+	attrs := msp.AllTraces()[0].ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Attributes()
+	assert.Equal(t, map[string]any{
+		"tailsampling.decision": "sampled",
+		"tailsampling.policies": []any{"sampled-policy"},
+	}, attrs.AsRaw())
+
+	decisionLogs := logs.FilterMessage("Sampling decision").All()
+	require.Len(t, decisionLogs, 1)
+	assert.Equal(t, map[string]any{
+		"traceID":  uInt64ToTraceID(1).String(),
+		"decision": "sampled",
+		"policies": map[string]any{
+			"sampled-policy":     "sampled",
+			"not-sampled-policy": "not_sampled",
+		},
+	}, decisionLogs[0].ContextMap())
+	assert.Equal(t, zap.DebugLevel, decisionLogs[0].Level)
+
+	// late spans of the sampled trace are explained the same way
+	require.NoError(t, p.ConsumeTraces(context.Background(), simpleTracesWithID(uInt64ToTraceID(1))))
+	require.Len(t, msp.AllTraces(), 2)
+	attrs = msp.AllTraces()[1].ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Attributes()
+	assert.Equal(t, map[string]any{
+		"tailsampling.decision": "sampled",
+		"tailsampling.policies": []any{"sampled-policy"},
+	}, attrs.AsRaw())
+
+	// not sampled trace
+	sampledPolicy.NextDecision = sampling.NotSampled
+	require.NoError(t, p.ConsumeTraces(context.Background(), simpleTracesWithID(uInt64ToTraceID(2))))
+	tsp.policyTicker.OnTick()
+	tsp.policyTicker.OnTick()
+
+	assert.Len(t, msp.AllTraces(), 2)
+	decisionLogs = logs.FilterMessage("Sampling decision").All()
+	require.Len(t, decisionLogs, 2)
+	assert.Equal(t, "not_sampled", decisionLogs[1].ContextMap()["decision"])
+}
+
+func TestDecisionExplanationDisabled(t *testing.T) {
+	msp := new(consumertest.TracesSink)
+	p, err := newTracesProcessor(context.Background(), processortest.NewNopSettings(), msp, Config{
+		DecisionWait: defaultTestDecisionWait,
+		NumTraces:    defaultNumTraces,
+		PolicyCfgs:   testPolicy,
+	}, withDecisionBatcher(newSyncIDBatcher()))
+	require.NoError(t, err)
+	require.NoError(t, p.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		require.NoError(t, p.Shutdown(context.Background()))
+	}()
+	tsp := p.(*tailSamplingSpanProcessor)
+
+	require.NoError(t, p.ConsumeTraces(context.Background(), simpleTraces()))
+	tsp.policyTicker.OnTick()
+	tsp.policyTicker.OnTick()
+
+	require.Len(t, msp.AllTraces(), 1)
+	assert.Equal(t, 0, msp.AllTraces()[0].ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Attributes().Len())
+}
@@ -0,0 +1,360 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package tailsamplingprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor"
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/sampling"
+)
+
+const (
+	storageTraceIndexKey     = "trace_index"
+	storageDecisionIndexKey  = "decision_index"
+	storageTraceKeyPrefix    = "trace/"
+	storageDecisionKeyPrefix = "decision/"
+
+	// traceDataHeaderSize is the size of the arrival time, decision time and final decision
+	// preceding the spans of a persisted trace.
+	traceDataHeaderSize = 8 + 8 + 4
+
+	// minIndexCompaction is the number of removed IDs an index keeps in its segments before
+	// being compacted.
+	minIndexCompaction = 1024
+)
+
+var errCorruptedTraceData = errors.New("corrupted trace data")
+
+// traceStorage persists the traces held by the processor, with their final decision, so that
+// pending traces and decisions survive a restart. Changed traces are recorded as they happen
+// and written in a single batch by flush. Once a sampled trace is dropped from memory, its
+// decision is persisted on its own, keeping up to maxDecisions of them like the sampled
+// decision cache.
+type traceStorage struct {
+	client       storage.Client
+	maxDecisions int
+
+	mu      sync.Mutex
+	changed map[pcommon.TraceID]struct{}
+	sampled []pcommon.TraceID
+
+	// The fields below are only accessed by load and flush.
+	traces    *idIndex
+	decisions *idIndex
+	// decided holds the IDs of the persisted decisions, the oldest first.
+	decided []pcommon.TraceID
+}
+
+func newTraceStorage(client storage.Client, maxDecisions int) *traceStorage {
+	return &traceStorage{
+		client:       client,
+		maxDecisions: maxDecisions,
+		changed:      make(map[pcommon.TraceID]struct{}),
+		traces:       newIDIndex(storageTraceIndexKey),
+		decisions:    newIDIndex(storageDecisionIndexKey),
+	}
+}
+
+// traceChanged records that the trace was added, updated or removed since the last flush.
+func (s *traceStorage) traceChanged(id pcommon.TraceID) {
+	s.mu.Lock()
+	s.changed[id] = struct{}{}
+	s.mu.Unlock()
+}
+
+// traceDropped records that the trace was removed from memory with the given decision.
+func (s *traceStorage) traceDropped(id pcommon.TraceID, decision sampling.Decision) {
+	s.mu.Lock()
+	s.changed[id] = struct{}{}
+	if decision == sampling.Sampled && s.maxDecisions > 0 {
+		s.sampled = append(s.sampled, id)
+	}
+	s.mu.Unlock()
+}
+
+// load returns the persisted traces, and the IDs of the sampled traces which were dropped
+// from memory, the oldest first.
+func (s *traceStorage) load(ctx context.Context) (map[pcommon.TraceID]*sampling.TraceData, []pcommon.TraceID, error) {
+	ids, err := s.traces.load(ctx, s.client)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	traces := make(map[pcommon.TraceID]*sampling.TraceData, len(ids))
+	var errs error
+	var removed []pcommon.TraceID
+	for _, id := range ids {
+		data, err := s.client.Get(ctx, traceKey(id))
+		if err != nil {
+			return nil, nil, err
+		}
+		if data == nil {
+			removed = append(removed, id)
+			continue
+		}
+		trace, err := unmarshalTraceData(data)
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("trace %s: %w", id, err))
+			continue
+		}
+		traces[id] = trace
+	}
+	s.traces.remove(removed...)
+
+	ids, err = s.decisions.load(ctx, s.client)
+	if err != nil {
+		return nil, nil, err
+	}
+	removed = removed[:0]
+	for _, id := range ids {
+		data, err := s.client.Get(ctx, decisionKey(id))
+		if err != nil {
+			return nil, nil, err
+		}
+		if data == nil {
+			removed = append(removed, id)
+			continue
+		}
+		s.decided = append(s.decided, id)
+	}
+	s.decisions.remove(removed...)
+
+	return traces, s.decided, errs
+}
+
+// flush writes the traces changed since the last flush. lookup returns the trace currently held
+// by the processor, traces which are not held anymore are deleted from the storage.
+func (s *traceStorage) flush(ctx context.Context, lookup func(pcommon.TraceID) (*sampling.TraceData, bool)) error {
+	s.mu.Lock()
+	changed, sampled := s.changed, s.sampled
+	s.changed = make(map[pcommon.TraceID]struct{})
+	s.sampled = nil
+	s.mu.Unlock()
+
+	if len(changed) == 0 && len(sampled) == 0 {
+		return nil
+	}
+
+	var errs error
+	var ops []storage.Operation
+	var added, removed []pcommon.TraceID
+	for id := range changed {
+		trace, ok := lookup(id)
+		if !ok {
+			if s.traces.contains(id) {
+				removed = append(removed, id)
+				ops = append(ops, storage.DeleteOperation(traceKey(id)))
+			}
+			continue
+		}
+		data, err := marshalTraceData(trace)
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("trace %s: %w", id, err))
+			continue
+		}
+		if !s.traces.contains(id) {
+			added = append(added, id)
+		}
+		ops = append(ops, storage.SetOperation(traceKey(id), data))
+	}
+	ops = append(ops, s.traces.update(added, removed)...)
+
+	added, removed = added[:0], removed[:0]
+	for _, id := range sampled {
+		if s.decisions.contains(id) {
+			continue
+		}
+		added = append(added, id)
+		s.decided = append(s.decided, id)
+		ops = append(ops, storage.SetOperation(decisionKey(id), []byte{1}))
+	}
+	for len(s.decided) > s.maxDecisions {
+		removed = append(removed, s.decided[0])
+		ops = append(ops, storage.DeleteOperation(decisionKey(s.decided[0])))
+		s.decided = s.decided[1:]
+	}
+	ops = append(ops, s.decisions.update(added, removed)...)
+
+	return errors.Join(errs, s.client.Batch(ctx, ops...))
+}
+
+func (s *traceStorage) close(ctx context.Context) error {
+	return s.client.Close(ctx)
+}
+
+// idIndex persists a set of trace IDs, so that they can be listed when loading the storage.
+// The IDs are written in numbered segments and each update only writes the added IDs in a
+// new segment, the key of the index holding the range of the segments. Removed IDs are kept
+// in the segments until they outnumber the remaining ones, the index is then compacted into
+// a single segment.
+type idIndex struct {
+	key string
+	ids map[pcommon.TraceID]struct{}
+
+	// first and next are the numbers of the first segment and of the next one to be written.
+	first, next uint64
+	// written is the number of IDs in the segments, including the removed ones.
+	written int
+}
+
+func newIDIndex(key string) *idIndex {
+	return &idIndex{
+		key: key,
+		ids: make(map[pcommon.TraceID]struct{}),
+	}
+}
+
+func (x *idIndex) contains(id pcommon.TraceID) bool {
+	_, ok := x.ids[id]
+	return ok
+}
+
+// load returns the IDs of the index in the order they were added.
+func (x *idIndex) load(ctx context.Context, client storage.Client) ([]pcommon.TraceID, error) {
+	header, err := client.Get(ctx, x.key)
+	if err != nil || header == nil {
+		return nil, err
+	}
+	if len(header) != 16 {
+		return nil, fmt.Errorf("%w: invalid index %q length %d", errCorruptedTraceData, x.key, len(header))
+	}
+	x.first = binary.BigEndian.Uint64(header[0:])
+	x.next = binary.BigEndian.Uint64(header[8:])
+
+	var ids []pcommon.TraceID
+	for n := x.first; n < x.next; n++ {
+		segment, err := client.Get(ctx, x.segmentKey(n))
+		if err != nil {
+			return nil, err
+		}
+		if len(segment)%16 != 0 {
+			return nil, fmt.Errorf("%w: invalid index segment %q length %d", errCorruptedTraceData, x.segmentKey(n), len(segment))
+		}
+		for i := 0; i < len(segment); i += 16 {
+			id := pcommon.TraceID(segment[i : i+16])
+			x.written++
+			if _, ok := x.ids[id]; ok {
+				continue
+			}
+			x.ids[id] = struct{}{}
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// remove removes the IDs without writing the index, the change is written by the next update.
+func (x *idIndex) remove(ids ...pcommon.TraceID) {
+	for _, id := range ids {
+		delete(x.ids, id)
+	}
+}
+
+// update returns the operations adding and removing the IDs from the index.
+func (x *idIndex) update(added, removed []pcommon.TraceID) []storage.Operation {
+	x.remove(removed...)
+	if len(added) == 0 && x.written-len(x.ids) < max(minIndexCompaction, len(x.ids)) {
+		return nil
+	}
+
+	var ops []storage.Operation
+	segment := make([]byte, 0, 16*len(added))
+	for _, id := range added {
+		x.ids[id] = struct{}{}
+		segment = append(segment, id[:]...)
+	}
+	x.written += len(added)
+
+	if removedIDs := x.written - len(x.ids); removedIDs >= max(minIndexCompaction, len(x.ids)) {
+		segment = make([]byte, 0, 16*len(x.ids))
+		for id := range x.ids {
+			segment = append(segment, id[:]...)
+		}
+		for n := x.first; n < x.next; n++ {
+			ops = append(ops, storage.DeleteOperation(x.segmentKey(n)))
+		}
+		x.first = x.next
+		x.written = len(x.ids)
+	}
+
+	ops = append(ops, storage.SetOperation(x.segmentKey(x.next), segment))
+	x.next++
+	header := make([]byte, 16)
+	binary.BigEndian.PutUint64(header[0:], x.first)
+	binary.BigEndian.PutUint64(header[8:], x.next)
+	return append(ops, storage.SetOperation(x.key, header))
+}
+
+func (x *idIndex) segmentKey(n uint64) string {
+	return x.key + "/" + strconv.FormatUint(n, 10)
+}
+
+func traceKey(id pcommon.TraceID) string {
+	return storageTraceKeyPrefix + id.String()
+}
+
+func decisionKey(id pcommon.TraceID) string {
+	return storageDecisionKeyPrefix + id.String()
+}
+
+// marshalTraceData encodes the arrival time, decision time and final decision of the trace
+// followed by the spans still waiting for a decision.
+func marshalTraceData(trace *sampling.TraceData) ([]byte, error) {
+	trace.Lock()
+	defer trace.Unlock()
+
+	spans, err := (&ptrace.ProtoMarshaler{}).MarshalTraces(trace.ReceivedBatches)
+	if err != nil {
+		return nil, err
+	}
+	data := make([]byte, traceDataHeaderSize, traceDataHeaderSize+len(spans))
+	binary.BigEndian.PutUint64(data[0:], timeToUnixNano(trace.ArrivalTime))
+	binary.BigEndian.PutUint64(data[8:], timeToUnixNano(trace.DecisionTime))
+	binary.BigEndian.PutUint32(data[16:], uint32(trace.FinalDecision))
+	return append(data, spans...), nil
+}
+
+func unmarshalTraceData(data []byte) (*sampling.TraceData, error) {
+	if len(data) < traceDataHeaderSize {
+		return nil, errCorruptedTraceData
+	}
+	spans, err := (&ptrace.ProtoUnmarshaler{}).UnmarshalTraces(data[traceDataHeaderSize:])
+	if err != nil {
+		return nil, err
+	}
+	spanCount := &atomic.Int64{}
+	spanCount.Store(int64(spans.SpanCount()))
+	return &sampling.TraceData{
+		ArrivalTime:     unixNanoToTime(binary.BigEndian.Uint64(data[0:])),
+		DecisionTime:    unixNanoToTime(binary.BigEndian.Uint64(data[8:])),
+		FinalDecision:   sampling.Decision(binary.BigEndian.Uint32(data[16:])),
+		SpanCount:       spanCount,
+		ReceivedBatches: spans,
+	}, nil
+}
+
+func timeToUnixNano(t time.Time) uint64 {
+	if t.IsZero() {
+		return 0
+	}
+	return uint64(t.UnixNano())
+}
+
+func unixNanoToTime(ns uint64) time.Time {
+	if ns == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, int64(ns))
+}
@@ -0,0 +1,352 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package tailsamplingprocessor
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/processor/processortest"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storagetest"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/sampling"
+)
+
+func TestTraceDataRoundTrip(t *testing.T) {
+	spanCount := &atomic.Int64{}
+	spanCount.Store(1)
+	trace := &sampling.TraceData{
+		ArrivalTime:     time.Unix(10, 20),
+		SpanCount:       spanCount,
+		ReceivedBatches: simpleTraces(),
+		FinalDecision:   sampling.Unspecified,
+	}
+
+	data, err := marshalTraceData(trace)
+	require.NoError(t, err)
+	got, err := unmarshalTraceData(data)
+	require.NoError(t, err)
+	assert.True(t, trace.ArrivalTime.Equal(got.ArrivalTime))
+	assert.True(t, got.DecisionTime.IsZero())
+	assert.Equal(t, sampling.Unspecified, got.FinalDecision)
+	assert.Equal(t, int64(1), got.SpanCount.Load())
+	assert.Equal(t, trace.ReceivedBatches, got.ReceivedBatches)
+
+	trace.DecisionTime = time.Unix(30, 0)
+	trace.FinalDecision = sampling.NotSampled
+	trace.ReceivedBatches = ptrace.NewTraces()
+	data, err = marshalTraceData(trace)
+	require.NoError(t, err)
+	got, err = unmarshalTraceData(data)
+	require.NoError(t, err)
+	assert.True(t, trace.DecisionTime.Equal(got.DecisionTime))
+	assert.Equal(t, sampling.NotSampled, got.FinalDecision)
+	assert.Equal(t, 0, got.ReceivedBatches.SpanCount())
+
+	_, err = unmarshalTraceData(data[:traceDataHeaderSize-1])
+	assert.ErrorIs(t, err, errCorruptedTraceData)
+}
+
+func TestStorageNotFound(t *testing.T) {
+	storageID := storagetest.NewNonStorageID("test")
+	cfg := Config{
+		DecisionWait: defaultTestDecisionWait,
+		NumTraces:    defaultNumTraces,
+		PolicyCfgs:   testPolicy,
+		StorageID:    &storageID,
+	}
+	p, err := newTracesProcessor(context.Background(), processortest.NewNopSettings(), consumertest.NewNop(), cfg, withDecisionBatcher(newSyncIDBatcher()))
+	require.NoError(t, err)
+
+	require.Error(t, p.Start(context.Background(), storagetest.NewStorageHost()))
+	require.Error(t, p.Start(context.Background(), storagetest.NewStorageHost().WithNonStorageExtension("test")))
+}
+
+func TestTracesSurviveRestart(t *testing.T) {
+	storageDir := t.TempDir()
+	ext := storagetest.NewFileBackedStorageExtension("test", storageDir)
+	host := storagetest.NewStorageHost().WithExtension(ext.ID, ext)
+	cfg := Config{
+		DecisionWait: time.Millisecond,
+		NumTraces:    defaultNumTraces,
+		StorageID:    &ext.ID,
+	}
+	// The storage client of the processor depends on its ID, which must not change across the restart.
+	set := processortest.NewNopSettings()
+	notSampledID := uInt64ToTraceID(1)
+	pendingID := uInt64ToTraceID(2)
+
+	// Before the restart, a first trace is not sampled and a second one is still pending.
+	msp := new(consumertest.TracesSink)
+	mpe := &mockPolicyEvaluator{NextDecision: sampling.NotSampled}
+	policies := []*policy{{name: "mock-policy", evaluator: mpe, attribute: metric.WithAttributes(attribute.String("policy", "mock-policy"))}}
+	p, err := newTracesProcessor(context.Background(), set, msp, cfg, withDecisionBatcher(newSyncIDBatcher()), withPolicies(policies))
+	require.NoError(t, err)
+	require.NoError(t, p.Start(context.Background(), host))
+
+	require.NoError(t, p.ConsumeTraces(context.Background(), simpleTracesWithID(notSampledID)))
+	tsp := p.(*tailSamplingSpanProcessor)
+	tsp.policyTicker.OnTick() // the first tick always gets an empty batch
+	tsp.policyTicker.OnTick()
+	require.Equal(t, 1, mpe.EvaluationCount)
+
+	require.NoError(t, p.ConsumeTraces(context.Background(), simpleTracesWithID(pendingID)))
+	require.NoError(t, p.Shutdown(context.Background()))
+	assert.Empty(t, msp.AllTraces())
+	// After the restart, the decision is kept and the pending trace is evaluated.
+	mpe = &mockPolicyEvaluator{NextDecision: sampling.Sampled}
+	policies = []*policy{{name: "mock-policy", evaluator: mpe, attribute: metric.WithAttributes(attribute.String("policy", "mock-policy"))}}
+	p, err = newTracesProcessor(context.Background(), set, msp, cfg, withDecisionBatcher(newSyncIDBatcher()), withPolicies(policies))
+	require.NoError(t, err)
+	require.NoError(t, p.Start(context.Background(), host))
+	defer func() {
+		require.NoError(t, p.Shutdown(context.Background()))
+	}()
+
+	tsp = p.(*tailSamplingSpanProcessor)
+	assert.Equal(t, uint64(2), tsp.numTracesOnMap.Load())
+
+	require.NoError(t, p.ConsumeTraces(context.Background(), simpleTracesWithID(notSampledID)))
+	require.Eventually(t, func() bool {
+		tsp.policyTicker.OnTick()
+		return len(msp.AllTraces()) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, 1, mpe.EvaluationCount)
+	assert.Equal(t, pendingID, msp.AllTraces()[0].ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).TraceID())
+}
+
+func TestDroppedTracesAreRemovedFromStorage(t *testing.T) {
+	ext := storagetest.NewInMemoryStorageExtension("test")
+	client, err := ext.GetClient(context.Background(), component.KindProcessor, component.MustNewID("tail_sampling"), "")
+	require.NoError(t, err)
+	s := newTraceStorage(client, 0)
+
+	traces := map[pcommon.TraceID]*sampling.TraceData{}
+	lookup := func(id pcommon.TraceID) (*sampling.TraceData, bool) {
+		trace, ok := traces[id]
+		return trace, ok
+	}
+	for i := uint64(1); i <= 3; i++ {
+		id := uInt64ToTraceID(i)
+		spanCount := &atomic.Int64{}
+		spanCount.Store(1)
+		traces[id] = &sampling.TraceData{ArrivalTime: time.Now(), SpanCount: spanCount, ReceivedBatches: simpleTracesWithID(id)}
+		s.traceChanged(id)
+	}
+	require.NoError(t, s.flush(context.Background(), lookup))
+
+	delete(traces, uInt64ToTraceID(2))
+	s.traceDropped(uInt64ToTraceID(2), sampling.Sampled)
+	require.NoError(t, s.flush(context.Background(), lookup))
+
+	loaded, sampled, err := newTraceStorage(client, 0).load(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, sampled)
+	assert.Len(t, loaded, 2)
+	assert.Contains(t, loaded, uInt64ToTraceID(1))
+	assert.Contains(t, loaded, uInt64ToTraceID(3))
+	data, err := client.Get(context.Background(), traceKey(uInt64ToTraceID(2)))
+	require.NoError(t, err)
+	assert.Nil(t, data)
+}
+
+func TestDroppedSampledDecisionsArePersisted(t *testing.T) {
+	ext := storagetest.NewInMemoryStorageExtension("test")
+	client, err := ext.GetClient(context.Background(), component.KindProcessor, component.MustNewID("tail_sampling"), "")
+	require.NoError(t, err)
+	s := newTraceStorage(client, 2)
+	lookup := func(pcommon.TraceID) (*sampling.TraceData, bool) {
+		return nil, false
+	}
+
+	s.traceDropped(uInt64ToTraceID(1), sampling.Sampled)
+	s.traceDropped(uInt64ToTraceID(2), sampling.NotSampled)
+	s.traceDropped(uInt64ToTraceID(3), sampling.Sampled)
+	require.NoError(t, s.flush(context.Background(), lookup))
+	s.traceDropped(uInt64ToTraceID(4), sampling.Sampled)
+	require.NoError(t, s.flush(context.Background(), lookup))
+
+	// Only the latest decisions are kept, like in the sampled decision cache.
+	loaded, sampled, err := newTraceStorage(client, 2).load(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, loaded)
+	assert.Equal(t, []pcommon.TraceID{uInt64ToTraceID(3), uInt64ToTraceID(4)}, sampled)
+	data, err := client.Get(context.Background(), decisionKey(uInt64ToTraceID(1)))
+	require.NoError(t, err)
+	assert.Nil(t, data)
+}
+
+func TestIDIndexCompaction(t *testing.T) {
+	client := storagetest.NewInMemoryClient(component.KindProcessor, component.MustNewID("tail_sampling"), "")
+	x := newIDIndex("index")
+	apply := func(added, removed []pcommon.TraceID) {
+		require.NoError(t, client.Batch(context.Background(), x.update(added, removed)...))
+	}
+
+	// Every update writes a new segment holding the added IDs only.
+	var all []pcommon.TraceID
+	for i := uint64(1); i <= minIndexCompaction+1; i++ {
+		all = append(all, uInt64ToTraceID(i))
+		apply(all[len(all)-1:], nil)
+	}
+	assert.Equal(t, uint64(minIndexCompaction+1), x.next-x.first)
+
+	// Removing IDs doesn't write the index until most of them are removed.
+	assert.Empty(t, x.update(nil, all[:minIndexCompaction/2]))
+	apply(nil, all[minIndexCompaction/2:minIndexCompaction])
+	assert.Equal(t, uint64(1), x.next-x.first)
+
+	loaded, err := newIDIndex("index").load(context.Background(), client)
+	require.NoError(t, err)
+	assert.Equal(t, all[minIndexCompaction:], loaded)
+	keys := 0
+	for n := uint64(0); n < x.next; n++ {
+		data, err := client.Get(context.Background(), x.segmentKey(n))
+		require.NoError(t, err)
+		if data != nil {
+			keys++
+		}
+	}
+	assert.Equal(t, 1, keys)
+}
+
+// sharedStorage is a storage extension returning the same client to every component,
+// like a storage shared by several collectors.
+type sharedStorage struct {
+	component.StartFunc
+	component.ShutdownFunc
+	client storage.Client
+}
+
+func (s *sharedStorage) GetClient(context.Context, component.Kind, component.ID, string) (storage.Client, error) {
+	return s.client, nil
+}
+
+func TestSharedDecisionCache(t *testing.T) {
+	storageID := component.MustNewID("shared")
+	ext := &sharedStorage{client: storagetest.NewInMemoryClient(component.KindProcessor, component.MustNewID("tail_sampling"), "decisions")}
+	host := storagetest.NewStorageHost().WithExtension(storageID, ext)
+	cfg := Config{
+		DecisionWait:  defaultTestDecisionWait,
+		NumTraces:     defaultNumTraces,
+		DecisionCache: DecisionCacheConfig{SampledCacheSize: 10, StorageID: &storageID},
+	}
+	newProcessor := func(decision sampling.Decision, next *consumertest.TracesSink) (*tailSamplingSpanProcessor, *mockPolicyEvaluator) {
+		mpe := &mockPolicyEvaluator{NextDecision: decision}
+		policies := []*policy{{name: "mock-policy", evaluator: mpe, attribute: metric.WithAttributes(attribute.String("policy", "mock-policy"))}}
+		p, err := newTracesProcessor(context.Background(), processortest.NewNopSettings(), next, cfg, withDecisionBatcher(newSyncIDBatcher()), withPolicies(policies))
+		require.NoError(t, err)
+		require.NoError(t, p.Start(context.Background(), host))
+		return p.(*tailSamplingSpanProcessor), mpe
+	}
+
+	sink1 := new(consumertest.TracesSink)
+	tsp1, _ := newProcessor(sampling.Sampled, sink1)
+	sink2 := new(consumertest.TracesSink)
+	tsp2, mpe2 := newProcessor(sampling.NotSampled, sink2)
+
+	sampledID := uInt64ToTraceID(1)
+	pendingID := uInt64ToTraceID(2)
+
+	// Both processors receive spans of the same trace, the second one is still waiting for a decision.
+	require.NoError(t, tsp1.ConsumeTraces(context.Background(), simpleTracesWithID(sampledID)))
+	require.NoError(t, tsp2.ConsumeTraces(context.Background(), simpleTracesWithID(sampledID)))
+	tsp1.policyTicker.OnTick() // the first tick always gets an empty batch
+	tsp1.policyTicker.OnTick()
+	require.Len(t, sink1.AllTraces(), 1)
+
+	// The first processor's decision applies to the second one.
+	tsp2.policyTicker.OnTick()
+	tsp2.policyTicker.OnTick()
+	assert.Equal(t, 0, mpe2.EvaluationCount)
+	require.Len(t, sink2.AllTraces(), 1)
+
+	// Spans of a trace sampled by another processor are released once a decision is due, without a
+	// storage lookup while they are received.
+	require.NoError(t, tsp1.ConsumeTraces(context.Background(), simpleTracesWithID(pendingID)))
+	tsp1.policyTicker.OnTick()
+	tsp1.policyTicker.OnTick()
+	require.Len(t, sink1.AllTraces(), 2)
+	require.NoError(t, tsp2.ConsumeTraces(context.Background(), simpleTracesWithID(pendingID)))
+	assert.Len(t, sink2.AllTraces(), 1)
+	tsp2.policyTicker.OnTick()
+	tsp2.policyTicker.OnTick()
+	assert.Equal(t, 0, mpe2.EvaluationCount)
+	assert.Len(t, sink2.AllTraces(), 2)
+
+	require.NoError(t, tsp1.Shutdown(context.Background()))
+	require.NoError(t, tsp2.Shutdown(context.Background()))
+}
+
+// countingClient counts the accesses to the storage.
+type countingClient struct {
+	storage.Client
+	sets    atomic.Int64
+	batches atomic.Int64
+}
+
+func (c *countingClient) Set(ctx context.Context, key string, value []byte) error {
+	c.sets.Add(1)
+	return c.Client.Set(ctx, key, value)
+}
+
+func (c *countingClient) Batch(ctx context.Context, ops ...storage.Operation) error {
+	c.batches.Add(1)
+	for _, op := range ops {
+		if op.Type == storage.Set {
+			c.sets.Add(1)
+		}
+	}
+	return c.Client.Batch(ctx, ops...)
+}
+
+func TestSharedDecisionIsStoredOnce(t *testing.T) {
+	storageID := component.MustNewID("shared")
+	client := &countingClient{Client: storagetest.NewInMemoryClient(component.KindProcessor, component.MustNewID("tail_sampling"), "decisions")}
+	host := storagetest.NewStorageHost().WithExtension(storageID, &sharedStorage{client: client})
+	cfg := Config{
+		DecisionWait:  defaultTestDecisionWait,
+		NumTraces:     defaultNumTraces,
+		DecisionCache: DecisionCacheConfig{SampledCacheSize: 10, StorageID: &storageID},
+	}
+	mpe := &mockPolicyEvaluator{NextDecision: sampling.Sampled}
+	policies := []*policy{{name: "mock-policy", evaluator: mpe, attribute: metric.WithAttributes(attribute.String("policy", "mock-policy"))}}
+	sink := new(consumertest.TracesSink)
+	p, err := newTracesProcessor(context.Background(), processortest.NewNopSettings(), sink, cfg, withDecisionBatcher(newSyncIDBatcher()), withPolicies(policies))
+	require.NoError(t, err)
+	require.NoError(t, p.Start(context.Background(), host))
+	defer func() {
+		require.NoError(t, p.Shutdown(context.Background()))
+	}()
+	tsp := p.(*tailSamplingSpanProcessor)
+
+	id := uInt64ToTraceID(1)
+	require.NoError(t, p.ConsumeTraces(context.Background(), simpleTracesWithID(id)))
+	tsp.policyTicker.OnTick() // the first tick always gets an empty batch
+	tsp.policyTicker.OnTick()
+	require.Equal(t, int64(1), client.sets.Load())
+	batches := client.batches.Load()
+
+	// Late spans, before and after the trace is dropped from memory, don't write the decision again,
+	// and spans are received without accessing the storage.
+	require.NoError(t, p.ConsumeTraces(context.Background(), simpleTracesWithID(id)))
+	tsp.dropTrace(id, time.Now())
+	require.NoError(t, p.ConsumeTraces(context.Background(), simpleTracesWithID(id)))
+	require.NoError(t, p.ConsumeTraces(context.Background(), simpleTracesWithID(uInt64ToTraceID(2))))
+	assert.Len(t, sink.AllTraces(), 3)
+	assert.Equal(t, int64(1), client.sets.Load())
+	assert.Equal(t, batches, client.batches.Load())
+}
@@ -5,12 +5,14 @@ package transformprocessor // import "github.com/open-telemetry/opentelemetry-co
 
 import (
 	"errors"
+	"fmt"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/featuregate"
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/lookuptable"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor/internal/common"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor/internal/logs"
@@ -41,7 +43,15 @@ type Config struct {
 	LogStatements    []common.ContextStatements `mapstructure:"log_statements"`
 
 	FlattenData bool `mapstructure:"flatten_data"`
-	logger      *zap.Logger
+
+	// LookupTables maps the name of lookup tables, used by the `Lookup` converter and the `lookup` editor, to the
+	// storage extension they are read from.
+	LookupTables map[string]component.ID `mapstructure:"lookup_tables"`
+
+	// Functions declares user-defined functions, which may be called like editors by the statements of all contexts.
+	Functions []ottl.FunctionDefinition `mapstructure:"functions"`
+
+	logger *zap.Logger
 }
 
 var _ component.Config = (*Config)(nil)
@@ -53,8 +63,25 @@ func (c *Config) Validate() error {
 		c.logger.Sugar().Infof("Metric conversion functions use metric context since %s is enabled. If your statements are not parsing, check if you're using the metrics conversion functions via the datapoint context.", metrics.UseConvertBetweenSumAndGaugeMetricContext.ID())
 	}
 
+	names := make(map[string]bool, len(c.Functions))
+	for _, definition := range c.Functions {
+		if names[definition.Name] {
+			errors = multierr.Append(errors, fmt.Errorf("duplicate function %q", definition.Name))
+		}
+		names[definition.Name] = true
+		if err := definition.Validate(); err != nil {
+			errors = multierr.Append(errors, err)
+		}
+	}
+	if errors != nil {
+		return errors
+	}
+
+	// The statements are parsed with tables which are not started, as they are not used by the validation
+	lookupTables := ottlLookupTables(lookuptable.NewTables(c.LookupTables, component.KindProcessor, component.ID{}))
+
 	if len(c.TraceStatements) > 0 {
-		pc, err := common.NewTraceParserCollection(component.TelemetrySettings{Logger: zap.NewNop()}, common.WithSpanParser(traces.SpanFunctions()), common.WithSpanEventParser(traces.SpanEventFunctions()))
+		pc, err := common.NewTraceParserCollection(component.TelemetrySettings{Logger: zap.NewNop()}, common.WithSpanParser(traces.SpanFunctions()), common.WithSpanEventParser(traces.SpanEventFunctions()), common.WithTraceFunctionDefinitions(c.Functions), common.WithTraceLookupTables(lookupTables))
 		if err != nil {
 			return err
 		}
@@ -67,7 +94,7 @@ func (c *Config) Validate() error {
 	}
 
 	if len(c.MetricStatements) > 0 {
-		pc, err := common.NewMetricParserCollection(component.TelemetrySettings{Logger: zap.NewNop()}, common.WithMetricParser(metrics.MetricFunctions()), common.WithDataPointParser(metrics.DataPointFunctions()))
+		pc, err := common.NewMetricParserCollection(component.TelemetrySettings{Logger: zap.NewNop()}, common.WithMetricParser(metrics.MetricFunctions()), common.WithDataPointParser(metrics.DataPointFunctions()), common.WithMetricFunctionDefinitions(c.Functions), common.WithMetricLookupTables(lookupTables))
 		if err != nil {
 			return err
 		}
@@ -80,7 +107,7 @@ func (c *Config) Validate() error {
 	}
 
 	if len(c.LogStatements) > 0 {
-		pc, err := common.NewLogParserCollection(component.TelemetrySettings{Logger: zap.NewNop()}, common.WithLogParser(logs.LogFunctions()))
+		pc, err := common.NewLogParserCollection(component.TelemetrySettings{Logger: zap.NewNop()}, common.WithLogParser(logs.LogFunctions()), common.WithLogFunctionDefinitions(c.Functions), common.WithLogLookupTables(lookupTables))
 		if err != nil {
 			return err
 		}
@@ -125,6 +125,52 @@ func TestLoadConfig(t *testing.T) {
 				LogStatements:    []common.ContextStatements{},
 			},
 		},
+		{
+			id: component.NewIDWithName(metadata.Type, "lookup_tables"),
+			expected: &Config{
+				ErrorMode:        ottl.PropagateError,
+				TraceStatements:  []common.ContextStatements{},
+				MetricStatements: []common.ContextStatements{},
+				LogStatements: []common.ContextStatements{
+					{
+						Context: "log",
+						Statements: []string{
+							`lookup(attributes["team"], "owners", resource.attributes["k8s.namespace.name"])`,
+						},
+					},
+				},
+				LookupTables: map[string]component.ID{
+					"owners": component.MustNewIDWithName("file_storage", "owners"),
+				},
+			},
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "functions"),
+			expected: &Config{
+				ErrorMode:        ottl.PropagateError,
+				TraceStatements:  []common.ContextStatements{},
+				MetricStatements: []common.ContextStatements{},
+				LogStatements: []common.ContextStatements{
+					{
+						Context: "log",
+						Statements: []string{
+							`copy_to(attributes["user"], resource.attributes["user.name"])`,
+							`for each k, v in attributes["labels"] { copy_to(attributes[k], v) }`,
+						},
+					},
+				},
+				Functions: []ottl.FunctionDefinition{
+					{
+						Name:       "copy_to",
+						Parameters: []string{"target", "value"},
+						Statements: []string{`set(target, value) where value != nil`},
+					},
+				},
+			},
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "bad_function"),
+		},
 		{
 			id: component.NewIDWithName(metadata.Type, "bad_syntax_trace"),
 		},
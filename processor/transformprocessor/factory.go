@@ -12,6 +12,8 @@ import (
 	"go.opentelemetry.io/collector/processor"
 	"go.opentelemetry.io/collector/processor/processorhelper"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/lookuptable"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/sharedcomponent"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor/internal/common"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor/internal/logs"
@@ -22,6 +24,9 @@ import (
 
 var processorCapabilities = consumer.Capabilities{MutatesData: true}
 
+// lookupTables holds the storage lookup tables of the processors, shared by their traces, metrics and logs instances
+var lookupTables = sharedcomponent.NewSharedComponents()
+
 func NewFactory() processor.Factory {
 	return processor.NewFactory(
 		metadata.Type,
@@ -48,8 +53,9 @@ func createLogsProcessor(
 	nextConsumer consumer.Logs,
 ) (processor.Logs, error) {
 	oCfg := cfg.(*Config)
+	tables, tableOptions := storageLookupTables(oCfg, set)
 
-	proc, err := logs.NewProcessor(oCfg.LogStatements, oCfg.ErrorMode, oCfg.FlattenData, set.TelemetrySettings)
+	proc, err := logs.NewProcessor(oCfg.LogStatements, oCfg.ErrorMode, oCfg.FlattenData, set.TelemetrySettings, common.WithLogFunctionDefinitions(oCfg.Functions), common.WithLogLookupTables(tables))
 	if err != nil {
 		return nil, fmt.Errorf("invalid config for \"transform\" processor %w", err)
 	}
@@ -59,7 +65,7 @@ func createLogsProcessor(
 		cfg,
 		nextConsumer,
 		proc.ProcessLogs,
-		processorhelper.WithCapabilities(processorCapabilities))
+		append(tableOptions, processorhelper.WithCapabilities(processorCapabilities))...)
 }
 
 func createTracesProcessor(
@@ -69,8 +75,9 @@ func createTracesProcessor(
 	nextConsumer consumer.Traces,
 ) (processor.Traces, error) {
 	oCfg := cfg.(*Config)
+	tables, tableOptions := storageLookupTables(oCfg, set)
 
-	proc, err := traces.NewProcessor(oCfg.TraceStatements, oCfg.ErrorMode, set.TelemetrySettings)
+	proc, err := traces.NewProcessor(oCfg.TraceStatements, oCfg.ErrorMode, set.TelemetrySettings, common.WithTraceFunctionDefinitions(oCfg.Functions), common.WithTraceLookupTables(tables))
 	if err != nil {
 		return nil, fmt.Errorf("invalid config for \"transform\" processor %w", err)
 	}
@@ -80,7 +87,7 @@ func createTracesProcessor(
 		cfg,
 		nextConsumer,
 		proc.ProcessTraces,
-		processorhelper.WithCapabilities(processorCapabilities))
+		append(tableOptions, processorhelper.WithCapabilities(processorCapabilities))...)
 }
 
 func createMetricsProcessor(
@@ -91,8 +98,9 @@ func createMetricsProcessor(
 ) (processor.Metrics, error) {
 	oCfg := cfg.(*Config)
 	oCfg.logger = set.Logger
+	tables, tableOptions := storageLookupTables(oCfg, set)
 
-	proc, err := metrics.NewProcessor(oCfg.MetricStatements, oCfg.ErrorMode, set.TelemetrySettings)
+	proc, err := metrics.NewProcessor(oCfg.MetricStatements, oCfg.ErrorMode, set.TelemetrySettings, common.WithMetricFunctionDefinitions(oCfg.Functions), common.WithMetricLookupTables(tables))
 	if err != nil {
 		return nil, fmt.Errorf("invalid config for \"transform\" processor %w", err)
 	}
@@ -102,5 +110,29 @@ func createMetricsProcessor(
 		cfg,
 		nextConsumer,
 		proc.ProcessMetrics,
-		processorhelper.WithCapabilities(processorCapabilities))
+		append(tableOptions, processorhelper.WithCapabilities(processorCapabilities))...)
+}
+
+// storageLookupTables returns the storage lookup tables of the processor, shared by its traces, metrics and logs
+// instances, and the options starting and stopping them.
+func storageLookupTables(cfg *Config, set processor.Settings) (map[string]ottl.LookupTable, []processorhelper.Option) {
+	if len(cfg.LookupTables) == 0 {
+		return nil, nil
+	}
+	tables := lookupTables.GetOrAdd(cfg, func() component.Component {
+		return lookuptable.NewTables(cfg.LookupTables, component.KindProcessor, set.ID)
+	})
+	return ottlLookupTables(tables.Unwrap().(*lookuptable.Tables)), []processorhelper.Option{
+		processorhelper.WithStart(tables.Start),
+		processorhelper.WithShutdown(tables.Shutdown),
+	}
+}
+
+// ottlLookupTables returns the storage lookup tables as OTTL lookup tables.
+func ottlLookupTables(tables *lookuptable.Tables) map[string]ottl.LookupTable {
+	ottlTables := make(map[string]ottl.LookupTable, len(tables.Tables()))
+	for name, table := range tables.Tables() {
+		ottlTables[name] = table
+	}
+	return ottlTables
 }
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/consumer/consumertest"
@@ -16,6 +17,7 @@ import (
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.opentelemetry.io/collector/processor/processortest"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storagetest"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor/internal/common"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor/internal/metadata"
@@ -175,6 +177,65 @@ func TestFactoryCreateLogsProcessor(t *testing.T) {
 	assert.Equal(t, "pass", val.Str())
 }
 
+func TestFactoryCreateProcessorsWithLookupTables(t *testing.T) {
+	ext := storagetest.NewFileBackedStorageExtension("test", t.TempDir())
+	host := storagetest.NewStorageHost().WithExtension(ext.ID, ext)
+	set := processortest.NewNopSettings()
+
+	// Populate the table before the processors are started
+	client, err := ext.GetClient(context.Background(), component.KindProcessor, set.ID, "owners")
+	require.NoError(t, err)
+	require.NoError(t, client.Set(context.Background(), "checkout", []byte("payments")))
+	require.NoError(t, client.Close(context.Background()))
+
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+	oCfg := cfg.(*Config)
+	oCfg.LookupTables = map[string]component.ID{"owners": ext.ID}
+	oCfg.LogStatements = []common.ContextStatements{
+		{
+			Context:    "log",
+			Statements: []string{`lookup(attributes["team"], "owners", body)`},
+		},
+	}
+	oCfg.TraceStatements = []common.ContextStatements{
+		{
+			Context:    "span",
+			Statements: []string{`set(attributes["team"], Lookup("owners", name))`},
+		},
+	}
+
+	lp, err := factory.CreateLogsProcessor(context.Background(), set, cfg, consumertest.NewNop())
+	require.NoError(t, err)
+	tp, err := factory.CreateTracesProcessor(context.Background(), set, cfg, consumertest.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, lp.Start(context.Background(), host))
+	require.NoError(t, tp.Start(context.Background(), host))
+
+	ld := plog.NewLogs()
+	log := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	log.Body().SetStr("checkout")
+	require.NoError(t, lp.ConsumeLogs(context.Background(), ld))
+	val, ok := log.Attributes().Get("team")
+	assert.True(t, ok)
+	assert.Equal(t, "payments", val.Str())
+
+	td := ptrace.NewTraces()
+	span := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	span.SetName("search")
+	require.NoError(t, tp.ConsumeTraces(context.Background(), td))
+	_, ok = span.Attributes().Get("team")
+	assert.False(t, ok)
+
+	require.NoError(t, lp.Shutdown(context.Background()))
+	require.NoError(t, tp.Shutdown(context.Background()))
+
+	// The tables are only provided to the statements of the processor configuring them
+	oCfg.LookupTables = nil
+	_, err = factory.CreateLogsProcessor(context.Background(), set, cfg, consumertest.NewNop())
+	assert.ErrorContains(t, err, `lookup table "owners" is not configured`)
+}
+
 func TestFactoryCreateLogsProcessor_InvalidActions(t *testing.T) {
 	factory := NewFactory()
 	cfg := factory.CreateDefaultConfig()
@@ -80,6 +80,22 @@ func WithLogErrorMode(errorMode ottl.ErrorMode) LogParserCollectionOption {
 	}
 }
 
+// WithLogLookupTables provides lookup tables, by name, to all the parsers of the collection.
+func WithLogLookupTables(tables map[string]ottl.LookupTable) LogParserCollectionOption {
+	return func(lp *LogParserCollection) error {
+		lp.lookupTables = tables
+		return nil
+	}
+}
+
+// WithLogFunctionDefinitions declares user-defined functions in all the parsers of the collection.
+func WithLogFunctionDefinitions(definitions []ottl.FunctionDefinition) LogParserCollectionOption {
+	return func(lp *LogParserCollection) error {
+		lp.functionDefinitions = definitions
+		return nil
+	}
+}
+
 func NewLogParserCollection(settings component.TelemetrySettings, options ...LogParserCollectionOption) (*LogParserCollection, error) {
 	rp, err := ottlresource.NewParser(ResourceFunctions(), settings)
 	if err != nil {
@@ -103,6 +119,14 @@ func NewLogParserCollection(settings component.TelemetrySettings, options ...Log
 			return nil, err
 		}
 	}
+	if len(lpc.functionDefinitions) > 0 {
+		lpc.defineFunctions()
+		ottllog.WithFunctionDefinitions(lpc.functionDefinitions)(&lpc.logParser)
+	}
+	if len(lpc.lookupTables) > 0 {
+		lpc.provideLookupTables()
+		ottllog.WithLookupTables(lpc.lookupTables)(&lpc.logParser)
+	}
 
 	return lpc, nil
 }
@@ -206,6 +206,22 @@ func WithMetricErrorMode(errorMode ottl.ErrorMode) MetricParserCollectionOption
 	}
 }
 
+// WithMetricLookupTables provides lookup tables, by name, to all the parsers of the collection.
+func WithMetricLookupTables(tables map[string]ottl.LookupTable) MetricParserCollectionOption {
+	return func(mp *MetricParserCollection) error {
+		mp.lookupTables = tables
+		return nil
+	}
+}
+
+// WithMetricFunctionDefinitions declares user-defined functions in all the parsers of the collection.
+func WithMetricFunctionDefinitions(definitions []ottl.FunctionDefinition) MetricParserCollectionOption {
+	return func(mp *MetricParserCollection) error {
+		mp.functionDefinitions = definitions
+		return nil
+	}
+}
+
 func NewMetricParserCollection(settings component.TelemetrySettings, options ...MetricParserCollectionOption) (*MetricParserCollection, error) {
 	rp, err := ottlresource.NewParser(ResourceFunctions(), settings)
 	if err != nil {
@@ -229,6 +245,16 @@ func NewMetricParserCollection(settings component.TelemetrySettings, options ...
 			return nil, err
 		}
 	}
+	if len(mpc.functionDefinitions) > 0 {
+		mpc.defineFunctions()
+		ottlmetric.WithFunctionDefinitions(mpc.functionDefinitions)(&mpc.metricParser)
+		ottldatapoint.WithFunctionDefinitions(mpc.functionDefinitions)(&mpc.dataPointParser)
+	}
+	if len(mpc.lookupTables) > 0 {
+		mpc.provideLookupTables()
+		ottlmetric.WithLookupTables(mpc.lookupTables)(&mpc.metricParser)
+		ottldatapoint.WithLookupTables(mpc.lookupTables)(&mpc.dataPointParser)
+	}
 
 	return mpc, nil
 }
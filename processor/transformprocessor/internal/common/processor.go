@@ -170,10 +170,24 @@ func (s scopeStatements) ConsumeLogs(ctx context.Context, ld plog.Logs) error {
 }
 
 type parserCollection struct {
-	settings       component.TelemetrySettings
-	resourceParser ottl.Parser[ottlresource.TransformContext]
-	scopeParser    ottl.Parser[ottlscope.TransformContext]
-	errorMode      ottl.ErrorMode
+	settings            component.TelemetrySettings
+	resourceParser      ottl.Parser[ottlresource.TransformContext]
+	scopeParser         ottl.Parser[ottlscope.TransformContext]
+	errorMode           ottl.ErrorMode
+	functionDefinitions []ottl.FunctionDefinition
+	lookupTables        map[string]ottl.LookupTable
+}
+
+// defineFunctions declares the user-defined functions in the resource and scope parsers.
+func (pc *parserCollection) defineFunctions() {
+	ottlresource.WithFunctionDefinitions(pc.functionDefinitions)(&pc.resourceParser)
+	ottlscope.WithFunctionDefinitions(pc.functionDefinitions)(&pc.scopeParser)
+}
+
+// provideLookupTables provides the lookup tables to the resource and scope parsers.
+func (pc *parserCollection) provideLookupTables() {
+	ottlresource.WithLookupTables(pc.lookupTables)(&pc.resourceParser)
+	ottlscope.WithLookupTables(pc.lookupTables)(&pc.scopeParser)
 }
 
 type baseContext interface {
@@ -211,14 +225,14 @@ func (pc parserCollection) parseCommonContextStatements(contextStatement Context
 	}
 }
 
-func parseGlobalExpr[K any](
-	boolExprFunc func([]string, map[string]ottl.Factory[K], ottl.ErrorMode, component.TelemetrySettings) (expr.BoolExpr[K], error),
+func parseGlobalExpr[K any, O ~func(*ottl.Parser[K])](
+	boolExprFunc func([]string, map[string]ottl.Factory[K], ottl.ErrorMode, component.TelemetrySettings, ...O) (expr.BoolExpr[K], error),
 	conditions []string,
 	pc parserCollection,
 	standardFuncs map[string]ottl.Factory[K]) (expr.BoolExpr[K], error) {
 
 	if len(conditions) > 0 {
-		return boolExprFunc(conditions, standardFuncs, pc.errorMode, pc.settings)
+		return boolExprFunc(conditions, standardFuncs, pc.errorMode, pc.settings, O(ottl.WithLookupTables[K](pc.lookupTables)))
 	}
 	// By default, set the global expression to always true unless conditions are specified.
 	return expr.AlwaysTrue[K](), nil
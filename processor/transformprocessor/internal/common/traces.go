@@ -134,6 +134,22 @@ func WithTraceErrorMode(errorMode ottl.ErrorMode) TraceParserCollectionOption {
 	}
 }
 
+// WithTraceLookupTables provides lookup tables, by name, to all the parsers of the collection.
+func WithTraceLookupTables(tables map[string]ottl.LookupTable) TraceParserCollectionOption {
+	return func(tp *TraceParserCollection) error {
+		tp.lookupTables = tables
+		return nil
+	}
+}
+
+// WithTraceFunctionDefinitions declares user-defined functions in all the parsers of the collection.
+func WithTraceFunctionDefinitions(definitions []ottl.FunctionDefinition) TraceParserCollectionOption {
+	return func(tp *TraceParserCollection) error {
+		tp.functionDefinitions = definitions
+		return nil
+	}
+}
+
 func NewTraceParserCollection(settings component.TelemetrySettings, options ...TraceParserCollectionOption) (*TraceParserCollection, error) {
 	rp, err := ottlresource.NewParser(ResourceFunctions(), settings)
 	if err != nil {
@@ -157,6 +173,16 @@ func NewTraceParserCollection(settings component.TelemetrySettings, options ...T
 			return nil, err
 		}
 	}
+	if len(tpc.functionDefinitions) > 0 {
+		tpc.defineFunctions()
+		ottlspan.WithFunctionDefinitions(tpc.functionDefinitions)(&tpc.spanParser)
+		ottlspanevent.WithFunctionDefinitions(tpc.functionDefinitions)(&tpc.spanEventParser)
+	}
+	if len(tpc.lookupTables) > 0 {
+		tpc.provideLookupTables()
+		ottlspan.WithLookupTables(tpc.lookupTables)(&tpc.spanParser)
+		ottlspanevent.WithLookupTables(tpc.lookupTables)(&tpc.spanEventParser)
+	}
 
 	return tpc, nil
 }
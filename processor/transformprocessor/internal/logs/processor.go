@@ -23,8 +23,9 @@ type Processor struct {
 	flatMode bool
 }
 
-func NewProcessor(contextStatements []common.ContextStatements, errorMode ottl.ErrorMode, flatMode bool, settings component.TelemetrySettings) (*Processor, error) {
-	pc, err := common.NewLogParserCollection(settings, common.WithLogParser(LogFunctions()), common.WithLogErrorMode(errorMode))
+func NewProcessor(contextStatements []common.ContextStatements, errorMode ottl.ErrorMode, flatMode bool, settings component.TelemetrySettings, options ...common.LogParserCollectionOption) (*Processor, error) {
+	options = append([]common.LogParserCollectionOption{common.WithLogParser(LogFunctions()), common.WithLogErrorMode(errorMode)}, options...)
+	pc, err := common.NewLogParserCollection(settings, options...)
 	if err != nil {
 		return nil, err
 	}
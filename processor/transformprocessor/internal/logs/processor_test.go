@@ -481,6 +481,45 @@ func Test_ProcessLogs_MixContext(t *testing.T) {
 	}
 }
 
+func Test_ProcessLogs_FunctionDefinitions(t *testing.T) {
+	definitions := []ottl.FunctionDefinition{
+		{
+			Name:       "copy_to",
+			Parameters: []string{"target", "value"},
+			Statements: []string{`set(target, value)`},
+		},
+	}
+	contextStatements := []common.ContextStatements{
+		{
+			Context:    "resource",
+			Statements: []string{`copy_to(attributes["host"], attributes["host.name"])`},
+		},
+		{
+			Context:    "log",
+			Statements: []string{`for each i, flag in Split(attributes["flags"], "|") { copy_to(attributes[flag], i) }`},
+		},
+	}
+
+	td := constructLogs()
+	processor, err := NewProcessor(contextStatements, ottl.IgnoreError, false, componenttest.NewNopTelemetrySettings(), common.WithLogFunctionDefinitions(definitions))
+	assert.NoError(t, err)
+
+	_, err = processor.ProcessLogs(context.Background(), td)
+	assert.NoError(t, err)
+
+	exTd := constructLogs()
+	exTd.ResourceLogs().At(0).Resource().Attributes().PutStr("host", "localhost")
+	first := exTd.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Attributes()
+	first.PutInt("A", 0)
+	first.PutInt("B", 1)
+	first.PutInt("C", 2)
+	second := exTd.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(1).Attributes()
+	second.PutInt("C", 0)
+	second.PutInt("D", 1)
+
+	assert.Equal(t, exTd, td)
+}
+
 func Test_ProcessTraces_Error(t *testing.T) {
 	tests := []struct {
 		statement string
@@ -32,6 +32,27 @@ type MessageMarking struct {
 	OnError bool `mapstructure:"on_error"`
 }
 
+// CommitAfterConsume controls the commit of the offsets once the messages are accepted by the pipeline.
+type CommitAfterConsume struct {
+	// If true, a message is marked once the next consumer accepted its data, and its offset is
+	// committed before the next message of the partition is processed. This overrides the
+	// autocommit and message_marking settings. Messages are delivered at least once: a message
+	// accepted by the next consumer is consumed again if its offset could not be committed.
+	Enable bool `mapstructure:"enable"`
+}
+
+type DeadLetter struct {
+	// The topic to which the messages that fail unmarshaling or are permanently rejected
+	// by the next consumer are produced. Disabled if empty.
+	Topic string `mapstructure:"topic"`
+	// The transactional ID of the producer of the dead letter topic, used when commit_after_consume
+	// is enabled to produce a message to the dead letter topic and commit its offset in a single
+	// transaction. It must be unique to each collector instance. The ID of the receiver and the signal
+	// are appended to it, as each signal of a receiver has its own producer. Defaults to
+	// "<group_id>-<hostname>".
+	TransactionalID string `mapstructure:"transactional_id"`
+}
+
 type HeaderExtraction struct {
 	ExtractHeaders bool     `mapstructure:"extract_headers"`
 	Headers        []string `mapstructure:"headers"`
@@ -79,6 +100,12 @@ type Config struct {
 	// Extract headers from kafka records
 	HeaderExtraction HeaderExtraction `mapstructure:"header_extraction"`
 
+	// Commits offsets only once the messages are accepted by the pipeline
+	CommitAfterConsume CommitAfterConsume `mapstructure:"commit_after_consume"`
+
+	// Produces the messages that cannot be processed to a dead letter topic
+	DeadLetter DeadLetter `mapstructure:"dead_letter"`
+
 	// The minimum bytes per fetch from Kafka (default "1")
 	MinFetchSize int32 `mapstructure:"min_fetch_size"`
 	// The default bytes per fetch from Kafka (default "1048576")
@@ -99,6 +99,13 @@ func TestLoadConfig(t *testing.T) {
 					Enable:   true,
 					Interval: 1 * time.Second,
 				},
+				CommitAfterConsume: CommitAfterConsume{
+					Enable: true,
+				},
+				DeadLetter: DeadLetter{
+					Topic:           "logs_dlq",
+					TransactionalID: "otel-collector-1",
+				},
 				MinFetchSize:     1,
 				DefaultFetchSize: 1048576,
 				MaxFetchSize:     0,
@@ -0,0 +1,184 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package kafkareceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kafkareceiver"
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/IBM/sarama"
+	"go.opentelemetry.io/collector/component"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka"
+)
+
+const (
+	deadLetterReasonUnmarshal = "unmarshal_failed"
+	deadLetterReasonRejected  = "rejected"
+
+	deadLetterHeaderReason    = "otel.dead_letter.reason"
+	deadLetterHeaderError     = "otel.dead_letter.error"
+	deadLetterHeaderTopic     = "otel.dead_letter.topic"
+	deadLetterHeaderPartition = "otel.dead_letter.partition"
+	deadLetterHeaderOffset    = "otel.dead_letter.offset"
+)
+
+// deadLetterQueue produces the messages that cannot be processed to a dead letter topic,
+// so that they neither block their partition nor get lost.
+type deadLetterQueue struct {
+	topic    string
+	groupID  string
+	producer sarama.SyncProducer
+	// autocommit is true when the offsets of the marked messages are committed by the consumer group.
+	autocommit bool
+
+	// mu serializes the transactions of the producer, as the partitions are consumed concurrently.
+	mu sync.Mutex
+}
+
+// newDeadLetterQueue returns the dead letter queue of a signal of the receiver with the given ID.
+func newDeadLetterQueue(config Config, id component.ID, signal component.DataType) (*deadLetterQueue, error) {
+	var transactionalID string
+	if config.CommitAfterConsume.Enable {
+		var err error
+		if transactionalID, err = deadLetterTransactionalID(config, id, signal); err != nil {
+			return nil, err
+		}
+	}
+	producer, err := createDeadLetterProducer(config, transactionalID)
+	if err != nil {
+		return nil, err
+	}
+	autocommit, _ := offsetCommitSettings(config)
+	return &deadLetterQueue{
+		topic:      config.DeadLetter.Topic,
+		groupID:    config.GroupID,
+		producer:   producer,
+		autocommit: autocommit,
+	}, nil
+}
+
+// deadLetterTransactionalID returns the transactional ID of the dead letter producer of a signal of
+// the receiver with the given ID. The ID of the receiver and the signal are appended to the configured
+// transactional ID, so that the producers of the signals and receivers of a collector, which all get
+// their own producer, don't fence each other.
+func deadLetterTransactionalID(config Config, id component.ID, signal component.DataType) (string, error) {
+	prefix := config.DeadLetter.TransactionalID
+	if prefix == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return "", fmt.Errorf("failed to get the hostname for the default transactional id: %w", err)
+		}
+		prefix = config.GroupID + "-" + hostname
+	}
+	return prefix + "-" + id.String() + "-" + signal.String(), nil
+}
+
+// createDeadLetterProducer returns the producer of the dead letter topic, which is transactional if
+// transactionalID is not empty.
+func createDeadLetterProducer(config Config, transactionalID string) (sarama.SyncProducer, error) {
+	saramaConfig := sarama.NewConfig()
+	saramaConfig.ClientID = config.ClientID
+	saramaConfig.Metadata.Full = config.Metadata.Full
+	saramaConfig.Metadata.Retry.Max = config.Metadata.Retry.Max
+	saramaConfig.Metadata.Retry.Backoff = config.Metadata.Retry.Backoff
+	// These settings are required by the sarama.SyncProducer implementation.
+	saramaConfig.Producer.Return.Successes = true
+	saramaConfig.Producer.Return.Errors = true
+	saramaConfig.Producer.RequiredAcks = sarama.WaitForAll
+
+	if transactionalID != "" {
+		saramaConfig.Producer.Idempotent = true
+		saramaConfig.Producer.Transaction.ID = transactionalID
+		saramaConfig.Net.MaxOpenRequests = 1
+	}
+
+	var err error
+	if config.ResolveCanonicalBootstrapServersOnly {
+		saramaConfig.Net.ResolveCanonicalBootstrapServers = true
+	}
+	if config.ProtocolVersion != "" {
+		if saramaConfig.Version, err = sarama.ParseKafkaVersion(config.ProtocolVersion); err != nil {
+			return nil, err
+		}
+	}
+	if err = kafka.ConfigureAuthentication(config.Authentication, saramaConfig); err != nil {
+		return nil, err
+	}
+	return sarama.NewSyncProducer(config.Brokers, saramaConfig)
+}
+
+// send produces the message to the dead letter topic, with headers describing why it could not be
+// processed, and marks it. Its offset is committed right away unless offsets are auto-committed.
+// With a transactional producer, the message is produced and its offset committed in a single
+// transaction.
+func (q *deadLetterQueue) send(session sarama.ConsumerGroupSession, message *sarama.ConsumerMessage, reason string, cause error) error {
+	msg := &sarama.ProducerMessage{
+		Topic:   q.topic,
+		Key:     sarama.ByteEncoder(message.Key),
+		Value:   sarama.ByteEncoder(message.Value),
+		Headers: deadLetterHeaders(message, reason, cause),
+	}
+
+	if !q.producer.IsTransactional() {
+		if _, _, err := q.producer.SendMessage(msg); err != nil {
+			return fmt.Errorf("failed to produce message to dead letter topic: %w", err)
+		}
+		session.MarkMessage(message, "")
+		if !q.autocommit {
+			session.Commit()
+		}
+		return nil
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if err := q.producer.BeginTxn(); err != nil {
+		return fmt.Errorf("failed to begin dead letter transaction: %w", err)
+	}
+	if _, _, err := q.producer.SendMessage(msg); err != nil {
+		return q.abort(fmt.Errorf("failed to produce message to dead letter topic: %w", err))
+	}
+	if err := q.producer.AddMessageToTxn(message, q.groupID, nil); err != nil {
+		return q.abort(fmt.Errorf("failed to add offset to dead letter transaction: %w", err))
+	}
+	if err := q.producer.CommitTxn(); err != nil {
+		return q.abort(fmt.Errorf("failed to commit dead letter transaction: %w", err))
+	}
+	// Keep the session in line with the offset committed by the transaction.
+	session.MarkMessage(message, "")
+	return nil
+}
+
+func (q *deadLetterQueue) abort(err error) error {
+	if abortErr := q.producer.AbortTxn(); abortErr != nil {
+		return errors.Join(err, fmt.Errorf("failed to abort dead letter transaction: %w", abortErr))
+	}
+	return err
+}
+
+func (q *deadLetterQueue) close() error {
+	return q.producer.Close()
+}
+
+// deadLetterHeaders returns the headers of the message followed by the reason and error which
+// prevented its processing, and its origin.
+func deadLetterHeaders(message *sarama.ConsumerMessage, reason string, cause error) []sarama.RecordHeader {
+	headers := make([]sarama.RecordHeader, 0, len(message.Headers)+5)
+	for _, header := range message.Headers {
+		if header != nil {
+			headers = append(headers, *header)
+		}
+	}
+	return append(headers,
+		sarama.RecordHeader{Key: []byte(deadLetterHeaderReason), Value: []byte(reason)},
+		sarama.RecordHeader{Key: []byte(deadLetterHeaderError), Value: []byte(cause.Error())},
+		sarama.RecordHeader{Key: []byte(deadLetterHeaderTopic), Value: []byte(message.Topic)},
+		sarama.RecordHeader{Key: []byte(deadLetterHeaderPartition), Value: []byte(strconv.Itoa(int(message.Partition)))},
+		sarama.RecordHeader{Key: []byte(deadLetterHeaderOffset), Value: []byte(strconv.FormatInt(message.Offset, 10))},
+	)
+}
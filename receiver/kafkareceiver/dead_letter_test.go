@@ -0,0 +1,254 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package kafkareceiver
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/receiver/receiverhelper"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+	"go.uber.org/zap"
+)
+
+// markingConsumerGroupSession records the offsets of the marked messages.
+type markingConsumerGroupSession struct {
+	testConsumerGroupSession
+	mu      sync.Mutex
+	marked  []int64
+	commits int
+}
+
+func (s *markingConsumerGroupSession) MarkMessage(message *sarama.ConsumerMessage, _ string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.marked = append(s.marked, message.Offset)
+}
+
+func (s *markingConsumerGroupSession) Commit() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.commits++
+}
+
+func (s *markingConsumerGroupSession) commitCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.commits
+}
+
+func (s *markingConsumerGroupSession) markedOffsets() []int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.marked
+}
+
+func deadLetterChecker(reason string) mocks.MessageChecker {
+	return func(msg *sarama.ProducerMessage) error {
+		if msg.Topic != "dlq" {
+			return errors.New("unexpected topic " + msg.Topic)
+		}
+		for _, header := range msg.Headers {
+			if string(header.Key) == deadLetterHeaderReason && string(header.Value) == reason {
+				return nil
+			}
+		}
+		return errors.New("missing reason header")
+	}
+}
+
+func newDeadLetterTracesHandler(t *testing.T, producer sarama.SyncProducer, nextConsumer *consumertest.TracesSink, err error) *tracesConsumerGroupHandler {
+	obsrecv, obsErr := receiverhelper.NewObsReport(receiverhelper.ObsReportSettings{ReceiverCreateSettings: receivertest.NewNopSettings()})
+	require.NoError(t, obsErr)
+	c := &tracesConsumerGroupHandler{
+		unmarshaler:      newPdataTracesUnmarshaler(&ptrace.ProtoUnmarshaler{}, defaultEncoding),
+		logger:           zap.NewNop(),
+		ready:            make(chan bool),
+		nextConsumer:     nextConsumer,
+		obsrecv:          obsrecv,
+		headerExtractor:  &nopHeaderExtractor{},
+		telemetryBuilder: nopTelemetryBuilder(t),
+		messageMarking:   MessageMarking{After: true},
+		deadLetter:       &deadLetterQueue{topic: "dlq", groupID: defaultGroupID, producer: producer},
+	}
+	if err != nil {
+		c.nextConsumer = consumertest.NewErr(err)
+	}
+	return c
+}
+
+func consumeClaim(t *testing.T, c sarama.ConsumerGroupHandler, session sarama.ConsumerGroupSession, messages ...*sarama.ConsumerMessage) error {
+	groupClaim := &testConsumerGroupClaim{
+		messageChan: make(chan *sarama.ConsumerMessage),
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- c.ConsumeClaim(session, groupClaim)
+	}()
+	for _, message := range messages {
+		groupClaim.messageChan <- message
+	}
+	close(groupClaim.messageChan)
+	return <-errCh
+}
+
+func tracesMessage(t *testing.T, offset int64) *sarama.ConsumerMessage {
+	td := ptrace.NewTraces()
+	td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	bts, err := (&ptrace.ProtoMarshaler{}).MarshalTraces(td)
+	require.NoError(t, err)
+	return &sarama.ConsumerMessage{Value: bts, Offset: offset}
+}
+
+func TestTracesConsumerGroupHandler_dead_letter_unmarshal(t *testing.T) {
+	producer := mocks.NewSyncProducer(t, sarama.NewConfig())
+	producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(deadLetterChecker(deadLetterReasonUnmarshal))
+	sink := new(consumertest.TracesSink)
+	c := newDeadLetterTracesHandler(t, producer, sink, nil)
+
+	session := &markingConsumerGroupSession{testConsumerGroupSession: testConsumerGroupSession{ctx: context.Background()}}
+	err := consumeClaim(t, c, session, &sarama.ConsumerMessage{Value: []byte("!@#"), Offset: 1}, tracesMessage(t, 2))
+	require.NoError(t, err)
+	require.NoError(t, producer.Close())
+
+	// the invalid message does not block the partition
+	assert.Equal(t, []int64{1, 2}, session.markedOffsets())
+	assert.Equal(t, 1, sink.SpanCount())
+}
+
+func TestTracesConsumerGroupHandler_dead_letter_autocommit(t *testing.T) {
+	producer := mocks.NewSyncProducer(t, sarama.NewConfig())
+	producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(deadLetterChecker(deadLetterReasonUnmarshal))
+	c := newDeadLetterTracesHandler(t, producer, new(consumertest.TracesSink), nil)
+	c.autocommitEnabled = true
+	c.deadLetter.autocommit = true
+
+	// the offset of the dead-lettered message is left to the auto-commit
+	session := &markingConsumerGroupSession{testConsumerGroupSession: testConsumerGroupSession{ctx: context.Background()}}
+	require.NoError(t, consumeClaim(t, c, session, &sarama.ConsumerMessage{Value: []byte("!@#"), Offset: 1}))
+	require.NoError(t, producer.Close())
+	assert.Equal(t, []int64{1}, session.markedOffsets())
+	assert.Zero(t, session.commitCount())
+}
+
+func TestTracesConsumerGroupHandler_dead_letter_rejected(t *testing.T) {
+	producer := mocks.NewSyncProducer(t, sarama.NewConfig())
+	producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(deadLetterChecker(deadLetterReasonRejected))
+	c := newDeadLetterTracesHandler(t, producer, nil, consumererror.NewPermanent(errors.New("rejected")))
+
+	session := &markingConsumerGroupSession{testConsumerGroupSession: testConsumerGroupSession{ctx: context.Background()}}
+	require.NoError(t, consumeClaim(t, c, session, tracesMessage(t, 1)))
+	require.NoError(t, producer.Close())
+	assert.Equal(t, []int64{1}, session.markedOffsets())
+}
+
+func TestTracesConsumerGroupHandler_dead_letter_retryable(t *testing.T) {
+	// retryable errors are not sent to the dead letter topic
+	producer := mocks.NewSyncProducer(t, sarama.NewConfig())
+	consumerErr := errors.New("retry later")
+	c := newDeadLetterTracesHandler(t, producer, nil, consumerErr)
+
+	session := &markingConsumerGroupSession{testConsumerGroupSession: testConsumerGroupSession{ctx: context.Background()}}
+	assert.ErrorIs(t, consumeClaim(t, c, session, tracesMessage(t, 1)), consumerErr)
+	require.NoError(t, producer.Close())
+	assert.Empty(t, session.markedOffsets())
+}
+
+func TestTracesConsumerGroupHandler_dead_letter_failure(t *testing.T) {
+	producer := mocks.NewSyncProducer(t, sarama.NewConfig())
+	producer.ExpectSendMessageAndFail(errors.New("unavailable"))
+	c := newDeadLetterTracesHandler(t, producer, new(consumertest.TracesSink), nil)
+
+	session := &markingConsumerGroupSession{testConsumerGroupSession: testConsumerGroupSession{ctx: context.Background()}}
+	assert.Error(t, consumeClaim(t, c, session, &sarama.ConsumerMessage{Value: []byte("!@#"), Offset: 1}))
+	require.NoError(t, producer.Close())
+	assert.Empty(t, session.markedOffsets())
+}
+
+func TestTracesConsumerGroupHandler_dead_letter_transactional(t *testing.T) {
+	config := sarama.NewConfig()
+	config.Producer.Idempotent = true
+	config.Producer.RequiredAcks = sarama.WaitForAll
+	config.Producer.Transaction.ID = "otel-collector-test"
+	config.Net.MaxOpenRequests = 1
+	producer := mocks.NewSyncProducer(t, config)
+	producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(deadLetterChecker(deadLetterReasonUnmarshal))
+	c := newDeadLetterTracesHandler(t, producer, new(consumertest.TracesSink), nil)
+
+	session := &markingConsumerGroupSession{testConsumerGroupSession: testConsumerGroupSession{ctx: context.Background()}}
+	require.NoError(t, consumeClaim(t, c, session, &sarama.ConsumerMessage{Value: []byte("!@#"), Offset: 1}))
+	require.NoError(t, producer.Close())
+	assert.Equal(t, []int64{1}, session.markedOffsets())
+}
+
+func TestDeadLetterHeaders(t *testing.T) {
+	message := &sarama.ConsumerMessage{
+		Topic:     "otlp_spans",
+		Partition: 3,
+		Offset:    42,
+		Headers:   []*sarama.RecordHeader{{Key: []byte("key"), Value: []byte("value")}},
+	}
+	headers := deadLetterHeaders(message, deadLetterReasonRejected, errors.New("invalid data"))
+	assert.Equal(t, []sarama.RecordHeader{
+		{Key: []byte("key"), Value: []byte("value")},
+		{Key: []byte(deadLetterHeaderReason), Value: []byte(deadLetterReasonRejected)},
+		{Key: []byte(deadLetterHeaderError), Value: []byte("invalid data")},
+		{Key: []byte(deadLetterHeaderTopic), Value: []byte("otlp_spans")},
+		{Key: []byte(deadLetterHeaderPartition), Value: []byte("3")},
+		{Key: []byte(deadLetterHeaderOffset), Value: []byte("42")},
+	}, headers)
+}
+
+func TestDeadLetterTransactionalID(t *testing.T) {
+	hostname, err := os.Hostname()
+	require.NoError(t, err)
+	config := Config{GroupID: "otel-collector"}
+	receiverID := component.MustNewIDWithName("kafka", "orders")
+
+	id, err := deadLetterTransactionalID(config, receiverID, component.DataTypeLogs)
+	require.NoError(t, err)
+	assert.Equal(t, "otel-collector-"+hostname+"-kafka/orders-logs", id)
+
+	// the producers of the signals and receivers of a collector must not fence each other
+	ids := map[string]bool{id: true}
+	for _, receiverID := range []component.ID{receiverID, component.MustNewID("kafka")} {
+		for _, signal := range []component.DataType{component.DataTypeTraces, component.DataTypeMetrics, component.DataTypeLogs} {
+			id, err := deadLetterTransactionalID(config, receiverID, signal)
+			require.NoError(t, err)
+			ids[id] = true
+		}
+	}
+	assert.Len(t, ids, 6)
+
+	config.DeadLetter.TransactionalID = "collector-0"
+	id, err = deadLetterTransactionalID(config, receiverID, component.DataTypeTraces)
+	require.NoError(t, err)
+	assert.Equal(t, "collector-0-kafka/orders-traces", id)
+}
+
+func TestOffsetCommitSettings(t *testing.T) {
+	config := Config{
+		AutoCommit:     AutoCommit{Enable: true},
+		MessageMarking: MessageMarking{After: false, OnError: true},
+	}
+	autocommit, marking := offsetCommitSettings(config)
+	assert.True(t, autocommit)
+	assert.Equal(t, config.MessageMarking, marking)
+
+	config.CommitAfterConsume.Enable = true
+	autocommit, marking = offsetCommitSettings(config)
+	assert.False(t, autocommit)
+	assert.Equal(t, MessageMarking{After: true, OnError: false}, marking)
+}
@@ -14,6 +14,7 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componentstatus"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/ptrace"
@@ -50,6 +51,7 @@ type kafkaTracesConsumer struct {
 
 	autocommitEnabled bool
 	messageMarking    MessageMarking
+	deadLetter        *deadLetterQueue
 	headerExtraction  bool
 	headers           []string
 	minFetchSize      int32
@@ -71,6 +73,7 @@ type kafkaMetricsConsumer struct {
 
 	autocommitEnabled bool
 	messageMarking    MessageMarking
+	deadLetter        *deadLetterQueue
 	headerExtraction  bool
 	headers           []string
 	minFetchSize      int32
@@ -92,6 +95,7 @@ type kafkaLogsConsumer struct {
 
 	autocommitEnabled bool
 	messageMarking    MessageMarking
+	deadLetter        *deadLetterQueue
 	headerExtraction  bool
 	headers           []string
 	minFetchSize      int32
@@ -109,13 +113,14 @@ func newTracesReceiver(config Config, set receiver.Settings, nextConsumer consum
 		return nil, err
 	}
 
+	autocommitEnabled, messageMarking := offsetCommitSettings(config)
 	return &kafkaTracesConsumer{
 		config:            config,
 		topics:            []string{config.Topic},
 		nextConsumer:      nextConsumer,
 		settings:          set,
-		autocommitEnabled: config.AutoCommit.Enable,
-		messageMarking:    config.MessageMarking,
+		autocommitEnabled: autocommitEnabled,
+		messageMarking:    messageMarking,
 		headerExtraction:  config.HeaderExtraction.ExtractHeaders,
 		headers:           config.HeaderExtraction.Headers,
 		telemetryBuilder:  telemetryBuilder,
@@ -125,13 +130,22 @@ func newTracesReceiver(config Config, set receiver.Settings, nextConsumer consum
 	}, nil
 }
 
+// offsetCommitSettings returns whether offsets are auto-committed and how messages are marked,
+// commit_after_consume requiring messages to be marked and committed after their processing.
+func offsetCommitSettings(config Config) (bool, MessageMarking) {
+	if config.CommitAfterConsume.Enable {
+		return false, MessageMarking{After: true, OnError: false}
+	}
+	return config.AutoCommit.Enable, config.MessageMarking
+}
+
 func createKafkaClient(config Config) (sarama.ConsumerGroup, error) {
 	saramaConfig := sarama.NewConfig()
 	saramaConfig.ClientID = config.ClientID
 	saramaConfig.Metadata.Full = config.Metadata.Full
 	saramaConfig.Metadata.Retry.Max = config.Metadata.Retry.Max
 	saramaConfig.Metadata.Retry.Backoff = config.Metadata.Retry.Backoff
-	saramaConfig.Consumer.Offsets.AutoCommit.Enable = config.AutoCommit.Enable
+	saramaConfig.Consumer.Offsets.AutoCommit.Enable, _ = offsetCommitSettings(config)
 	saramaConfig.Consumer.Offsets.AutoCommit.Interval = config.AutoCommit.Interval
 	saramaConfig.Consumer.Group.Session.Timeout = config.SessionTimeout
 	saramaConfig.Consumer.Group.Heartbeat.Interval = config.HeartbeatInterval
@@ -190,6 +204,12 @@ func (c *kafkaTracesConsumer) Start(_ context.Context, host component.Host) erro
 			return err
 		}
 	}
+	// deadLetter may be set in tests to inject fake implementation.
+	if c.config.DeadLetter.Topic != "" && c.deadLetter == nil {
+		if c.deadLetter, err = newDeadLetterQueue(c.config, c.settings.ID, component.DataTypeTraces); err != nil {
+			return err
+		}
+	}
 	consumerGroup := &tracesConsumerGroupHandler{
 		logger:            c.settings.Logger,
 		unmarshaler:       c.unmarshaler,
@@ -200,6 +220,7 @@ func (c *kafkaTracesConsumer) Start(_ context.Context, host component.Host) erro
 		messageMarking:    c.messageMarking,
 		headerExtractor:   &nopHeaderExtractor{},
 		telemetryBuilder:  c.telemetryBuilder,
+		deadLetter:        c.deadLetter,
 	}
 	if c.headerExtraction {
 		consumerGroup.headerExtractor = &headerExtractor{
@@ -237,10 +258,14 @@ func (c *kafkaTracesConsumer) Shutdown(context.Context) error {
 		return nil
 	}
 	c.cancelConsumeLoop()
-	if c.consumerGroup == nil {
-		return nil
+	var err error
+	if c.consumerGroup != nil {
+		err = c.consumerGroup.Close()
+	}
+	if c.deadLetter != nil {
+		err = errors.Join(err, c.deadLetter.close())
 	}
-	return c.consumerGroup.Close()
+	return err
 }
 
 func newMetricsReceiver(config Config, set receiver.Settings, nextConsumer consumer.Metrics) (*kafkaMetricsConsumer, error) {
@@ -249,13 +274,14 @@ func newMetricsReceiver(config Config, set receiver.Settings, nextConsumer consu
 		return nil, err
 	}
 
+	autocommitEnabled, messageMarking := offsetCommitSettings(config)
 	return &kafkaMetricsConsumer{
 		config:            config,
 		topics:            []string{config.Topic},
 		nextConsumer:      nextConsumer,
 		settings:          set,
-		autocommitEnabled: config.AutoCommit.Enable,
-		messageMarking:    config.MessageMarking,
+		autocommitEnabled: autocommitEnabled,
+		messageMarking:    messageMarking,
 		headerExtraction:  config.HeaderExtraction.ExtractHeaders,
 		headers:           config.HeaderExtraction.Headers,
 		telemetryBuilder:  telemetryBuilder,
@@ -298,6 +324,12 @@ func (c *kafkaMetricsConsumer) Start(_ context.Context, host component.Host) err
 			return err
 		}
 	}
+	// deadLetter may be set in tests to inject fake implementation.
+	if c.config.DeadLetter.Topic != "" && c.deadLetter == nil {
+		if c.deadLetter, err = newDeadLetterQueue(c.config, c.settings.ID, component.DataTypeMetrics); err != nil {
+			return err
+		}
+	}
 	metricsConsumerGroup := &metricsConsumerGroupHandler{
 		logger:            c.settings.Logger,
 		unmarshaler:       c.unmarshaler,
@@ -308,6 +340,7 @@ func (c *kafkaMetricsConsumer) Start(_ context.Context, host component.Host) err
 		messageMarking:    c.messageMarking,
 		headerExtractor:   &nopHeaderExtractor{},
 		telemetryBuilder:  c.telemetryBuilder,
+		deadLetter:        c.deadLetter,
 	}
 	if c.headerExtraction {
 		metricsConsumerGroup.headerExtractor = &headerExtractor{
@@ -345,10 +378,14 @@ func (c *kafkaMetricsConsumer) Shutdown(context.Context) error {
 		return nil
 	}
 	c.cancelConsumeLoop()
-	if c.consumerGroup == nil {
-		return nil
+	var err error
+	if c.consumerGroup != nil {
+		err = c.consumerGroup.Close()
+	}
+	if c.deadLetter != nil {
+		err = errors.Join(err, c.deadLetter.close())
 	}
-	return c.consumerGroup.Close()
+	return err
 }
 
 func newLogsReceiver(config Config, set receiver.Settings, nextConsumer consumer.Logs) (*kafkaLogsConsumer, error) {
@@ -357,13 +394,14 @@ func newLogsReceiver(config Config, set receiver.Settings, nextConsumer consumer
 		return nil, err
 	}
 
+	autocommitEnabled, messageMarking := offsetCommitSettings(config)
 	return &kafkaLogsConsumer{
 		config:            config,
 		topics:            []string{config.Topic},
 		nextConsumer:      nextConsumer,
 		settings:          set,
-		autocommitEnabled: config.AutoCommit.Enable,
-		messageMarking:    config.MessageMarking,
+		autocommitEnabled: autocommitEnabled,
+		messageMarking:    messageMarking,
 		headerExtraction:  config.HeaderExtraction.ExtractHeaders,
 		headers:           config.HeaderExtraction.Headers,
 		telemetryBuilder:  telemetryBuilder,
@@ -409,6 +447,12 @@ func (c *kafkaLogsConsumer) Start(_ context.Context, host component.Host) error
 			return err
 		}
 	}
+	// deadLetter may be set in tests to inject fake implementation.
+	if c.config.DeadLetter.Topic != "" && c.deadLetter == nil {
+		if c.deadLetter, err = newDeadLetterQueue(c.config, c.settings.ID, component.DataTypeLogs); err != nil {
+			return err
+		}
+	}
 	logsConsumerGroup := &logsConsumerGroupHandler{
 		logger:            c.settings.Logger,
 		unmarshaler:       c.unmarshaler,
@@ -419,6 +463,7 @@ func (c *kafkaLogsConsumer) Start(_ context.Context, host component.Host) error
 		messageMarking:    c.messageMarking,
 		headerExtractor:   &nopHeaderExtractor{},
 		telemetryBuilder:  c.telemetryBuilder,
+		deadLetter:        c.deadLetter,
 	}
 	if c.headerExtraction {
 		logsConsumerGroup.headerExtractor = &headerExtractor{
@@ -456,10 +501,14 @@ func (c *kafkaLogsConsumer) Shutdown(context.Context) error {
 		return nil
 	}
 	c.cancelConsumeLoop()
-	if c.consumerGroup == nil {
-		return nil
+	var err error
+	if c.consumerGroup != nil {
+		err = c.consumerGroup.Close()
 	}
-	return c.consumerGroup.Close()
+	if c.deadLetter != nil {
+		err = errors.Join(err, c.deadLetter.close())
+	}
+	return err
 }
 
 type tracesConsumerGroupHandler struct {
@@ -477,6 +526,7 @@ type tracesConsumerGroupHandler struct {
 	autocommitEnabled bool
 	messageMarking    MessageMarking
 	headerExtractor   HeaderExtractor
+	deadLetter        *deadLetterQueue
 }
 
 type metricsConsumerGroupHandler struct {
@@ -494,6 +544,7 @@ type metricsConsumerGroupHandler struct {
 	autocommitEnabled bool
 	messageMarking    MessageMarking
 	headerExtractor   HeaderExtractor
+	deadLetter        *deadLetterQueue
 }
 
 type logsConsumerGroupHandler struct {
@@ -511,6 +562,7 @@ type logsConsumerGroupHandler struct {
 	autocommitEnabled bool
 	messageMarking    MessageMarking
 	headerExtractor   HeaderExtractor
+	deadLetter        *deadLetterQueue
 }
 
 var _ sarama.ConsumerGroupHandler = (*tracesConsumerGroupHandler)(nil)
@@ -562,6 +614,12 @@ func (c *tracesConsumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSe
 			if err != nil {
 				c.logger.Error("failed to unmarshal message", zap.Error(err))
 				c.telemetryBuilder.KafkaReceiverUnmarshalFailedSpans.Add(session.Context(), 1, metric.WithAttributes(attribute.String(attrInstanceName, c.id.String())))
+				if c.deadLetter != nil {
+					if err = c.deadLetter.send(session, message, deadLetterReasonUnmarshal, err); err != nil {
+						return err
+					}
+					continue
+				}
 				if c.messageMarking.After && c.messageMarking.OnError {
 					session.MarkMessage(message, "")
 				}
@@ -573,6 +631,12 @@ func (c *tracesConsumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSe
 			err = c.nextConsumer.ConsumeTraces(session.Context(), traces)
 			c.obsrecv.EndTracesOp(ctx, c.unmarshaler.Encoding(), spanCount, err)
 			if err != nil {
+				if c.deadLetter != nil && consumererror.IsPermanent(err) {
+					if err = c.deadLetter.send(session, message, deadLetterReasonRejected, err); err != nil {
+						return err
+					}
+					continue
+				}
 				if c.messageMarking.After && c.messageMarking.OnError {
 					session.MarkMessage(message, "")
 				}
@@ -639,6 +703,12 @@ func (c *metricsConsumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupS
 			if err != nil {
 				c.logger.Error("failed to unmarshal message", zap.Error(err))
 				c.telemetryBuilder.KafkaReceiverUnmarshalFailedMetricPoints.Add(session.Context(), 1, metric.WithAttributes(attribute.String(attrInstanceName, c.id.String())))
+				if c.deadLetter != nil {
+					if err = c.deadLetter.send(session, message, deadLetterReasonUnmarshal, err); err != nil {
+						return err
+					}
+					continue
+				}
 				if c.messageMarking.After && c.messageMarking.OnError {
 					session.MarkMessage(message, "")
 				}
@@ -650,6 +720,12 @@ func (c *metricsConsumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupS
 			err = c.nextConsumer.ConsumeMetrics(session.Context(), metrics)
 			c.obsrecv.EndMetricsOp(ctx, c.unmarshaler.Encoding(), dataPointCount, err)
 			if err != nil {
+				if c.deadLetter != nil && consumererror.IsPermanent(err) {
+					if err = c.deadLetter.send(session, message, deadLetterReasonRejected, err); err != nil {
+						return err
+					}
+					continue
+				}
 				if c.messageMarking.After && c.messageMarking.OnError {
 					session.MarkMessage(message, "")
 				}
@@ -716,6 +792,12 @@ func (c *logsConsumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSess
 			if err != nil {
 				c.logger.Error("failed to unmarshal message", zap.Error(err))
 				c.telemetryBuilder.KafkaReceiverUnmarshalFailedLogRecords.Add(ctx, 1, metric.WithAttributes(attribute.String(attrInstanceName, c.id.String())))
+				if c.deadLetter != nil {
+					if err = c.deadLetter.send(session, message, deadLetterReasonUnmarshal, err); err != nil {
+						return err
+					}
+					continue
+				}
 				if c.messageMarking.After && c.messageMarking.OnError {
 					session.MarkMessage(message, "")
 				}
@@ -726,6 +808,12 @@ func (c *logsConsumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSess
 			err = c.nextConsumer.ConsumeLogs(session.Context(), logs)
 			c.obsrecv.EndLogsOp(ctx, c.unmarshaler.Encoding(), logRecordCount, err)
 			if err != nil {
+				if c.deadLetter != nil && consumererror.IsPermanent(err) {
+					if err = c.deadLetter.send(session, message, deadLetterReasonRejected, err); err != nil {
+						return err
+					}
+					continue
+				}
 				if c.messageMarking.After && c.messageMarking.OnError {
 					session.MarkMessage(message, "")
 				}